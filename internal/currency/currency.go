@@ -2,15 +2,16 @@ package currency
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
-// CoinsPerPound represents how many coins make up one pound of weight
-const CoinsPerPound = 50.0
-
-// Denomination represents a currency type
+// Denomination identifies a kind of coin, such as "gp"
 type Denomination string
 
+// The standard coins every campaign uses. What they weigh and are worth,
+// like any regional coins or trade bars a campaign adds, comes from the
+// coins table.
 const (
 	PlatinumPieces Denomination = "pp"
 	GoldPieces     Denomination = "gp"
@@ -19,203 +20,230 @@ const (
 	CopperPieces   Denomination = "cp"
 )
 
-// Purse represents a character's currency holdings
-type Purse struct {
-	PlatinumPieces int64
-	GoldPieces     int64
-	ElectrumPieces int64
-	SilverPieces   int64
-	CopperPieces   int64
-}
-
-// Currency conversion rates
-var conversionTable = map[Denomination]map[Denomination]int64{
-	PlatinumPieces: {
-		PlatinumPieces: 1,
-		GoldPieces:     5,
-		ElectrumPieces: 10,
-		SilverPieces:   50,
-		CopperPieces:   500,
-	},
-	GoldPieces: {
-		PlatinumPieces: 0,
-		GoldPieces:     1,
-		ElectrumPieces: 2,
-		SilverPieces:   10,
-		CopperPieces:   100,
-	},
-	ElectrumPieces: {
-		PlatinumPieces: 0,
-		GoldPieces:     0,
-		ElectrumPieces: 1,
-		SilverPieces:   5,
-		CopperPieces:   50,
-	},
-	SilverPieces: {
-		PlatinumPieces: 0,
-		GoldPieces:     0,
-		ElectrumPieces: 0,
-		SilverPieces:   1,
-		CopperPieces:   10,
-	},
-	CopperPieces: {
-		PlatinumPieces: 0,
-		GoldPieces:     0,
-		ElectrumPieces: 0,
-		SilverPieces:   0,
-		CopperPieces:   1,
-	},
-}
-
-// Convert converts an amount of one denomination to another
-func Convert(amount int64, from, to Denomination) (converted, remainder int64) {
-	if from == to {
-		return amount, 0
-	}
+// Coin is one denomination in use in a campaign
+type Coin struct {
+	Denomination Denomination
+	Name         string
+	Weight       float64 // Pounds per coin
+	Value        int64   // Copper pieces per coin
+}
 
-	rate := conversionTable[from][to]
-	if rate == 0 {
-		rate = conversionTable[to][from]
-		if rate == 0 {
-			return 0, amount
+// Coinage is the set of coins in use in a campaign
+type Coinage struct {
+	coins          []Coin // Most valuable first
+	byDenomination map[Denomination]Coin
+}
+
+// NewCoinage builds a coinage from its coins. Coins worth nothing can't be
+// counted out and are left out.
+func NewCoinage(coins []Coin) *Coinage {
+	c := &Coinage{byDenomination: make(map[Denomination]Coin, len(coins))}
+	for _, coin := range coins {
+		if coin.Value <= 0 {
+			continue
 		}
-		converted = amount / rate
-		remainder = amount % rate
-		return converted, remainder
+		c.coins = append(c.coins, coin)
+		c.byDenomination[coin.Denomination] = coin
 	}
+	sort.SliceStable(c.coins, func(i, j int) bool {
+		if c.coins[i].Value != c.coins[j].Value {
+			return c.coins[i].Value > c.coins[j].Value
+		}
+		return c.coins[i].Denomination < c.coins[j].Denomination
+	})
+	return c
+}
 
-	return amount * rate, 0
+// Coins lists the coins in use, most valuable first
+func (c *Coinage) Coins() []Coin {
+	return c.coins
 }
 
-// GetTotalCoins gets the total number of coins in the purse
-func GetTotalCoins(p *Purse) int64 {
-	return p.PlatinumPieces + p.GoldPieces + p.ElectrumPieces + p.SilverPieces + p.CopperPieces
+// Coin looks up a denomination
+func (c *Coinage) Coin(denom Denomination) (Coin, bool) {
+	coin, ok := c.byDenomination[denom]
+	return coin, ok
 }
 
-// GetTotalWeight calculates the total weight of all coins in pounds
-func GetTotalWeight(p *Purse) float64 {
-	totalCoins := GetTotalCoins(p)
-	return float64(totalCoins) / CoinsPerPound
+// CheckCoin validates a coin a campaign adds to the standard ones
+func (c *Coinage) CheckCoin(coin Coin) error {
+	if coin.Denomination == "" || coin.Name == "" {
+		return fmt.Errorf("%w: it needs a denomination and a name", ErrBadCoin)
+	}
+	if strings.ContainsAny(string(coin.Denomination), " ,") {
+		return fmt.Errorf("%w: a denomination is a single word", ErrBadCoin)
+	}
+	if coin.Value <= 0 {
+		return fmt.Errorf("%w: %s must be worth at least a copper piece", ErrBadCoin, coin.Name)
+	}
+	if coin.Weight < 0 {
+		return fmt.Errorf("%w: %s can't weigh less than nothing", ErrBadCoin, coin.Name)
+	}
+	if _, ok := c.Coin(coin.Denomination); ok {
+		return fmt.Errorf("%w: %s is a standard coin", ErrBadCoin, coin.Denomination)
+	}
+	return nil
 }
 
-func AddToPurse(p *Purse, amount int64, denom Denomination) {
-	switch denom {
-	case PlatinumPieces:
-		p.PlatinumPieces += amount
-	case GoldPieces:
-		p.GoldPieces += amount
-	case ElectrumPieces:
-		p.ElectrumPieces += amount
-	case SilverPieces:
-		p.SilverPieces += amount
-	case CopperPieces:
-		p.CopperPieces += amount
+// denominations lists the denominations in use, most valuable first
+func (c *Coinage) denominations() []Denomination {
+	denoms := make([]Denomination, len(c.coins))
+	for i, coin := range c.coins {
+		denoms[i] = coin.Denomination
 	}
+	return denoms
 }
 
-func RemoveFromPurse(p *Purse, amount int64, denom Denomination) bool {
-	switch denom {
-	case PlatinumPieces:
-		if p.PlatinumPieces >= amount {
-			p.PlatinumPieces -= amount
-			return true
-		}
-		// Not enough platinum
-		return false
+// valueOf is what one coin of a denomination is worth in copper pieces, or
+// 0 for a coin not in use
+func (c *Coinage) valueOf(denom Denomination) int64 {
+	return c.byDenomination[denom].Value
+}
 
-	case GoldPieces:
-		if p.GoldPieces >= amount {
-			p.GoldPieces -= amount
-			return true
-		}
-		// Not enough gold, try converting from platinum
-		ppNeeded := (amount - p.GoldPieces + 4) / 5 // Ceiling division
-		if p.PlatinumPieces >= ppNeeded {
-			p.PlatinumPieces -= ppNeeded
-			p.GoldPieces += ppNeeded*5 - amount
-			return true
-		}
-		return false
+// Convert converts an amount of one denomination to another. Whatever
+// doesn't make a whole coin of the new denomination is returned as a
+// remainder in the old one.
+func (c *Coinage) Convert(amount int64, from, to Denomination) (converted, remainder int64) {
+	if from == to {
+		return amount, 0
+	}
 
-	case ElectrumPieces:
-		if p.ElectrumPieces >= amount {
-			p.ElectrumPieces -= amount
-			return true
-		}
-		// Try converting from higher denominations
-		// First check if we have gold pieces
-		if p.GoldPieces > 0 {
-			gpNeeded := (amount - p.ElectrumPieces + 1) / 2 // Ceiling division
-			if p.GoldPieces >= gpNeeded {
-				p.GoldPieces -= gpNeeded
-				p.ElectrumPieces += gpNeeded*2 - amount
-				return true
-			}
-		}
-		// Not enough direct conversion possible
-		return false
+	fromValue, toValue := c.valueOf(from), c.valueOf(to)
+	if fromValue == 0 || toValue == 0 {
+		return 0, amount
+	}
 
-	case SilverPieces:
-		if p.SilverPieces >= amount {
-			p.SilverPieces -= amount
-			return true
-		}
-		// Try from electrum first
-		if p.ElectrumPieces > 0 {
-			epNeeded := (amount - p.SilverPieces + 4) / 5 // Ceiling division
-			if p.ElectrumPieces >= epNeeded {
-				p.ElectrumPieces -= epNeeded
-				p.SilverPieces += epNeeded*5 - amount
-				return true
-			}
-		}
-		// Not enough direct conversion possible
-		return false
+	value := amount * fromValue
+	converted = value / toValue
+	remainder = (value % toValue) / fromValue
+	return converted, remainder
+}
 
-	case CopperPieces:
-		if p.CopperPieces >= amount {
-			p.CopperPieces -= amount
-			return true
-		}
-		// Try from silver first
-		if p.SilverPieces > 0 {
-			spNeeded := (amount - p.CopperPieces + 9) / 10 // Ceiling division
-			if p.SilverPieces >= spNeeded {
-				p.SilverPieces -= spNeeded
-				p.CopperPieces += spNeeded*10 - amount
-				return true
-			}
+// Value returns the worth of a purse in copper pieces. Coins no longer in
+// use are worth nothing.
+func (c *Coinage) Value(p Purse) int64 {
+	var total int64
+	for denom, count := range p {
+		total += count * c.valueOf(denom)
+	}
+	return total
+}
+
+// Weight returns the weight of a purse's coins in pounds
+func (c *Coinage) Weight(p Purse) float64 {
+	var total float64
+	for denom, count := range p {
+		total += float64(count) * c.byDenomination[denom].Weight
+	}
+	return total
+}
+
+// Format shows a purse's coins most valuable first, such as "3 gp, 5 sp"
+func (c *Coinage) Format(p Purse) string {
+	var parts []string
+	for _, denom := range c.order(p) {
+		if count := p.Of(denom); count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count, denom))
 		}
-		// Not enough direct conversion possible
-		return false
 	}
 
-	return false
+	if len(parts) == 0 {
+		return "0 cp"
+	}
+
+	return strings.Join(parts, ", ")
 }
 
-// FormatPurse formats the purse contents as a readable string
-func FormatPurse(p *Purse) string {
+// FormatChange shows coins going in and out of a purse, such as
+// "+3 gp, -5 sp"
+func (c *Coinage) FormatChange(p Purse) string {
 	var parts []string
+	for _, denom := range c.order(p) {
+		if count := p.Of(denom); count != 0 {
+			parts = append(parts, fmt.Sprintf("%+d %s", count, denom))
+		}
+	}
 
-	if p.PlatinumPieces > 0 {
-		parts = append(parts, fmt.Sprintf("%d pp", p.PlatinumPieces))
+	if len(parts) == 0 {
+		return "none"
 	}
-	if p.GoldPieces > 0 {
-		parts = append(parts, fmt.Sprintf("%d gp", p.GoldPieces))
+
+	return strings.Join(parts, ", ")
+}
+
+// order lists the denominations to show a purse in: the coins in use most
+// valuable first, then any the campaign has stopped using, which still show
+// up
+func (c *Coinage) order(p Purse) []Denomination {
+	denoms := c.denominations()
+	var others []Denomination
+	for denom := range p {
+		if _, ok := c.byDenomination[denom]; !ok {
+			others = append(others, denom)
+		}
 	}
-	if p.ElectrumPieces > 0 {
-		parts = append(parts, fmt.Sprintf("%d ep", p.ElectrumPieces))
+	sort.Slice(others, func(i, j int) bool { return others[i] < others[j] })
+	return append(denoms, others...)
+}
+
+// Remove takes an amount of one denomination out of a purse. When there
+// aren't enough of those coins the amount is paid from any mix of coins
+// instead, with change given in as few coins as possible. It reports false,
+// changing nothing, when the purse is worth less than the amount.
+func (c *Coinage) Remove(p Purse, amount int64, denom Denomination) bool {
+	if amount < 0 || c.valueOf(denom) == 0 {
+		return false
 	}
-	if p.SilverPieces > 0 {
-		parts = append(parts, fmt.Sprintf("%d sp", p.SilverPieces))
+	if p.Of(denom) >= amount {
+		p.Add(denom, -amount)
+		return true
 	}
-	if p.CopperPieces > 0 {
-		parts = append(parts, fmt.Sprintf("%d cp", p.CopperPieces))
+	_, _, ok := c.Pay(p, amount*c.valueOf(denom))
+	return ok
+}
+
+// Purse holds a number of coins of each denomination
+type Purse map[Denomination]int64
+
+// Add puts coins of a denomination in the purse, or takes them out when
+// the amount is negative
+func (p Purse) Add(denom Denomination, amount int64) {
+	if count := p[denom] + amount; count != 0 {
+		p[denom] = count
+	} else {
+		delete(p, denom)
 	}
+}
 
-	if len(parts) == 0 {
-		return "0 cp"
+// Of is the number of coins of a denomination in the purse
+func (p Purse) Of(denom Denomination) int64 {
+	return p[denom]
+}
+
+// Count is the number of coins in the purse
+func (p Purse) Count() int64 {
+	var total int64
+	for _, count := range p {
+		total += count
 	}
+	return total
+}
 
-	return strings.Join(parts, ", ")
+// Clone copies the purse
+func (p Purse) Clone() Purse {
+	clone := make(Purse, len(p))
+	for denom, count := range p {
+		clone[denom] = count
+	}
+	return clone
+}
+
+// Diff is the coins that turn one purse into another, negative for coins
+// taken out
+func Diff(before, after Purse) Purse {
+	diff := after.Clone()
+	for denom, count := range before {
+		diff.Add(denom, -count)
+	}
+	return diff
 }
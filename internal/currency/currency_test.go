@@ -0,0 +1,66 @@
+package currency
+
+import "testing"
+
+// standardCoinage is the five coins every campaign starts with
+func standardCoinage() *Coinage {
+	return NewCoinage([]Coin{
+		{Denomination: PlatinumPieces, Name: "Platinum Piece", Weight: 0.01, Value: 500},
+		{Denomination: GoldPieces, Name: "Gold Piece", Weight: 0.01, Value: 100},
+		{Denomination: ElectrumPieces, Name: "Electrum Piece", Weight: 0.01, Value: 50},
+		{Denomination: SilverPieces, Name: "Silver Piece", Weight: 0.01, Value: 10},
+		{Denomination: CopperPieces, Name: "Copper Piece", Weight: 0.01, Value: 1},
+	})
+}
+
+// samePurse reports whether two purses hold the same coins
+func samePurse(a, b Purse) bool {
+	for denom, count := range a {
+		if b.Of(denom) != count {
+			return false
+		}
+	}
+	for denom, count := range b {
+		if a.Of(denom) != count {
+			return false
+		}
+	}
+	return true
+}
+
+func TestConvert(t *testing.T) {
+	c := standardCoinage()
+	tests := []struct {
+		name          string
+		amount        int64
+		from, to      Denomination
+		wantConverted int64
+		wantRemainder int64
+	}{
+		{"platinum to silver", 3, PlatinumPieces, SilverPieces, 150, 0},
+		{"platinum to copper", 2, PlatinumPieces, CopperPieces, 1000, 0},
+		{"gold to platinum with gold left", 7, GoldPieces, PlatinumPieces, 1, 2},
+		{"silver to platinum with silver left", 123, SilverPieces, PlatinumPieces, 2, 23},
+		{"copper short of a gold piece", 25, CopperPieces, GoldPieces, 0, 25},
+		{"electrum to gold with electrum left", 3, ElectrumPieces, GoldPieces, 1, 1},
+		{"same denomination", 4, GoldPieces, GoldPieces, 4, 0},
+		{"coin not in use", 5, GoldPieces, "tb", 0, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, remainder := c.Convert(tt.amount, tt.from, tt.to)
+			if converted != tt.wantConverted || remainder != tt.wantRemainder {
+				t.Errorf("Convert(%d, %s, %s) = %d, %d; want %d, %d",
+					tt.amount, tt.from, tt.to, converted, remainder, tt.wantConverted, tt.wantRemainder)
+			}
+			// Nothing is lost between the two denominations
+			if tt.wantConverted > 0 {
+				value := converted*c.valueOf(tt.to) + remainder*c.valueOf(tt.from)
+				if value != tt.amount*c.valueOf(tt.from) {
+					t.Errorf("Convert(%d, %s, %s) is worth %d cp; want %d",
+						tt.amount, tt.from, tt.to, value, tt.amount*c.valueOf(tt.from))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// DefaultExchangeFeePercent is what a money changer keeps of the coins
+// handed over when no other fee is given
+const DefaultExchangeFeePercent = 5
+
+// Ways to consolidate a purse
+const (
+	FewestCoins  = "fewest"
+	LightestLoad = "lightest"
+)
+
+var (
+	ErrNotEnoughCoins    = errors.New("not enough coins")
+	ErrBadExchange       = errors.New("invalid exchange")
+	ErrBadFee            = errors.New("the fee must be between 0% and 100%")
+	ErrNothingToExchange = errors.New("nothing to exchange")
+	ErrBadCoin           = errors.New("invalid coin")
+)
+
+// IsRuleError reports whether err came from the coinage or money changing
+// rules rather than from reading or writing the purse
+func IsRuleError(err error) bool {
+	for _, ruleErr := range []error{ErrNotEnoughCoins, ErrBadExchange, ErrBadFee, ErrNothingToExchange, ErrBadCoin} {
+		if errors.Is(err, ruleErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Exchange records coins handed to a money changer and what came back
+type Exchange struct {
+	Given    Purse
+	Received Purse
+	Fee      int64 // Copper pieces kept by the changer
+}
+
+// arrange counts out an amount in copper pieces taking as many of each
+// denomination as possible in the given order
+func (c *Coinage) arrange(amount int64, order []Denomination) Purse {
+	p := Purse{}
+	for _, denom := range order {
+		coinValue := c.valueOf(denom)
+		p.Add(denom, amount/coinValue)
+		amount %= coinValue
+	}
+	return p
+}
+
+// lightestOrder lists denominations from least to most weight per copper
+// piece of value. A coin worth a single copper piece makes up whatever the
+// coins before it leave over.
+func (c *Coinage) lightestOrder() []Denomination {
+	order := c.denominations()
+	sort.SliceStable(order, func(i, j int) bool {
+		return c.weightPerCopper(order[i]) < c.weightPerCopper(order[j])
+	})
+	return order
+}
+
+func (c *Coinage) weightPerCopper(denom Denomination) float64 {
+	coin := c.byDenomination[denom]
+	return coin.Weight / float64(coin.Value)
+}
+
+// fee is a percentage of a value in copper pieces, rounded up
+func fee(value, feePercent int64) int64 {
+	return (value*feePercent + 99) / 100
+}
+
+// ChangeCoins has a money changer turn some coins of one denomination into
+// another, keeping a fee. Value too small to make a whole coin of the new
+// denomination comes back in smaller coins.
+func (c *Coinage) ChangeCoins(p Purse, amount int64, from, to Denomination, feePercent int64) (Exchange, error) {
+	if feePercent < 0 || feePercent > 100 {
+		return Exchange{}, ErrBadFee
+	}
+	if c.valueOf(from) == 0 || c.valueOf(to) == 0 || from == to {
+		return Exchange{}, fmt.Errorf("%w: %s into %s", ErrBadExchange, from, to)
+	}
+	if amount <= 0 {
+		return Exchange{}, fmt.Errorf("%w: give at least one coin", ErrBadExchange)
+	}
+	if p.Of(from) < amount {
+		return Exchange{}, fmt.Errorf("%w: only %d %s", ErrNotEnoughCoins, p.Of(from), from)
+	}
+
+	value := amount * c.valueOf(from)
+	kept := fee(value, feePercent)
+	net := value - kept
+	if net < c.valueOf(to) {
+		return Exchange{}, fmt.Errorf("%w: %d %s won't buy a single %s after the fee", ErrBadExchange, amount, from, to)
+	}
+
+	exchange := Exchange{Given: Purse{from: amount}, Fee: kept}
+	exchange.Received = c.MakeChange(net % c.valueOf(to))
+	exchange.Received.Add(to, net/c.valueOf(to))
+
+	apply(p, exchange)
+	return exchange, nil
+}
+
+// Consolidate has a money changer turn a purse into as few coins, or as
+// little weight, as its value allows. The fee is charged only on the coins
+// that change hands.
+func (c *Coinage) Consolidate(p Purse, mode string, feePercent int64) (Exchange, error) {
+	if feePercent < 0 || feePercent > 100 {
+		return Exchange{}, ErrBadFee
+	}
+	var order []Denomination
+	switch mode {
+	case FewestCoins:
+		order = c.denominations()
+	case LightestLoad:
+		order = c.lightestOrder()
+	default:
+		return Exchange{}, fmt.Errorf("%w: can't consolidate by %q", ErrBadExchange, mode)
+	}
+
+	// The fee depends on which coins change hands and which coins change
+	// hands depends on the fee: raise the fee until it covers the coins
+	// handed over, then lower it while a smaller one still does
+	value := c.Value(p)
+	exchange, handed := c.consolidation(p, value, 0, order)
+	for need := fee(handed, feePercent); need > exchange.Fee; need = fee(handed, feePercent) {
+		exchange, handed = c.consolidation(p, value, need, order)
+	}
+	for {
+		lower, lowerHanded := c.consolidation(p, value, fee(handed, feePercent), order)
+		if lower.Fee >= exchange.Fee || fee(lowerHanded, feePercent) > lower.Fee {
+			break
+		}
+		exchange, handed = lower, lowerHanded
+	}
+
+	if exchange.Given.Count() == 0 {
+		return Exchange{}, fmt.Errorf("%w: the purse is already as %s as it gets", ErrNothingToExchange, consolidateLabel(mode))
+	}
+
+	apply(p, exchange)
+	return exchange, nil
+}
+
+// consolidation works out the exchange that leaves a purse holding its
+// value less a fee arranged in the given order, and the value of the coins
+// handed over
+func (c *Coinage) consolidation(p Purse, value, kept int64, order []Denomination) (Exchange, int64) {
+	exchange := Exchange{Given: Purse{}, Received: Purse{}, Fee: kept}
+	target := c.arrange(value-kept, order)
+	var handed int64
+	for denom, diff := range Diff(p, target) {
+		if diff > 0 {
+			exchange.Received.Add(denom, diff)
+		} else {
+			exchange.Given.Add(denom, -diff)
+			handed += -diff * c.valueOf(denom)
+		}
+	}
+	return exchange, handed
+}
+
+func consolidateLabel(mode string) string {
+	if mode == LightestLoad {
+		return "light"
+	}
+	return "compact"
+}
+
+// apply hands over the given coins and takes in the received ones
+func apply(p Purse, exchange Exchange) {
+	for denom, count := range exchange.Received {
+		p.Add(denom, count)
+	}
+	for denom, count := range exchange.Given {
+		p.Add(denom, -count)
+	}
+}
@@ -0,0 +1,231 @@
+package currency
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChangeCoins(t *testing.T) {
+	c := standardCoinage()
+	tests := []struct {
+		name         string
+		purse        Purse
+		amount       int64
+		from, to     Denomination
+		feePercent   int64
+		wantReceived Purse
+		wantFee      int64
+		wantPurse    Purse
+		wantErr      error
+	}{
+		{
+			name:         "gold into platinum with a fee",
+			purse:        Purse{GoldPieces: 10},
+			amount:       10,
+			from:         GoldPieces,
+			to:           PlatinumPieces,
+			feePercent:   5,
+			wantReceived: Purse{PlatinumPieces: 1, GoldPieces: 4, ElectrumPieces: 1},
+			wantFee:      50,
+			wantPurse:    Purse{PlatinumPieces: 1, GoldPieces: 4, ElectrumPieces: 1},
+		},
+		{
+			name:         "platinum into copper without a fee",
+			purse:        Purse{PlatinumPieces: 2},
+			amount:       1,
+			from:         PlatinumPieces,
+			to:           CopperPieces,
+			wantReceived: Purse{CopperPieces: 500},
+			wantPurse:    Purse{PlatinumPieces: 1, CopperPieces: 500},
+		},
+		{
+			name:         "fee rounds up",
+			purse:        Purse{SilverPieces: 3},
+			amount:       3,
+			from:         SilverPieces,
+			to:           CopperPieces,
+			feePercent:   5,
+			wantReceived: Purse{CopperPieces: 28},
+			wantFee:      2,
+			wantPurse:    Purse{CopperPieces: 28},
+		},
+		{
+			name:       "more coins than held",
+			purse:      Purse{GoldPieces: 1},
+			amount:     2,
+			from:       GoldPieces,
+			to:         SilverPieces,
+			wantPurse:  Purse{GoldPieces: 1},
+			wantErr:    ErrNotEnoughCoins,
+			feePercent: 5,
+		},
+		{
+			name:      "too little for one coin after the fee",
+			purse:     Purse{GoldPieces: 5},
+			amount:    5,
+			from:      GoldPieces,
+			to:        PlatinumPieces,
+			wantPurse: Purse{GoldPieces: 5},
+			wantErr:   ErrBadExchange,
+			// 500 cp less 1% is short of a platinum piece
+			feePercent: 1,
+		},
+		{
+			name:      "same denomination",
+			purse:     Purse{GoldPieces: 5},
+			amount:    5,
+			from:      GoldPieces,
+			to:        GoldPieces,
+			wantPurse: Purse{GoldPieces: 5},
+			wantErr:   ErrBadExchange,
+		},
+		{
+			name:       "fee out of range",
+			purse:      Purse{GoldPieces: 5},
+			amount:     5,
+			from:       GoldPieces,
+			to:         SilverPieces,
+			feePercent: 101,
+			wantPurse:  Purse{GoldPieces: 5},
+			wantErr:    ErrBadFee,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := c.Value(tt.purse)
+			exchange, err := c.ChangeCoins(tt.purse, tt.amount, tt.from, tt.to, tt.feePercent)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ChangeCoins error = %v; want %v", err, tt.wantErr)
+			}
+			if !samePurse(tt.purse, tt.wantPurse) {
+				t.Errorf("ChangeCoins left %v; want %v", tt.purse, tt.wantPurse)
+			}
+			if err != nil {
+				return
+			}
+			if !samePurse(exchange.Received, tt.wantReceived) {
+				t.Errorf("ChangeCoins received %v; want %v", exchange.Received, tt.wantReceived)
+			}
+			if exchange.Fee != tt.wantFee {
+				t.Errorf("ChangeCoins fee = %d; want %d", exchange.Fee, tt.wantFee)
+			}
+			if got := c.Value(tt.purse) + exchange.Fee; got != before {
+				t.Errorf("ChangeCoins purse and fee worth %d cp; want %d", got, before)
+			}
+		})
+	}
+}
+
+func TestConsolidate(t *testing.T) {
+	standard := standardCoinage()
+	// Gold is lighter for its worth than platinum here
+	heavyPlatinum := NewCoinage([]Coin{
+		{Denomination: PlatinumPieces, Weight: 0.1, Value: 500},
+		{Denomination: GoldPieces, Weight: 0.01, Value: 100},
+		{Denomination: SilverPieces, Weight: 0.01, Value: 10},
+		{Denomination: CopperPieces, Weight: 0.01, Value: 1},
+	})
+
+	tests := []struct {
+		name         string
+		coinage      *Coinage
+		purse        Purse
+		mode         string
+		feePercent   int64
+		wantGiven    Purse
+		wantReceived Purse
+		wantFee      int64
+		wantErr      error
+	}{
+		{
+			name:         "fewest coins without a fee",
+			coinage:      standard,
+			purse:        Purse{SilverPieces: 10, CopperPieces: 250},
+			mode:         FewestCoins,
+			wantGiven:    Purse{SilverPieces: 10, CopperPieces: 250},
+			wantReceived: Purse{GoldPieces: 3, ElectrumPieces: 1},
+		},
+		{
+			name:         "fewest coins with a fee",
+			coinage:      standard,
+			purse:        Purse{CopperPieces: 1000},
+			mode:         FewestCoins,
+			feePercent:   5,
+			wantGiven:    Purse{CopperPieces: 1000},
+			wantReceived: Purse{PlatinumPieces: 1, GoldPieces: 4, ElectrumPieces: 1},
+			wantFee:      50,
+		},
+		{
+			name:         "fee only on the coins that change hands",
+			coinage:      standard,
+			purse:        Purse{PlatinumPieces: 4, CopperPieces: 200},
+			mode:         FewestCoins,
+			feePercent:   10,
+			wantGiven:    Purse{CopperPieces: 200},
+			wantReceived: Purse{GoldPieces: 1, ElectrumPieces: 1, SilverPieces: 3},
+			wantFee:      20,
+		},
+		{
+			name:         "lightest load",
+			coinage:      heavyPlatinum,
+			purse:        Purse{PlatinumPieces: 1},
+			mode:         LightestLoad,
+			wantGiven:    Purse{PlatinumPieces: 1},
+			wantReceived: Purse{GoldPieces: 5},
+		},
+		{
+			name:    "already compact",
+			coinage: standard,
+			purse:   Purse{GoldPieces: 1},
+			mode:    FewestCoins,
+			wantErr: ErrNothingToExchange,
+		},
+		{
+			name:    "unknown mode",
+			coinage: standard,
+			purse:   Purse{CopperPieces: 100},
+			mode:    "shiniest",
+			wantErr: ErrBadExchange,
+		},
+		{
+			name:       "fee out of range",
+			coinage:    standard,
+			purse:      Purse{CopperPieces: 100},
+			mode:       FewestCoins,
+			feePercent: -1,
+			wantErr:    ErrBadFee,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.coinage
+			before := c.Value(tt.purse)
+			original := tt.purse.Clone()
+			exchange, err := c.Consolidate(tt.purse, tt.mode, tt.feePercent)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Consolidate error = %v; want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !samePurse(tt.purse, original) {
+					t.Errorf("Consolidate changed the purse to %v on error", tt.purse)
+				}
+				return
+			}
+			if !samePurse(exchange.Given, tt.wantGiven) {
+				t.Errorf("Consolidate gave %v; want %v", exchange.Given, tt.wantGiven)
+			}
+			if !samePurse(exchange.Received, tt.wantReceived) {
+				t.Errorf("Consolidate received %v; want %v", exchange.Received, tt.wantReceived)
+			}
+			if exchange.Fee != tt.wantFee {
+				t.Errorf("Consolidate fee = %d; want %d", exchange.Fee, tt.wantFee)
+			}
+			if got := c.Value(tt.purse) + exchange.Fee; got != before {
+				t.Errorf("Consolidate purse and fee worth %d cp; want %d", got, before)
+			}
+			if handed := c.Value(exchange.Given); exchange.Fee < fee(handed, tt.feePercent) {
+				t.Errorf("Consolidate fee %d cp doesn't cover %d%% of %d cp handed over", exchange.Fee, tt.feePercent, handed)
+			}
+		})
+	}
+}
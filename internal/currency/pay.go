@@ -0,0 +1,69 @@
+package currency
+
+import "math"
+
+// FromGold converts a price in gold pieces, which may be fractional, to
+// copper pieces, rounding to the nearest copper. Item values are kept in
+// gold pieces of a hundred copper whatever the campaign's coins.
+func FromGold(gp float64) int64 {
+	return int64(math.Round(gp * 100))
+}
+
+// MakeChange counts out an amount in copper pieces in as few coins as
+// possible
+func (c *Coinage) MakeChange(amount int64) Purse {
+	return c.arrange(amount, c.denominations())
+}
+
+// Pay takes an amount in copper pieces out of a purse. Coins are handed
+// over largest first; when the purse can't cover the amount exactly the
+// smallest coin that covers the rest is added, any coins the overpayment
+// makes unnecessary are kept back, and change is given in as few coins as
+// possible. The purse is left untouched and ok is false when its coins are
+// worth less than the amount.
+func (c *Coinage) Pay(p Purse, amount int64) (paid, change Purse, ok bool) {
+	if amount <= 0 {
+		return Purse{}, Purse{}, true
+	}
+	if c.Value(p) < amount {
+		return Purse{}, Purse{}, false
+	}
+
+	paid = Purse{}
+	owed := amount
+	for _, coin := range c.coins {
+		coins := owed / coin.Value
+		if have := p.Of(coin.Denomination); coins > have {
+			coins = have
+		}
+		paid.Add(coin.Denomination, coins)
+		owed -= coins * coin.Value
+	}
+
+	// Every coin left is worth more than what is still owed, so the
+	// smallest of them settles the bill
+	if owed > 0 {
+		for i := len(c.coins) - 1; i >= 0; i-- {
+			denom := c.coins[i].Denomination
+			if p.Of(denom) > paid.Of(denom) {
+				paid.Add(denom, 1)
+				break
+			}
+		}
+	}
+
+	// Keep back coins the overpayment has made unnecessary
+	over := c.Value(paid) - amount
+	for _, coin := range c.coins {
+		coins := over / coin.Value
+		if handed := paid.Of(coin.Denomination); coins > handed {
+			coins = handed
+		}
+		paid.Add(coin.Denomination, -coins)
+		over -= coins * coin.Value
+	}
+
+	change = c.MakeChange(over)
+	apply(p, Exchange{Given: paid, Received: change})
+	return paid, change, true
+}
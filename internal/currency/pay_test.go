@@ -0,0 +1,105 @@
+package currency
+
+import "testing"
+
+func TestPay(t *testing.T) {
+	c := standardCoinage()
+	tests := []struct {
+		name       string
+		purse      Purse
+		amount     int64
+		wantPaid   Purse
+		wantChange Purse
+		wantPurse  Purse
+		wantOK     bool
+	}{
+		{
+			name:       "exact coins",
+			purse:      Purse{GoldPieces: 5, SilverPieces: 3},
+			amount:     230,
+			wantPaid:   Purse{GoldPieces: 2, SilverPieces: 3},
+			wantChange: Purse{},
+			wantPurse:  Purse{GoldPieces: 3},
+			wantOK:     true,
+		},
+		{
+			name:       "copper from gold only",
+			purse:      Purse{GoldPieces: 3},
+			amount:     1,
+			wantPaid:   Purse{GoldPieces: 1},
+			wantChange: Purse{ElectrumPieces: 1, SilverPieces: 4, CopperPieces: 9},
+			wantPurse:  Purse{GoldPieces: 2, ElectrumPieces: 1, SilverPieces: 4, CopperPieces: 9},
+			wantOK:     true,
+		},
+		{
+			name:       "coins made unnecessary are kept back",
+			purse:      Purse{PlatinumPieces: 1, GoldPieces: 1},
+			amount:     150,
+			wantPaid:   Purse{PlatinumPieces: 1},
+			wantChange: Purse{GoldPieces: 3, ElectrumPieces: 1},
+			wantPurse:  Purse{GoldPieces: 4, ElectrumPieces: 1},
+			wantOK:     true,
+		},
+		{
+			name:       "nothing owed",
+			purse:      Purse{GoldPieces: 1},
+			amount:     0,
+			wantPaid:   Purse{},
+			wantChange: Purse{},
+			wantPurse:  Purse{GoldPieces: 1},
+			wantOK:     true,
+		},
+		{
+			name:       "too little in the purse",
+			purse:      Purse{SilverPieces: 5},
+			amount:     100,
+			wantPaid:   Purse{},
+			wantChange: Purse{},
+			wantPurse:  Purse{SilverPieces: 5},
+			wantOK:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := c.Value(tt.purse)
+			paid, change, ok := c.Pay(tt.purse, tt.amount)
+			if ok != tt.wantOK {
+				t.Fatalf("Pay ok = %v; want %v", ok, tt.wantOK)
+			}
+			if !samePurse(paid, tt.wantPaid) {
+				t.Errorf("Pay paid %v; want %v", paid, tt.wantPaid)
+			}
+			if !samePurse(change, tt.wantChange) {
+				t.Errorf("Pay gave change %v; want %v", change, tt.wantChange)
+			}
+			if !samePurse(tt.purse, tt.wantPurse) {
+				t.Errorf("Pay left %v; want %v", tt.purse, tt.wantPurse)
+			}
+			if ok {
+				if got := c.Value(paid) - c.Value(change); got != tt.amount {
+					t.Errorf("Pay handed over %d cp net; want %d", got, tt.amount)
+				}
+				if got := c.Value(tt.purse); got != before-tt.amount {
+					t.Errorf("Pay left %d cp in the purse; want %d", got, before-tt.amount)
+				}
+			}
+		})
+	}
+}
+
+func TestMakeChange(t *testing.T) {
+	c := standardCoinage()
+	tests := []struct {
+		amount int64
+		want   Purse
+	}{
+		{0, Purse{}},
+		{99, Purse{ElectrumPieces: 1, SilverPieces: 4, CopperPieces: 9}},
+		{1234, Purse{PlatinumPieces: 2, GoldPieces: 2, SilverPieces: 3, CopperPieces: 4}},
+	}
+	for _, tt := range tests {
+		if got := c.MakeChange(tt.amount); !samePurse(got, tt.want) {
+			t.Errorf("MakeChange(%d) = %v; want %v", tt.amount, got, tt.want)
+		}
+	}
+}
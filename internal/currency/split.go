@@ -0,0 +1,112 @@
+package currency
+
+// Conversion records coins that had to be changed into a smaller
+// denomination to finish a split
+type Conversion struct {
+	Amount    int64
+	From      Denomination
+	Converted int64
+	To        Denomination
+}
+
+// SplitResult is the outcome of dividing a hoard
+type SplitResult struct {
+	Shares      []Purse      // Coins for each entitlement, in order
+	Remainder   Purse        // Coins that could not be divided
+	Conversions []Conversion // Changes made between denominations
+}
+
+// Split hands out a hoard's coins to cover each entitlement, given in
+// copper pieces. Larger coins are handed out first and only coins that
+// cannot be handed out whole are changed into the next denomination down,
+// so the hoard is broken into as little small change as possible.
+func (c *Coinage) Split(hoard Purse, entitlements []int64) SplitResult {
+	result := SplitResult{Shares: make([]Purse, len(entitlements)), Remainder: Purse{}}
+	for i := range result.Shares {
+		result.Shares[i] = Purse{}
+	}
+
+	owed := make([]int64, len(entitlements))
+	copy(owed, entitlements)
+
+	// Value too small for a whole coin of the next denomination is
+	// carried down in copper pieces until a coin can take it
+	var carried, carriedValue int64
+	for i, coin := range c.coins {
+		denom := coin.Denomination
+		available := hoard.Of(denom) + carried
+		carried = 0
+
+		for j := range owed {
+			if available == 0 {
+				break
+			}
+			if owed[j] <= 0 {
+				continue
+			}
+			coins := owed[j] / coin.Value
+			if coins > available {
+				coins = available
+			}
+			result.Shares[j].Add(denom, coins)
+			owed[j] -= coins * coin.Value
+			available -= coins
+		}
+
+		if available == 0 && carriedValue == 0 {
+			continue
+		}
+
+		// Change what is left only if someone is still owed something
+		if i+1 < len(c.coins) && anyOwed(owed) {
+			next := c.coins[i+1]
+			value := available*coin.Value + carriedValue
+			carried, carriedValue = value/next.Value, value%next.Value
+			if available > 0 {
+				result.Conversions = append(result.Conversions, Conversion{
+					Amount:    available,
+					From:      denom,
+					Converted: carried,
+					To:        next.Denomination,
+				})
+			}
+			continue
+		}
+		result.Remainder.Add(denom, available)
+		for d, count := range c.MakeChange(carriedValue) {
+			result.Remainder.Add(d, count)
+		}
+		carriedValue = 0
+	}
+
+	return result
+}
+
+// Apportion divides a value by weight, rounding each portion down
+func Apportion(total int64, weights []int64) []int64 {
+	portions := make([]int64, len(weights))
+	var sum int64
+	for _, w := range weights {
+		if w > 0 {
+			sum += w
+		}
+	}
+	if sum == 0 {
+		return portions
+	}
+	for i, w := range weights {
+		if w > 0 {
+			portions[i] = total * w / sum
+		}
+	}
+	return portions
+}
+
+func anyOwed(owed []int64) bool {
+	for _, o := range owed {
+		if o > 0 {
+			return true
+		}
+	}
+	return false
+}
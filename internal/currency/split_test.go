@@ -0,0 +1,127 @@
+package currency
+
+import "testing"
+
+func TestApportion(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int64
+		weights []int64
+		want    []int64
+	}{
+		{"even shares", 1000, []int64{100, 100}, []int64{500, 500}},
+		{"half share", 1000, []int64{100, 100, 50}, []int64{400, 400, 200}},
+		{"rounded down", 100, []int64{1, 1, 1}, []int64{33, 33, 33}},
+		{"negative weight gets nothing", 90, []int64{100, -5, 50}, []int64{60, 0, 30}},
+		{"no weights", 100, []int64{0, 0}, []int64{0, 0}},
+		{"nothing to share", 0, []int64{100, 50}, []int64{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Apportion(tt.total, tt.weights)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apportion(%d, %v) = %v; want %v", tt.total, tt.weights, got, tt.want)
+			}
+			var sum int64
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Apportion(%d, %v) = %v; want %v", tt.total, tt.weights, got, tt.want)
+					break
+				}
+				sum += got[i]
+			}
+			// Rounding down loses less than a copper piece a share
+			if sum > tt.total || (sum > 0 && tt.total-sum >= int64(len(got))) {
+				t.Errorf("Apportion(%d, %v) hands out %d", tt.total, tt.weights, sum)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	c := standardCoinage()
+	tests := []struct {
+		name            string
+		hoard           Purse
+		weights         []int64
+		wantShares      []Purse
+		wantRemainder   Purse
+		wantConversions int
+	}{
+		{
+			name:          "even split",
+			hoard:         Purse{GoldPieces: 10},
+			weights:       []int64{100, 100},
+			wantShares:    []Purse{{GoldPieces: 5}, {GoldPieces: 5}},
+			wantRemainder: Purse{},
+		},
+		{
+			name:            "gold changed into electrum",
+			hoard:           Purse{GoldPieces: 1},
+			weights:         []int64{100, 100},
+			wantShares:      []Purse{{ElectrumPieces: 1}, {ElectrumPieces: 1}},
+			wantRemainder:   Purse{},
+			wantConversions: 1,
+		},
+		{
+			name:          "indivisible copper left over",
+			hoard:         Purse{GoldPieces: 3, CopperPieces: 1},
+			weights:       []int64{100, 100, 100},
+			wantShares:    []Purse{{GoldPieces: 1}, {GoldPieces: 1}, {GoldPieces: 1}},
+			wantRemainder: Purse{CopperPieces: 1},
+		},
+		{
+			name:    "half shares and mixed coins",
+			hoard:   Purse{PlatinumPieces: 3, GoldPieces: 7, ElectrumPieces: 3, SilverPieces: 11, CopperPieces: 23},
+			weights: []int64{100, 100, 50, 50, 50},
+		},
+		{
+			name:    "platinum among many",
+			hoard:   Purse{PlatinumPieces: 1},
+			weights: []int64{100, 100, 100, 100, 100, 100, 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hoardValue := c.Value(tt.hoard)
+			entitlements := Apportion(hoardValue, tt.weights)
+			result := c.Split(tt.hoard, entitlements)
+
+			if len(result.Shares) != len(entitlements) {
+				t.Fatalf("Split gave %d shares; want %d", len(result.Shares), len(entitlements))
+			}
+			for i, want := range tt.wantShares {
+				if !samePurse(result.Shares[i], want) {
+					t.Errorf("Split share %d = %v; want %v", i, result.Shares[i], want)
+				}
+			}
+			if tt.wantRemainder != nil && !samePurse(result.Remainder, tt.wantRemainder) {
+				t.Errorf("Split remainder = %v; want %v", result.Remainder, tt.wantRemainder)
+			}
+			if tt.wantShares != nil && len(result.Conversions) != tt.wantConversions {
+				t.Errorf("Split made %d conversions; want %d", len(result.Conversions), tt.wantConversions)
+			}
+
+			// Shares and remainder together are the whole hoard, and no
+			// share is more than its entitlement
+			total := c.Value(result.Remainder)
+			for i, share := range result.Shares {
+				value := c.Value(share)
+				if value > entitlements[i] {
+					t.Errorf("Split share %d worth %d cp; entitled to %d", i, value, entitlements[i])
+				}
+				total += value
+			}
+			if total != hoardValue {
+				t.Errorf("Split shares and remainder worth %d cp; hoard worth %d", total, hoardValue)
+			}
+
+			// Every conversion keeps its value
+			for _, conv := range result.Conversions {
+				if conv.Amount*c.valueOf(conv.From) < conv.Converted*c.valueOf(conv.To) {
+					t.Errorf("Split converted %d %s into %d %s", conv.Amount, conv.From, conv.Converted, conv.To)
+				}
+			}
+		})
+	}
+}
@@ -24,12 +24,7 @@ INSERT INTO
         intelligence,
         wisdom,
         charisma,
-        experience_points,
-        platinum_pieces,
-        gold_pieces,
-        electrum_pieces,
-        silver_pieces,
-        copper_pieces
+        experience_points
     )
 VALUES
     (
@@ -45,13 +40,8 @@ VALUES
         ?,
         ?,
         ?,
-        ?,
-        ?,
-        ?,
-        ?,
-        ?,
         ?
-    ) RETURNING id, user_id, name, class, level, max_hp, current_hp, strength, dexterity, constitution, intelligence, wisdom, charisma, experience_points, platinum_pieces, gold_pieces, electrum_pieces, silver_pieces, copper_pieces, created_at, updated_at
+    ) RETURNING id, user_id, name, class, level, max_hp, current_hp, strength, dexterity, constitution, intelligence, wisdom, charisma, experience_points, created_at, updated_at
 `
 
 type CreateCharacterParams struct {
@@ -68,11 +58,6 @@ type CreateCharacterParams struct {
 	Wisdom           int64  `json:"wisdom"`
 	Charisma         int64  `json:"charisma"`
 	ExperiencePoints int64  `json:"experience_points"`
-	PlatinumPieces   int64  `json:"platinum_pieces"`
-	GoldPieces       int64  `json:"gold_pieces"`
-	ElectrumPieces   int64  `json:"electrum_pieces"`
-	SilverPieces     int64  `json:"silver_pieces"`
-	CopperPieces     int64  `json:"copper_pieces"`
 }
 
 func (q *Queries) CreateCharacter(ctx context.Context, arg CreateCharacterParams) (Character, error) {
@@ -90,11 +75,6 @@ func (q *Queries) CreateCharacter(ctx context.Context, arg CreateCharacterParams
 		arg.Wisdom,
 		arg.Charisma,
 		arg.ExperiencePoints,
-		arg.PlatinumPieces,
-		arg.GoldPieces,
-		arg.ElectrumPieces,
-		arg.SilverPieces,
-		arg.CopperPieces,
 	)
 	var i Character
 	err := row.Scan(
@@ -112,11 +92,6 @@ func (q *Queries) CreateCharacter(ctx context.Context, arg CreateCharacterParams
 		&i.Wisdom,
 		&i.Charisma,
 		&i.ExperiencePoints,
-		&i.PlatinumPieces,
-		&i.GoldPieces,
-		&i.ElectrumPieces,
-		&i.SilverPieces,
-		&i.CopperPieces,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -142,7 +117,7 @@ func (q *Queries) DeleteCharacter(ctx context.Context, arg DeleteCharacterParams
 
 const getCharacter = `-- name: GetCharacter :one
 SELECT
-    id, user_id, name, class, level, max_hp, current_hp, strength, dexterity, constitution, intelligence, wisdom, charisma, experience_points, platinum_pieces, gold_pieces, electrum_pieces, silver_pieces, copper_pieces, created_at, updated_at
+    id, user_id, name, class, level, max_hp, current_hp, strength, dexterity, constitution, intelligence, wisdom, charisma, experience_points, created_at, updated_at
 FROM
     characters
 WHERE
@@ -175,11 +150,6 @@ func (q *Queries) GetCharacter(ctx context.Context, arg GetCharacterParams) (Cha
 		&i.Wisdom,
 		&i.Charisma,
 		&i.ExperiencePoints,
-		&i.PlatinumPieces,
-		&i.GoldPieces,
-		&i.ElectrumPieces,
-		&i.SilverPieces,
-		&i.CopperPieces,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -188,7 +158,7 @@ func (q *Queries) GetCharacter(ctx context.Context, arg GetCharacterParams) (Cha
 
 const listCharactersByUser = `-- name: ListCharactersByUser :many
 SELECT
-    id, user_id, name, class, level, max_hp, current_hp, strength, dexterity, constitution, intelligence, wisdom, charisma, experience_points, platinum_pieces, gold_pieces, electrum_pieces, silver_pieces, copper_pieces, created_at, updated_at
+    id, user_id, name, class, level, max_hp, current_hp, strength, dexterity, constitution, intelligence, wisdom, charisma, experience_points, created_at, updated_at
 FROM
     characters
 WHERE
@@ -221,11 +191,6 @@ func (q *Queries) ListCharactersByUser(ctx context.Context, userID int64) ([]Cha
 			&i.Wisdom,
 			&i.Charisma,
 			&i.ExperiencePoints,
-			&i.PlatinumPieces,
-			&i.GoldPieces,
-			&i.ElectrumPieces,
-			&i.SilverPieces,
-			&i.CopperPieces,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -257,15 +222,10 @@ SET
     wisdom = ?,
     charisma = ?,
     experience_points = ?,
-    platinum_pieces = ?,
-    gold_pieces = ?,
-    electrum_pieces = ?,
-    silver_pieces = ?,
-    copper_pieces = ?,
     updated_at = CURRENT_TIMESTAMP
 WHERE
     id = ?
-    AND user_id = ? RETURNING id, user_id, name, class, level, max_hp, current_hp, strength, dexterity, constitution, intelligence, wisdom, charisma, experience_points, platinum_pieces, gold_pieces, electrum_pieces, silver_pieces, copper_pieces, created_at, updated_at
+    AND user_id = ? RETURNING id, user_id, name, class, level, max_hp, current_hp, strength, dexterity, constitution, intelligence, wisdom, charisma, experience_points, created_at, updated_at
 `
 
 type UpdateCharacterParams struct {
@@ -281,11 +241,6 @@ type UpdateCharacterParams struct {
 	Wisdom           int64  `json:"wisdom"`
 	Charisma         int64  `json:"charisma"`
 	ExperiencePoints int64  `json:"experience_points"`
-	PlatinumPieces   int64  `json:"platinum_pieces"`
-	GoldPieces       int64  `json:"gold_pieces"`
-	ElectrumPieces   int64  `json:"electrum_pieces"`
-	SilverPieces     int64  `json:"silver_pieces"`
-	CopperPieces     int64  `json:"copper_pieces"`
 	ID               int64  `json:"id"`
 	UserID           int64  `json:"user_id"`
 }
@@ -304,11 +259,6 @@ func (q *Queries) UpdateCharacter(ctx context.Context, arg UpdateCharacterParams
 		arg.Wisdom,
 		arg.Charisma,
 		arg.ExperiencePoints,
-		arg.PlatinumPieces,
-		arg.GoldPieces,
-		arg.ElectrumPieces,
-		arg.SilverPieces,
-		arg.CopperPieces,
 		arg.ID,
 		arg.UserID,
 	)
@@ -328,11 +278,6 @@ func (q *Queries) UpdateCharacter(ctx context.Context, arg UpdateCharacterParams
 		&i.Wisdom,
 		&i.Charisma,
 		&i.ExperiencePoints,
-		&i.PlatinumPieces,
-		&i.GoldPieces,
-		&i.ElectrumPieces,
-		&i.SilverPieces,
-		&i.CopperPieces,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -16,56 +16,56 @@ INSERT INTO
     character_inventory (
         character_id,
         item_id,
-        item_type,
         quantity,
         container_id,
         equipment_slot_id,
+        enhancement_bonus,
         notes
     )
 VALUES
     (?, ?, ?, ?, ?, ?, ?) RETURNING id,
     character_id,
     item_id,
-    item_type,
     quantity,
     container_id,
     equipment_slot_id,
+    enhancement_bonus,
     notes,
     created_at,
     updated_at
 `
 
 type AddItemToInventoryParams struct {
-	CharacterID     int64          `json:"character_id"`
-	ItemID          int64          `json:"item_id"`
-	ItemType        string         `json:"item_type"`
-	Quantity        int64          `json:"quantity"`
-	ContainerID     sql.NullInt64  `json:"container_id"`
-	EquipmentSlotID sql.NullInt64  `json:"equipment_slot_id"`
-	Notes           sql.NullString `json:"notes"`
+	CharacterID      int64          `json:"character_id"`
+	ItemID           int64          `json:"item_id"`
+	Quantity         int64          `json:"quantity"`
+	ContainerID      sql.NullInt64  `json:"container_id"`
+	EquipmentSlotID  sql.NullInt64  `json:"equipment_slot_id"`
+	EnhancementBonus int64          `json:"enhancement_bonus"`
+	Notes            sql.NullString `json:"notes"`
 }
 
 type AddItemToInventoryRow struct {
-	ID              int64          `json:"id"`
-	CharacterID     int64          `json:"character_id"`
-	ItemID          int64          `json:"item_id"`
-	ItemType        string         `json:"item_type"`
-	Quantity        int64          `json:"quantity"`
-	ContainerID     sql.NullInt64  `json:"container_id"`
-	EquipmentSlotID sql.NullInt64  `json:"equipment_slot_id"`
-	Notes           sql.NullString `json:"notes"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
+	ID               int64          `json:"id"`
+	CharacterID      int64          `json:"character_id"`
+	ItemID           int64          `json:"item_id"`
+	Quantity         int64          `json:"quantity"`
+	ContainerID      sql.NullInt64  `json:"container_id"`
+	EquipmentSlotID  sql.NullInt64  `json:"equipment_slot_id"`
+	EnhancementBonus int64          `json:"enhancement_bonus"`
+	Notes            sql.NullString `json:"notes"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
 }
 
 func (q *Queries) AddItemToInventory(ctx context.Context, arg AddItemToInventoryParams) (AddItemToInventoryRow, error) {
 	row := q.db.QueryRowContext(ctx, addItemToInventory,
 		arg.CharacterID,
 		arg.ItemID,
-		arg.ItemType,
 		arg.Quantity,
 		arg.ContainerID,
 		arg.EquipmentSlotID,
+		arg.EnhancementBonus,
 		arg.Notes,
 	)
 	var i AddItemToInventoryRow
@@ -73,10 +73,10 @@ func (q *Queries) AddItemToInventory(ctx context.Context, arg AddItemToInventory
 		&i.ID,
 		&i.CharacterID,
 		&i.ItemID,
-		&i.ItemType,
 		&i.Quantity,
 		&i.ContainerID,
 		&i.EquipmentSlotID,
+		&i.EnhancementBonus,
 		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
@@ -89,7 +89,6 @@ INSERT INTO
     character_inventory (
         character_id,
         item_id,
-        item_type,
         quantity,
         container_id,
         equipment_slot_id,
@@ -97,11 +96,10 @@ INSERT INTO
         notes
     )
 VALUES
-    (?, ?, 'magical_item', 1, ?, ?, ?, ?) 
+    (?, ?, 1, ?, ?, ?, ?) 
 RETURNING id,
     character_id,
     item_id,
-    item_type,
     quantity,
     container_id,
     equipment_slot_id,
@@ -124,7 +122,6 @@ type AddMagicalItemToInventoryRow struct {
 	ID              int64          `json:"id"`
 	CharacterID     int64          `json:"character_id"`
 	ItemID          int64          `json:"item_id"`
-	ItemType        string         `json:"item_type"`
 	Quantity        int64          `json:"quantity"`
 	ContainerID     sql.NullInt64  `json:"container_id"`
 	EquipmentSlotID sql.NullInt64  `json:"equipment_slot_id"`
@@ -148,7 +145,6 @@ func (q *Queries) AddMagicalItemToInventory(ctx context.Context, arg AddMagicalI
 		&i.ID,
 		&i.CharacterID,
 		&i.ItemID,
-		&i.ItemType,
 		&i.Quantity,
 		&i.ContainerID,
 		&i.EquipmentSlotID,
@@ -160,11 +156,129 @@ func (q *Queries) AddMagicalItemToInventory(ctx context.Context, arg AddMagicalI
 	return i, err
 }
 
+const clearMissilesFired = `-- name: ClearMissilesFired :exec
+DELETE FROM missiles_fired
+WHERE
+    character_id = ?
+`
+
+func (q *Queries) ClearMissilesFired(ctx context.Context, characterID int64) error {
+	_, err := q.db.ExecContext(ctx, clearMissilesFired, characterID)
+	return err
+}
+
+const copyItemProperties = `-- name: CopyItemProperties :exec
+INSERT INTO
+    character_inventory_properties (inventory_id, name, notes)
+SELECT
+    ?, name, notes
+FROM
+    character_inventory_properties
+WHERE
+    inventory_id = ?
+`
+
+type CopyItemPropertiesParams struct {
+	InventoryID   int64 `json:"inventory_id"`
+	InventoryID_2 int64 `json:"inventory_id_2"`
+}
+
+func (q *Queries) CopyItemProperties(ctx context.Context, arg CopyItemPropertiesParams) error {
+	_, err := q.db.ExecContext(ctx, copyItemProperties, arg.InventoryID, arg.InventoryID_2)
+	return err
+}
+
+const createItemProperty = `-- name: CreateItemProperty :one
+INSERT INTO
+    character_inventory_properties (inventory_id, name, notes)
+SELECT
+    ci.id, ?, ?
+FROM
+    character_inventory ci
+WHERE
+    ci.id = ?
+    AND ci.character_id = ? RETURNING id, inventory_id, name, notes, created_at
+`
+
+type CreateItemPropertyParams struct {
+	Name        string         `json:"name"`
+	Notes       sql.NullString `json:"notes"`
+	ID          int64          `json:"id"`
+	CharacterID int64          `json:"character_id"`
+}
+
+func (q *Queries) CreateItemProperty(ctx context.Context, arg CreateItemPropertyParams) (CharacterInventoryProperty, error) {
+	row := q.db.QueryRowContext(ctx, createItemProperty,
+		arg.Name,
+		arg.Notes,
+		arg.ID,
+		arg.CharacterID,
+	)
+	var i CharacterInventoryProperty
+	err := row.Scan(
+		&i.ID,
+		&i.InventoryID,
+		&i.Name,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteItemProperty = `-- name: DeleteItemProperty :exec
+DELETE FROM character_inventory_properties
+WHERE
+    id = ?
+    AND inventory_id IN (
+        SELECT
+            id
+        FROM
+            character_inventory
+        WHERE
+            character_id = ?
+    )
+`
+
+type DeleteItemPropertyParams struct {
+	ID          int64 `json:"id"`
+	CharacterID int64 `json:"character_id"`
+}
+
+func (q *Queries) DeleteItemProperty(ctx context.Context, arg DeleteItemPropertyParams) error {
+	_, err := q.db.ExecContext(ctx, deleteItemProperty, arg.ID, arg.CharacterID)
+	return err
+}
+
+const dropPack = `-- name: DropPack :exec
+UPDATE character_inventory
+SET
+    dropped = 1,
+    dropped_location = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?
+`
+
+type DropPackParams struct {
+	DroppedLocation sql.NullString `json:"dropped_location"`
+	ID              int64          `json:"id"`
+	CharacterID     int64          `json:"character_id"`
+}
+
+func (q *Queries) DropPack(ctx context.Context, arg DropPackParams) error {
+	_, err := q.db.ExecContext(ctx, dropPack, arg.DroppedLocation, arg.ID, arg.CharacterID)
+	return err
+}
+
 const equipItem = `-- name: EquipItem :exec
 UPDATE character_inventory
 SET 
     equipment_slot_id = ?,
-    container_id = NULL
+    container_id = NULL,
+    storage_location_id = NULL,
+    dropped = 0,
+    dropped_location = NULL
 WHERE 
     id = ?
     AND character_id = ?
@@ -189,7 +303,6 @@ FROM
 WHERE 
     character_id = ?
     AND item_id = ?
-    AND item_type = ?
     AND container_id = ?
 LIMIT 1
 `
@@ -197,7 +310,6 @@ LIMIT 1
 type FindStackableItemInContainerParams struct {
 	CharacterID int64         `json:"character_id"`
 	ItemID      int64         `json:"item_id"`
-	ItemType    string        `json:"item_type"`
 	ContainerID sql.NullInt64 `json:"container_id"`
 }
 
@@ -207,12 +319,7 @@ type FindStackableItemInContainerRow struct {
 }
 
 func (q *Queries) FindStackableItemInContainer(ctx context.Context, arg FindStackableItemInContainerParams) (FindStackableItemInContainerRow, error) {
-	row := q.db.QueryRowContext(ctx, findStackableItemInContainer,
-		arg.CharacterID,
-		arg.ItemID,
-		arg.ItemType,
-		arg.ContainerID,
-	)
+	row := q.db.QueryRowContext(ctx, findStackableItemInContainer, arg.CharacterID, arg.ItemID, arg.ContainerID)
 	var i FindStackableItemInContainerRow
 	err := row.Scan(&i.ID, &i.Quantity)
 	return i, err
@@ -226,16 +333,14 @@ FROM
 WHERE 
     character_id = ?
     AND item_id = ?
-    AND item_type = ?
     AND container_id IS NULL
     AND equipment_slot_id IS NULL
 LIMIT 1
 `
 
 type FindStackableItemInInventoryParams struct {
-	CharacterID int64  `json:"character_id"`
-	ItemID      int64  `json:"item_id"`
-	ItemType    string `json:"item_type"`
+	CharacterID int64 `json:"character_id"`
+	ItemID      int64 `json:"item_id"`
 }
 
 type FindStackableItemInInventoryRow struct {
@@ -244,162 +349,31 @@ type FindStackableItemInInventoryRow struct {
 }
 
 func (q *Queries) FindStackableItemInInventory(ctx context.Context, arg FindStackableItemInInventoryParams) (FindStackableItemInInventoryRow, error) {
-	row := q.db.QueryRowContext(ctx, findStackableItemInInventory, arg.CharacterID, arg.ItemID, arg.ItemType)
+	row := q.db.QueryRowContext(ctx, findStackableItemInInventory, arg.CharacterID, arg.ItemID)
 	var i FindStackableItemInInventoryRow
 	err := row.Scan(&i.ID, &i.Quantity)
 	return i, err
 }
 
-const getAllAmmunition = `-- name: GetAllAmmunition :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    ammunition
-ORDER BY 
-    name
-`
-
-type GetAllAmmunitionRow struct {
-	ID     int64         `json:"id"`
-	Name   string        `json:"name"`
-	Weight sql.NullInt64 `json:"weight"`
-	CostGp float64       `json:"cost_gp"`
-}
-
-func (q *Queries) GetAllAmmunition(ctx context.Context) ([]GetAllAmmunitionRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllAmmunition)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetAllAmmunitionRow
-	for rows.Next() {
-		var i GetAllAmmunitionRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Weight,
-			&i.CostGp,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getAllArmor = `-- name: GetAllArmor :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    armor
-ORDER BY 
-    name
-`
-
-type GetAllArmorRow struct {
-	ID     int64  `json:"id"`
-	Name   string `json:"name"`
-	Weight int64  `json:"weight"`
-	CostGp int64  `json:"cost_gp"`
-}
-
-func (q *Queries) GetAllArmor(ctx context.Context) ([]GetAllArmorRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllArmor)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetAllArmorRow
-	for rows.Next() {
-		var i GetAllArmorRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Weight,
-			&i.CostGp,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getAllEquipment = `-- name: GetAllEquipment :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    equipment
-ORDER BY 
-    name
-`
-
-type GetAllEquipmentRow struct {
-	ID     int64         `json:"id"`
-	Name   string        `json:"name"`
-	Weight sql.NullInt64 `json:"weight"`
-	CostGp float64       `json:"cost_gp"`
-}
-
-func (q *Queries) GetAllEquipment(ctx context.Context) ([]GetAllEquipmentRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllEquipment)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetAllEquipmentRow
-	for rows.Next() {
-		var i GetAllEquipmentRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Weight,
-			&i.CostGp,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const getAllMagicalItems = `-- name: GetAllMagicalItems :many
 SELECT 
-    id, name, description, weight, cost_gp, max_charges, category, effect_description
+    i.id, i.name, i.description, i.weight, i.value as cost_gp, mi.max_charges, mi.category, mi.effect_description
 FROM 
-    magical_items
+    items i
+    JOIN magical_items mi ON mi.item_id = i.id
 ORDER BY 
-    name
+    i.name
 `
 
 type GetAllMagicalItemsRow struct {
-	ID                int64  `json:"id"`
-	Name              string `json:"name"`
-	Description       string `json:"description"`
-	Weight            int64  `json:"weight"`
-	CostGp            int64  `json:"cost_gp"`
-	MaxCharges        int64  `json:"max_charges"`
-	Category          string `json:"category"`
-	EffectDescription string `json:"effect_description"`
+	ID                int64          `json:"id"`
+	Name              string         `json:"name"`
+	Description       sql.NullString `json:"description"`
+	Weight            float64        `json:"weight"`
+	CostGp            float64        `json:"cost_gp"`
+	MaxCharges        int64          `json:"max_charges"`
+	Category          string         `json:"category"`
+	EffectDescription string         `json:"effect_description"`
 }
 
 func (q *Queries) GetAllMagicalItems(ctx context.Context) ([]GetAllMagicalItemsRow, error) {
@@ -434,242 +408,172 @@ func (q *Queries) GetAllMagicalItems(ctx context.Context) ([]GetAllMagicalItemsR
 	return items, nil
 }
 
-const getAllRangedWeapons = `-- name: GetAllRangedWeapons :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    ranged_weapons
-ORDER BY 
-    name
-`
-
-type GetAllRangedWeaponsRow struct {
-	ID     int64         `json:"id"`
-	Name   string        `json:"name"`
-	Weight int64         `json:"weight"`
-	CostGp sql.NullInt64 `json:"cost_gp"`
-}
-
-func (q *Queries) GetAllRangedWeapons(ctx context.Context) ([]GetAllRangedWeaponsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllRangedWeapons)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetAllRangedWeaponsRow
-	for rows.Next() {
-		var i GetAllRangedWeaponsRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Weight,
-			&i.CostGp,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getAllShields = `-- name: GetAllShields :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    shields
-ORDER BY 
-    name
+const getAmmunitionPerUnit = `-- name: GetAmmunitionPerUnit :one
+SELECT
+    CAST(COALESCE(a.quantity, 1) AS INTEGER) as per_unit
+FROM
+    items i
+    LEFT JOIN ammunition a ON a.item_id = i.id
+WHERE
+    i.id = ?
 `
 
-type GetAllShieldsRow struct {
-	ID     int64  `json:"id"`
-	Name   string `json:"name"`
-	Weight int64  `json:"weight"`
-	CostGp int64  `json:"cost_gp"`
+func (q *Queries) GetAmmunitionPerUnit(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getAmmunitionPerUnit, id)
+	var per_unit int64
+	err := row.Scan(&per_unit)
+	return per_unit, err
 }
 
-func (q *Queries) GetAllShields(ctx context.Context) ([]GetAllShieldsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllShields)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetAllShieldsRow
-	for rows.Next() {
-		var i GetAllShieldsRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Weight,
-			&i.CostGp,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getAllWeapons = `-- name: GetAllWeapons :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    weapons
-ORDER BY 
-    name
+const getCatalogItemRules = `-- name: GetCatalogItemRules :one
+SELECT
+    i.id,
+    i.name,
+    i.weight,
+    CAST(COALESCE((SELECT GROUP_CONCAT(t.tag) FROM item_tags t WHERE t.item_id = i.id), '') AS TEXT) as tags,
+    c.capacity_weight,
+    c.capacity_items,
+    c.weight_multiplier,
+    CAST(COALESCE((SELECT GROUP_CONCAT(cat.tag) FROM container_allowed_tags cat WHERE cat.container_id = c.id), '') AS TEXT) as allowed_tags,
+    CAST(COALESCE(am.quantity, 1) AS INTEGER) as bundle_size
+FROM
+    items i
+    LEFT JOIN ammunition am ON am.item_id = i.id
+    LEFT JOIN containers c ON c.base_item_id = i.id
+WHERE
+    i.id = ?
 `
 
-type GetAllWeaponsRow struct {
-	ID     int64  `json:"id"`
-	Name   string `json:"name"`
-	Weight int64  `json:"weight"`
-	CostGp int64  `json:"cost_gp"`
-}
-
-func (q *Queries) GetAllWeapons(ctx context.Context) ([]GetAllWeaponsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllWeapons)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetAllWeaponsRow
-	for rows.Next() {
-		var i GetAllWeaponsRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Weight,
-			&i.CostGp,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type GetCatalogItemRulesRow struct {
+	ID               int64           `json:"id"`
+	Name             string          `json:"name"`
+	Weight           float64         `json:"weight"`
+	Tags             string          `json:"tags"`
+	CapacityWeight   sql.NullFloat64 `json:"capacity_weight"`
+	CapacityItems    sql.NullInt64   `json:"capacity_items"`
+	WeightMultiplier sql.NullFloat64 `json:"weight_multiplier"`
+	AllowedTags      string          `json:"allowed_tags"`
+	BundleSize       int64           `json:"bundle_size"`
+}
+
+func (q *Queries) GetCatalogItemRules(ctx context.Context, id int64) (GetCatalogItemRulesRow, error) {
+	row := q.db.QueryRowContext(ctx, getCatalogItemRules, id)
+	var i GetCatalogItemRulesRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Weight,
+		&i.Tags,
+		&i.CapacityWeight,
+		&i.CapacityItems,
+		&i.WeightMultiplier,
+		&i.AllowedTags,
+		&i.BundleSize,
+	)
+	return i, err
 }
 
 const getCharacterInventoryItems = `-- name: GetCharacterInventoryItems :many
 SELECT 
-    ci.id, ci.character_id, ci.item_id, ci.item_type, ci.quantity,
+    ci.id, ci.character_id, ci.item_id, i.item_type, ci.quantity,
     ci.container_id, ci.equipment_slot_id, ci.notes,
     ci.created_at, ci.updated_at,
-    CASE 
-        WHEN ci.item_type = 'equipment' THEN e.name
-        WHEN ci.item_type = 'weapon' THEN w.name
-        WHEN ci.item_type = 'armor' THEN a.name
-        WHEN ci.item_type = 'ammunition' THEN am.name
-        WHEN ci.item_type = 'container' THEN e.name  -- Use equipment name as fallback
-        WHEN ci.item_type = 'shield' THEN s.name
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.name
-    END as item_name,
-    CASE 
-        WHEN ci.item_type = 'equipment' THEN e.weight
-        WHEN ci.item_type = 'weapon' THEN w.weight
-        WHEN ci.item_type = 'armor' THEN a.weight
-        WHEN ci.item_type = 'ammunition' THEN am.weight
-        WHEN ci.item_type = 'container' THEN e.weight  -- Use equipment weight as fallback
-        WHEN ci.item_type = 'shield' THEN s.weight
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.weight
-        ELSE 0
-    END as item_weight,
-    CASE 
-        WHEN ci.item_type = 'shield' THEN s.defense_bonus
-        ELSE NULL
-    END as defense_bonus,
-    CASE 
-        WHEN ci.item_type = 'weapon' THEN w.damage
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.damage
-        ELSE NULL
-    END as damage,
-    CASE 
-        WHEN ci.item_type = 'weapon' THEN w.attacks_per_round
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.rate_of_fire
-        ELSE NULL
-    END as attacks_per_round,
-    CASE 
-        WHEN ci.item_type = 'armor' THEN a.movement_rate
-        ELSE NULL
-    END as movement_rate,
-    CASE 
-        WHEN ci.item_type = 'armor' THEN a.armor_class
-        ELSE NULL
-    END as armor_class,
-    CASE
-        WHEN ci.item_type = 'weapon' THEN w.enhancement_bonus
-        WHEN ci.item_type = 'armor' THEN a.enhancement_bonus
-        WHEN ci.item_type = 'shield' THEN s.enhancement_bonus
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.enhancement_bonus
-        WHEN ci.item_type = 'ammunition' THEN am.enhancement_bonus
-        ELSE NULL
-    END as enhancement_bonus,
+    i.name as item_name,
+    i.weight as item_weight,
+    s.defense_bonus,
+    COALESCE(w.damage, rw.damage) as damage,
+    COALESCE(w.attacks_per_round, rw.rate_of_fire) as attacks_per_round,
+    a.movement_rate,
+    a.armor_class,
+    ci.enhancement_bonus,
     es.name as slot_name,
-    CASE 
-        WHEN ci.item_type = 'container' THEN c.capacity_weight
-        ELSE NULL
-    END as container_capacity,
-    CASE 
-        WHEN ci.item_type = 'container' THEN c.capacity_items
-        ELSE NULL
-    END as container_max_items
+    c.capacity_weight as container_capacity,
+    c.capacity_items as container_max_items,
+    c.weight_multiplier as container_weight_multiplier,
+    ci.charges,
+    ci.is_identified,
+    i.appearance,
+    ci.cursed,
+    ci.curse_target,
+    ci.curse_ability,
+    ci.curse_modifier,
+    ci.curse_notes,
+    ci.condition,
+    CAST(COALESCE(ic.armor_class_penalty, 0) AS INTEGER) as condition_ac_penalty,
+    CAST(COALESCE(ic.usable, 1) AS BOOLEAN) as condition_usable,
+    CAST(COALESCE(ic.value_percent, 100) AS INTEGER) as condition_value_percent,
+    i.value as item_value,
+    ci.appraised_value,
+    ti.kind as treasure_kind,
+    CAST(COALESCE(am.quantity, 1) AS INTEGER) as bundle_size,
+    ci.missiles_used,
+    ci.storage_location_id,
+    ci.dropped,
+    ci.dropped_location
 FROM 
     character_inventory ci
+    JOIN items i ON ci.item_id = i.id
+    LEFT JOIN ammunition am ON am.item_id = i.id
+    LEFT JOIN item_conditions ic ON ic.name = ci.condition
+    LEFT JOIN treasure_items ti ON ti.item_id = i.id
     LEFT JOIN equipment_slots es ON ci.equipment_slot_id = es.id
-    LEFT JOIN equipment e ON ci.item_type = 'equipment' AND ci.item_id = e.id
-    LEFT JOIN weapons w ON ci.item_type = 'weapon' AND ci.item_id = w.id
-    LEFT JOIN armor a ON ci.item_type = 'armor' AND ci.item_id = a.id
-    LEFT JOIN ammunition am ON ci.item_type = 'ammunition' AND ci.item_id = am.id
-    LEFT JOIN containers c ON ci.item_type = 'container' AND ci.item_id = c.id
-    LEFT JOIN shields s ON ci.item_type = 'shield' AND ci.item_id = s.id
-    LEFT JOIN ranged_weapons rw ON ci.item_type = 'ranged_weapon' AND ci.item_id = rw.id
+    LEFT JOIN weapons w ON w.item_id = i.id
+    LEFT JOIN ranged_weapons rw ON rw.item_id = i.id
+    LEFT JOIN armor a ON a.item_id = i.id
+    LEFT JOIN shields s ON s.item_id = i.id
+    LEFT JOIN containers c ON c.base_item_id = i.id
 WHERE 
     ci.character_id = ?
 ORDER BY 
     ci.equipment_slot_id IS NULL, 
     es.name,
     ci.container_id IS NOT NULL,
-    item_name
+    i.name
 `
 
 type GetCharacterInventoryItemsRow struct {
-	ID                int64          `json:"id"`
-	CharacterID       int64          `json:"character_id"`
-	ItemID            int64          `json:"item_id"`
-	ItemType          string         `json:"item_type"`
-	Quantity          int64          `json:"quantity"`
-	ContainerID       sql.NullInt64  `json:"container_id"`
-	EquipmentSlotID   sql.NullInt64  `json:"equipment_slot_id"`
-	Notes             sql.NullString `json:"notes"`
-	CreatedAt         time.Time      `json:"created_at"`
-	UpdatedAt         time.Time      `json:"updated_at"`
-	ItemName          interface{}    `json:"item_name"`
-	ItemWeight        int64          `json:"item_weight"`
-	DefenseBonus      interface{}    `json:"defense_bonus"`
-	Damage            interface{}    `json:"damage"`
-	AttacksPerRound   interface{}    `json:"attacks_per_round"`
-	MovementRate      interface{}    `json:"movement_rate"`
-	ArmorClass        interface{}    `json:"armor_class"`
-	EnhancementBonus  interface{}    `json:"enhancement_bonus"`
-	SlotName          sql.NullString `json:"slot_name"`
-	ContainerCapacity interface{}    `json:"container_capacity"`
-	ContainerMaxItems interface{}    `json:"container_max_items"`
+	ID                        int64           `json:"id"`
+	CharacterID               int64           `json:"character_id"`
+	ItemID                    int64           `json:"item_id"`
+	ItemType                  string          `json:"item_type"`
+	Quantity                  int64           `json:"quantity"`
+	ContainerID               sql.NullInt64   `json:"container_id"`
+	EquipmentSlotID           sql.NullInt64   `json:"equipment_slot_id"`
+	Notes                     sql.NullString  `json:"notes"`
+	CreatedAt                 time.Time       `json:"created_at"`
+	UpdatedAt                 time.Time       `json:"updated_at"`
+	ItemName                  string          `json:"item_name"`
+	ItemWeight                float64         `json:"item_weight"`
+	DefenseBonus              sql.NullInt64   `json:"defense_bonus"`
+	Damage                    sql.NullString  `json:"damage"`
+	AttacksPerRound           sql.NullString  `json:"attacks_per_round"`
+	MovementRate              sql.NullInt64   `json:"movement_rate"`
+	ArmorClass                sql.NullInt64   `json:"armor_class"`
+	EnhancementBonus          int64           `json:"enhancement_bonus"`
+	SlotName                  sql.NullString  `json:"slot_name"`
+	ContainerCapacity         sql.NullFloat64 `json:"container_capacity"`
+	ContainerMaxItems         sql.NullInt64   `json:"container_max_items"`
+	ContainerWeightMultiplier sql.NullFloat64 `json:"container_weight_multiplier"`
+	Charges                   sql.NullInt64   `json:"charges"`
+	IsIdentified              bool            `json:"is_identified"`
+	Appearance                sql.NullString  `json:"appearance"`
+	Cursed                    bool            `json:"cursed"`
+	CurseTarget               sql.NullString  `json:"curse_target"`
+	CurseAbility              sql.NullString  `json:"curse_ability"`
+	CurseModifier             int64           `json:"curse_modifier"`
+	CurseNotes                sql.NullString  `json:"curse_notes"`
+	Condition                 string          `json:"condition"`
+	ConditionAcPenalty        int64           `json:"condition_ac_penalty"`
+	ConditionUsable           bool            `json:"condition_usable"`
+	ConditionValuePercent     int64           `json:"condition_value_percent"`
+	ItemValue                 float64         `json:"item_value"`
+	AppraisedValue            sql.NullFloat64 `json:"appraised_value"`
+	TreasureKind              sql.NullString  `json:"treasure_kind"`
+	BundleSize                int64           `json:"bundle_size"`
+	MissilesUsed              int64           `json:"missiles_used"`
+	StorageLocationID         sql.NullInt64   `json:"storage_location_id"`
+	Dropped                   bool            `json:"dropped"`
+	DroppedLocation           sql.NullString  `json:"dropped_location"`
 }
 
 func (q *Queries) GetCharacterInventoryItems(ctx context.Context, characterID int64) ([]GetCharacterInventoryItemsRow, error) {
@@ -703,6 +607,27 @@ func (q *Queries) GetCharacterInventoryItems(ctx context.Context, characterID in
 			&i.SlotName,
 			&i.ContainerCapacity,
 			&i.ContainerMaxItems,
+			&i.ContainerWeightMultiplier,
+			&i.Charges,
+			&i.IsIdentified,
+			&i.Appearance,
+			&i.Cursed,
+			&i.CurseTarget,
+			&i.CurseAbility,
+			&i.CurseModifier,
+			&i.CurseNotes,
+			&i.Condition,
+			&i.ConditionAcPenalty,
+			&i.ConditionUsable,
+			&i.ConditionValuePercent,
+			&i.ItemValue,
+			&i.AppraisedValue,
+			&i.TreasureKind,
+			&i.BundleSize,
+			&i.MissilesUsed,
+			&i.StorageLocationID,
+			&i.Dropped,
+			&i.DroppedLocation,
 		); err != nil {
 			return nil, err
 		}
@@ -720,15 +645,14 @@ func (q *Queries) GetCharacterInventoryItems(ctx context.Context, characterID in
 const getChargedItem = `-- name: GetChargedItem :one
 SELECT 
     ci.id, ci.character_id, ci.charges, 
-    mi.name, mi.description, mi.effect_description, mi.category
+    i.name, i.description, mi.effect_description, mi.category
 FROM 
     character_inventory ci
-JOIN 
-    magical_items mi ON ci.item_id = mi.id
+    JOIN items i ON ci.item_id = i.id
+    JOIN magical_items mi ON mi.item_id = i.id
 WHERE 
     ci.id = ?
     AND ci.character_id = ?
-    AND ci.item_type = 'magical_item'
 `
 
 type GetChargedItemParams struct {
@@ -737,13 +661,13 @@ type GetChargedItemParams struct {
 }
 
 type GetChargedItemRow struct {
-	ID                int64         `json:"id"`
-	CharacterID       int64         `json:"character_id"`
-	Charges           sql.NullInt64 `json:"charges"`
-	Name              string        `json:"name"`
-	Description       string        `json:"description"`
-	EffectDescription string        `json:"effect_description"`
-	Category          string        `json:"category"`
+	ID                int64          `json:"id"`
+	CharacterID       int64          `json:"character_id"`
+	Charges           sql.NullInt64  `json:"charges"`
+	Name              string         `json:"name"`
+	Description       sql.NullString `json:"description"`
+	EffectDescription string         `json:"effect_description"`
+	Category          string         `json:"category"`
 }
 
 func (q *Queries) GetChargedItem(ctx context.Context, arg GetChargedItemParams) (GetChargedItemRow, error) {
@@ -771,7 +695,6 @@ JOIN
     containers c ON ci.item_id = c.base_item_id
 WHERE 
     ci.id = ?
-    AND ci.item_type = 'container'
 `
 
 type GetContainerCapacityRow struct {
@@ -788,42 +711,19 @@ func (q *Queries) GetContainerCapacity(ctx context.Context, id int64) (GetContai
 
 const getContainerContents = `-- name: GetContainerContents :many
 SELECT 
-    ci.id, ci.character_id, ci.item_id, ci.item_type, ci.quantity,
+    ci.id, ci.character_id, ci.item_id, i.item_type, ci.quantity,
     ci.container_id, ci.equipment_slot_id, ci.notes,
     ci.created_at, ci.updated_at,
-    CASE 
-        WHEN ci.item_type = 'equipment' THEN e.name
-        WHEN ci.item_type = 'weapon' THEN w.name
-        WHEN ci.item_type = 'armor' THEN a.name
-        WHEN ci.item_type = 'ammunition' THEN am.name
-        WHEN ci.item_type = 'container' THEN e.name  -- Use equipment name as fallback
-        WHEN ci.item_type = 'shield' THEN s.name
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.name
-    END as item_name,
-    CASE 
-        WHEN ci.item_type = 'equipment' THEN e.weight
-        WHEN ci.item_type = 'weapon' THEN w.weight
-        WHEN ci.item_type = 'armor' THEN a.weight
-        WHEN ci.item_type = 'ammunition' THEN am.weight
-        WHEN ci.item_type = 'container' THEN e.weight  -- Use equipment weight as fallback
-        WHEN ci.item_type = 'shield' THEN s.weight
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.weight
-        ELSE 0
-    END as item_weight
+    i.name as item_name,
+    i.weight as item_weight
 FROM 
     character_inventory ci
-    LEFT JOIN equipment e ON ci.item_type = 'equipment' AND ci.item_id = e.id
-    LEFT JOIN weapons w ON ci.item_type = 'weapon' AND ci.item_id = w.id
-    LEFT JOIN armor a ON ci.item_type = 'armor' AND ci.item_id = a.id
-    LEFT JOIN ammunition am ON ci.item_type = 'ammunition' AND ci.item_id = am.id
-    LEFT JOIN containers c ON ci.item_type = 'container' AND ci.item_id = c.id
-    LEFT JOIN shields s ON ci.item_type = 'shield' AND ci.item_id = s.id
-    LEFT JOIN ranged_weapons rw ON ci.item_type = 'ranged_weapon' AND ci.item_id = rw.id
+    JOIN items i ON ci.item_id = i.id
 WHERE 
     ci.container_id = ?
     AND ci.character_id = ?
 ORDER BY 
-    item_name
+    i.name
 `
 
 type GetContainerContentsParams struct {
@@ -842,8 +742,8 @@ type GetContainerContentsRow struct {
 	Notes           sql.NullString `json:"notes"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
-	ItemName        interface{}    `json:"item_name"`
-	ItemWeight      int64          `json:"item_weight"`
+	ItemName        string         `json:"item_name"`
+	ItemWeight      float64        `json:"item_weight"`
 }
 
 func (q *Queries) GetContainerContents(ctx context.Context, arg GetContainerContentsParams) ([]GetContainerContentsRow, error) {
@@ -884,65 +784,307 @@ func (q *Queries) GetContainerContents(ctx context.Context, arg GetContainerCont
 
 const getContainerWeight = `-- name: GetContainerWeight :one
 SELECT 
-    COALESCE(SUM(
-        CASE 
-            WHEN ci.item_type = 'equipment' THEN e.weight * ci.quantity
-            WHEN ci.item_type = 'weapon' THEN w.weight * ci.quantity
-            WHEN ci.item_type = 'armor' THEN a.weight * ci.quantity
-            WHEN ci.item_type = 'ammunition' THEN COALESCE(am.weight, 0) * ci.quantity
-            WHEN ci.item_type = 'container' THEN COALESCE(e.weight, 0) * ci.quantity  -- Use equipment weight as fallback
-            WHEN ci.item_type = 'shield' THEN s.weight * ci.quantity
-            WHEN ci.item_type = 'ranged_weapon' THEN rw.weight * ci.quantity
-            ELSE 0
-        END
-    ), 0) as total_weight
+    CAST(COALESCE(SUM(i.weight * ci.quantity), 0) AS REAL) as total_weight
 FROM 
     character_inventory ci
-    LEFT JOIN equipment e ON ci.item_type = 'equipment' AND ci.item_id = e.id
-    LEFT JOIN weapons w ON ci.item_type = 'weapon' AND ci.item_id = w.id
-    LEFT JOIN armor a ON ci.item_type = 'armor' AND ci.item_id = a.id
-    LEFT JOIN ammunition am ON ci.item_type = 'ammunition' AND ci.item_id = am.id
-    LEFT JOIN containers c ON ci.item_type = 'container' AND ci.item_id = c.id
-    LEFT JOIN shields s ON ci.item_type = 'shield' AND ci.item_id = s.id
-    LEFT JOIN ranged_weapons rw ON ci.item_type = 'ranged_weapon' AND ci.item_id = rw.id
+    JOIN items i ON ci.item_id = i.id
 WHERE 
     ci.container_id = ?
 `
 
-func (q *Queries) GetContainerWeight(ctx context.Context, containerID sql.NullInt64) (interface{}, error) {
+func (q *Queries) GetContainerWeight(ctx context.Context, containerID sql.NullInt64) (float64, error) {
 	row := q.db.QueryRowContext(ctx, getContainerWeight, containerID)
-	var total_weight interface{}
+	var total_weight float64
 	err := row.Scan(&total_weight)
 	return total_weight, err
 }
 
-const getEnhancedArmor = `-- name: GetEnhancedArmor :many
+const getEquipmentSlots = `-- name: GetEquipmentSlots :many
+SELECT 
+    id, name, description, slot_group
+FROM 
+    equipment_slots
+ORDER BY 
+    name
+`
+
+func (q *Queries) GetEquipmentSlots(ctx context.Context) ([]EquipmentSlot, error) {
+	rows, err := q.db.QueryContext(ctx, getEquipmentSlots)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EquipmentSlot
+	for rows.Next() {
+		var i EquipmentSlot
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.SlotGroup,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEquippedItems = `-- name: GetEquippedItems :many
+SELECT
+    ci.id, ci.character_id, ci.item_id, i.item_type, ci.quantity,
+    ci.container_id, ci.equipment_slot_id, ci.notes, ci.created_at, ci.updated_at,
+    es.name as slot_name,
+    i.name as item_name,
+    i.weight as item_weight
+FROM
+    character_inventory ci
+    JOIN items i ON ci.item_id = i.id
+    JOIN equipment_slots es ON ci.equipment_slot_id = es.id
+WHERE
+    ci.character_id = ?
+    AND ci.equipment_slot_id IS NOT NULL
+ORDER BY
+    es.name
+`
+
+type GetEquippedItemsRow struct {
+	ID              int64          `json:"id"`
+	CharacterID     int64          `json:"character_id"`
+	ItemID          int64          `json:"item_id"`
+	ItemType        string         `json:"item_type"`
+	Quantity        int64          `json:"quantity"`
+	ContainerID     sql.NullInt64  `json:"container_id"`
+	EquipmentSlotID sql.NullInt64  `json:"equipment_slot_id"`
+	Notes           sql.NullString `json:"notes"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	SlotName        string         `json:"slot_name"`
+	ItemName        string         `json:"item_name"`
+	ItemWeight      float64        `json:"item_weight"`
+}
+
+func (q *Queries) GetEquippedItems(ctx context.Context, characterID int64) ([]GetEquippedItemsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getEquippedItems, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetEquippedItemsRow
+	for rows.Next() {
+		var i GetEquippedItemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.ItemID,
+			&i.ItemType,
+			&i.Quantity,
+			&i.ContainerID,
+			&i.EquipmentSlotID,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SlotName,
+			&i.ItemName,
+			&i.ItemWeight,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInventoryItemIdentity = `-- name: GetInventoryItemIdentity :one
+SELECT
+    ci.id,
+    ci.is_identified,
+    ci.identified_by,
+    ci.enhancement_bonus,
+    i.name,
+    i.appearance,
+    i.item_type,
+    CAST((SELECT COUNT(*) FROM character_inventory_properties p WHERE p.inventory_id = ci.id) AS INTEGER) as property_count,
+    ci.cursed,
+    ci.equipment_slot_id
+FROM
+    character_inventory ci
+    JOIN items i ON ci.item_id = i.id
+WHERE
+    ci.id = ?
+    AND ci.character_id = ?
+`
+
+type GetInventoryItemIdentityParams struct {
+	ID          int64 `json:"id"`
+	CharacterID int64 `json:"character_id"`
+}
+
+type GetInventoryItemIdentityRow struct {
+	ID               int64          `json:"id"`
+	IsIdentified     bool           `json:"is_identified"`
+	IdentifiedBy     sql.NullString `json:"identified_by"`
+	EnhancementBonus int64          `json:"enhancement_bonus"`
+	Name             string         `json:"name"`
+	Appearance       sql.NullString `json:"appearance"`
+	ItemType         string         `json:"item_type"`
+	PropertyCount    int64          `json:"property_count"`
+	Cursed           bool           `json:"cursed"`
+	EquipmentSlotID  sql.NullInt64  `json:"equipment_slot_id"`
+}
+
+func (q *Queries) GetInventoryItemIdentity(ctx context.Context, arg GetInventoryItemIdentityParams) (GetInventoryItemIdentityRow, error) {
+	row := q.db.QueryRowContext(ctx, getInventoryItemIdentity, arg.ID, arg.CharacterID)
+	var i GetInventoryItemIdentityRow
+	err := row.Scan(
+		&i.ID,
+		&i.IsIdentified,
+		&i.IdentifiedBy,
+		&i.EnhancementBonus,
+		&i.Name,
+		&i.Appearance,
+		&i.ItemType,
+		&i.PropertyCount,
+		&i.Cursed,
+		&i.EquipmentSlotID,
+	)
+	return i, err
+}
+
+const getItemSlotRules = `-- name: GetItemSlotRules :one
+SELECT
+    i.id,
+    i.name,
+    i.two_handed,
+    CAST(COALESCE(
+        (SELECT GROUP_CONCAT(r.slot_group) FROM equipment_slot_rules r WHERE r.item_id = i.id),
+        (SELECT mc.slot_group FROM magical_items m JOIN magical_item_categories mc ON mc.name = m.category WHERE m.item_id = i.id),
+        (SELECT GROUP_CONCAT(r.slot_group) FROM equipment_slot_rules r WHERE r.item_type = i.item_type),
+        ''
+    ) AS TEXT) as slot_groups
+FROM
+    items i
+WHERE
+    i.id = ?
+`
+
+type GetItemSlotRulesRow struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	TwoHanded  bool   `json:"two_handed"`
+	SlotGroups string `json:"slot_groups"`
+}
+
+func (q *Queries) GetItemSlotRules(ctx context.Context, id int64) (GetItemSlotRulesRow, error) {
+	row := q.db.QueryRowContext(ctx, getItemSlotRules, id)
+	var i GetItemSlotRulesRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.TwoHanded,
+		&i.SlotGroups,
+	)
+	return i, err
+}
+
+const getMagicalItemByID = `-- name: GetMagicalItemByID :one
+SELECT 
+    i.id, i.name, i.description, i.weight, i.value as cost_gp, mi.max_charges, mi.category, mi.effect_description
+FROM 
+    items i
+    JOIN magical_items mi ON mi.item_id = i.id
+WHERE 
+    i.id = ?
+`
+
+type GetMagicalItemByIDRow struct {
+	ID                int64          `json:"id"`
+	Name              string         `json:"name"`
+	Description       sql.NullString `json:"description"`
+	Weight            float64        `json:"weight"`
+	CostGp            float64        `json:"cost_gp"`
+	MaxCharges        int64          `json:"max_charges"`
+	Category          string         `json:"category"`
+	EffectDescription string         `json:"effect_description"`
+}
+
+func (q *Queries) GetMagicalItemByID(ctx context.Context, id int64) (GetMagicalItemByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getMagicalItemByID, id)
+	var i GetMagicalItemByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Weight,
+		&i.CostGp,
+		&i.MaxCharges,
+		&i.Category,
+		&i.EffectDescription,
+	)
+	return i, err
+}
+
+const isSlotOccupied = `-- name: IsSlotOccupied :one
+SELECT 
+    COUNT(*) > 0 as is_occupied
+FROM 
+    character_inventory
+WHERE 
+    character_id = ? 
+    AND equipment_slot_id = ?
+`
+
+type IsSlotOccupiedParams struct {
+	CharacterID     int64         `json:"character_id"`
+	EquipmentSlotID sql.NullInt64 `json:"equipment_slot_id"`
+}
+
+func (q *Queries) IsSlotOccupied(ctx context.Context, arg IsSlotOccupiedParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isSlotOccupied, arg.CharacterID, arg.EquipmentSlotID)
+	var is_occupied bool
+	err := row.Scan(&is_occupied)
+	return is_occupied, err
+}
+
+const listCatalogItems = `-- name: ListCatalogItems :many
 SELECT 
-    id, name, weight, cost_gp
+    id, name, weight, value as cost_gp
 FROM 
-    armor
+    items
 WHERE
-    enhancement_bonus = ?
+    item_type = ?
 ORDER BY 
     name
 `
 
-type GetEnhancedArmorRow struct {
-	ID     int64  `json:"id"`
-	Name   string `json:"name"`
-	Weight int64  `json:"weight"`
-	CostGp int64  `json:"cost_gp"`
+type ListCatalogItemsRow struct {
+	ID     int64   `json:"id"`
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+	CostGp float64 `json:"cost_gp"`
 }
 
-func (q *Queries) GetEnhancedArmor(ctx context.Context, enhancementBonus sql.NullInt64) ([]GetEnhancedArmorRow, error) {
-	rows, err := q.db.QueryContext(ctx, getEnhancedArmor, enhancementBonus)
+func (q *Queries) ListCatalogItems(ctx context.Context, itemType string) ([]ListCatalogItemsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCatalogItems, itemType)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetEnhancedArmorRow
+	var items []ListCatalogItemsRow
 	for rows.Next() {
-		var i GetEnhancedArmorRow
+		var i ListCatalogItemsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
@@ -962,38 +1104,34 @@ func (q *Queries) GetEnhancedArmor(ctx context.Context, enhancementBonus sql.Nul
 	return items, nil
 }
 
-const getEnhancedRangedWeapons = `-- name: GetEnhancedRangedWeapons :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    ranged_weapons
+const listCharacterItemProperties = `-- name: ListCharacterItemProperties :many
+SELECT
+    p.id, p.inventory_id, p.name, p.notes, p.created_at
+FROM
+    character_inventory_properties p
+    JOIN character_inventory ci ON p.inventory_id = ci.id
 WHERE
-    enhancement_bonus = ?
-ORDER BY 
-    name
+    ci.character_id = ?
+ORDER BY
+    p.inventory_id,
+    p.id
 `
 
-type GetEnhancedRangedWeaponsRow struct {
-	ID     int64         `json:"id"`
-	Name   string        `json:"name"`
-	Weight int64         `json:"weight"`
-	CostGp sql.NullInt64 `json:"cost_gp"`
-}
-
-func (q *Queries) GetEnhancedRangedWeapons(ctx context.Context, enhancementBonus sql.NullInt64) ([]GetEnhancedRangedWeaponsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getEnhancedRangedWeapons, enhancementBonus)
+func (q *Queries) ListCharacterItemProperties(ctx context.Context, characterID int64) ([]CharacterInventoryProperty, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterItemProperties, characterID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetEnhancedRangedWeaponsRow
+	var items []CharacterInventoryProperty
 	for rows.Next() {
-		var i GetEnhancedRangedWeaponsRow
+		var i CharacterInventoryProperty
 		if err := rows.Scan(
 			&i.ID,
+			&i.InventoryID,
 			&i.Name,
-			&i.Weight,
-			&i.CostGp,
+			&i.Notes,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1008,38 +1146,72 @@ func (q *Queries) GetEnhancedRangedWeapons(ctx context.Context, enhancementBonus
 	return items, nil
 }
 
-const getEnhancedShields = `-- name: GetEnhancedShields :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    shields
+const listContainerRuleEntries = `-- name: ListContainerRuleEntries :many
+SELECT
+    ci.id,
+    ci.container_id,
+    ci.quantity,
+    i.name as item_name,
+    i.weight as item_weight,
+    CAST(COALESCE((SELECT GROUP_CONCAT(t.tag) FROM item_tags t WHERE t.item_id = ci.item_id), '') AS TEXT) as tags,
+    c.capacity_weight,
+    c.capacity_items,
+    c.weight_multiplier,
+    CAST(COALESCE((SELECT GROUP_CONCAT(cat.tag) FROM container_allowed_tags cat WHERE cat.container_id = c.id), '') AS TEXT) as allowed_tags,
+    CAST(COALESCE(am.quantity, 1) AS INTEGER) as bundle_size,
+    ci.missiles_used,
+    ci.is_identified,
+    i.appearance
+FROM
+    character_inventory ci
+    JOIN items i ON ci.item_id = i.id
+    LEFT JOIN ammunition am ON am.item_id = i.id
+    LEFT JOIN containers c ON c.base_item_id = i.id
 WHERE
-    enhancement_bonus = ?
-ORDER BY 
-    name
+    ci.character_id = ?
 `
 
-type GetEnhancedShieldsRow struct {
-	ID     int64  `json:"id"`
-	Name   string `json:"name"`
-	Weight int64  `json:"weight"`
-	CostGp int64  `json:"cost_gp"`
-}
-
-func (q *Queries) GetEnhancedShields(ctx context.Context, enhancementBonus sql.NullInt64) ([]GetEnhancedShieldsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getEnhancedShields, enhancementBonus)
+type ListContainerRuleEntriesRow struct {
+	ID               int64           `json:"id"`
+	ContainerID      sql.NullInt64   `json:"container_id"`
+	Quantity         int64           `json:"quantity"`
+	ItemName         string          `json:"item_name"`
+	ItemWeight       float64         `json:"item_weight"`
+	Tags             string          `json:"tags"`
+	CapacityWeight   sql.NullFloat64 `json:"capacity_weight"`
+	CapacityItems    sql.NullInt64   `json:"capacity_items"`
+	WeightMultiplier sql.NullFloat64 `json:"weight_multiplier"`
+	AllowedTags      string          `json:"allowed_tags"`
+	BundleSize       int64           `json:"bundle_size"`
+	MissilesUsed     int64           `json:"missiles_used"`
+	IsIdentified     bool            `json:"is_identified"`
+	Appearance       sql.NullString  `json:"appearance"`
+}
+
+func (q *Queries) ListContainerRuleEntries(ctx context.Context, characterID int64) ([]ListContainerRuleEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listContainerRuleEntries, characterID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetEnhancedShieldsRow
+	var items []ListContainerRuleEntriesRow
 	for rows.Next() {
-		var i GetEnhancedShieldsRow
+		var i ListContainerRuleEntriesRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.Name,
-			&i.Weight,
-			&i.CostGp,
+			&i.ContainerID,
+			&i.Quantity,
+			&i.ItemName,
+			&i.ItemWeight,
+			&i.Tags,
+			&i.CapacityWeight,
+			&i.CapacityItems,
+			&i.WeightMultiplier,
+			&i.AllowedTags,
+			&i.BundleSize,
+			&i.MissilesUsed,
+			&i.IsIdentified,
+			&i.Appearance,
 		); err != nil {
 			return nil, err
 		}
@@ -1054,38 +1226,57 @@ func (q *Queries) GetEnhancedShields(ctx context.Context, enhancementBonus sql.N
 	return items, nil
 }
 
-const getEnhancedWeapons = `-- name: GetEnhancedWeapons :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    weapons
+const listEquipRuleEntries = `-- name: ListEquipRuleEntries :many
+SELECT
+    ci.id,
+    ci.equipment_slot_id,
+    i.name as item_name,
+    i.two_handed,
+    CAST(COALESCE(
+        (SELECT GROUP_CONCAT(r.slot_group) FROM equipment_slot_rules r WHERE r.item_id = i.id),
+        (SELECT mc.slot_group FROM magical_items m JOIN magical_item_categories mc ON mc.name = m.category WHERE m.item_id = i.id),
+        (SELECT GROUP_CONCAT(r.slot_group) FROM equipment_slot_rules r WHERE r.item_type = i.item_type),
+        ''
+    ) AS TEXT) as slot_groups,
+    ci.cursed,
+    ci.is_identified,
+    i.appearance
+FROM
+    character_inventory ci
+    JOIN items i ON ci.item_id = i.id
 WHERE
-    enhancement_bonus = ?
-ORDER BY 
-    name
+    ci.character_id = ?
 `
 
-type GetEnhancedWeaponsRow struct {
-	ID     int64  `json:"id"`
-	Name   string `json:"name"`
-	Weight int64  `json:"weight"`
-	CostGp int64  `json:"cost_gp"`
+type ListEquipRuleEntriesRow struct {
+	ID              int64          `json:"id"`
+	EquipmentSlotID sql.NullInt64  `json:"equipment_slot_id"`
+	ItemName        string         `json:"item_name"`
+	TwoHanded       bool           `json:"two_handed"`
+	SlotGroups      string         `json:"slot_groups"`
+	Cursed          bool           `json:"cursed"`
+	IsIdentified    bool           `json:"is_identified"`
+	Appearance      sql.NullString `json:"appearance"`
 }
 
-func (q *Queries) GetEnhancedWeapons(ctx context.Context, enhancementBonus sql.NullInt64) ([]GetEnhancedWeaponsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getEnhancedWeapons, enhancementBonus)
+func (q *Queries) ListEquipRuleEntries(ctx context.Context, characterID int64) ([]ListEquipRuleEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listEquipRuleEntries, characterID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetEnhancedWeaponsRow
+	var items []ListEquipRuleEntriesRow
 	for rows.Next() {
-		var i GetEnhancedWeaponsRow
+		var i ListEquipRuleEntriesRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.Name,
-			&i.Weight,
-			&i.CostGp,
+			&i.EquipmentSlotID,
+			&i.ItemName,
+			&i.TwoHanded,
+			&i.SlotGroups,
+			&i.Cursed,
+			&i.IsIdentified,
+			&i.Appearance,
 		); err != nil {
 			return nil, err
 		}
@@ -1100,25 +1291,35 @@ func (q *Queries) GetEnhancedWeapons(ctx context.Context, enhancementBonus sql.N
 	return items, nil
 }
 
-const getEquipmentSlots = `-- name: GetEquipmentSlots :many
-SELECT 
-    id, name, description
-FROM 
-    equipment_slots
-ORDER BY 
-    name
+const listMissilesFired = `-- name: ListMissilesFired :many
+SELECT
+    id, character_id, inventory_id, item_id, enhancement_bonus, count, created_at
+FROM
+    missiles_fired
+WHERE
+    character_id = ?
+ORDER BY
+    id
 `
 
-func (q *Queries) GetEquipmentSlots(ctx context.Context) ([]EquipmentSlot, error) {
-	rows, err := q.db.QueryContext(ctx, getEquipmentSlots)
+func (q *Queries) ListMissilesFired(ctx context.Context, characterID int64) ([]MissilesFired, error) {
+	rows, err := q.db.QueryContext(ctx, listMissilesFired, characterID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []EquipmentSlot
+	var items []MissilesFired
 	for rows.Next() {
-		var i EquipmentSlot
-		if err := rows.Scan(&i.ID, &i.Name, &i.Description); err != nil {
+		var i MissilesFired
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.InventoryID,
+			&i.ItemID,
+			&i.EnhancementBonus,
+			&i.Count,
+			&i.CreatedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -1132,86 +1333,76 @@ func (q *Queries) GetEquipmentSlots(ctx context.Context) ([]EquipmentSlot, error
 	return items, nil
 }
 
-const getEquippedItems = `-- name: GetEquippedItems :many
+const listRangedEntries = `-- name: ListRangedEntries :many
 SELECT
-    ci.id, ci.character_id, ci.item_id, ci.item_type, ci.quantity,
-    ci.container_id, ci.equipment_slot_id, ci.notes, ci.created_at, ci.updated_at,
-    es.name as slot_name,
-    CASE 
-        WHEN ci.item_type = 'equipment' THEN e.name
-        WHEN ci.item_type = 'weapon' THEN w.name
-        WHEN ci.item_type = 'armor' THEN a.name
-        WHEN ci.item_type = 'ammunition' THEN am.name
-        WHEN ci.item_type = 'container' THEN c.name
-        WHEN ci.item_type = 'shield' THEN s.name
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.name
-    END as item_name,
-    CASE 
-        WHEN ci.item_type = 'equipment' THEN e.weight
-        WHEN ci.item_type = 'weapon' THEN w.weight
-        WHEN ci.item_type = 'armor' THEN a.weight
-        WHEN ci.item_type = 'ammunition' THEN am.weight
-        WHEN ci.item_type = 'container' THEN c.weight
-        WHEN ci.item_type = 'shield' THEN s.weight
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.weight
-        ELSE 0
-    END as item_weight
+    ci.id,
+    ci.item_id,
+    i.name as item_name,
+    i.item_type,
+    ci.quantity,
+    ci.missiles_used,
+    ci.enhancement_bonus,
+    ci.container_id,
+    ci.equipment_slot_id,
+    CAST(COALESCE(a.quantity, 1) AS INTEGER) as per_unit,
+    rw.ammunition_tag,
+    CAST(COALESCE((SELECT GROUP_CONCAT(t.tag) FROM item_tags t WHERE t.item_id = ci.item_id), '') AS TEXT) as tags,
+    CAST(COALESCE((SELECT GROUP_CONCAT(cat.tag) FROM containers c JOIN container_allowed_tags cat ON cat.container_id = c.id WHERE c.base_item_id = ci.item_id), '') AS TEXT) as allowed_tags,
+    CAST(COALESCE(ic.usable, 1) AS BOOLEAN) as usable
 FROM
     character_inventory ci
-    JOIN equipment_slots es ON ci.equipment_slot_id = es.id
-    LEFT JOIN equipment e ON ci.item_type = 'equipment' AND ci.item_id = e.id
-    LEFT JOIN weapons w ON ci.item_type = 'weapon' AND ci.item_id = w.id
-    LEFT JOIN armor a ON ci.item_type = 'armor' AND ci.item_id = a.id
-    LEFT JOIN ammunition am ON ci.item_type = 'ammunition' AND ci.item_id = am.id
-    LEFT JOIN containers c ON ci.item_type = 'container' AND ci.item_id = c.id
-    LEFT JOIN shields s ON ci.item_type = 'shield' AND ci.item_id = s.id
-    LEFT JOIN ranged_weapons rw ON ci.item_type = 'ranged_weapon' AND ci.item_id = rw.id
+    JOIN items i ON ci.item_id = i.id
+    LEFT JOIN item_conditions ic ON ic.name = ci.condition
+    LEFT JOIN ammunition a ON a.item_id = i.id
+    LEFT JOIN ranged_weapons rw ON rw.item_id = i.id
 WHERE
     ci.character_id = ?
-    AND ci.equipment_slot_id IS NOT NULL
+    AND i.item_type IN ('ammunition', 'ranged_weapon', 'container')
 ORDER BY
-    es.name
+    ci.id
 `
 
-type GetEquippedItemsRow struct {
-	ID              int64          `json:"id"`
-	CharacterID     int64          `json:"character_id"`
-	ItemID          int64          `json:"item_id"`
-	ItemType        string         `json:"item_type"`
-	Quantity        int64          `json:"quantity"`
-	ContainerID     sql.NullInt64  `json:"container_id"`
-	EquipmentSlotID sql.NullInt64  `json:"equipment_slot_id"`
-	Notes           sql.NullString `json:"notes"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	SlotName        string         `json:"slot_name"`
-	ItemName        interface{}    `json:"item_name"`
-	ItemWeight      int64          `json:"item_weight"`
-}
-
-func (q *Queries) GetEquippedItems(ctx context.Context, characterID int64) ([]GetEquippedItemsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getEquippedItems, characterID)
+type ListRangedEntriesRow struct {
+	ID               int64          `json:"id"`
+	ItemID           int64          `json:"item_id"`
+	ItemName         string         `json:"item_name"`
+	ItemType         string         `json:"item_type"`
+	Quantity         int64          `json:"quantity"`
+	MissilesUsed     int64          `json:"missiles_used"`
+	EnhancementBonus int64          `json:"enhancement_bonus"`
+	ContainerID      sql.NullInt64  `json:"container_id"`
+	EquipmentSlotID  sql.NullInt64  `json:"equipment_slot_id"`
+	PerUnit          int64          `json:"per_unit"`
+	AmmunitionTag    sql.NullString `json:"ammunition_tag"`
+	Tags             string         `json:"tags"`
+	AllowedTags      string         `json:"allowed_tags"`
+	Usable           bool           `json:"usable"`
+}
+
+func (q *Queries) ListRangedEntries(ctx context.Context, characterID int64) ([]ListRangedEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRangedEntries, characterID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetEquippedItemsRow
+	var items []ListRangedEntriesRow
 	for rows.Next() {
-		var i GetEquippedItemsRow
+		var i ListRangedEntriesRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.CharacterID,
 			&i.ItemID,
+			&i.ItemName,
 			&i.ItemType,
 			&i.Quantity,
+			&i.MissilesUsed,
+			&i.EnhancementBonus,
 			&i.ContainerID,
 			&i.EquipmentSlotID,
-			&i.Notes,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.SlotName,
-			&i.ItemName,
-			&i.ItemWeight,
+			&i.PerUnit,
+			&i.AmmunitionTag,
+			&i.Tags,
+			&i.AllowedTags,
+			&i.Usable,
 		); err != nil {
 			return nil, err
 		}
@@ -1226,82 +1417,74 @@ func (q *Queries) GetEquippedItems(ctx context.Context, characterID int64) ([]Ge
 	return items, nil
 }
 
-const getMagicalItemByID = `-- name: GetMagicalItemByID :one
-SELECT 
-    id, name, description, weight, cost_gp, max_charges, category, effect_description
-FROM 
-    magical_items
+const moveItemToContainer = `-- name: MoveItemToContainer :exec
+UPDATE character_inventory
+SET 
+    container_id = ?,
+    equipment_slot_id = NULL,
+    storage_location_id = NULL,
+    dropped = 0,
+    dropped_location = NULL
 WHERE 
     id = ?
+    AND character_id = ?
 `
 
-type GetMagicalItemByIDRow struct {
-	ID                int64  `json:"id"`
-	Name              string `json:"name"`
-	Description       string `json:"description"`
-	Weight            int64  `json:"weight"`
-	CostGp            int64  `json:"cost_gp"`
-	MaxCharges        int64  `json:"max_charges"`
-	Category          string `json:"category"`
-	EffectDescription string `json:"effect_description"`
+type MoveItemToContainerParams struct {
+	ContainerID sql.NullInt64 `json:"container_id"`
+	ID          int64         `json:"id"`
+	CharacterID int64         `json:"character_id"`
 }
 
-func (q *Queries) GetMagicalItemByID(ctx context.Context, id int64) (GetMagicalItemByIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getMagicalItemByID, id)
-	var i GetMagicalItemByIDRow
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Description,
-		&i.Weight,
-		&i.CostGp,
-		&i.MaxCharges,
-		&i.Category,
-		&i.EffectDescription,
-	)
-	return i, err
+func (q *Queries) MoveItemToContainer(ctx context.Context, arg MoveItemToContainerParams) error {
+	_, err := q.db.ExecContext(ctx, moveItemToContainer, arg.ContainerID, arg.ID, arg.CharacterID)
+	return err
 }
 
-const isSlotOccupied = `-- name: IsSlotOccupied :one
-SELECT 
-    COUNT(*) > 0 as is_occupied
-FROM 
-    character_inventory
-WHERE 
-    character_id = ? 
-    AND equipment_slot_id = ?
+const pickUpPack = `-- name: PickUpPack :exec
+UPDATE character_inventory
+SET
+    dropped = 0,
+    dropped_location = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?
 `
 
-type IsSlotOccupiedParams struct {
-	CharacterID     int64         `json:"character_id"`
-	EquipmentSlotID sql.NullInt64 `json:"equipment_slot_id"`
+type PickUpPackParams struct {
+	ID          int64 `json:"id"`
+	CharacterID int64 `json:"character_id"`
 }
 
-func (q *Queries) IsSlotOccupied(ctx context.Context, arg IsSlotOccupiedParams) (bool, error) {
-	row := q.db.QueryRowContext(ctx, isSlotOccupied, arg.CharacterID, arg.EquipmentSlotID)
-	var is_occupied bool
-	err := row.Scan(&is_occupied)
-	return is_occupied, err
+func (q *Queries) PickUpPack(ctx context.Context, arg PickUpPackParams) error {
+	_, err := q.db.ExecContext(ctx, pickUpPack, arg.ID, arg.CharacterID)
+	return err
 }
 
-const moveItemToContainer = `-- name: MoveItemToContainer :exec
-UPDATE character_inventory
-SET 
-    container_id = ?,
-    equipment_slot_id = NULL
-WHERE 
-    id = ?
-    AND character_id = ?
+const recordMissilesFired = `-- name: RecordMissilesFired :exec
+INSERT INTO
+    missiles_fired (character_id, inventory_id, item_id, enhancement_bonus, count)
+VALUES
+    (?, ?, ?, ?, ?)
 `
 
-type MoveItemToContainerParams struct {
-	ContainerID sql.NullInt64 `json:"container_id"`
-	ID          int64         `json:"id"`
-	CharacterID int64         `json:"character_id"`
+type RecordMissilesFiredParams struct {
+	CharacterID      int64         `json:"character_id"`
+	InventoryID      sql.NullInt64 `json:"inventory_id"`
+	ItemID           int64         `json:"item_id"`
+	EnhancementBonus int64         `json:"enhancement_bonus"`
+	Count            int64         `json:"count"`
 }
 
-func (q *Queries) MoveItemToContainer(ctx context.Context, arg MoveItemToContainerParams) error {
-	_, err := q.db.ExecContext(ctx, moveItemToContainer, arg.ContainerID, arg.ID, arg.CharacterID)
+func (q *Queries) RecordMissilesFired(ctx context.Context, arg RecordMissilesFiredParams) error {
+	_, err := q.db.ExecContext(ctx, recordMissilesFired,
+		arg.CharacterID,
+		arg.InventoryID,
+		arg.ItemID,
+		arg.EnhancementBonus,
+		arg.Count,
+	)
 	return err
 }
 
@@ -1339,48 +1522,204 @@ func (q *Queries) RemoveItemFromInventory(ctx context.Context, arg RemoveItemFro
 	return err
 }
 
-const splitStack = `-- name: SplitStack :exec
+const setAmmunitionCount = `-- name: SetAmmunitionCount :exec
+UPDATE character_inventory
+SET
+    quantity = ?,
+    missiles_used = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?
+`
+
+type SetAmmunitionCountParams struct {
+	Quantity     int64 `json:"quantity"`
+	MissilesUsed int64 `json:"missiles_used"`
+	ID           int64 `json:"id"`
+	CharacterID  int64 `json:"character_id"`
+}
+
+func (q *Queries) SetAmmunitionCount(ctx context.Context, arg SetAmmunitionCountParams) error {
+	_, err := q.db.ExecContext(ctx, setAmmunitionCount,
+		arg.Quantity,
+		arg.MissilesUsed,
+		arg.ID,
+		arg.CharacterID,
+	)
+	return err
+}
+
+const setItemCurse = `-- name: SetItemCurse :exec
+UPDATE character_inventory
+SET
+    cursed = ?,
+    curse_target = ?,
+    curse_ability = ?,
+    curse_modifier = ?,
+    curse_notes = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?
+`
+
+type SetItemCurseParams struct {
+	Cursed        bool           `json:"cursed"`
+	CurseTarget   sql.NullString `json:"curse_target"`
+	CurseAbility  sql.NullString `json:"curse_ability"`
+	CurseModifier int64          `json:"curse_modifier"`
+	CurseNotes    sql.NullString `json:"curse_notes"`
+	ID            int64          `json:"id"`
+	CharacterID   int64          `json:"character_id"`
+}
+
+func (q *Queries) SetItemCurse(ctx context.Context, arg SetItemCurseParams) error {
+	_, err := q.db.ExecContext(ctx, setItemCurse,
+		arg.Cursed,
+		arg.CurseTarget,
+		arg.CurseAbility,
+		arg.CurseModifier,
+		arg.CurseNotes,
+		arg.ID,
+		arg.CharacterID,
+	)
+	return err
+}
+
+const setItemEnhancement = `-- name: SetItemEnhancement :exec
+UPDATE character_inventory
+SET
+    enhancement_bonus = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?
+`
+
+type SetItemEnhancementParams struct {
+	EnhancementBonus int64 `json:"enhancement_bonus"`
+	ID               int64 `json:"id"`
+	CharacterID      int64 `json:"character_id"`
+}
+
+func (q *Queries) SetItemEnhancement(ctx context.Context, arg SetItemEnhancementParams) error {
+	_, err := q.db.ExecContext(ctx, setItemEnhancement, arg.EnhancementBonus, arg.ID, arg.CharacterID)
+	return err
+}
+
+const setItemIdentified = `-- name: SetItemIdentified :exec
+UPDATE character_inventory
+SET
+    is_identified = ?,
+    identified_by = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?
+`
+
+type SetItemIdentifiedParams struct {
+	IsIdentified bool           `json:"is_identified"`
+	IdentifiedBy sql.NullString `json:"identified_by"`
+	ID           int64          `json:"id"`
+	CharacterID  int64          `json:"character_id"`
+}
+
+func (q *Queries) SetItemIdentified(ctx context.Context, arg SetItemIdentifiedParams) error {
+	_, err := q.db.ExecContext(ctx, setItemIdentified,
+		arg.IsIdentified,
+		arg.IdentifiedBy,
+		arg.ID,
+		arg.CharacterID,
+	)
+	return err
+}
+
+const splitStack = `-- name: SplitStack :one
 INSERT INTO character_inventory (
     character_id, 
     item_id,
-    item_type,
     quantity,
     container_id,
     equipment_slot_id,
+    enhancement_bonus,
     notes,
+    is_identified,
+    identified_by,
+    cursed,
+    curse_target,
+    curse_ability,
+    curse_modifier,
+    curse_notes,
+    condition,
+    appraised_value,
+    charges,
+    custom_name,
+    custom_notes,
+    storage_location_id,
+    dropped,
+    dropped_location,
     created_at,
     updated_at
 )
 SELECT 
     ci.character_id,
     ci.item_id,
-    ci.item_type,
     ?,
-    ci.container_id,
+    ?,
     NULL,
+    ci.enhancement_bonus,
     ci.notes,
+    ci.is_identified,
+    ci.identified_by,
+    ci.cursed,
+    ci.curse_target,
+    ci.curse_ability,
+    ci.curse_modifier,
+    ci.curse_notes,
+    ci.condition,
+    ci.appraised_value,
+    ci.charges,
+    ci.custom_name,
+    ci.custom_notes,
+    ci.storage_location_id,
+    ci.dropped,
+    ci.dropped_location,
     CURRENT_TIMESTAMP,
     CURRENT_TIMESTAMP
 FROM 
     character_inventory ci
 WHERE 
     ci.id = ?
+    AND ci.character_id = ? RETURNING id
 `
 
 type SplitStackParams struct {
-	Quantity int64 `json:"quantity"`
-	ID       int64 `json:"id"`
+	Quantity    int64         `json:"quantity"`
+	ContainerID sql.NullInt64 `json:"container_id"`
+	ID          int64         `json:"id"`
+	CharacterID int64         `json:"character_id"`
 }
 
-func (q *Queries) SplitStack(ctx context.Context, arg SplitStackParams) error {
-	_, err := q.db.ExecContext(ctx, splitStack, arg.Quantity, arg.ID)
-	return err
+func (q *Queries) SplitStack(ctx context.Context, arg SplitStackParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, splitStack,
+		arg.Quantity,
+		arg.ContainerID,
+		arg.ID,
+		arg.CharacterID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
 }
 
 const unequipItem = `-- name: UnequipItem :exec
 UPDATE character_inventory
 SET 
-    equipment_slot_id = NULL
+    equipment_slot_id = NULL,
+    dropped = 0,
+    dropped_location = NULL
 WHERE 
     id = ?
     AND character_id = ?
@@ -1406,7 +1745,7 @@ SET
     updated_at = CURRENT_TIMESTAMP
 WHERE
     id = ?
-    AND character_id = ? RETURNING id, character_id, item_id, item_type, quantity, container_id, equipment_slot_id, notes, created_at, updated_at
+    AND character_id = ? RETURNING id, character_id, item_id, quantity, container_id, equipment_slot_id, notes, created_at, updated_at
 `
 
 type UpdateInventoryItemParams struct {
@@ -1422,7 +1761,6 @@ type UpdateInventoryItemRow struct {
 	ID              int64          `json:"id"`
 	CharacterID     int64          `json:"character_id"`
 	ItemID          int64          `json:"item_id"`
-	ItemType        string         `json:"item_type"`
 	Quantity        int64          `json:"quantity"`
 	ContainerID     sql.NullInt64  `json:"container_id"`
 	EquipmentSlotID sql.NullInt64  `json:"equipment_slot_id"`
@@ -1445,7 +1783,6 @@ func (q *Queries) UpdateInventoryItem(ctx context.Context, arg UpdateInventoryIt
 		&i.ID,
 		&i.CharacterID,
 		&i.ItemID,
-		&i.ItemType,
 		&i.Quantity,
 		&i.ContainerID,
 		&i.EquipmentSlotID,
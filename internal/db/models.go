@@ -10,28 +10,48 @@ import (
 )
 
 type Ammunition struct {
-	ID               int64         `json:"id"`
-	Name             string        `json:"name"`
-	CostGp           float64       `json:"cost_gp"`
-	Weight           sql.NullInt64 `json:"weight"`
-	Quantity         sql.NullInt64 `json:"quantity"`
-	EnhancementBonus sql.NullInt64 `json:"enhancement_bonus"`
-	CreatedAt        sql.NullTime  `json:"created_at"`
-	UpdatedAt        sql.NullTime  `json:"updated_at"`
+	ID        int64         `json:"id"`
+	Quantity  sql.NullInt64 `json:"quantity"`
+	CreatedAt sql.NullTime  `json:"created_at"`
+	UpdatedAt sql.NullTime  `json:"updated_at"`
+	ItemID    sql.NullInt64 `json:"item_id"`
 }
 
 type Armor struct {
-	ID               int64         `json:"id"`
-	Name             string        `json:"name"`
-	ArmorClass       int64         `json:"armor_class"`
-	CostGp           int64         `json:"cost_gp"`
-	DamageReduction  int64         `json:"damage_reduction"`
-	Weight           int64         `json:"weight"`
-	ArmorType        string        `json:"armor_type"`
-	MovementRate     int64         `json:"movement_rate"`
-	EnhancementBonus sql.NullInt64 `json:"enhancement_bonus"`
-	CreatedAt        sql.NullTime  `json:"created_at"`
-	UpdatedAt        sql.NullTime  `json:"updated_at"`
+	ID              int64         `json:"id"`
+	ArmorClass      int64         `json:"armor_class"`
+	DamageReduction int64         `json:"damage_reduction"`
+	ArmorType       string        `json:"armor_type"`
+	MovementRate    int64         `json:"movement_rate"`
+	CreatedAt       sql.NullTime  `json:"created_at"`
+	UpdatedAt       sql.NullTime  `json:"updated_at"`
+	ItemID          sql.NullInt64 `json:"item_id"`
+}
+
+type Campaign struct {
+	ID             int64     `json:"id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	RefereeID      int64     `json:"referee_id"`
+	InviteCode     string    `json:"invite_code"`
+	RefereeCanEdit bool      `json:"referee_can_edit"`
+	DrainXpPolicy  string    `json:"drain_xp_policy"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type CampaignCharacter struct {
+	CharacterID int64     `json:"character_id"`
+	CampaignID  int64     `json:"campaign_id"`
+	AddedAt     time.Time `json:"added_at"`
+}
+
+type CampaignMember struct {
+	CampaignID int64         `json:"campaign_id"`
+	UserID     int64         `json:"user_id"`
+	Status     string        `json:"status"`
+	InvitedBy  sql.NullInt64 `json:"invited_by"`
+	CreatedAt  time.Time     `json:"created_at"`
 }
 
 type Character struct {
@@ -49,32 +69,115 @@ type Character struct {
 	Wisdom           int64     `json:"wisdom"`
 	Charisma         int64     `json:"charisma"`
 	ExperiencePoints int64     `json:"experience_points"`
-	PlatinumPieces   int64     `json:"platinum_pieces"`
-	GoldPieces       int64     `json:"gold_pieces"`
-	ElectrumPieces   int64     `json:"electrum_pieces"`
-	SilverPieces     int64     `json:"silver_pieces"`
-	CopperPieces     int64     `json:"copper_pieces"`
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 }
 
+type CharacterCoin struct {
+	CharacterID  int64  `json:"character_id"`
+	Denomination string `json:"denomination"`
+	Quantity     int64  `json:"quantity"`
+}
+
+type CharacterCondition struct {
+	ID          int64          `json:"id"`
+	CharacterID int64          `json:"character_id"`
+	Name        string         `json:"name"`
+	Notes       sql.NullString `json:"notes"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type CharacterGameClock struct {
+	CharacterID  int64     `json:"character_id"`
+	ElapsedHours int64     `json:"elapsed_hours"`
+	TrackTime    bool      `json:"track_time"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
 type CharacterInventory struct {
+	ID                int64           `json:"id"`
+	CharacterID       int64           `json:"character_id"`
+	ItemID            int64           `json:"item_id"`
+	Quantity          int64           `json:"quantity"`
+	ContainerID       sql.NullInt64   `json:"container_id"`
+	EquipmentSlotID   sql.NullInt64   `json:"equipment_slot_id"`
+	Position          sql.NullString  `json:"position"`
+	CustomName        sql.NullString  `json:"custom_name"`
+	CustomNotes       sql.NullString  `json:"custom_notes"`
+	IsIdentified      bool            `json:"is_identified"`
+	Charges           sql.NullInt64   `json:"charges"`
+	Condition         string          `json:"condition"`
+	Notes             sql.NullString  `json:"notes"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+	EnhancementBonus  int64           `json:"enhancement_bonus"`
+	MissilesUsed      int64           `json:"missiles_used"`
+	IdentifiedBy      sql.NullString  `json:"identified_by"`
+	Cursed            bool            `json:"cursed"`
+	CurseTarget       sql.NullString  `json:"curse_target"`
+	CurseAbility      sql.NullString  `json:"curse_ability"`
+	CurseModifier     int64           `json:"curse_modifier"`
+	CurseNotes        sql.NullString  `json:"curse_notes"`
+	AppraisedValue    sql.NullFloat64 `json:"appraised_value"`
+	StorageLocationID sql.NullInt64   `json:"storage_location_id"`
+	Dropped           bool            `json:"dropped"`
+	DroppedLocation   sql.NullString  `json:"dropped_location"`
+}
+
+type CharacterInventoryProperty struct {
+	ID          int64          `json:"id"`
+	InventoryID int64          `json:"inventory_id"`
+	Name        string         `json:"name"`
+	Notes       sql.NullString `json:"notes"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type CharacterItemEffect struct {
+	ID            int64          `json:"id"`
+	CharacterID   int64          `json:"character_id"`
+	SourceName    string         `json:"source_name"`
+	EffectType    string         `json:"effect_type"`
+	Ability       sql.NullString `json:"ability"`
+	Modifier      int64          `json:"modifier"`
+	ConditionID   sql.NullInt64  `json:"condition_id"`
+	ExpiresAtHour sql.NullInt64  `json:"expires_at_hour"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+type CharacterLevelHistory struct {
+	ID          int64         `json:"id"`
+	CharacterID int64         `json:"character_id"`
+	Level       int64         `json:"level"`
+	HpGained    int64         `json:"hp_gained"`
+	DrainID     sql.NullInt64 `json:"drain_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+type CharacterResourceUse struct {
+	CharacterID int64  `json:"character_id"`
+	Resource    string `json:"resource"`
+	Used        int64  `json:"used"`
+}
+
+type CharacterTransfer struct {
 	ID              int64          `json:"id"`
-	CharacterID     int64          `json:"character_id"`
-	ItemID          int64          `json:"item_id"`
-	ItemType        string         `json:"item_type"`
+	CampaignID      int64          `json:"campaign_id"`
+	FromCharacterID int64          `json:"from_character_id"`
+	ToCharacterID   int64          `json:"to_character_id"`
+	InventoryID     sql.NullInt64  `json:"inventory_id"`
 	Quantity        int64          `json:"quantity"`
-	ContainerID     sql.NullInt64  `json:"container_id"`
-	EquipmentSlotID sql.NullInt64  `json:"equipment_slot_id"`
-	Position        sql.NullString `json:"position"`
-	CustomName      sql.NullString `json:"custom_name"`
-	CustomNotes     sql.NullString `json:"custom_notes"`
-	IsIdentified    bool           `json:"is_identified"`
-	Charges         sql.NullInt64  `json:"charges"`
-	Condition       string         `json:"condition"`
+	Status          string         `json:"status"`
 	Notes           sql.NullString `json:"notes"`
+	OfferedBy       int64          `json:"offered_by"`
+	ResolvedBy      sql.NullInt64  `json:"resolved_by"`
 	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
+	ResolvedAt      sql.NullTime   `json:"resolved_at"`
+}
+
+type CharacterTransferCoin struct {
+	TransferID   int64  `json:"transfer_id"`
+	Denomination string `json:"denomination"`
+	Amount       int64  `json:"amount"`
 }
 
 type CharacterWeaponMastery struct {
@@ -87,20 +190,23 @@ type CharacterWeaponMastery struct {
 }
 
 type Coin struct {
-	Denomination  string  `json:"denomination"`
-	Name          string  `json:"name"`
-	WeightPerCoin float64 `json:"weight_per_coin"`
-	BaseValue     int64   `json:"base_value"`
+	ID            int64         `json:"id"`
+	CampaignID    sql.NullInt64 `json:"campaign_id"`
+	Denomination  string        `json:"denomination"`
+	Name          string        `json:"name"`
+	WeightPerCoin float64       `json:"weight_per_coin"`
+	BaseValue     int64         `json:"base_value"`
 }
 
 type Container struct {
-	ID             int64         `json:"id"`
-	BaseItemID     int64         `json:"base_item_id"`
-	CapacityWeight float64       `json:"capacity_weight"`
-	CapacityItems  sql.NullInt64 `json:"capacity_items"`
-	ContainerType  string        `json:"container_type"`
-	CreatedAt      time.Time     `json:"created_at"`
-	UpdatedAt      time.Time     `json:"updated_at"`
+	ID               int64         `json:"id"`
+	BaseItemID       int64         `json:"base_item_id"`
+	CapacityWeight   float64       `json:"capacity_weight"`
+	CapacityItems    sql.NullInt64 `json:"capacity_items"`
+	ContainerType    string        `json:"container_type"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+	WeightMultiplier float64       `json:"weight_multiplier"`
 }
 
 type ContainerAllowedTag struct {
@@ -108,20 +214,42 @@ type ContainerAllowedTag struct {
 	Tag         string `json:"tag"`
 }
 
-type Equipment struct {
+type CurrencyTransaction struct {
 	ID          int64          `json:"id"`
-	Name        string         `json:"name"`
-	CostGp      float64        `json:"cost_gp"`
-	Weight      sql.NullInt64  `json:"weight"`
-	Description sql.NullString `json:"description"`
-	CreatedAt   sql.NullTime   `json:"created_at"`
-	UpdatedAt   sql.NullTime   `json:"updated_at"`
+	CharacterID int64          `json:"character_id"`
+	Reason      string         `json:"reason"`
+	ItemID      sql.NullInt64  `json:"item_id"`
+	XpAwardID   sql.NullInt64  `json:"xp_award_id"`
+	ActorID     int64          `json:"actor_id"`
+	Notes       sql.NullString `json:"notes"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type CurrencyTransactionCoin struct {
+	TransactionID int64  `json:"transaction_id"`
+	Denomination  string `json:"denomination"`
+	Amount        int64  `json:"amount"`
+}
+
+type Equipment struct {
+	ID        int64         `json:"id"`
+	CreatedAt sql.NullTime  `json:"created_at"`
+	UpdatedAt sql.NullTime  `json:"updated_at"`
+	ItemID    sql.NullInt64 `json:"item_id"`
 }
 
 type EquipmentSlot struct {
 	ID          int64          `json:"id"`
 	Name        string         `json:"name"`
 	Description sql.NullString `json:"description"`
+	SlotGroup   string         `json:"slot_group"`
+}
+
+type EquipmentSlotRule struct {
+	ID        int64          `json:"id"`
+	ItemType  sql.NullString `json:"item_type"`
+	ItemID    sql.NullInt64  `json:"item_id"`
+	SlotGroup string         `json:"slot_group"`
 }
 
 type Item struct {
@@ -134,6 +262,18 @@ type Item struct {
 	MaxStack    sql.NullInt64  `json:"max_stack"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
+	ItemType    string         `json:"item_type"`
+	TwoHanded   bool           `json:"two_handed"`
+	Appearance  sql.NullString `json:"appearance"`
+}
+
+type ItemCondition struct {
+	Name              string `json:"name"`
+	Rank              int64  `json:"rank"`
+	ValuePercent      int64  `json:"value_percent"`
+	ArmorClassPenalty int64  `json:"armor_class_penalty"`
+	Usable            bool   `json:"usable"`
+	Description       string `json:"description"`
 }
 
 type ItemTag struct {
@@ -141,33 +281,128 @@ type ItemTag struct {
 	Tag    string `json:"tag"`
 }
 
+type ItemWearEvent struct {
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	Steps       int64  `json:"steps"`
+	ItemTypes   string `json:"item_types"`
+	Description string `json:"description"`
+}
+
+type LevelDrain struct {
+	ID          int64          `json:"id"`
+	CharacterID int64          `json:"character_id"`
+	FromLevel   int64          `json:"from_level"`
+	ToLevel     int64          `json:"to_level"`
+	HpRemoved   int64          `json:"hp_removed"`
+	XpAwardID   int64          `json:"xp_award_id"`
+	DrainedBy   int64          `json:"drained_by"`
+	RestoredBy  sql.NullInt64  `json:"restored_by"`
+	RestoredAt  sql.NullTime   `json:"restored_at"`
+	Notes       sql.NullString `json:"notes"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
 type MagicalItem struct {
-	ID                int64        `json:"id"`
-	Name              string       `json:"name"`
-	Description       string       `json:"description"`
-	Weight            int64        `json:"weight"`
-	CostGp            int64        `json:"cost_gp"`
-	MaxCharges        int64        `json:"max_charges"`
-	Category          string       `json:"category"`
-	EffectDescription string       `json:"effect_description"`
-	CreatedAt         sql.NullTime `json:"created_at"`
-	UpdatedAt         sql.NullTime `json:"updated_at"`
+	ID                int64         `json:"id"`
+	MaxCharges        int64         `json:"max_charges"`
+	Category          string        `json:"category"`
+	EffectDescription string        `json:"effect_description"`
+	CreatedAt         sql.NullTime  `json:"created_at"`
+	UpdatedAt         sql.NullTime  `json:"updated_at"`
+	ItemID            sql.NullInt64 `json:"item_id"`
+}
+
+type MagicalItemCategory struct {
+	Name           string         `json:"name"`
+	SlotGroup      sql.NullString `json:"slot_group"`
+	MaxFunctioning sql.NullInt64  `json:"max_functioning"`
+	Description    string         `json:"description"`
+}
+
+type MagicalItemEffect struct {
+	ID            int64          `json:"id"`
+	MagicalItemID int64          `json:"magical_item_id"`
+	EffectType    string         `json:"effect_type"`
+	Dice          sql.NullString `json:"dice"`
+	SpellID       sql.NullInt64  `json:"spell_id"`
+	ConditionName sql.NullString `json:"condition_name"`
+	Ability       sql.NullString `json:"ability"`
+	BonusTarget   sql.NullString `json:"bonus_target"`
+	Modifier      int64          `json:"modifier"`
+	DurationHours sql.NullInt64  `json:"duration_hours"`
+}
+
+type MissilesFired struct {
+	ID               int64         `json:"id"`
+	CharacterID      int64         `json:"character_id"`
+	InventoryID      sql.NullInt64 `json:"inventory_id"`
+	ItemID           int64         `json:"item_id"`
+	EnhancementBonus int64         `json:"enhancement_bonus"`
+	Count            int64         `json:"count"`
+	CreatedAt        time.Time     `json:"created_at"`
+}
+
+type PartyStashCoin struct {
+	CampaignID   int64  `json:"campaign_id"`
+	Denomination string `json:"denomination"`
+	Amount       int64  `json:"amount"`
+}
+
+type PartyStashItem struct {
+	ID             int64           `json:"id"`
+	CampaignID     int64           `json:"campaign_id"`
+	ItemID         int64           `json:"item_id"`
+	Quantity       int64           `json:"quantity"`
+	AppraisedValue sql.NullFloat64 `json:"appraised_value"`
+	Notes          sql.NullString  `json:"notes"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+type PartyStashLog struct {
+	ID          int64          `json:"id"`
+	CampaignID  int64          `json:"campaign_id"`
+	Action      string         `json:"action"`
+	ItemID      sql.NullInt64  `json:"item_id"`
+	Quantity    int64          `json:"quantity"`
+	Coins       sql.NullString `json:"coins"`
+	CharacterID sql.NullInt64  `json:"character_id"`
+	UserID      int64          `json:"user_id"`
+	Notes       sql.NullString `json:"notes"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type PriceList struct {
+	ID                 int64     `json:"id"`
+	CampaignID         int64     `json:"campaign_id"`
+	Settlement         string    `json:"settlement"`
+	MarkupPercent      int64     `json:"markup_percent"`
+	SellPercent        int64     `json:"sell_percent"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+	TreasureFeePercent int64     `json:"treasure_fee_percent"`
+}
+
+type PriceListItem struct {
+	PriceListID   int64         `json:"price_list_id"`
+	ItemID        int64         `json:"item_id"`
+	Available     bool          `json:"available"`
+	MarkupPercent sql.NullInt64 `json:"markup_percent"`
 }
 
 type RangedWeapon struct {
-	ID               int64          `json:"id"`
-	Name             string         `json:"name"`
-	WeaponType       string         `json:"weapon_type"`
-	CostGp           sql.NullInt64  `json:"cost_gp"`
-	Weight           int64          `json:"weight"`
-	RateOfFire       string         `json:"rate_of_fire"`
-	RangeShort       sql.NullInt64  `json:"range_short"`
-	RangeMedium      sql.NullInt64  `json:"range_medium"`
-	RangeLong        sql.NullInt64  `json:"range_long"`
-	Damage           sql.NullString `json:"damage"`
-	EnhancementBonus sql.NullInt64  `json:"enhancement_bonus"`
-	CreatedAt        sql.NullTime   `json:"created_at"`
-	UpdatedAt        sql.NullTime   `json:"updated_at"`
+	ID            int64          `json:"id"`
+	WeaponType    string         `json:"weapon_type"`
+	RateOfFire    string         `json:"rate_of_fire"`
+	RangeShort    sql.NullInt64  `json:"range_short"`
+	RangeMedium   sql.NullInt64  `json:"range_medium"`
+	RangeLong     sql.NullInt64  `json:"range_long"`
+	Damage        sql.NullString `json:"damage"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	UpdatedAt     sql.NullTime   `json:"updated_at"`
+	ItemID        sql.NullInt64  `json:"item_id"`
+	AmmunitionTag sql.NullString `json:"ammunition_tag"`
 }
 
 type RangedWeaponProperty struct {
@@ -181,6 +416,17 @@ type RangedWeaponPropertyLink struct {
 	PropertyID int64 `json:"property_id"`
 }
 
+type RestMode struct {
+	Code            string `json:"code"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	DurationHours   int64  `json:"duration_hours"`
+	HealingFormula  string `json:"healing_formula"`
+	ResetsResources bool   `json:"resets_resources"`
+	RationsConsumed int64  `json:"rations_consumed"`
+	SortOrder       int64  `json:"sort_order"`
+}
+
 type Session struct {
 	Token     string    `json:"token"`
 	UserID    int64     `json:"user_id"`
@@ -188,14 +434,17 @@ type Session struct {
 }
 
 type Shield struct {
-	ID               int64         `json:"id"`
-	Name             string        `json:"name"`
-	CostGp           int64         `json:"cost_gp"`
-	Weight           int64         `json:"weight"`
-	DefenseBonus     int64         `json:"defense_bonus"`
-	EnhancementBonus sql.NullInt64 `json:"enhancement_bonus"`
-	CreatedAt        sql.NullTime  `json:"created_at"`
-	UpdatedAt        sql.NullTime  `json:"updated_at"`
+	ID           int64         `json:"id"`
+	DefenseBonus int64         `json:"defense_bonus"`
+	CreatedAt    sql.NullTime  `json:"created_at"`
+	UpdatedAt    sql.NullTime  `json:"updated_at"`
+	ItemID       sql.NullInt64 `json:"item_id"`
+}
+
+type ShopCartItem struct {
+	CharacterID int64 `json:"character_id"`
+	ItemID      int64 `json:"item_id"`
+	Quantity    int64 `json:"quantity"`
 }
 
 type Spell struct {
@@ -214,6 +463,49 @@ type SpellLevel struct {
 	Level   int64  `json:"level"`
 }
 
+type StorageLocation struct {
+	ID          int64          `json:"id"`
+	CharacterID sql.NullInt64  `json:"character_id"`
+	CampaignID  sql.NullInt64  `json:"campaign_id"`
+	Name        string         `json:"name"`
+	Kind        string         `json:"kind"`
+	Notes       sql.NullString `json:"notes"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+type StorageLocationCoin struct {
+	StorageLocationID int64  `json:"storage_location_id"`
+	CharacterID       int64  `json:"character_id"`
+	Denomination      string `json:"denomination"`
+	Amount            int64  `json:"amount"`
+}
+
+type TreasureItem struct {
+	ItemID int64  `json:"item_id"`
+	Kind   string `json:"kind"`
+}
+
+type TreasureSplit struct {
+	ID            int64     `json:"id"`
+	CampaignID    int64     `json:"campaign_id"`
+	RefereeID     int64     `json:"referee_id"`
+	Description   string    `json:"description"`
+	TotalValueCp  int64     `json:"total_value_cp"`
+	Remainder     string    `json:"remainder"`
+	HenchmanCoins string    `json:"henchman_coins"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type TreasureSplitShare struct {
+	SplitID     int64         `json:"split_id"`
+	CharacterID int64         `json:"character_id"`
+	Weight      int64         `json:"weight"`
+	ValueCp     int64         `json:"value_cp"`
+	XpAwardID   sql.NullInt64 `json:"xp_award_id"`
+	Coins       string        `json:"coins"`
+}
+
 type User struct {
 	ID           int64       `json:"id"`
 	Username     string      `json:"username"`
@@ -225,20 +517,16 @@ type User struct {
 }
 
 type Weapon struct {
-	ID                int64          `json:"id"`
-	Name              string         `json:"name"`
-	Reach             int64          `json:"reach"`
-	CostGp            int64          `json:"cost_gp"`
-	Weight            int64          `json:"weight"`
-	RangeShort        sql.NullInt64  `json:"range_short"`
-	RangeMedium       sql.NullInt64  `json:"range_medium"`
-	RangeLong         sql.NullInt64  `json:"range_long"`
-	AttacksPerRound   sql.NullString `json:"attacks_per_round"`
-	Damage            string         `json:"damage"`
-	EnhancementBonus  sql.NullInt64  `json:"enhancement_bonus"`
-	MagicalProperties sql.NullString `json:"magical_properties"`
-	CreatedAt         sql.NullTime   `json:"created_at"`
-	UpdatedAt         sql.NullTime   `json:"updated_at"`
+	ID              int64          `json:"id"`
+	Reach           int64          `json:"reach"`
+	RangeShort      sql.NullInt64  `json:"range_short"`
+	RangeMedium     sql.NullInt64  `json:"range_medium"`
+	RangeLong       sql.NullInt64  `json:"range_long"`
+	AttacksPerRound sql.NullString `json:"attacks_per_round"`
+	Damage          string         `json:"damage"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	UpdatedAt       sql.NullTime   `json:"updated_at"`
+	ItemID          sql.NullInt64  `json:"item_id"`
 }
 
 type WeaponProperty struct {
@@ -251,3 +539,15 @@ type WeaponPropertyLink struct {
 	WeaponID   int64 `json:"weapon_id"`
 	PropertyID int64 `json:"property_id"`
 }
+
+type XpAward struct {
+	ID              int64          `json:"id"`
+	CharacterID     int64          `json:"character_id"`
+	Amount          int64          `json:"amount"`
+	Source          string         `json:"source"`
+	SessionDate     time.Time      `json:"session_date"`
+	AwardedBy       int64          `json:"awarded_by"`
+	ReversesAwardID sql.NullInt64  `json:"reverses_award_id"`
+	Notes           sql.NullString `json:"notes"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
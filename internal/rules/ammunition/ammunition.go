@@ -0,0 +1,197 @@
+package ammunition
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultRecoveryPercent is the chance each missile shot is found again
+// after a fight
+const DefaultRecoveryPercent = 50
+
+var (
+	ErrNoLauncher   = errors.New("no missile weapon is equipped")
+	ErrNoAmmunition = errors.New("no ammunition is ready")
+	ErrWrongAmmo    = errors.New("that ammunition doesn't fit")
+	ErrNotReady     = errors.New("ammunition isn't ready")
+	ErrOutOfAmmo    = errors.New("not enough ammunition")
+	ErrOneAtATime   = errors.New("magic missiles are shot one at a time")
+	ErrBadPercent   = errors.New("recovery chance must be between 0 and 100")
+)
+
+// IsRuleError reports whether err came from breaking an ammunition rule
+// rather than from reading or writing the inventory
+func IsRuleError(err error) bool {
+	for _, ruleErr := range []error{ErrNoLauncher, ErrNoAmmunition, ErrWrongAmmo, ErrNotReady, ErrOutOfAmmo, ErrOneAtATime, ErrBadPercent} {
+		if errors.Is(err, ruleErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Launcher is an equipped missile weapon
+type Launcher struct {
+	ID          int64
+	Name        string
+	AmmoTag     string // Item tag of the missiles it shoots
+	Enhancement int64
+}
+
+// Shoots reports whether the launcher takes the stack's missiles
+func (l Launcher) Shoots(s Stack) bool {
+	return s.fits(l.AmmoTag)
+}
+
+// Stack is an owned stack of ammunition. Quantity counts bundles of PerUnit
+// missiles, and Used how many have been shot from the first bundle.
+type Stack struct {
+	ID          int64
+	ItemID      int64
+	Name        string
+	Tags        []string
+	Quantity    int64
+	PerUnit     int64
+	Used        int64
+	Enhancement int64
+	ContainerID int64 // 0 when not in a container
+}
+
+// Remaining is how many missiles are left in the stack
+func (s Stack) Remaining() int64 {
+	return s.Quantity*s.perUnit() - s.Used
+}
+
+// Label names the stack with its bonus, e.g. "Arrows +1"
+func (s Stack) Label() string {
+	if s.Enhancement == 0 {
+		return s.Name
+	}
+	return fmt.Sprintf("%s %+d", s.Name, s.Enhancement)
+}
+
+func (s Stack) perUnit() int64 {
+	if s.PerUnit < 1 {
+		return 1
+	}
+	return s.PerUnit
+}
+
+func (s Stack) fits(tag string) bool {
+	for _, t := range s.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Pack turns a count of loose missiles back into bundles, with a partly
+// used bundle first
+func Pack(missiles, perUnit int64) (quantity, used int64) {
+	if perUnit < 1 {
+		perUnit = 1
+	}
+	quantity = (missiles + perUnit - 1) / perUnit
+	return quantity, quantity*perUnit - missiles
+}
+
+// Holder is an equipped quiver, case or pouch and the missiles it takes
+type Holder struct {
+	ID          int64
+	AllowedTags []string
+}
+
+// Ready lists the stacks a launcher can shoot: matching missiles in an
+// equipped holder made for them
+func Ready(launcher Launcher, stacks []Stack, holders []Holder) []Stack {
+	var ready []Stack
+	for _, s := range stacks {
+		if launcher.Shoots(s) && s.Remaining() > 0 && heldFor(s.ContainerID, launcher.AmmoTag, holders) {
+			ready = append(ready, s)
+		}
+	}
+	return ready
+}
+
+func heldFor(containerID int64, tag string, holders []Holder) bool {
+	for _, h := range holders {
+		if h.ID != containerID {
+			continue
+		}
+		for _, allowed := range h.AllowedTags {
+			if allowed == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Shot is the outcome of loosing missiles from a stack
+type Shot struct {
+	Launcher  Launcher
+	Stack     Stack
+	Missiles  int64
+	Bonus     int64 // Added to hit and damage for each missile
+	Remaining int64
+	Quantity  int64 // The stack's new bundle count; 0 when it is used up
+	Used      int64
+}
+
+// Describe summarises the shot for the player
+func (s Shot) Describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Shot %d %s from %s", s.Missiles, s.Stack.Label(), s.Launcher.Name)
+	if s.Bonus != 0 {
+		fmt.Fprintf(&b, " at %+d to hit and damage", s.Bonus)
+	}
+	fmt.Fprintf(&b, ", %d left", s.Remaining)
+	return b.String()
+}
+
+// Fire looses missiles from a ready stack. Enhanced missiles are valuable
+// enough to be tracked singly, so only one is shot at a time and its bonus
+// adds to the launcher's.
+func Fire(launcher Launcher, stack Stack, missiles int64) (Shot, error) {
+	if !launcher.Shoots(stack) {
+		return Shot{}, fmt.Errorf("%w: %s can't shoot %s", ErrWrongAmmo, launcher.Name, stack.Name)
+	}
+	if missiles < 1 {
+		missiles = 1
+	}
+	if stack.Enhancement != 0 && missiles > 1 {
+		return Shot{}, ErrOneAtATime
+	}
+	if missiles > stack.Remaining() {
+		return Shot{}, fmt.Errorf("%w: only %d %s left", ErrOutOfAmmo, stack.Remaining(), stack.Label())
+	}
+
+	remaining := stack.Remaining() - missiles
+	quantity, used := Pack(remaining, stack.perUnit())
+	return Shot{
+		Launcher:  launcher,
+		Stack:     stack,
+		Missiles:  missiles,
+		Bonus:     launcher.Enhancement + stack.Enhancement,
+		Remaining: remaining,
+		Quantity:  quantity,
+		Used:      used,
+	}, nil
+}
+
+// Recover rolls percentile dice for each missile shot and returns how many
+// are found intact
+func Recover(missiles int64, percent int, roll func(sides int) int) (int64, error) {
+	if percent < 0 || percent > 100 {
+		return 0, ErrBadPercent
+	}
+	var found int64
+	for i := int64(0); i < missiles; i++ {
+		if roll(100) <= percent {
+			found++
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,40 @@
+package character
+
+import "fmt"
+
+// TurnUndeadUsesPerDay is how many times a cleric may turn undead between rests
+const TurnUndeadUsesPerDay = 3
+
+// DailyResource is a limited use that a full rest restores
+type DailyResource struct {
+	Key  string
+	Name string
+	Max  int
+}
+
+// GetDailyResources lists the spell slots and class feature uses a
+// character has at the given level
+func GetDailyResources(class string, level int64) []DailyResource {
+	var resources []DailyResource
+
+	slots := GetClassProgression(class).GetSpellSlots(level)
+	for i, count := range []int{slots.Level1, slots.Level2, slots.Level3, slots.Level4, slots.Level5, slots.Level6} {
+		if count > 0 {
+			resources = append(resources, DailyResource{
+				Key:  fmt.Sprintf("spell_slot_%d", i+1),
+				Name: fmt.Sprintf("Level %d Spells", i+1),
+				Max:  count,
+			})
+		}
+	}
+
+	if class == "Cleric" {
+		resources = append(resources, DailyResource{
+			Key:  "turn_undead",
+			Name: "Turn Undead",
+			Max:  TurnUndeadUsesPerDay,
+		})
+	}
+
+	return resources
+}
@@ -172,3 +172,59 @@ func (c ClassProgression) GetHitDice(level int64) string {
 	// If level is beyond progression, return last defined hit dice
 	return c.Levels[len(c.Levels)-1].HitDice
 }
+
+// GetSpellSlots returns the spell slots available at a given level
+func (c ClassProgression) GetSpellSlots(level int64) SpellSlots {
+	if level < 1 {
+		return SpellSlots{}
+	}
+	for _, l := range c.Levels {
+		if l.Level == level {
+			return l.Spells
+		}
+	}
+	// If level is beyond progression, return last defined spell slots
+	return c.Levels[len(c.Levels)-1].Spells
+}
+
+// DrainXPPolicy decides where within a level a drained character's XP lands
+type DrainXPPolicy string
+
+const (
+	// DrainToMidpoint leaves the character halfway to their old level
+	DrainToMidpoint DrainXPPolicy = "midpoint"
+	// DrainToMinimum leaves the character at the bare minimum for the new level
+	DrainToMinimum DrainXPPolicy = "minimum"
+)
+
+// DefaultDrainXPPolicy is used when no campaign setting overrides it
+const DefaultDrainXPPolicy = DrainToMidpoint
+
+// IsValidDrainXPPolicy reports whether policy names a known drain policy
+func IsValidDrainXPPolicy(policy string) bool {
+	switch DrainXPPolicy(policy) {
+	case DrainToMidpoint, DrainToMinimum:
+		return true
+	}
+	return false
+}
+
+// GetDrainedXP returns the XP a character has after being drained to level
+func (c ClassProgression) GetDrainedXP(level int64, policy DrainXPPolicy) int64 {
+	var current, next int64 = -1, -1
+	for _, l := range c.Levels {
+		if l.Level == level {
+			current = l.XPRequired
+		}
+		if l.Level == level+1 {
+			next = l.XPRequired
+		}
+	}
+	if current < 0 {
+		return 0
+	}
+	if policy == DrainToMinimum || next < 0 {
+		return current
+	}
+	return current + (next-current)/2
+}
@@ -0,0 +1,282 @@
+package containers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// MaxNestingDepth is how many containers deep an item may be packed, e.g.
+// a pouch inside a sack inside a bag of holding
+const MaxNestingDepth = 3
+
+// PackSlot is the equipment slot a pack is worn in when it can be dropped
+// at the start of a fight
+const PackSlot = "back"
+
+var (
+	ErrNotContainer  = errors.New("that item is not a container")
+	ErrSelf          = errors.New("a container cannot hold itself")
+	ErrDescendant    = errors.New("a container cannot go inside something it holds")
+	ErrTooDeep       = errors.New("containers cannot be nested that deep")
+	ErrOverWeight    = errors.New("not enough room")
+	ErrTooManyItems  = errors.New("too many items")
+	ErrTagNotAllowed = errors.New("item does not fit")
+	ErrNotPack       = errors.New("only a container worn on the back can be dropped")
+)
+
+// IsRuleError reports whether err came from breaking a container rule
+// rather than from reading or writing the inventory
+func IsRuleError(err error) bool {
+	for _, ruleErr := range []error{ErrNotContainer, ErrSelf, ErrDescendant, ErrTooDeep, ErrOverWeight, ErrTooManyItems, ErrTagNotAllowed, ErrNotPack} {
+		if errors.Is(err, ruleErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Spec describes what a container can hold
+type Spec struct {
+	CapacityWeight   float64  // Most weight the contents may have, in pounds
+	CapacityItems    int64    // Most items it may hold; 0 means no limit
+	AllowedTags      []string // Item tags it accepts; empty accepts anything
+	WeightMultiplier float64  // Fraction of the contents' weight it passes on
+}
+
+// Entry is one stack in a character's inventory
+type Entry struct {
+	ID          int64
+	Name        string
+	ContainerID int64   // 0 when not in a container
+	Weight      float64 // Weight of one item, or of one bundle when bundled
+	Quantity    int64
+	Bundle      int64 // Items weighed together, e.g. 12 arrows to the pound; 0 or 1 when weighed singly
+	Used        int64 // Items gone from the first bundle
+	Tags        []string
+	Container   *Spec // nil unless the item is a container
+}
+
+// StackWeight is what the whole stack weighs. Bundled items weigh their
+// share of the bundle each, so a bundle with some used weighs less.
+func (e Entry) StackWeight() float64 {
+	if e.Bundle <= 1 {
+		return e.Weight * float64(e.Quantity)
+	}
+	items := e.Quantity*e.Bundle - e.Used
+	if items < 0 {
+		items = 0
+	}
+	return e.Weight / float64(e.Bundle) * float64(items)
+}
+
+// Inventory indexes a character's entries by container so loads and nesting
+// can be worked out
+type Inventory struct {
+	entries  map[int64]Entry
+	children map[int64][]int64
+}
+
+// NewInventory builds an Inventory from a character's entries
+func NewInventory(entries []Entry) *Inventory {
+	inv := &Inventory{
+		entries:  make(map[int64]Entry, len(entries)),
+		children: make(map[int64][]int64),
+	}
+	for _, e := range entries {
+		inv.entries[e.ID] = e
+		if e.ContainerID != 0 {
+			inv.children[e.ContainerID] = append(inv.children[e.ContainerID], e.ID)
+		}
+	}
+	return inv
+}
+
+// Entry returns the entry with the given ID
+func (inv *Inventory) Entry(id int64) (Entry, bool) {
+	e, ok := inv.entries[id]
+	return e, ok
+}
+
+// Load is the weight pressing on a container: everything inside it, with
+// nested weight-reducing containers passing on only their fraction
+func (inv *Inventory) Load(id int64) float64 {
+	var load float64
+	for _, child := range inv.children[id] {
+		load += inv.CarriedWeight(child)
+	}
+	return load
+}
+
+// ContentsWeight is how much a container's contents add to its own weight
+func (inv *Inventory) ContentsWeight(id int64) float64 {
+	e, ok := inv.entries[id]
+	if !ok || e.Container == nil {
+		return 0
+	}
+	return inv.Load(id) * e.Container.WeightMultiplier
+}
+
+// CarriedWeight is the weight an entry adds to whatever holds it, including
+// its contents
+func (inv *Inventory) CarriedWeight(id int64) float64 {
+	e, ok := inv.entries[id]
+	if !ok {
+		return 0
+	}
+	return e.StackWeight() + inv.ContentsWeight(id)
+}
+
+// ItemCount is the number of items directly inside a container
+func (inv *Inventory) ItemCount(id int64) int64 {
+	var count int64
+	for _, child := range inv.children[id] {
+		count += inv.entries[child].Quantity
+	}
+	return count
+}
+
+// Outermost is the entry that isn't in a container which id is packed
+// inside, or id itself when it isn't in one
+func (inv *Inventory) Outermost(id int64) int64 {
+	seen := map[int64]bool{id: true}
+	for parent := inv.entries[id].ContainerID; parent != 0 && !seen[parent]; parent = inv.entries[parent].ContainerID {
+		seen[parent] = true
+		id = parent
+	}
+	return id
+}
+
+// CheckDrop reports whether an entry worn in slot is a pack that can be
+// dropped, taking everything in it along
+func CheckDrop(e Entry, slot string) error {
+	if e.Container == nil || slot != PackSlot {
+		return fmt.Errorf("%w: %s isn't one", ErrNotPack, e.Name)
+	}
+	return nil
+}
+
+// level is how deep a container sits: 1 when carried, 2 inside another
+// container and so on
+func (inv *Inventory) level(id int64) int {
+	level := 1
+	seen := map[int64]bool{id: true}
+	for parent := inv.entries[id].ContainerID; parent != 0 && !seen[parent]; parent = inv.entries[parent].ContainerID {
+		seen[parent] = true
+		level++
+	}
+	return level
+}
+
+// height is how many levels of containers an entry brings with it: 0 for an
+// ordinary item, 1 for an empty container
+func (inv *Inventory) height(id int64, seen map[int64]bool) int {
+	e := inv.entries[id]
+	if e.Container == nil || seen[id] {
+		return 0
+	}
+	seen[id] = true
+	deepest := 0
+	for _, child := range inv.children[id] {
+		if h := inv.height(child, seen); h > deepest {
+			deepest = h
+		}
+	}
+	return deepest + 1
+}
+
+// holds reports whether id is somewhere inside container
+func (inv *Inventory) holds(container, id int64) bool {
+	seen := map[int64]bool{}
+	for parent := inv.entries[id].ContainerID; parent != 0 && !seen[parent]; parent = inv.entries[parent].ContainerID {
+		if parent == container {
+			return true
+		}
+		seen[parent] = true
+	}
+	return false
+}
+
+// CheckPlacement reports whether item may be put into the container with
+// ID target. The item may already be in the inventory, in which case it is
+// moved along with its contents, or be new with an ID of 0. Quantity on the
+// item is how many are being placed, so part of a stack can be checked
+// before it is split off.
+func (inv *Inventory) CheckPlacement(item Entry, target int64) error {
+	container, ok := inv.entries[target]
+	if !ok || container.Container == nil {
+		return ErrNotContainer
+	}
+	spec := container.Container
+
+	if item.ID != 0 {
+		if item.ID == target {
+			return ErrSelf
+		}
+		if inv.holds(item.ID, target) {
+			return fmt.Errorf("%w: %s is inside %s", ErrDescendant, container.Name, item.Name)
+		}
+	}
+
+	if len(spec.AllowedTags) > 0 && !sharesTag(item.Tags, spec.AllowedTags) {
+		return fmt.Errorf("%w: %s only holds %s", ErrTagNotAllowed, container.Name, joinTags(spec.AllowedTags))
+	}
+
+	// Whatever is already in the target doesn't count twice when it is
+	// rearranged within the same container
+	load := inv.Load(target)
+	count := inv.ItemCount(target)
+	if item.ID != 0 && inv.entries[item.ID].ContainerID == target {
+		load -= inv.CarriedWeight(item.ID)
+		count -= inv.entries[item.ID].Quantity
+	}
+
+	incoming := item.StackWeight()
+	height := 0
+	if item.ID != 0 {
+		incoming += inv.ContentsWeight(item.ID)
+		height = inv.height(item.ID, map[int64]bool{})
+	} else if item.Container != nil {
+		height = 1
+	}
+
+	if spec.CapacityItems > 0 && count+item.Quantity > spec.CapacityItems {
+		return fmt.Errorf("%w: %s holds at most %d items and already has %d", ErrTooManyItems, container.Name, spec.CapacityItems, count)
+	}
+
+	if load+incoming > spec.CapacityWeight+1e-9 {
+		return fmt.Errorf("%w: %s has room for %s lbs but %s weighs %s lbs", ErrOverWeight,
+			container.Name, formatPounds(math.Max(spec.CapacityWeight-load, 0)), item.Name, formatPounds(incoming))
+	}
+
+	if height > 0 && inv.level(target)+height > MaxNestingDepth {
+		return fmt.Errorf("%w: at most %d levels", ErrTooDeep, MaxNestingDepth)
+	}
+
+	return nil
+}
+
+func sharesTag(tags, allowed []string) bool {
+	for _, tag := range tags {
+		for _, a := range allowed {
+			if tag == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// joinTags lists tags as plural nouns, e.g. "arrows, bolts"
+func joinTags(tags []string) string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = strings.ReplaceAll(tag, "_", " ") + "s"
+	}
+	return strings.Join(names, ", ")
+}
+
+func formatPounds(lbs float64) string {
+	return fmt.Sprintf("%.4g", math.Round(lbs*10)/10)
+}
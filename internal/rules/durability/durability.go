@@ -0,0 +1,171 @@
+package durability
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Repaired is the condition a repair brings an item back to. Only new gear
+// is pristine.
+const Repaired = "good"
+
+// RepairCostPercent is the share of an item's value a smith charges for each
+// step of repair, and RepairHoursPerStep the game time each step takes
+const (
+	RepairCostPercent  = 10
+	RepairHoursPerStep = 8
+)
+
+var (
+	ErrUnknownCondition = errors.New("unknown item condition")
+	ErrUnknownEvent     = errors.New("unknown wear event")
+	ErrNotAffected      = errors.New("that doesn't harm this item")
+	ErrNothingToRepair  = errors.New("nothing to repair")
+	ErrBroken           = errors.New("item is broken")
+	ErrCannotAfford     = errors.New("not enough coin for the repair")
+)
+
+// IsRuleError reports whether err came from the condition rules rather
+// than from reading or writing the inventory
+func IsRuleError(err error) bool {
+	for _, ruleErr := range []error{ErrUnknownCondition, ErrUnknownEvent, ErrNotAffected, ErrNothingToRepair, ErrBroken, ErrCannotAfford} {
+		if errors.Is(err, ruleErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Condition is one step on the condition scale
+type Condition struct {
+	Name              string
+	Rank              int64 // 0 is best
+	ValuePercent      int64 // Share of full value the item fetches
+	ArmorClassPenalty int64 // Points of protection lost by armour and shields
+	Usable            bool
+}
+
+// SaleValue is what an item of the given full value fetches in this
+// condition
+func (c Condition) SaleValue(value float64) float64 {
+	return value * float64(c.ValuePercent) / 100
+}
+
+// Protection is how much an armour or shield value still counts for in this
+// condition: nothing once broken, less once damaged
+func (c Condition) Protection(bonus int64) int64 {
+	if !c.Usable {
+		return 0
+	}
+	if bonus -= c.ArmorClassPenalty; bonus < 0 {
+		return 0
+	}
+	return bonus
+}
+
+// Event is something that knocks items down the condition scale
+type Event struct {
+	Name      string
+	Label     string
+	Steps     int64
+	ItemTypes []string // Empty when any item can be struck
+}
+
+// Affects reports whether the event can harm an item of the given type
+func (e Event) Affects(itemType string) bool {
+	if len(e.ItemTypes) == 0 {
+		return true
+	}
+	for _, t := range e.ItemTypes {
+		if t == itemType {
+			return true
+		}
+	}
+	return false
+}
+
+// Scale is the ordered list of conditions
+type Scale struct {
+	conditions []Condition
+	byName     map[string]int
+}
+
+// NewScale builds a Scale from conditions listed best first
+func NewScale(conditions []Condition) *Scale {
+	s := &Scale{conditions: conditions, byName: make(map[string]int, len(conditions))}
+	for i, c := range conditions {
+		s.byName[c.Name] = i
+	}
+	return s
+}
+
+// Get returns the named condition
+func (s *Scale) Get(name string) (Condition, error) {
+	i, ok := s.byName[name]
+	if !ok {
+		return Condition{}, fmt.Errorf("%w: %q", ErrUnknownCondition, name)
+	}
+	return s.conditions[i], nil
+}
+
+// Conditions lists the scale, best first
+func (s *Scale) Conditions() []Condition {
+	return s.conditions
+}
+
+// Degrade works out the condition an item is left in after an event. Items
+// already broken stay broken.
+func (s *Scale) Degrade(current string, event Event, itemType string) (Condition, error) {
+	i, ok := s.byName[current]
+	if !ok {
+		return Condition{}, fmt.Errorf("%w: %q", ErrUnknownCondition, current)
+	}
+	if !event.Affects(itemType) {
+		return Condition{}, fmt.Errorf("%w: %s only strikes %s", ErrNotAffected, strings.ToLower(event.Label), strings.ReplaceAll(strings.Join(event.ItemTypes, " or "), "_", " "))
+	}
+	i += int(event.Steps)
+	if i >= len(s.conditions) {
+		i = len(s.conditions) - 1
+	}
+	return s.conditions[i], nil
+}
+
+// Repair is the work needed to bring an item back to good condition
+type Repair struct {
+	From  Condition
+	To    Condition
+	Steps int64
+	Cost  int64 // Gold pieces
+	Hours int64
+}
+
+// PlanRepair works out the cost and time of repairing an item of the given
+// full value. Each step costs a tenth of its value, at least 1 gp.
+func (s *Scale) PlanRepair(current string, value float64) (Repair, error) {
+	from, err := s.Get(current)
+	if err != nil {
+		return Repair{}, err
+	}
+	to, err := s.Get(Repaired)
+	if err != nil {
+		return Repair{}, err
+	}
+	if from.Rank <= to.Rank {
+		return Repair{}, fmt.Errorf("%w: it is in %s condition", ErrNothingToRepair, from.Name)
+	}
+
+	steps := int64(s.byName[from.Name] - s.byName[to.Name])
+	perStep := int64(math.Ceil(value * RepairCostPercent / 100))
+	if perStep < 1 {
+		perStep = 1
+	}
+	return Repair{
+		From:  from,
+		To:    to,
+		Steps: steps,
+		Cost:  perStep * steps,
+		Hours: RepairHoursPerStep * steps,
+	}, nil
+}
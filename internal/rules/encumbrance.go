@@ -1,5 +1,20 @@
 package rules
 
+// BaseMovementRate is an unarmoured character's movement in feet per round
+const BaseMovementRate = 40
+
+// EncumbranceMovementPenalty is how many feet per round an encumbrance
+// level takes off movement
+func EncumbranceMovementPenalty(level string) int64 {
+	switch level {
+	case "Encumbered":
+		return 10
+	case "Heavy", "Over":
+		return 20
+	}
+	return 0
+}
+
 type EncumbranceThresholds struct {
 	Score               int64 `json:"score"`
 	BaseEncumbered      int   `json:"base_encumbered"`       // -10 MV, -1 AC
@@ -0,0 +1,206 @@
+package equipment
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// HandGroup is the slot group a two-handed item fills both of
+const HandGroup = "hand"
+
+var (
+	ErrUnknownSlot  = errors.New("no such equipment slot")
+	ErrNotWearable  = errors.New("item can't be equipped")
+	ErrWrongSlot    = errors.New("item doesn't fit that slot")
+	ErrSlotOccupied = errors.New("slot is taken")
+	ErrCursed       = errors.New("a curse holds it fast")
+)
+
+// IsRuleError reports whether err came from breaking an equipment rule
+// rather than from reading or writing the inventory
+func IsRuleError(err error) bool {
+	for _, ruleErr := range []error{ErrUnknownSlot, ErrNotWearable, ErrWrongSlot, ErrSlotOccupied, ErrCursed} {
+		if errors.Is(err, ruleErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Slot is one place on the body an item can be equipped
+type Slot struct {
+	ID    int64
+	Name  string
+	Group string
+}
+
+// Label is the slot name as players read it, e.g. "right ring 1"
+func (s Slot) Label() string {
+	return strings.ReplaceAll(s.Name, "_", " ")
+}
+
+// Item is an owned item with the slot groups it may be equipped to
+type Item struct {
+	ID         int64
+	Name       string
+	SlotID     int64 // 0 when not equipped
+	TwoHanded  bool
+	SlotGroups []string
+	Cursed     bool // Won't come off once equipped
+}
+
+// Loadout is a character's equipment slots and items, used to check where an
+// item may go and what would have to come off first
+type Loadout struct {
+	slots  []Slot
+	byID   map[int64]Slot
+	items  map[int64]Item
+	inSlot map[int64]int64
+}
+
+// NewLoadout builds a Loadout from the slots and a character's items
+func NewLoadout(slots []Slot, items []Item) *Loadout {
+	l := &Loadout{
+		slots:  slots,
+		byID:   make(map[int64]Slot, len(slots)),
+		items:  make(map[int64]Item, len(items)),
+		inSlot: make(map[int64]int64),
+	}
+	for _, s := range slots {
+		l.byID[s.ID] = s
+	}
+	for _, it := range items {
+		l.items[it.ID] = it
+		if it.SlotID != 0 {
+			l.inSlot[it.SlotID] = it.ID
+		}
+	}
+	return l
+}
+
+// Item returns the item with the given ID
+func (l *Loadout) Item(id int64) (Item, bool) {
+	it, ok := l.items[id]
+	return it, ok
+}
+
+// Slot returns the slot with the given ID
+func (l *Loadout) Slot(id int64) (Slot, bool) {
+	s, ok := l.byID[id]
+	return s, ok
+}
+
+// CheckFits reports whether item may go in the slot at all, ignoring what is
+// already equipped
+func (l *Loadout) CheckFits(item Item, slotID int64) error {
+	slot, ok := l.byID[slotID]
+	if !ok {
+		return ErrUnknownSlot
+	}
+	if len(item.SlotGroups) == 0 {
+		return fmt.Errorf("%w: %s isn't worn or wielded", ErrNotWearable, item.Name)
+	}
+	for _, group := range item.SlotGroups {
+		if group == slot.Group {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s goes on %s, not %s", ErrWrongSlot, item.Name, strings.Join(item.SlotGroups, " or "), slot.Label())
+}
+
+// Blockers lists the items that must come off before item can go in the
+// slot: whatever is in the slot, the other hand's item when equipping
+// something two-handed, and a two-handed item held in the other hand
+func (l *Loadout) Blockers(item Item, slotID int64) []Item {
+	slot := l.byID[slotID]
+	seen := map[int64]bool{item.ID: true}
+	var blockers []Item
+	add := func(id int64) {
+		if id == 0 || seen[id] {
+			return
+		}
+		seen[id] = true
+		blockers = append(blockers, l.items[id])
+	}
+
+	add(l.inSlot[slotID])
+	if slot.Group == HandGroup {
+		for _, other := range l.slots {
+			if other.Group != HandGroup || other.ID == slotID {
+				continue
+			}
+			held := l.inSlot[other.ID]
+			if item.TwoHanded || l.items[held].TwoHanded {
+				add(held)
+			}
+		}
+	}
+
+	sort.Slice(blockers, func(i, j int) bool { return blockers[i].ID < blockers[j].ID })
+	return blockers
+}
+
+// CheckEquip applies both the slot and occupancy rules, for places that
+// can't offer to swap
+func (l *Loadout) CheckEquip(item Item, slotID int64) error {
+	if err := l.CheckFits(item, slotID); err != nil {
+		return err
+	}
+	if err := l.CheckStuck(item, slotID); err != nil {
+		return err
+	}
+	if blockers := l.Blockers(item, slotID); len(blockers) > 0 {
+		return fmt.Errorf("%w: unequip %s first", ErrSlotOccupied, Names(blockers))
+	}
+	return nil
+}
+
+// CheckRelease reports whether an item may leave its slot. A cursed item
+// stays equipped until the curse is removed.
+func CheckRelease(item Item) error {
+	if item.Cursed && item.SlotID != 0 {
+		return fmt.Errorf("%w: %s won't come off", ErrCursed, item.Name)
+	}
+	return nil
+}
+
+// CheckStuck reports whether a curse keeps item, or anything in its way, from
+// moving so item can go in the slot
+func (l *Loadout) CheckStuck(item Item, slotID int64) error {
+	if item.SlotID != slotID {
+		if err := CheckRelease(item); err != nil {
+			return err
+		}
+	}
+	for _, blocker := range l.Blockers(item, slotID) {
+		if err := CheckRelease(blocker); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SlotsFor lists the slots item may be equipped to
+func (l *Loadout) SlotsFor(item Item) []Slot {
+	var slots []Slot
+	for _, s := range l.slots {
+		if l.CheckFits(item, s.ID) == nil {
+			slots = append(slots, s)
+		}
+	}
+	return slots
+}
+
+// Names joins item names for a message, e.g. "Dagger and Shield, Small"
+func Names(items []Item) string {
+	names := make([]string, len(items))
+	for i, it := range items {
+		names[i] = it.Name
+	}
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
@@ -0,0 +1,91 @@
+package magic
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Ways a curse can be lifted
+const (
+	UncurseBySpell   = "spell"
+	UncurseByReferee = "referee"
+)
+
+var (
+	ErrNotCursed  = errors.New("that item is not cursed")
+	ErrBadCurse   = errors.New("invalid curse")
+	ErrNoUncurse  = errors.New("unknown way to remove a curse")
+	ErrCurseOwner = errors.New("only the referee can lift a curse by fiat")
+)
+
+var abilities = map[string]bool{
+	"strength":     true,
+	"dexterity":    true,
+	"constitution": true,
+	"intelligence": true,
+	"wisdom":       true,
+	"charisma":     true,
+}
+
+// Curse is what a curse does to whoever has the item equipped, beyond any
+// negative enhancement bonus. A curse with no target only keeps the item on.
+type Curse struct {
+	Target   string // armor_class, saving_throw, movement or ability
+	Ability  string
+	Modifier int64 // Zero or less
+	Notes    string
+}
+
+// Check validates a curse's penalty
+func (c Curse) Check() error {
+	switch c.Target {
+	case "":
+		if c.Modifier != 0 {
+			return fmt.Errorf("%w: a penalty needs something to apply to", ErrBadCurse)
+		}
+		return nil
+	case TargetArmorClass, TargetSavingThrow, TargetMovement:
+	case TargetAbility:
+		if !abilities[c.Ability] {
+			return fmt.Errorf("%w: an ability penalty needs an ability", ErrBadCurse)
+		}
+	default:
+		return fmt.Errorf("%w: %q can't be cursed", ErrBadCurse, c.Target)
+	}
+	if c.Modifier >= 0 {
+		return fmt.Errorf("%w: the penalty must be below zero", ErrBadCurse)
+	}
+	return nil
+}
+
+// Label describes the curse's penalty, e.g. "-2 AC"
+func (c Curse) Label() string {
+	if c.Target == "" {
+		return "can't be removed"
+	}
+	return BonusLabel(c.Target, c.Ability, c.Modifier)
+}
+
+// RemoveCurse checks a curse can be lifted the given way
+func RemoveCurse(cursed bool, method string) error {
+	switch method {
+	case UncurseBySpell, UncurseByReferee:
+	default:
+		return fmt.Errorf("%w: %q", ErrNoUncurse, method)
+	}
+	if !cursed {
+		return ErrNotCursed
+	}
+	return nil
+}
+
+// UncurseLabel describes how a curse was lifted
+func UncurseLabel(method string) string {
+	switch method {
+	case UncurseBySpell:
+		return "lifted by a cleric's remove curse"
+	case UncurseByReferee:
+		return "lifted by the referee"
+	}
+	return ""
+}
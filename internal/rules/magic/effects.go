@@ -0,0 +1,161 @@
+package magic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/marbh56/mordezzan/internal/rules/rest"
+)
+
+// Effect types stored in magical_item_effects
+const (
+	EffectHeal            = "heal"
+	EffectCastSpell       = "cast_spell"
+	EffectCondition       = "condition"
+	EffectAbilityModifier = "ability_modifier"
+	EffectPassiveBonus    = "passive_bonus"
+)
+
+// What a passive bonus improves
+const (
+	TargetArmorClass  = "armor_class"
+	TargetSavingThrow = "saving_throw"
+	TargetMovement    = "movement"
+	TargetAbility     = "ability"
+)
+
+var (
+	ErrNoCharges   = errors.New("this item has no charges remaining")
+	ErrPassiveOnly = errors.New("this item works while equipped")
+)
+
+// IsRuleError reports whether err came from the rules for using,
+// identifying or uncursing an item rather than from reading or writing the
+// character
+func IsRuleError(err error) bool {
+	for _, ruleErr := range []error{ErrNoCharges, ErrPassiveOnly, ErrAlreadyIdentified, ErrUnknownMethod, ErrCannotAfford, ErrRefereeOnly,
+		ErrNotCursed, ErrBadCurse, ErrNoUncurse, ErrCurseOwner} {
+		if errors.Is(err, ruleErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Effect is one thing a magical item does
+type Effect struct {
+	Type          string
+	Dice          string // Healing, e.g. "2d4+2"
+	SpellName     string
+	SpellRange    string
+	SpellDuration string
+	ConditionName string
+	Ability       string // strength, dexterity and so on
+	BonusTarget   string // armor_class, saving_throw, movement or ability
+	Modifier      int64
+	DurationHours int64
+	Timed         bool // False when the effect lasts until removed
+}
+
+// Usable reports whether using the item does anything, as opposed to an
+// item whose effects are all passive
+func Usable(effects []Effect) bool {
+	for _, e := range effects {
+		if e.Type != EffectPassiveBonus {
+			return true
+		}
+	}
+	return len(effects) == 0
+}
+
+// ExpiresAt is the game hour a lasting effect started at now ends, if it
+// ends at all
+func (e Effect) ExpiresAt(now int64) (int64, bool) {
+	if !e.Timed {
+		return 0, false
+	}
+	return now + e.DurationHours, true
+}
+
+// Heal rolls a healing effect and returns the new hit points and how many
+// were restored; hit points never go above maximum
+func Heal(dice string, current, max int64, roll func(sides int) int) (newHP, healed int64, err error) {
+	healing, err := rest.EvaluateHealing(dice, 0, 0, roll)
+	if err != nil {
+		return current, 0, err
+	}
+	newHP = current + healing.Amount
+	if healing.Full || newHP > max {
+		newHP = max
+	}
+	if newHP < current {
+		newHP = current
+	}
+	return newHP, newHP - current, nil
+}
+
+// Bonuses are the passive bonuses from equipped items. AC and saving throw
+// bonuses improve the character, so they are subtracted from descending AC
+// and save targets.
+type Bonuses struct {
+	ArmorClass  int64
+	SavingThrow int64
+	Movement    int64            // Feet per round
+	Abilities   map[string]int64 // By ability name
+}
+
+// Add counts a passive effect. Ability is only used for ability bonuses.
+func (b *Bonuses) Add(target, ability string, modifier int64) {
+	switch target {
+	case TargetArmorClass:
+		b.ArmorClass += modifier
+	case TargetSavingThrow:
+		b.SavingThrow += modifier
+	case TargetMovement:
+		b.Movement += modifier
+	case TargetAbility:
+		if b.Abilities == nil {
+			b.Abilities = make(map[string]int64)
+		}
+		b.Abilities[ability] += modifier
+	}
+}
+
+// BonusLabel describes a passive bonus, e.g. "+1 AC"
+func BonusLabel(target, ability string, modifier int64) string {
+	switch target {
+	case TargetArmorClass:
+		return fmt.Sprintf("%+d AC", modifier)
+	case TargetSavingThrow:
+		return fmt.Sprintf("%+d saving throws", modifier)
+	case TargetMovement:
+		return fmt.Sprintf("%+d ft movement", modifier)
+	case TargetAbility:
+		return AbilityLabel(ability, modifier)
+	}
+	return ""
+}
+
+// AbilityLabel names an ability score modifier, e.g. "Strength +4"
+func AbilityLabel(ability string, modifier int64) string {
+	if ability == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s%s %+d", strings.ToUpper(ability[:1]), ability[1:], modifier)
+}
+
+// SpellLabel describes a spell cast from an item
+func SpellLabel(e Effect) string {
+	var details []string
+	if e.SpellRange != "" {
+		details = append(details, "range "+e.SpellRange)
+	}
+	if e.SpellDuration != "" {
+		details = append(details, "duration "+e.SpellDuration)
+	}
+	if len(details) == 0 {
+		return e.SpellName
+	}
+	return fmt.Sprintf("%s (%s)", e.SpellName, strings.Join(details, ", "))
+}
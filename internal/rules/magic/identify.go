@@ -0,0 +1,85 @@
+package magic
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Ways an item can be identified
+const (
+	IdentifyBySpell   = "spell"
+	IdentifyBySage    = "sage"
+	IdentifyByUse     = "use"
+	IdentifyByReferee = "referee"
+)
+
+// DefaultSageFee is what a sage charges in gold pieces to study an item
+const DefaultSageFee = 100
+
+var (
+	ErrAlreadyIdentified = errors.New("that item is already identified")
+	ErrUnknownMethod     = errors.New("unknown way to identify an item")
+	ErrCannotAfford      = errors.New("not enough coin for the sage's fee")
+	ErrRefereeOnly       = errors.New("only the referee can reveal an item")
+)
+
+// Identity is what an owned item really is and what it looks like
+type Identity struct {
+	Name        string
+	Appearance  string // Empty when it looks like what it is
+	ItemType    string
+	Enhancement int64
+	Properties  int
+	Cursed      bool
+	Identified  bool
+}
+
+// Concealed reports whether a newly found item has anything to hide: a
+// magical item, a bonus, a named property or a curse. Mundane gear is known
+// at once.
+func (i Identity) Concealed() bool {
+	return i.ItemType == "magical_item" || i.Enhancement != 0 || i.Properties > 0 || i.Cursed
+}
+
+// Shown is the name an item goes by. Unidentified items show their
+// appearance, or their catalog name without any bonus.
+func (i Identity) Shown() string {
+	if i.Identified {
+		if i.Enhancement != 0 {
+			return fmt.Sprintf("%s %+d", i.Name, i.Enhancement)
+		}
+		return i.Name
+	}
+	if i.Appearance != "" {
+		return i.Appearance
+	}
+	return i.Name
+}
+
+// Identify checks an item can be identified the given way
+func Identify(identity Identity, method string) error {
+	switch method {
+	case IdentifyBySpell, IdentifyBySage, IdentifyByUse, IdentifyByReferee:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownMethod, method)
+	}
+	if identity.Identified {
+		return ErrAlreadyIdentified
+	}
+	return nil
+}
+
+// MethodLabel describes how an item was identified
+func MethodLabel(method string) string {
+	switch method {
+	case IdentifyBySpell:
+		return "identified by spell"
+	case IdentifyBySage:
+		return "identified by a sage"
+	case IdentifyByUse:
+		return "identified by trial and error"
+	case IdentifyByReferee:
+		return "revealed by the referee"
+	}
+	return ""
+}
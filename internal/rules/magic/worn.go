@@ -0,0 +1,60 @@
+package magic
+
+import "fmt"
+
+// Worn is an equipped magical item
+type Worn struct {
+	InventoryID int64
+	Name        string
+	Category    string
+	SlotGroup   string // Group of the slot it is in
+	WornIn      string // Group its category is worn in; empty when it isn't worn
+	Limit       int64  // How many of its category function at once; 0 for no cap
+	Limited     bool
+}
+
+// Idle is a worn item that gives no benefit, and why
+type Idle struct {
+	Worn
+	Reason string
+}
+
+// Functioning works out which worn items give their passive bonuses. An
+// item only works in the slot its category is worn in, and where a
+// category caps how many function at once, the first ones put on work.
+func Functioning(worn []Worn) (working map[int64]bool, idle []Idle) {
+	working = make(map[int64]bool, len(worn))
+	counted := make(map[string]int64)
+	for _, w := range worn {
+		if w.WornIn != "" && w.SlotGroup != w.WornIn {
+			idle = append(idle, Idle{Worn: w, Reason: fmt.Sprintf("must be worn on %s", slotGroupLabel(w.WornIn))})
+			continue
+		}
+		if w.Limited {
+			if counted[w.Category] >= w.Limit {
+				idle = append(idle, Idle{Worn: w, Reason: fmt.Sprintf("only %d %s function at once", w.Limit, plural(w.Category, w.Limit))})
+				continue
+			}
+			counted[w.Category]++
+		}
+		working[w.InventoryID] = true
+	}
+	return working, idle
+}
+
+func slotGroupLabel(group string) string {
+	switch group {
+	case "ring":
+		return "a finger"
+	case "hand":
+		return "a hand"
+	}
+	return "the " + group
+}
+
+func plural(category string, n int64) string {
+	if n == 1 {
+		return category
+	}
+	return category + "s"
+}
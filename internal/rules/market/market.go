@@ -0,0 +1,109 @@
+package market
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/marbh56/mordezzan/internal/currency"
+)
+
+// Prices where no price list applies: catalog value to buy, half of it to
+// sell, and a tenth of a gem or jewel's worth kept when buying one
+const (
+	DefaultMarkupPercent      = 100
+	DefaultSellPercent        = 50
+	DefaultTreasureFeePercent = 10
+)
+
+var (
+	ErrUnavailable   = errors.New("not for sale here")
+	ErrEmptyCart     = errors.New("the cart is empty")
+	ErrBadQuantity   = errors.New("invalid quantity")
+	ErrCannotAfford  = errors.New("not enough coin")
+	ErrUnidentified  = errors.New("merchants won't buy what nobody can name")
+	ErrNotEmpty      = errors.New("empty it before selling")
+	ErrWorthless     = errors.New("no merchant will pay for it")
+	ErrBadPriceList  = errors.New("invalid price list")
+	ErrWrongCampaign = errors.New("that price list belongs to another campaign")
+)
+
+// IsRuleError reports whether err came from the market rules rather than
+// from reading or writing the purse and inventory
+func IsRuleError(err error) bool {
+	for _, ruleErr := range []error{ErrUnavailable, ErrEmptyCart, ErrBadQuantity, ErrCannotAfford, ErrUnidentified, ErrNotEmpty, ErrWorthless, ErrBadPriceList, ErrWrongCampaign} {
+		if errors.Is(err, ruleErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Price is what one of an item of the given catalog value, in gold pieces,
+// costs at the given markup, in copper pieces rounded to the nearest copper
+func Price(value float64, markupPercent int64) int64 {
+	return (currency.FromGold(value)*markupPercent + 50) / 100
+}
+
+// SalePrice is what a merchant pays for one of an item of the given catalog
+// value in a condition fetching conditionPercent of it, in copper pieces
+// rounded down
+func SalePrice(value float64, conditionPercent, sellPercent int64) int64 {
+	return currency.FromGold(value) * conditionPercent * sellPercent / 10000
+}
+
+// TreasurePrice is what a merchant pays in coin for one gem, piece of
+// jewellery or art object of the given true worth, in gold pieces, less the
+// fee, in copper pieces rounded down. Condition doesn't come into it.
+func TreasurePrice(value float64, feePercent int64) int64 {
+	return currency.FromGold(value) * (100 - feePercent) / 100
+}
+
+// Line is one catalog item in a cart
+type Line struct {
+	ItemID    int64
+	Name      string
+	Quantity  int64
+	UnitPrice int64 // Copper pieces
+	Available bool
+}
+
+// Total is the price of the whole line in copper pieces
+func (l Line) Total() int64 {
+	return l.UnitPrice * l.Quantity
+}
+
+// Checkout prices a cart, refusing it when it is empty or holds anything
+// not for sale
+func Checkout(lines []Line) (int64, error) {
+	if len(lines) == 0 {
+		return 0, ErrEmptyCart
+	}
+	var total int64
+	for _, line := range lines {
+		if !line.Available {
+			return 0, fmt.Errorf("%w: %s", ErrUnavailable, line.Name)
+		}
+		if line.Quantity <= 0 {
+			return 0, fmt.Errorf("%w: %d %s", ErrBadQuantity, line.Quantity, line.Name)
+		}
+		total += line.Total()
+	}
+	return total, nil
+}
+
+// CheckPriceList validates a price list's percentages
+func CheckPriceList(settlement string, markupPercent, sellPercent, treasureFeePercent int64) error {
+	if settlement == "" {
+		return fmt.Errorf("%w: it needs a settlement", ErrBadPriceList)
+	}
+	if markupPercent <= 0 {
+		return fmt.Errorf("%w: markup must be above 0%%", ErrBadPriceList)
+	}
+	if sellPercent < 0 || sellPercent > 100 {
+		return fmt.Errorf("%w: merchants pay between 0%% and 100%%", ErrBadPriceList)
+	}
+	if treasureFeePercent < 0 || treasureFeePercent > 100 {
+		return fmt.Errorf("%w: the fee on treasure is between 0%% and 100%%", ErrBadPriceList)
+	}
+	return nil
+}
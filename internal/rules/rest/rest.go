@@ -0,0 +1,119 @@
+package rest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HoursPerDay is used when formatting the game clock
+const HoursPerDay = 24
+
+// Healing is the result of evaluating a healing formula
+type Healing struct {
+	Amount int64 // Hit points restored
+	Full   bool  // True when the formula heals to maximum
+}
+
+// EvaluateHealing works out the hit points a rest restores. Formulas are
+// terms joined by + or -, where each term is one or more factors joined by
+// *: a number, dice (1d3), "level" or "con" (the Constitution HP modifier).
+// The formula "max" heals fully. Healing is never negative.
+func EvaluateHealing(formula string, level int64, conMod int, roll func(sides int) int) (Healing, error) {
+	formula = strings.ToLower(strings.ReplaceAll(formula, " ", ""))
+	if formula == "" {
+		return Healing{}, fmt.Errorf("empty healing formula")
+	}
+	if formula == "max" {
+		return Healing{Full: true}, nil
+	}
+
+	// Split into signed terms
+	var total int64
+	sign := int64(1)
+	start := 0
+	for i := 0; i <= len(formula); i++ {
+		if i < len(formula) && formula[i] != '+' && formula[i] != '-' {
+			continue
+		}
+		term := formula[start:i]
+		if term == "" {
+			if i == 0 && i < len(formula) && formula[i] == '-' {
+				sign = -1
+				start = i + 1
+				continue
+			}
+			return Healing{}, fmt.Errorf("invalid healing formula %q", formula)
+		}
+
+		value, err := evaluateTerm(term, level, conMod, roll)
+		if err != nil {
+			return Healing{}, err
+		}
+		total += sign * value
+
+		if i < len(formula) && formula[i] == '-' {
+			sign = -1
+		} else {
+			sign = 1
+		}
+		start = i + 1
+	}
+
+	if total < 0 {
+		total = 0
+	}
+	return Healing{Amount: total}, nil
+}
+
+func evaluateTerm(term string, level int64, conMod int, roll func(sides int) int) (int64, error) {
+	product := int64(1)
+	for _, factor := range strings.Split(term, "*") {
+		value, err := evaluateFactor(factor, level, conMod, roll)
+		if err != nil {
+			return 0, err
+		}
+		product *= value
+	}
+	return product, nil
+}
+
+func evaluateFactor(factor string, level int64, conMod int, roll func(sides int) int) (int64, error) {
+	switch factor {
+	case "level":
+		return level, nil
+	case "con":
+		return int64(conMod), nil
+	}
+
+	if count, sides, ok := strings.Cut(factor, "d"); ok {
+		n := 1
+		if count != "" {
+			var err error
+			n, err = strconv.Atoi(count)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid dice %q", factor)
+			}
+		}
+		size, err := strconv.Atoi(sides)
+		if err != nil || size < 1 {
+			return 0, fmt.Errorf("invalid dice %q", factor)
+		}
+		var total int64
+		for i := 0; i < n; i++ {
+			total += int64(roll(size))
+		}
+		return total, nil
+	}
+
+	value, err := strconv.ParseInt(factor, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid healing term %q", factor)
+	}
+	return value, nil
+}
+
+// FormatGameTime renders elapsed hours as a campaign day and hour
+func FormatGameTime(elapsedHours int64) string {
+	return fmt.Sprintf("Day %d, %02d:00", elapsedHours/HoursPerDay+1, elapsedHours%HoursPerDay)
+}
@@ -0,0 +1,64 @@
+package stash
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Actions recorded in the stash log
+const (
+	ActionDeposit = "deposit"
+	ActionClaim   = "claim"
+	ActionSplit   = "split"
+)
+
+var (
+	ErrNothingDeposited = errors.New("deposit an item or some coins")
+	ErrBadQuantity      = errors.New("quantity must be at least 1")
+	ErrNotEnough        = errors.New("not enough in the stash")
+	ErrNotInParty       = errors.New("only a character in the campaign can claim from the stash")
+)
+
+// IsRuleError reports whether err came from breaking a stash rule rather
+// than from reading or writing the stash
+func IsRuleError(err error) bool {
+	for _, ruleErr := range []error{ErrNothingDeposited, ErrBadQuantity, ErrNotEnough, ErrNotInParty} {
+		if errors.Is(err, ruleErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckDeposit validates a deposit of quantity of an item, when there is
+// one, and coins in all
+func CheckDeposit(hasItem bool, quantity, coins int64) error {
+	if !hasItem && coins == 0 {
+		return ErrNothingDeposited
+	}
+	if hasItem && quantity < 1 {
+		return ErrBadQuantity
+	}
+	return nil
+}
+
+// CheckTake refuses to take more of something, an item or a denomination of
+// coin, than the stash holds
+func CheckTake(name string, held, want int64) error {
+	if want < 1 {
+		return ErrBadQuantity
+	}
+	if want > held {
+		return fmt.Errorf("%w: only %d %s there", ErrNotEnough, held, name)
+	}
+	return nil
+}
+
+// CheckClaimant requires the claiming character to be in the stash's
+// campaign. Campaign IDs are 0 for a character outside any campaign.
+func CheckClaimant(stashCampaign, characterCampaign int64) error {
+	if characterCampaign == 0 || characterCampaign != stashCampaign {
+		return ErrNotInParty
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kinds of storage location
+const (
+	KindMount = "mount"
+	KindCamp  = "camp"
+	KindHome  = "home"
+	KindBank  = "bank"
+)
+
+// Kind is a kind of storage location and how players read it
+type Kind struct {
+	Name  string
+	Label string
+}
+
+// Kinds lists the kinds of storage location, most portable first
+var Kinds = []Kind{
+	{KindMount, "Mount"},
+	{KindCamp, "Camp cache"},
+	{KindHome, "Home or rented room"},
+	{KindBank, "Bank vault"},
+}
+
+var (
+	ErrUnknownKind    = errors.New("unknown kind of storage location")
+	ErrNoName         = errors.New("storage location needs a name")
+	ErrNotYours       = errors.New("that storage location isn't this character's")
+	ErrNotEmpty       = errors.New("storage location isn't empty")
+	ErrBadAmount      = errors.New("amount must be at least 1")
+	ErrNotEnoughCoins = errors.New("not enough coins")
+)
+
+// IsRuleError reports whether err came from breaking a storage rule rather
+// than from reading or writing the inventory
+func IsRuleError(err error) bool {
+	for _, ruleErr := range []error{ErrUnknownKind, ErrNoName, ErrNotYours, ErrNotEmpty, ErrBadAmount, ErrNotEnoughCoins} {
+		if errors.Is(err, ruleErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// KindLabel is the label of a kind of storage location, or the kind itself
+// when it isn't known
+func KindLabel(kind string) string {
+	for _, k := range Kinds {
+		if k.Name == kind {
+			return k.Label
+		}
+	}
+	return kind
+}
+
+// CheckLocation validates a new storage location
+func CheckLocation(name, kind string) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrNoName
+	}
+	for _, k := range Kinds {
+		if k.Name == kind {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %q", ErrUnknownKind, kind)
+}
+
+// CheckEmpty refuses to remove a location that still holds items or coins
+func CheckEmpty(name string, contents int64) error {
+	if contents > 0 {
+		return fmt.Errorf("%w: take everything out of %s first", ErrNotEmpty, name)
+	}
+	return nil
+}
+
+// CheckCoins validates moving amount coins out of somewhere holding held
+func CheckCoins(held, amount int64, denomination string) error {
+	if amount < 1 {
+		return ErrBadAmount
+	}
+	if amount > held {
+		return fmt.Errorf("%w: only %d %s there", ErrNotEnoughCoins, held, denomination)
+	}
+	return nil
+}
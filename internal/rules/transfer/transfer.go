@@ -0,0 +1,77 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Statuses a transfer moves through. Only a pending transfer can change.
+const (
+	StatusPending   = "pending"
+	StatusAccepted  = "accepted"
+	StatusDeclined  = "declined"
+	StatusCancelled = "cancelled"
+)
+
+var (
+	ErrSameCharacter  = errors.New("a character can't give things to themselves")
+	ErrNotInCampaign  = errors.New("both characters must be in the same campaign")
+	ErrNothingOffered = errors.New("offer an item or some coins")
+	ErrBadQuantity    = errors.New("quantity must be at least 1")
+	ErrNotHeld        = errors.New("not enough to give")
+	ErrNotPending     = errors.New("that transfer has already been settled")
+	ErrNotRecipient   = errors.New("only the receiving character's player can accept or decline")
+	ErrNotSender      = errors.New("only the giving character can withdraw an offer")
+)
+
+// IsRuleError reports whether err came from breaking a transfer rule rather
+// than from reading or writing the inventory
+func IsRuleError(err error) bool {
+	for _, ruleErr := range []error{ErrSameCharacter, ErrNotInCampaign, ErrNothingOffered, ErrBadQuantity, ErrNotHeld, ErrNotPending, ErrNotRecipient, ErrNotSender} {
+		if errors.Is(err, ruleErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckParties validates who is giving to whom. Campaign IDs are 0 for a
+// character outside any campaign.
+func CheckParties(fromID, toID, fromCampaign, toCampaign int64) error {
+	if fromID == toID {
+		return ErrSameCharacter
+	}
+	if fromCampaign == 0 || fromCampaign != toCampaign {
+		return ErrNotInCampaign
+	}
+	return nil
+}
+
+// CheckOffer validates what is offered: quantity of an item, when there is
+// one, and how many coins in all
+func CheckOffer(hasItem bool, quantity, coins int64) error {
+	if !hasItem && coins == 0 {
+		return ErrNothingOffered
+	}
+	if hasItem && quantity < 1 {
+		return ErrBadQuantity
+	}
+	return nil
+}
+
+// CheckHeld refuses to give more of something, an item stack or a
+// denomination of coin, than the giver holds
+func CheckHeld(name string, held, want int64) error {
+	if want > held {
+		return fmt.Errorf("%w: only %d %s held", ErrNotHeld, held, name)
+	}
+	return nil
+}
+
+// CheckPending refuses to change a transfer that is already settled
+func CheckPending(status string) error {
+	if status != StatusPending {
+		return fmt.Errorf("%w (%s)", ErrNotPending, status)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package treasure
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/marbh56/mordezzan/internal/currency"
+)
+
+// ItemType is the catalog item type of gems, jewellery and art objects
+const ItemType = "treasure"
+
+// Kinds of treasure item
+const (
+	KindGem       = "gem"
+	KindJewellery = "jewellery"
+	KindArt       = "art"
+)
+
+var (
+	ErrNotTreasure  = errors.New("only gems, jewellery and art objects are appraised")
+	ErrBadAppraisal = errors.New("invalid appraisal")
+	ErrCannotAfford = errors.New("not enough coin for the appraiser's fee")
+)
+
+// IsRuleError reports whether err came from the treasure rules rather than
+// from reading or writing the inventory
+func IsRuleError(err error) bool {
+	for _, ruleErr := range []error{ErrNotTreasure, ErrBadAppraisal, ErrCannotAfford} {
+		if errors.Is(err, ruleErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Holding is a stack of one treasure item a character holds. Its true
+// worth is the catalog value, which the character doesn't know; the
+// appraised value is what they have been told it is worth, which may be
+// more or less.
+type Holding struct {
+	Appraised   float64 // Gold pieces each
+	IsAppraised bool
+	Quantity    int64
+}
+
+// Each is what one of the stack is taken to be worth in gold pieces, and
+// whether that is known at all. Until it is appraised nobody in the party
+// can say.
+func (h Holding) Each() (float64, bool) {
+	return h.Appraised, h.IsAppraised
+}
+
+// Worth is what the whole stack is taken to be worth in copper pieces, or
+// nothing until it is appraised
+func (h Holding) Worth() int64 {
+	each, known := h.Each()
+	if !known {
+		return 0
+	}
+	return currency.FromGold(each) * h.Quantity
+}
+
+// Total is what a character's appraised treasure is taken to be worth in
+// copper pieces, and how many unappraised items it leaves out
+func Total(holdings []Holding) (worth, unappraised int64) {
+	for _, h := range holdings {
+		if _, known := h.Each(); !known {
+			unappraised += h.Quantity
+			continue
+		}
+		worth += h.Worth()
+	}
+	return worth, unappraised
+}
+
+// CheckAppraisal validates an appraised value for an owned item
+func CheckAppraisal(itemType string, value float64) error {
+	if itemType != ItemType {
+		return ErrNotTreasure
+	}
+	if value < 0 {
+		return fmt.Errorf("%w: an item can't be worth less than nothing", ErrBadAppraisal)
+	}
+	return nil
+}
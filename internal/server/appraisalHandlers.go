@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/treasure"
+	"go.uber.org/zap"
+)
+
+// HandleAppraiseItem records what a gem, piece of jewellery or art object
+// has been valued at, paying the appraiser's fee if there is one
+func (s *Server) HandleAppraiseItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(r.FormValue("item_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid item ID", zap.Error(err))
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	value, err := strconv.ParseFloat(r.FormValue("value"), 64)
+	if err != nil {
+		renderInventoryWithMessage(w, r, characterID, "Error: enter what the item is worth")
+		return
+	}
+
+	var fee int64
+	if feeStr := r.FormValue("fee"); feeStr != "" {
+		fee, err = strconv.ParseInt(feeStr, 10, 64)
+		if err != nil || fee < 0 {
+			http.Error(w, "Invalid fee", http.StatusBadRequest)
+			return
+		}
+	}
+
+	queries := db.New(s.db)
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	message, err := s.appraiseItem(r.Context(), character, itemID, value, fee)
+	if err != nil {
+		if treasure.IsRuleError(err) {
+			renderInventoryWithMessage(w, r, characterID, "Error: "+err.Error())
+			return
+		}
+		if err == sql.ErrNoRows {
+			http.Error(w, "Item not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to appraise item",
+			zap.Error(err),
+			zap.Int64("item_id", itemID),
+			zap.Int64("character_id", characterID))
+		renderInventoryWithMessage(w, r, characterID, "Error appraising item")
+		return
+	}
+
+	logger.Info("Item appraised",
+		zap.Int64("character_id", characterID),
+		zap.Int64("item_id", itemID),
+		zap.Float64("value", value))
+
+	renderInventoryWithMessage(w, r, characterID, message)
+}
+
+// appraiseItem sets an item's appraised value, paying the appraiser from
+// the character's purse when there is a fee
+func (s *Server) appraiseItem(ctx context.Context, character db.Character, itemID int64, value float64, fee int64) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	item, err := qtx.GetInventoryTreasure(ctx, db.GetInventoryTreasureParams{
+		ID:          itemID,
+		CharacterID: character.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := treasure.CheckAppraisal(item.ItemType, value); err != nil {
+		return "", err
+	}
+
+	if fee > 0 {
+		paid, err := payFromPurse(ctx, qtx, character.ID, fee, "Appraisal: "+item.Name)
+		if err != nil {
+			return "", err
+		}
+		if !paid {
+			return "", fmt.Errorf("%w: the appraiser asks %d gp", treasure.ErrCannotAfford, fee)
+		}
+	}
+
+	if err := qtx.SetItemAppraisal(ctx, db.SetItemAppraisalParams{
+		AppraisedValue: sql.NullFloat64{Float64: value, Valid: true},
+		ID:             itemID,
+		CharacterID:    character.ID,
+	}); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s appraised at %s gp each", item.Name, strconv.FormatFloat(value, 'f', -1, 64)), nil
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/marbh56/mordezzan/internal/db"
+)
+
+// getReadableCharacter returns a character the user owns or referees. The
+// second result reports whether the user may also change the character.
+func getReadableCharacter(ctx context.Context, queries *db.Queries, characterID, userID int64) (db.Character, bool, error) {
+	character, err := queries.GetCharacter(ctx, db.GetCharacterParams{
+		ID:     characterID,
+		UserID: userID,
+	})
+	if err == nil {
+		return character, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return db.Character{}, false, err
+	}
+
+	// Not the owner, so fall back to the referee of the character's campaign
+	row, err := queries.GetRefereedCharacter(ctx, db.GetRefereedCharacterParams{
+		ID:        characterID,
+		RefereeID: userID,
+	})
+	if err != nil {
+		return db.Character{}, false, err
+	}
+	return refereedCharacter(row), row.RefereeCanEdit, nil
+}
+
+// getWritableCharacter returns a character the user may change: their own,
+// or one in a campaign they referee that allows referee edits. Anything
+// else is reported as sql.ErrNoRows.
+func getWritableCharacter(ctx context.Context, queries *db.Queries, characterID, userID int64) (db.Character, error) {
+	character, canEdit, err := getReadableCharacter(ctx, queries, characterID, userID)
+	if err != nil {
+		return db.Character{}, err
+	}
+	if !canEdit {
+		return db.Character{}, sql.ErrNoRows
+	}
+	return character, nil
+}
+
+func refereedCharacter(row db.GetRefereedCharacterRow) db.Character {
+	return db.Character{
+		ID:               row.ID,
+		UserID:           row.UserID,
+		Name:             row.Name,
+		Class:            row.Class,
+		Level:            row.Level,
+		MaxHp:            row.MaxHp,
+		CurrentHp:        row.CurrentHp,
+		Strength:         row.Strength,
+		Dexterity:        row.Dexterity,
+		Constitution:     row.Constitution,
+		Intelligence:     row.Intelligence,
+		Wisdom:           row.Wisdom,
+		Charisma:         row.Charisma,
+		ExperiencePoints: row.ExperiencePoints,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        row.UpdatedAt,
+	}
+}
+
+func campaignCharacter(row db.ListCampaignCharactersRow) db.Character {
+	return db.Character{
+		ID:               row.ID,
+		UserID:           row.UserID,
+		Name:             row.Name,
+		Class:            row.Class,
+		Level:            row.Level,
+		MaxHp:            row.MaxHp,
+		CurrentHp:        row.CurrentHp,
+		Strength:         row.Strength,
+		Dexterity:        row.Dexterity,
+		Constitution:     row.Constitution,
+		Intelligence:     row.Intelligence,
+		Wisdom:           row.Wisdom,
+		Charisma:         row.Charisma,
+		ExperiencePoints: row.ExperiencePoints,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        row.UpdatedAt,
+	}
+}
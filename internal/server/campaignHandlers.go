@@ -0,0 +1,780 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marbh56/mordezzan/internal/currency"
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	charRules "github.com/marbh56/mordezzan/internal/rules/character"
+	"go.uber.org/zap"
+)
+
+// Campaign member statuses
+const (
+	CampaignMemberInvited = "invited"
+	CampaignMemberActive  = "active"
+)
+
+// PartyMember is one character on a campaign's party roster
+type PartyMember struct {
+	CharacterViewModel
+	PlayerUsername string
+}
+
+// HandleCampaignList shows the campaigns a user referees or plays in,
+// along with any pending invitations
+func (s *Server) HandleCampaignList(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		logger.Error("Unauthorized access attempt",
+			zap.String("path", r.URL.Path),
+			zap.String("method", r.Method))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	queries := db.New(s.db)
+	campaigns, err := queries.ListCampaignsForUser(r.Context(), db.ListCampaignsForUserParams{
+		RefereeID: user.UserID,
+		UserID:    user.UserID,
+	})
+	if err != nil {
+		logger.Error("Failed to fetch campaigns",
+			zap.Error(err),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	invitations, err := queries.ListCampaignInvitations(r.Context(), user.UserID)
+	if err != nil {
+		logger.Warn("Failed to fetch campaign invitations",
+			zap.Error(err),
+			zap.Int64("user_id", user.UserID))
+	}
+
+	data := struct {
+		IsAuthenticated bool
+		Username        string
+		UserID          int64
+		Campaigns       []db.ListCampaignsForUserRow
+		Invitations     []db.ListCampaignInvitationsRow
+		FlashMessage    string
+		CurrentYear     int
+	}{
+		IsAuthenticated: true,
+		Username:        user.Username,
+		UserID:          user.UserID,
+		Campaigns:       campaigns,
+		Invitations:     invitations,
+		FlashMessage:    r.URL.Query().Get("message"),
+		CurrentYear:     time.Now().Year(),
+	}
+
+	RenderTemplate(w, "templates/campaigns/list.html", "base.html", data)
+}
+
+// HandleCampaignCreate creates a campaign refereed by the current user
+func (s *Server) HandleCampaignCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.Form.Get("name"))
+	if name == "" {
+		http.Redirect(w, r, "/campaigns?message=Campaign name is required", http.StatusSeeOther)
+		return
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		logger.Error("Failed to generate invite code", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	queries := db.New(s.db)
+	campaign, err := queries.CreateCampaign(r.Context(), db.CreateCampaignParams{
+		Name:        name,
+		Description: strings.TrimSpace(r.Form.Get("description")),
+		RefereeID:   user.UserID,
+		InviteCode:  code,
+	})
+	if err != nil {
+		logger.Error("Failed to create campaign",
+			zap.Error(err),
+			zap.Int64("user_id", user.UserID))
+		http.Redirect(w, r, "/campaigns?message=Error creating campaign", http.StatusSeeOther)
+		return
+	}
+
+	logger.Info("Campaign created",
+		zap.Int64("campaign_id", campaign.ID),
+		zap.Int64("referee_id", user.UserID))
+
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Campaign created", campaign.ID), http.StatusSeeOther)
+}
+
+// HandleCampaignDetail shows a campaign's members and party roster
+func (s *Server) HandleCampaignDetail(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	campaignID, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid campaign ID",
+			zap.Error(err),
+			zap.String("raw_id", r.URL.Query().Get("id")))
+		http.Error(w, "Invalid campaign ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	campaign, isReferee, err := getCampaignForUser(r.Context(), queries, campaignID, user.UserID)
+	if err != nil {
+		logger.Error("Campaign not found or user is not a member",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Campaign not found", http.StatusNotFound)
+		return
+	}
+
+	members, err := queries.ListCampaignMembers(r.Context(), campaignID)
+	if err != nil {
+		logger.Warn("Failed to fetch campaign members",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID))
+	}
+
+	party, err := s.loadParty(r.Context(), queries, campaignID)
+	if err != nil {
+		logger.Error("Failed to fetch party roster",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	// Characters the user could still bring into the campaign
+	var available []db.Character
+	characters, err := queries.ListCharactersByUser(r.Context(), user.UserID)
+	if err != nil {
+		logger.Warn("Failed to fetch user characters",
+			zap.Error(err),
+			zap.Int64("user_id", user.UserID))
+	}
+	for _, character := range characters {
+		if _, err := queries.GetCharacterCampaign(r.Context(), character.ID); errors.Is(err, sql.ErrNoRows) {
+			available = append(available, character)
+		}
+	}
+
+	var splits []TreasureSplitEntry
+	splitRows, err := queries.ListTreasureSplitsByCampaign(r.Context(), campaignID)
+	if err != nil {
+		logger.Warn("Failed to fetch treasure splits",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID))
+	}
+	for _, split := range splitRows {
+		splits = append(splits, TreasureSplitEntry{TreasureSplit: split})
+	}
+
+	// Settlement price lists, and the catalog to stock them from
+	var priceListItems []db.ListPriceListItemsRow
+	var catalog []db.ListShopItemsRow
+	priceLists, err := queries.ListCampaignPriceLists(r.Context(), campaignID)
+	if err != nil {
+		logger.Warn("Failed to fetch price lists",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID))
+	}
+	if isReferee {
+		if priceListItems, err = queries.ListPriceListItems(r.Context(), campaignID); err != nil {
+			logger.Warn("Failed to fetch price list items",
+				zap.Error(err),
+				zap.Int64("campaign_id", campaignID))
+		}
+		if catalog, err = queries.ListShopItems(r.Context(), sql.NullInt64{}); err != nil {
+			logger.Warn("Failed to fetch catalog",
+				zap.Error(err),
+				zap.Int64("campaign_id", campaignID))
+		}
+	}
+
+	// Regional coins and trade bars used alongside the standard coins
+	coins, err := queries.ListCampaignCoins(r.Context(), sql.NullInt64{Int64: campaignID, Valid: true})
+	if err != nil {
+		logger.Warn("Failed to fetch campaign coins",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID))
+	}
+
+	data := struct {
+		IsAuthenticated     bool
+		Username            string
+		UserID              int64
+		Campaign            db.Campaign
+		IsReferee           bool
+		Members             []db.ListCampaignMembersRow
+		Party               []PartyMember
+		AvailableCharacters []db.Character
+		TreasureSplits      []TreasureSplitEntry
+		PriceLists          []db.PriceList
+		PriceListItems      []db.ListPriceListItemsRow
+		Catalog             []db.ListShopItemsRow
+		Coins               []db.Coin
+		FlashMessage        string
+		CurrentYear         int
+	}{
+		IsAuthenticated:     true,
+		Username:            user.Username,
+		UserID:              user.UserID,
+		Campaign:            campaign,
+		IsReferee:           isReferee,
+		Members:             members,
+		Party:               party,
+		AvailableCharacters: available,
+		TreasureSplits:      splits,
+		PriceLists:          priceLists,
+		PriceListItems:      priceListItems,
+		Catalog:             catalog,
+		Coins:               coins,
+		FlashMessage:        r.URL.Query().Get("message"),
+		CurrentYear:         time.Now().Year(),
+	}
+
+	RenderTemplate(w, "templates/campaigns/detail.html", "base.html", data)
+}
+
+// HandleCampaignInvite invites a player to a campaign by username
+func (s *Server) HandleCampaignInvite(w http.ResponseWriter, r *http.Request) {
+	user, campaign, ok := s.refereeCampaignFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	username := strings.TrimSpace(r.Form.Get("username"))
+	queries := db.New(s.db)
+	invitee, err := queries.GetUserByUsername(r.Context(), username)
+	if err != nil {
+		logger.Warn("Invited user not found",
+			zap.Error(err),
+			zap.String("username", username))
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Error: No user named %s", campaign.ID, username), http.StatusSeeOther)
+		return
+	}
+	if invitee.ID == user.UserID {
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Error: The referee is already part of the campaign", campaign.ID), http.StatusSeeOther)
+		return
+	}
+
+	err = queries.InviteCampaignMember(r.Context(), db.InviteCampaignMemberParams{
+		CampaignID: campaign.ID,
+		UserID:     invitee.ID,
+		InvitedBy:  sql.NullInt64{Int64: user.UserID, Valid: true},
+	})
+	if err != nil {
+		logger.Error("Failed to invite campaign member",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaign.ID),
+			zap.Int64("invitee_id", invitee.ID))
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Error inviting player", campaign.ID), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Invited %s", campaign.ID, invitee.Username), http.StatusSeeOther)
+}
+
+// HandleCampaignJoin joins a campaign using its invite code
+func (s *Server) HandleCampaignJoin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	code := strings.ToLower(strings.TrimSpace(r.Form.Get("invite_code")))
+	campaign, err := queries.GetCampaignByInviteCode(r.Context(), code)
+	if err != nil {
+		logger.Warn("Unknown invite code",
+			zap.Error(err),
+			zap.Int64("user_id", user.UserID))
+		http.Redirect(w, r, "/campaigns?message=Error: Unknown invite code", http.StatusSeeOther)
+		return
+	}
+
+	if campaign.RefereeID != user.UserID {
+		err = queries.ActivateCampaignMember(r.Context(), db.ActivateCampaignMemberParams{
+			CampaignID: campaign.ID,
+			UserID:     user.UserID,
+		})
+		if err != nil {
+			logger.Error("Failed to join campaign",
+				zap.Error(err),
+				zap.Int64("campaign_id", campaign.ID),
+				zap.Int64("user_id", user.UserID))
+			http.Redirect(w, r, "/campaigns?message=Error joining campaign", http.StatusSeeOther)
+			return
+		}
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Joined %s", campaign.ID, campaign.Name), http.StatusSeeOther)
+}
+
+// HandleCampaignAccept accepts a pending invitation
+func (s *Server) HandleCampaignAccept(w http.ResponseWriter, r *http.Request) {
+	user, campaignID, ok := campaignIDFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, err := queries.GetCampaignMember(r.Context(), db.GetCampaignMemberParams{
+		CampaignID: campaignID,
+		UserID:     user.UserID,
+	}); err != nil {
+		logger.Warn("No invitation to accept",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID),
+			zap.Int64("user_id", user.UserID))
+		http.Redirect(w, r, "/campaigns?message=Error: Invitation not found", http.StatusSeeOther)
+		return
+	}
+
+	if err := queries.ActivateCampaignMember(r.Context(), db.ActivateCampaignMemberParams{
+		CampaignID: campaignID,
+		UserID:     user.UserID,
+	}); err != nil {
+		logger.Error("Failed to accept invitation",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID))
+		http.Redirect(w, r, "/campaigns?message=Error accepting invitation", http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Invitation accepted", campaignID), http.StatusSeeOther)
+}
+
+// HandleCampaignLeave declines an invitation or leaves a campaign, taking
+// the player's characters out of the party
+func (s *Server) HandleCampaignLeave(w http.ResponseWriter, r *http.Request) {
+	user, campaignID, ok := campaignIDFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.removeCampaignMember(r.Context(), campaignID, user.UserID); err != nil {
+		logger.Error("Failed to leave campaign",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID),
+			zap.Int64("user_id", user.UserID))
+		http.Redirect(w, r, "/campaigns?message=Error leaving campaign", http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/campaigns?message=Left campaign", http.StatusSeeOther)
+}
+
+// HandleCampaignRemoveMember lets the referee remove a player
+func (s *Server) HandleCampaignRemoveMember(w http.ResponseWriter, r *http.Request) {
+	_, campaign, ok := s.refereeCampaignFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseInt(r.Form.Get("user_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid member ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("user_id")))
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.removeCampaignMember(r.Context(), campaign.ID, memberID); err != nil {
+		logger.Error("Failed to remove campaign member",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaign.ID),
+			zap.Int64("member_id", memberID))
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Error removing player", campaign.ID), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Player removed", campaign.ID), http.StatusSeeOther)
+}
+
+// HandleCampaignAddCharacter attaches one of the user's characters to the party
+func (s *Server) HandleCampaignAddCharacter(w http.ResponseWriter, r *http.Request) {
+	user, campaignID, ok := campaignIDFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.Form.Get("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("character_id")))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, _, err := getCampaignForUser(r.Context(), queries, campaignID, user.UserID); err != nil {
+		logger.Error("Campaign not found or user is not a member",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID))
+		http.Error(w, "Campaign not found", http.StatusNotFound)
+		return
+	}
+
+	// Only a character's owner can bring it into a campaign
+	if _, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
+		ID:     characterID,
+		UserID: user.UserID,
+	}); err != nil {
+		logger.Error("Character not found or doesn't belong to user",
+			zap.Error(err),
+			zap.Int64("character_id", characterID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	if _, err := queries.GetCharacterCampaign(r.Context(), characterID); err == nil {
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Error: Character is already in a campaign", campaignID), http.StatusSeeOther)
+		return
+	}
+
+	if err := queries.AddCharacterToCampaign(r.Context(), db.AddCharacterToCampaignParams{
+		CampaignID:  campaignID,
+		CharacterID: characterID,
+	}); err != nil {
+		logger.Error("Failed to add character to campaign",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID),
+			zap.Int64("character_id", characterID))
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Error adding character", campaignID), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Character added to party", campaignID), http.StatusSeeOther)
+}
+
+// HandleCampaignRemoveCharacter takes a character out of the party. Both
+// the character's owner and the referee may do this.
+func (s *Server) HandleCampaignRemoveCharacter(w http.ResponseWriter, r *http.Request) {
+	user, campaignID, ok := campaignIDFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.Form.Get("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("character_id")))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	campaign, err := queries.GetCharacterCampaign(r.Context(), characterID)
+	if err != nil || campaign.ID != campaignID {
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	if campaign.RefereeID != user.UserID {
+		if _, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
+			ID:     characterID,
+			UserID: user.UserID,
+		}); err != nil {
+			logger.Error("Character not found or doesn't belong to user",
+				zap.Error(err),
+				zap.Int64("character_id", characterID))
+			http.Error(w, "Character not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	if err := queries.RemoveCharacterFromCampaign(r.Context(), db.RemoveCharacterFromCampaignParams{
+		CampaignID:  campaignID,
+		CharacterID: characterID,
+	}); err != nil {
+		logger.Error("Failed to remove character from campaign",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID),
+			zap.Int64("character_id", characterID))
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Error removing character", campaignID), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Character removed from party", campaignID), http.StatusSeeOther)
+}
+
+// HandleCampaignSettings updates the referee's access and house rules
+func (s *Server) HandleCampaignSettings(w http.ResponseWriter, r *http.Request) {
+	user, campaign, ok := s.refereeCampaignFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	policy := r.Form.Get("drain_xp_policy")
+	if !charRules.IsValidDrainXPPolicy(policy) {
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Error: Invalid energy drain setting", campaign.ID), http.StatusSeeOther)
+		return
+	}
+
+	queries := db.New(s.db)
+	err := queries.UpdateCampaignSettings(r.Context(), db.UpdateCampaignSettingsParams{
+		RefereeCanEdit: r.Form.Get("referee_can_edit") == "1",
+		DrainXpPolicy:  policy,
+		ID:             campaign.ID,
+		RefereeID:      user.UserID,
+	})
+	if err != nil {
+		logger.Error("Failed to update campaign settings",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaign.ID))
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Error saving settings", campaign.ID), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Settings saved", campaign.ID), http.StatusSeeOther)
+}
+
+// HandleCampaignInviteCode replaces a campaign's invite code so old codes
+// stop working
+func (s *Server) HandleCampaignInviteCode(w http.ResponseWriter, r *http.Request) {
+	user, campaign, ok := s.refereeCampaignFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		logger.Error("Failed to generate invite code", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	queries := db.New(s.db)
+	if err := queries.UpdateCampaignInviteCode(r.Context(), db.UpdateCampaignInviteCodeParams{
+		InviteCode: code,
+		ID:         campaign.ID,
+		RefereeID:  user.UserID,
+	}); err != nil {
+		logger.Error("Failed to update invite code",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaign.ID))
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Error creating invite code", campaign.ID), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=New invite code created", campaign.ID), http.StatusSeeOther)
+}
+
+// loadCampaignDetails attaches the character's campaign and conditions to
+// a view model
+func (s *Server) loadCampaignDetails(ctx context.Context, queries *db.Queries, vm *CharacterViewModel) {
+	if campaign, err := queries.GetCharacterCampaign(ctx, vm.ID); err == nil {
+		vm.CampaignID = campaign.ID
+		vm.CampaignName = campaign.Name
+	}
+
+	conditions, err := queries.ListCharacterConditions(ctx, vm.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch conditions",
+			zap.Error(err),
+			zap.Int64("character_id", vm.ID))
+	}
+	vm.Conditions = conditions
+}
+
+// loadParty builds the roster for a campaign, with armor class worked out
+// from each character's equipped gear
+func (s *Server) loadParty(ctx context.Context, queries *db.Queries, campaignID int64) ([]PartyMember, error) {
+	rows, err := queries.ListCampaignCharacters(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	coinage, err := campaignCoinage(ctx, queries, sql.NullInt64{Int64: campaignID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	party := make([]PartyMember, 0, len(rows))
+	for _, row := range rows {
+		inventory, err := queries.GetCharacterInventoryItems(ctx, row.ID)
+		if err != nil {
+			logger.Warn("Failed to fetch inventory for party roster",
+				zap.Error(err),
+				zap.Int64("character_id", row.ID))
+			inventory = []db.GetCharacterInventoryItemsRow{}
+		}
+
+		purse, err := loadPurse(ctx, queries, row.ID)
+		if err != nil {
+			logger.Warn("Failed to fetch purse for party roster",
+				zap.Error(err),
+				zap.Int64("character_id", row.ID))
+			purse = currency.Purse{}
+		}
+
+		vm := NewSafeCharacterViewModel(campaignCharacter(row), inventory, coinage, purse)
+		s.loadCampaignDetails(ctx, queries, &vm)
+		party = append(party, PartyMember{
+			CharacterViewModel: vm,
+			PlayerUsername:     row.PlayerUsername,
+		})
+	}
+	return party, nil
+}
+
+// removeCampaignMember drops a player and their characters from a campaign
+func (s *Server) removeCampaignMember(ctx context.Context, campaignID, userID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := db.New(s.db).WithTx(tx)
+	if err := qtx.RemoveMemberCharactersFromCampaign(ctx, db.RemoveMemberCharactersFromCampaignParams{
+		CampaignID: campaignID,
+		UserID:     userID,
+	}); err != nil {
+		return err
+	}
+	if err := qtx.DeleteCampaignMember(ctx, db.DeleteCampaignMemberParams{
+		CampaignID: campaignID,
+		UserID:     userID,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// getCampaignForUser returns a campaign the user referees or actively plays
+// in. The second result reports whether the user is the referee.
+func getCampaignForUser(ctx context.Context, queries *db.Queries, campaignID, userID int64) (db.Campaign, bool, error) {
+	campaign, err := queries.GetCampaign(ctx, campaignID)
+	if err != nil {
+		return db.Campaign{}, false, err
+	}
+	if campaign.RefereeID == userID {
+		return campaign, true, nil
+	}
+
+	member, err := queries.GetCampaignMember(ctx, db.GetCampaignMemberParams{
+		CampaignID: campaignID,
+		UserID:     userID,
+	})
+	if err != nil {
+		return db.Campaign{}, false, err
+	}
+	if member.Status != CampaignMemberActive {
+		return db.Campaign{}, false, sql.ErrNoRows
+	}
+	return campaign, false, nil
+}
+
+// campaignIDFromForm handles the shared checks for campaign POST handlers
+func campaignIDFromForm(w http.ResponseWriter, r *http.Request) (*db.GetSessionRow, int64, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return nil, 0, false
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, 0, false
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return nil, 0, false
+	}
+
+	campaignID, err := strconv.ParseInt(r.Form.Get("campaign_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid campaign ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("campaign_id")))
+		http.Error(w, "Invalid campaign ID", http.StatusBadRequest)
+		return nil, 0, false
+	}
+
+	return user, campaignID, true
+}
+
+// refereeCampaignFromForm is campaignIDFromForm for referee-only actions
+func (s *Server) refereeCampaignFromForm(w http.ResponseWriter, r *http.Request) (*db.GetSessionRow, db.Campaign, bool) {
+	user, campaignID, ok := campaignIDFromForm(w, r)
+	if !ok {
+		return nil, db.Campaign{}, false
+	}
+
+	campaign, err := db.New(s.db).GetCampaign(r.Context(), campaignID)
+	if err != nil || campaign.RefereeID != user.UserID {
+		logger.Error("Campaign not found or user is not the referee",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Campaign not found", http.StatusNotFound)
+		return nil, db.Campaign{}, false
+	}
+
+	return user, campaign, true
+}
+
+func generateInviteCode() (string, error) {
+	code := make([]byte, 4)
+	if _, err := rand.Read(code); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(code), nil
+}
@@ -3,7 +3,6 @@ package server
 import (
 	"database/sql"
 	"fmt"
-	"math/rand/v2"
 	"net/http"
 	"strconv"
 	"strings"
@@ -60,116 +59,6 @@ func renderCurrencySection(w http.ResponseWriter, character CharacterViewModel,
 	RenderTemplate(w, "templates/characters/_currency_section.html", "_currency_section", data)
 }
 
-func (s *Server) HandleRest(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		logger.Error("Invalid method for rest handler",
-			zap.String("method", r.Method))
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	user, ok := GetUserFromContext(r.Context())
-	if !ok {
-		logger.Error("Unauthorized access attempt to rest handler")
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
-	if err != nil {
-		logger.Error("Invalid character ID in rest handler",
-			zap.Error(err),
-			zap.String("raw_id", r.FormValue("character_id")))
-		http.Error(w, "Invalid character ID", http.StatusBadRequest)
-		return
-	}
-
-	queries := db.New(s.db)
-	character, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
-	if err != nil {
-		logger.Error("Failed to fetch character for rest",
-			zap.Error(err),
-			zap.Int64("character_id", characterID),
-			zap.Int64("user_id", user.UserID))
-		http.Error(w, "Character not found", http.StatusNotFound)
-		return
-	}
-
-	progression := charRules.GetClassProgression(character.Class)
-	hitDice := progression.GetHitDice(character.Level)
-
-	parts := strings.Split(hitDice, "d")
-	if len(parts) != 2 {
-		logger.Error("Invalid hit dice format",
-			zap.String("hit_dice", hitDice),
-			zap.Int64("character_id", characterID))
-		http.Error(w, "Invalid hit dice format", http.StatusInternalServerError)
-		return
-	}
-
-	diceSize, err := strconv.Atoi(parts[1])
-	if err != nil {
-		logger.Error("Error parsing dice size",
-			zap.Error(err),
-			zap.String("dice_part", parts[1]),
-			zap.Int64("character_id", characterID))
-		http.Error(w, "Invalid hit dice format", http.StatusInternalServerError)
-		return
-	}
-
-	total := rand.IntN(diceSize) + 1
-	conMods := ability_scores.CalculateConstitutionModifiers(character.Constitution)
-	total += conMods.HitPointMod
-
-	newHP := character.CurrentHp + int64(total)
-	if newHP > character.MaxHp {
-		newHP = character.MaxHp
-	}
-
-	updateParams := db.UpdateCharacterParams{
-		ID:               characterID,
-		UserID:           user.UserID,
-		Name:             character.Name,
-		Class:            character.Class,
-		Level:            character.Level,
-		MaxHp:            character.MaxHp,
-		CurrentHp:        newHP,
-		Strength:         character.Strength,
-		Dexterity:        character.Dexterity,
-		Constitution:     character.Constitution,
-		Intelligence:     character.Intelligence,
-		Wisdom:           character.Wisdom,
-		Charisma:         character.Charisma,
-		ExperiencePoints: character.ExperiencePoints,
-		PlatinumPieces:   character.PlatinumPieces,
-		GoldPieces:       character.GoldPieces,
-		ElectrumPieces:   character.ElectrumPieces,
-		SilverPieces:     character.SilverPieces,
-		CopperPieces:     character.CopperPieces,
-	}
-
-	_, err = queries.UpdateCharacter(r.Context(), updateParams)
-	if err != nil {
-		logger.Error("Failed to update character HP after rest",
-			zap.Error(err),
-			zap.Int64("character_id", characterID),
-			zap.Int64("new_hp", newHP),
-			zap.Int64("healing", int64(total)))
-		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error during rest", characterID), http.StatusSeeOther)
-		return
-	}
-
-	message := fmt.Sprintf("Rest complete! Healed for %d HP", total)
-	logger.Info("Character rest successful",
-		zap.Int64("character_id", characterID),
-		zap.Int64("healing", int64(total)),
-		zap.Int64("new_hp", newHP))
-	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, message), http.StatusSeeOther)
-}
-
 func calculateMinimumXPForLevel(class string, level int64) int64 {
 	progression := charRules.GetClassProgression(class)
 	for _, levelInfo := range progression.Levels {
@@ -435,8 +324,16 @@ func (s *Server) handleCharacterCreateSubmission(w http.ResponseWriter, r *http.
 	}
 
 	// Create character in database
-	queries := db.New(s.db)
-	character, err := queries.CreateCharacter(r.Context(), params)
+	tx, err := s.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := db.New(s.db).WithTx(tx)
+	character, err := qtx.CreateCharacter(r.Context(), params)
 	if err != nil {
 		logger.Error("Failed to create character in database",
 			zap.Error(err),
@@ -445,6 +342,40 @@ func (s *Server) handleCharacterCreateSubmission(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Characters starting above first level open their XP ledger with the
+	// minimum for that level
+	if minimumXP > 0 {
+		_, err = qtx.CreateXPAward(r.Context(), db.CreateXPAwardParams{
+			CharacterID: character.ID,
+			Amount:      minimumXP,
+			Source:      "starting",
+			SessionDate: sessionToday(),
+			AwardedBy:   user.UserID,
+			Notes:       sql.NullString{String: "Opening balance", Valid: true},
+		})
+		if err != nil {
+			logger.Error("Failed to record starting XP",
+				zap.Error(err),
+				zap.Int64("character_id", character.ID))
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := seedLevelHistory(r.Context(), qtx, character.ID, character.Level, character.MaxHp); err != nil {
+		logger.Error("Failed to record level history",
+			zap.Error(err),
+			zap.Int64("character_id", character.ID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit character creation", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
 	logger.Info("Character created successfully",
 		zap.Int64("character_id", character.ID),
 		zap.String("character_name", character.Name),
@@ -477,10 +408,7 @@ func (s *Server) HandleCharacterEdit(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodGet:
-		character, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
-			ID:     characterID,
-			UserID: user.UserID,
-		})
+		character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 		if err != nil {
 			logger.Error("Failed to fetch character",
 				zap.Error(err),
@@ -540,20 +468,32 @@ func (s *Server) HandleCharacterEdit(w http.ResponseWriter, r *http.Request) {
 		currentHp, _ := strconv.ParseInt(r.Form.Get("current_hp"), 10, 64)
 		level, _ := strconv.ParseInt(r.Form.Get("level"), 10, 64)
 
+		// XP and coins are not on the edit form; carry them over unchanged
+		existing, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+		if err != nil {
+			logger.Error("Failed to fetch character",
+				zap.Error(err),
+				zap.Int64("character_id", characterID),
+				zap.String("user_id", strconv.FormatInt(user.UserID, 10)))
+			http.Error(w, "Character not found", http.StatusNotFound)
+			return
+		}
+
 		updateParams := db.UpdateCharacterParams{
-			ID:           characterID,
-			UserID:       user.UserID,
-			Name:         r.Form.Get("name"),
-			Class:        r.Form.Get("class"),
-			Level:        level,
-			MaxHp:        maxHp,
-			CurrentHp:    currentHp,
-			Strength:     abilities["strength"],
-			Dexterity:    abilities["dexterity"],
-			Constitution: abilities["constitution"],
-			Intelligence: abilities["intelligence"],
-			Wisdom:       abilities["wisdom"],
-			Charisma:     abilities["charisma"],
+			ID:               characterID,
+			UserID:           existing.UserID,
+			Name:             r.Form.Get("name"),
+			Class:            r.Form.Get("class"),
+			Level:            level,
+			MaxHp:            maxHp,
+			CurrentHp:        currentHp,
+			Strength:         abilities["strength"],
+			Dexterity:        abilities["dexterity"],
+			Constitution:     abilities["constitution"],
+			Intelligence:     abilities["intelligence"],
+			Wisdom:           abilities["wisdom"],
+			Charisma:         abilities["charisma"],
+			ExperiencePoints: existing.ExperiencePoints,
 		}
 
 		_, err = queries.UpdateCharacter(r.Context(), updateParams)
@@ -652,140 +592,6 @@ func (s *Server) HandleDeleteCharacter(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/characters?message=Character deleted successfully", http.StatusSeeOther)
 }
 
-// safeGetItemName safely extracts an item name from an interface
-func safeGetItemName(v interface{}) string {
-	if v == nil {
-		return "Unknown Item"
-	}
-
-	switch val := v.(type) {
-	case string:
-		return val
-	case sql.NullString:
-		if val.Valid {
-			return val.String
-		}
-	}
-
-	// Fallback: convert to string representation
-	return fmt.Sprintf("%v", v)
-}
-
-// safeGetItemWeight safely extracts an item weight from an interface
-func safeGetItemWeight(v interface{}) int {
-	if v == nil {
-		return 0
-	}
-
-	switch val := v.(type) {
-	case int:
-		return val
-	case int64:
-		return int(val)
-	case float64:
-		return int(val)
-	case sql.NullInt64:
-		if val.Valid {
-			return int(val.Int64)
-		}
-	}
-
-	return 0
-}
-
-// safeGetNullString safely converts an interface to sql.NullString
-func safeGetNullString(v interface{}) sql.NullString {
-	if v == nil {
-		return sql.NullString{}
-	}
-
-	switch val := v.(type) {
-	case string:
-		return sql.NullString{String: val, Valid: val != ""}
-	case sql.NullString:
-		return val
-	}
-
-	// Try to convert to string
-	str := fmt.Sprintf("%v", v)
-	return sql.NullString{String: str, Valid: str != ""}
-}
-
-// safeGetInt64 safely extracts an int64 from an interface
-func safeGetInt64(v interface{}) (int64, bool) {
-	if v == nil {
-		return 0, false
-	}
-
-	switch val := v.(type) {
-	case int:
-		return int64(val), true
-	case int64:
-		return val, true
-	case float64:
-		return int64(val), true
-	case sql.NullInt64:
-		return val.Int64, val.Valid
-	}
-
-	return 0, false
-}
-
-// safeGetDefenseBonus safely extracts a defense bonus from an interface
-func safeGetDefenseBonus(v interface{}) (int64, bool) {
-	if v == nil {
-		return 0, false
-	}
-
-	switch val := v.(type) {
-	case int:
-		return int64(val), true
-	case int64:
-		return val, true
-	case float64:
-		return int64(val), true
-	}
-
-	return 0, false
-}
-
-func safeGetArmorClass(item db.GetCharacterInventoryItemsRow) (int64, bool) {
-	if item.ItemType != "armor" {
-		return 0, false
-	}
-
-	// Extract the armor class from the item's ArmorClass field
-	if item.ArmorClass == nil {
-		return 9, true // Default to Leather armor AC
-	}
-
-	ac, ok := interfaceToInt64(item.ArmorClass)
-	if !ok {
-		return 9, true // Default to Leather armor AC
-	}
-
-	return ac, true
-}
-
-func safeGetEnhancementBonus(item db.GetCharacterInventoryItemsRow) (int64, bool) {
-	if item.EnhancementBonus == nil {
-		return 0, false
-	}
-
-	switch value := item.EnhancementBonus.(type) {
-	case int64:
-		return value, true
-	case int:
-		return int64(value), true
-	case float64:
-		return int64(value), true
-	case sql.NullInt64:
-		return value.Int64, value.Valid
-	}
-
-	return 0, false
-}
-
 func (s *Server) HandleCharacterDetail(w http.ResponseWriter, r *http.Request) {
 	user, ok := GetUserFromContext(r.Context())
 	if !ok {
@@ -806,10 +612,7 @@ func (s *Server) HandleCharacterDetail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	queries := db.New(s.db)
-	character, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	character, canEdit, err := getReadableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Error fetching character",
 			zap.Int64("character_id", characterID),
@@ -860,7 +663,8 @@ func (s *Server) HandleCharacterDetail(w http.ResponseWriter, r *http.Request) {
 			}
 		}()
 
-		viewModel = NewSafeCharacterViewModel(character, inventory)
+		coinage, purse := characterCoins(r.Context(), queries, characterID)
+		viewModel = NewSafeCharacterViewModel(character, inventory, coinage, purse)
 	}()
 
 	// If viewModel is empty (due to panic), create a minimal one
@@ -882,11 +686,6 @@ func (s *Server) HandleCharacterDetail(w http.ResponseWriter, r *http.Request) {
 			Wisdom:           character.Wisdom,
 			Charisma:         character.Charisma,
 			ExperiencePoints: character.ExperiencePoints,
-			PlatinumPieces:   character.PlatinumPieces,
-			GoldPieces:       character.GoldPieces,
-			ElectrumPieces:   character.ElectrumPieces,
-			SilverPieces:     character.SilverPieces,
-			CopperPieces:     character.CopperPieces,
 			CreatedAt:        character.CreatedAt,
 			UpdatedAt:        character.UpdatedAt,
 			ArmorClass:       9, // Default AC
@@ -897,6 +696,15 @@ func (s *Server) HandleCharacterDetail(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	viewModel.IsOwner = character.UserID == user.UserID
+	viewModel.CanEdit = canEdit
+
+	// Anyone else looking is the referee, who knows what every item is
+	if !viewModel.IsOwner {
+		viewModel.revealItems()
+	}
+	s.loadCharacterDetails(r.Context(), queries, &viewModel)
+
 	// Prepare data for the template
 	data := struct {
 		IsAuthenticated bool
@@ -1042,6 +850,7 @@ func (s *Server) HandleCharacterDetail(w http.ResponseWriter, r *http.Request) {
 		"templates/characters/_hp_display.html",
 		"templates/characters/_hp_section.html",
 		"templates/characters/_currency_section.html",
+		"templates/characters/_rest_section.html",
 		"templates/characters/_xp_section.html", // Make sure this is included
 		"templates/characters/inventory_modal.html",
 		"templates/characters/_container.html",
@@ -1075,10 +884,7 @@ func (s *Server) HandleHPForm(w http.ResponseWriter, r *http.Request) {
 
 	// Get character from database to verify ownership
 	queries := db.New(s.db)
-	character, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Error fetching character", zap.Error(err))
 		http.Error(w, "Character not found", http.StatusNotFound)
@@ -1104,10 +910,7 @@ func (s *Server) HandleMaxHPForm(w http.ResponseWriter, r *http.Request) {
 	}
 
 	queries := db.New(s.db)
-	character, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Error fetching character", zap.Error(err))
 		http.Error(w, "Character not found", http.StatusNotFound)
@@ -1147,10 +950,7 @@ func (s *Server) HandleUpdateHP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	queries := db.New(s.db)
-	character, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Error fetching character", zap.Error(err))
 		http.Error(w, "Character not found", http.StatusNotFound)
@@ -1176,7 +976,7 @@ func (s *Server) HandleUpdateHP(w http.ResponseWriter, r *http.Request) {
 
 	updateParams := db.UpdateCharacterParams{
 		ID:               characterID,
-		UserID:           user.UserID,
+		UserID:           character.UserID,
 		Name:             character.Name,
 		Class:            character.Class,
 		Level:            character.Level,
@@ -1189,11 +989,6 @@ func (s *Server) HandleUpdateHP(w http.ResponseWriter, r *http.Request) {
 		Wisdom:           character.Wisdom,
 		Charisma:         character.Charisma,
 		ExperiencePoints: character.ExperiencePoints,
-		PlatinumPieces:   character.PlatinumPieces,
-		GoldPieces:       character.GoldPieces,
-		ElectrumPieces:   character.ElectrumPieces,
-		SilverPieces:     character.SilverPieces,
-		CopperPieces:     character.CopperPieces,
 	}
 
 	updatedCharacter, err := queries.UpdateCharacter(r.Context(), updateParams)
@@ -1253,10 +1048,7 @@ func (s *Server) HandleUpdateMaxHP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	queries := db.New(s.db)
-	character, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Error fetching character", zap.Error(err))
 		http.Error(w, "Character not found", http.StatusNotFound)
@@ -1286,7 +1078,7 @@ func (s *Server) HandleUpdateMaxHP(w http.ResponseWriter, r *http.Request) {
 
 	updateParams := db.UpdateCharacterParams{
 		ID:               characterID,
-		UserID:           user.UserID,
+		UserID:           character.UserID,
 		Name:             character.Name,
 		Class:            character.Class,
 		Level:            character.Level,
@@ -1299,20 +1091,38 @@ func (s *Server) HandleUpdateMaxHP(w http.ResponseWriter, r *http.Request) {
 		Wisdom:           character.Wisdom,
 		Charisma:         character.Charisma,
 		ExperiencePoints: character.ExperiencePoints,
-		PlatinumPieces:   character.PlatinumPieces,
-		GoldPieces:       character.GoldPieces,
-		ElectrumPieces:   character.ElectrumPieces,
-		SilverPieces:     character.SilverPieces,
-		CopperPieces:     character.CopperPieces,
 	}
 
-	updatedCharacter, err := queries.UpdateCharacter(r.Context(), updateParams)
+	tx, err := s.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		renderHPSection(w, character, "Error updating maximum HP")
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := queries.WithTx(tx)
+
+	updatedCharacter, err := qtx.UpdateCharacter(r.Context(), updateParams)
 	if err != nil {
 		logger.Error("Failed to update character max HP", zap.Error(err))
 		renderHPSection(w, character, "Error updating maximum HP")
 		return
 	}
 
+	// Credit the change to the current level so a drain knows what to take
+	if err := addLevelHitPoints(r.Context(), qtx, characterID, character.Level, maxHPChange); err != nil {
+		logger.Error("Failed to record level hit points", zap.Error(err))
+		renderHPSection(w, character, "Error updating maximum HP")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit max HP update", zap.Error(err))
+		renderHPSection(w, character, "Error updating maximum HP")
+		return
+	}
+
 	logger.Info("Character max HP updated successfully",
 		zap.Int64("character_id", characterID),
 		zap.Int64("old_max_hp", character.MaxHp),
@@ -2,6 +2,10 @@ package server
 
 import (
 	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/marbh56/mordezzan/internal/currency"
@@ -10,9 +14,15 @@ import (
 	"github.com/marbh56/mordezzan/internal/rules/ability_scores"
 	charRules "github.com/marbh56/mordezzan/internal/rules/character"
 	"github.com/marbh56/mordezzan/internal/rules/combat"
+	"github.com/marbh56/mordezzan/internal/rules/containers"
+	"github.com/marbh56/mordezzan/internal/rules/durability"
+	"github.com/marbh56/mordezzan/internal/rules/equipment"
+	"github.com/marbh56/mordezzan/internal/rules/magic"
+	"github.com/marbh56/mordezzan/internal/rules/storage"
+	"github.com/marbh56/mordezzan/internal/rules/treasure"
 )
 
-func NewSafeCharacterViewModel(c db.Character, inventory []db.GetCharacterInventoryItemsRow) CharacterViewModel {
+func NewSafeCharacterViewModel(c db.Character, inventory []db.GetCharacterInventoryItemsRow, coinage *currency.Coinage, purse currency.Purse) CharacterViewModel {
 	vm := CharacterViewModel{
 		ID:               c.ID,
 		UserID:           c.UserID,
@@ -30,11 +40,6 @@ func NewSafeCharacterViewModel(c db.Character, inventory []db.GetCharacterInvent
 		CreatedAt:        c.CreatedAt,
 		UpdatedAt:        c.UpdatedAt,
 		ExperiencePoints: c.ExperiencePoints,
-		PlatinumPieces:   c.PlatinumPieces,
-		GoldPieces:       c.GoldPieces,
-		ElectrumPieces:   c.ElectrumPieces,
-		SilverPieces:     c.SilverPieces,
-		CopperPieces:     c.CopperPieces,
 
 		// Initialize modifiers
 		StrengthModifiers:     ability_scores.CalculateStrengthModifiers(c.Strength),
@@ -46,6 +51,8 @@ func NewSafeCharacterViewModel(c db.Character, inventory []db.GetCharacterInvent
 
 		// Initialize inventory containers
 		ContainerItems: make(map[int64][]InventoryItem),
+		StoredItems:    make(map[int64][]InventoryItem),
+		StoredWeight:   make(map[int64]float64),
 	}
 
 	if classGetsFighterBonus(c.Class) {
@@ -69,30 +76,45 @@ func NewSafeCharacterViewModel(c db.Character, inventory []db.GetCharacterInvent
 	// Calculate base AC
 	baseAC := 9
 	var armorAC int64
+	var hasArmor bool
 	var shieldBonus int64
+	var armorMovement sql.NullInt64
 
-	// Check equipped items for armor and shield with safety checks
+	// Check equipped items for armor and shield
 	for _, item := range inventory {
-		if item.EquipmentSlotID.Valid {
-			if item.ItemType == "armor" {
-				// For armor, find the armor class value safely
-				if acVal, ok := safeGetArmorClass(item); ok {
-					armorAC = acVal
-				}
-			} else if item.ItemType == "shield" {
-				// For shields, get defense bonus safely
-				if bonus, ok := safeGetDefenseBonus(item.DefenseBonus); ok {
-					shieldBonus = bonus
-				}
+		if !item.EquipmentSlotID.Valid {
+			continue
+		}
+		// Enhancement bonuses live on the owned item and improve AC, while
+		// damage takes protection away
+		condition := itemCondition(item)
+		switch item.ItemType {
+		case "armor":
+			if item.ArmorClass.Valid {
+				armorAC = int64(baseAC) - condition.Protection(int64(baseAC)-item.ArmorClass.Int64+item.EnhancementBonus)
+				hasArmor = true
+			}
+			if item.MovementRate.Valid {
+				armorMovement = item.MovementRate
+			}
+		case "shield":
+			if item.DefenseBonus.Valid {
+				shieldBonus = condition.Protection(item.DefenseBonus.Int64 + item.EnhancementBonus)
 			}
 		}
 	}
 
 	// If armor is equipped, use its AC instead of base AC
-	if armorAC > 0 {
+	if hasArmor {
 		baseAC = int(armorAC)
 	}
 
+	vm.MovementRate = rules.BaseMovementRate
+	if armorMovement.Valid {
+		vm.MovementRate = armorMovement.Int64
+		vm.MovementReduced = armorMovement.Int64 < rules.BaseMovementRate
+	}
+
 	// Apply shield bonus if any
 	totalAC := baseAC - int(shieldBonus)
 
@@ -109,68 +131,147 @@ func NewSafeCharacterViewModel(c db.Character, inventory []db.GetCharacterInvent
 		MaximumCapacity:     encumbranceThresholds.MaximumCapacity,
 	}
 
-	// Add coin weight to total weight - using our currency package
-	purse := currency.Purse{
-		PlatinumPieces: c.PlatinumPieces,
-		GoldPieces:     c.GoldPieces,
-		ElectrumPieces: c.ElectrumPieces,
-		SilverPieces:   c.SilverPieces,
-		CopperPieces:   c.CopperPieces,
+	// Coins weigh what the coins table says they do
+	vm.setPurse(coinage, purse)
+	vm.InventoryStats.CoinWeight = coinage.Weight(purse)
+
+	// Containers work out how much their contents weigh, since
+	// weight-reducing ones pass on only part of it. Ammunition is weighed by
+	// the bundle, each missile counting its share.
+	entries := make([]containers.Entry, 0, len(inventory))
+	for _, item := range inventory {
+		entries = append(entries, containers.Entry{
+			ID:          item.ID,
+			ContainerID: item.ContainerID.Int64,
+			Weight:      item.ItemWeight,
+			Quantity:    item.Quantity,
+			Bundle:      item.BundleSize,
+			Used:        item.MissilesUsed,
+			Container:   containerSpec(item.ContainerCapacity, item.ContainerMaxItems, item.ContainerWeightMultiplier, ""),
+		})
 	}
-	coinageWeight := int(currency.GetTotalWeight(&purse) + 0.5) // Round to nearest pound
-	vm.InventoryStats.CoinWeight = coinageWeight
+	packed := containers.NewInventory(entries)
+	var containersWeight float64
 
-	// Process each inventory item with safety checks
+	// Items left at a storage location don't weigh on the character, nor do
+	// the contents of a container left there
+	storedAt := make(map[int64]int64)
 	for _, item := range inventory {
-		// Build inventory item with safe conversions
-		invItem := InventoryItem{
-			ID:              item.ID,
-			CharacterID:     item.CharacterID,
-			ItemType:        item.ItemType,
-			ItemID:          item.ItemID,
-			ItemName:        safeGetItemName(item.ItemName),
-			ItemWeight:      safeGetItemWeight(item.ItemWeight),
-			Quantity:        item.Quantity,
-			ContainerID:     item.ContainerID,
-			EquipmentSlotID: item.EquipmentSlotID,
-			SlotName:        item.SlotName,
-			Notes:           item.Notes,
-			CreatedAt:       item.CreatedAt,
-			UpdatedAt:       item.UpdatedAt,
+		if item.StorageLocationID.Valid {
+			storedAt[item.ID] = item.StorageLocationID.Int64
 		}
-
-		// Safely handle type-specific fields
-		if item.ItemType == "weapon" || item.ItemType == "ranged_weapon" {
-			invItem.Damage = safeGetNullString(item.Damage)
-			invItem.AttacksPerRound = safeGetNullString(item.AttacksPerRound)
+	}
+	var holdings []treasure.Holding
+
+	// Process each inventory item. Type-specific details are only set for
+	// items of that type.
+	for i, item := range inventory {
+		// Unidentified items go by their appearance until revealed
+		identity := magic.Identity{
+			Name:        item.ItemName,
+			Appearance:  item.Appearance.String,
+			Enhancement: item.EnhancementBonus,
+			Identified:  item.IsIdentified,
 		}
-
-		if item.ItemType == "armor" {
-			if val, ok := safeGetInt64(item.MovementRate); ok {
-				invItem.MovementRate = sql.NullInt64{Int64: val, Valid: true}
-			}
+		curse := magic.Curse{
+			Target:   item.CurseTarget.String,
+			Ability:  item.CurseAbility.String,
+			Modifier: item.CurseModifier,
 		}
 
-		if item.ItemType == "shield" {
-			invItem.DefenseBonus = item.DefenseBonus // This is already an interface{}
+		invItem := InventoryItem{
+			ID:                item.ID,
+			CharacterID:       item.CharacterID,
+			ItemType:          item.ItemType,
+			ItemID:            item.ItemID,
+			ItemName:          identity.Shown(),
+			TrueName:          itemDisplayName(item.ItemName, item.EnhancementBonus),
+			IsIdentified:      item.IsIdentified,
+			Hidden:            !item.IsIdentified,
+			ItemWeight:        item.ItemWeight,
+			StackWeight:       entries[i].StackWeight(),
+			Quantity:          item.Quantity,
+			ContainerID:       item.ContainerID,
+			EquipmentSlotID:   item.EquipmentSlotID,
+			SlotName:          item.SlotName,
+			Damage:            item.Damage,
+			AttacksPerRound:   item.AttacksPerRound,
+			MovementRate:      item.MovementRate,
+			DefenseBonus:      item.DefenseBonus,
+			EnhancementBonus:  item.EnhancementBonus,
+			Notes:             item.Notes,
+			ContainerLoad:     packed.Load(item.ID),
+			ContainerCapacity: item.ContainerCapacity.Float64,
+			ContainerMaxItems: item.ContainerMaxItems.Int64,
+			ContainerCount:    packed.ItemCount(item.ID),
+			Charges:           item.Charges,
+			Condition:         item.Condition,
+			Broken:            !item.ConditionUsable,
+			ItemValue:         item.ItemValue,
+			SaleValue:         itemCondition(item).SaleValue(item.ItemValue),
+			Cursed:            item.Cursed,
+			CurseLabel:        curse.Label(),
+			CurseNotes:        item.CurseNotes,
+			TreasureKind:      item.TreasureKind.String,
+			AppraisedValue:    item.AppraisedValue,
+			StoredAt:          item.StorageLocationID,
+			Dropped:           item.Dropped,
+			DroppedLocation:   item.DroppedLocation.String,
+			Droppable:         item.EquipmentSlotID.Valid && containers.CheckDrop(entries[i], item.SlotName.String) == nil,
+			CreatedAt:         item.CreatedAt,
+			UpdatedAt:         item.UpdatedAt,
 		}
 
-		// Calculate total weight for this item
-		itemTotalWeight := invItem.ItemWeight * int(invItem.Quantity)
+		if item.ItemType == treasure.ItemType {
+			holdings = append(holdings, treasure.Holding{
+				Appraised:   item.AppraisedValue.Float64,
+				IsAppraised: item.AppraisedValue.Valid,
+				Quantity:    item.Quantity,
+			})
+		}
 
 		// Distribute the item to the appropriate collection
+		if location, stored := storedAt[packed.Outermost(item.ID)]; stored {
+			if invItem.ContainerID.Valid {
+				containerID := invItem.ContainerID.Int64
+				vm.ContainerItems[containerID] = append(vm.ContainerItems[containerID], invItem)
+			} else {
+				vm.StoredItems[location] = append(vm.StoredItems[location], invItem)
+				vm.StoredWeight[location] += packed.CarriedWeight(invItem.ID)
+			}
+			continue
+		}
 		if invItem.EquipmentSlotID.Valid {
 			vm.EquippedItems = append(vm.EquippedItems, invItem)
-			vm.InventoryStats.EquippedWeight += itemTotalWeight
+			// A dropped pack is still worn as far as the slot goes, but it
+			// and everything in it are lying where they were left
+			if invItem.Dropped {
+				vm.DroppedPacks = append(vm.DroppedPacks, DroppedPack{
+					ID:       invItem.ID,
+					Name:     invItem.ItemName,
+					Location: invItem.DroppedLocation,
+					Weight:   packed.CarriedWeight(invItem.ID),
+				})
+				continue
+			}
+			vm.InventoryStats.EquippedWeight += invItem.StackWeight
 		} else if invItem.ContainerID.Valid {
 			containerID := invItem.ContainerID.Int64
 			vm.ContainerItems[containerID] = append(vm.ContainerItems[containerID], invItem)
-			vm.InventoryStats.ContainersWeight += itemTotalWeight
 		} else {
 			vm.CarriedItems = append(vm.CarriedItems, invItem)
-			vm.InventoryStats.CarriedWeight += itemTotalWeight
+			vm.InventoryStats.CarriedWeight += invItem.StackWeight
+		}
+
+		// Contents are weighed through the outermost container
+		if !invItem.ContainerID.Valid {
+			containersWeight += packed.ContentsWeight(invItem.ID)
 		}
 	}
+	vm.InventoryStats.ContainersWeight = containersWeight
+
+	vm.TreasureWorth, vm.UnappraisedTreasure = treasure.Total(holdings)
+	vm.NetWorth = coinage.Value(purse) + vm.TreasureWorth
 
 	// Calculate total weight and encumbrance level
 	vm.InventoryStats.TotalWeight = vm.InventoryStats.EquippedWeight +
@@ -179,19 +280,26 @@ func NewSafeCharacterViewModel(c db.Character, inventory []db.GetCharacterInvent
 		vm.InventoryStats.CoinWeight
 
 	// Determine encumbrance level based on TOTAL weight (including coins)
-	switch {
-	case vm.InventoryStats.TotalWeight > vm.InventoryStats.MaximumCapacity:
+	switch total := vm.InventoryStats.TotalWeight; {
+	case total > float64(vm.InventoryStats.MaximumCapacity):
 		vm.InventoryStats.EncumbranceLevel = "Over"
-	case vm.InventoryStats.TotalWeight > vm.InventoryStats.BaseHeavyEncumbered:
+	case total > float64(vm.InventoryStats.BaseHeavyEncumbered):
 		vm.InventoryStats.EncumbranceLevel = "Heavy"
-	case vm.InventoryStats.TotalWeight > vm.InventoryStats.BaseEncumbered:
+	case total > float64(vm.InventoryStats.BaseEncumbered):
 		vm.InventoryStats.EncumbranceLevel = "Encumbered"
 	default:
 		vm.InventoryStats.EncumbranceLevel = "None"
 	}
 
+	// The load slows the character on top of any armour
+	if penalty := rules.EncumbranceMovementPenalty(vm.InventoryStats.EncumbranceLevel); penalty > 0 {
+		vm.MovementRate = max(vm.MovementRate-penalty, 0)
+		vm.MovementEncumbered = true
+	}
+
 	// Calculate FA and generate combat matrix row
 	fa := combat.CalculateFightingAbility(c.Class, c.Level)
+	vm.FightingAbility = fa
 	vm.CombatMatrix = make([]int64, 19) // -9 to 9 AC
 	for ac := -9; ac <= 9; ac++ {
 		vm.CombatMatrix[ac+9] = combat.GetTargetNumber(fa, int64(ac))
@@ -201,47 +309,192 @@ func NewSafeCharacterViewModel(c db.Character, inventory []db.GetCharacterInvent
 	progression = charRules.GetClassProgression(vm.Class)
 	vm.SavingThrow = progression.GetSavingThrow(vm.Level)
 
+	// Spell slots follow the current level, so a drained caster loses them
+	vm.SpellSlots = progression.GetSpellSlots(vm.Level)
+
 	return vm
 }
 
 type InventoryItem struct {
-	ID               int64           `json:"id"`
-	CharacterID      int64           `json:"character_id"`
-	ItemType         string          `json:"item_type"`
-	ItemID           int64           `json:"item_id"`
-	ItemName         string          `json:"item_name"`
-	ItemWeight       int             `json:"item_weight"`
-	Quantity         int64           `json:"quantity"`
-	ContainerID      sql.NullInt64   `json:"container_id"`
-	EquipmentSlotID  sql.NullInt64   `json:"equipment_slot_id"`
-	SlotName         sql.NullString  `json:"slot_name"`
-	CustomName       sql.NullString  `json:"custom_name"`
-	CustomNotes      sql.NullString  `json:"custom_notes"`
-	IsIdentified     bool            `json:"is_identified"`
-	Charges          sql.NullInt64   `json:"charges"`
-	Condition        string          `json:"condition"`
-	Damage           sql.NullString  `json:"damage"`
-	AttacksPerRound  sql.NullString  `json:"attacks_per_round"`
-	MovementRate     sql.NullInt64   `json:"movement_rate"`
-	DefenseBonus     interface{}     `json:"defense_bonus"`
-	EnhancementBonus sql.NullInt64   `json:"enhancement_bonus,omitempty"`
-	Notes            sql.NullString  `json:"notes"`
-	ContainerOptions []InventoryItem `json:"container_options,omitempty"`
-	CreatedAt        time.Time       `json:"created_at"`
-	UpdatedAt        time.Time       `json:"updated_at"`
+	ID               int64                           `json:"id"`
+	CharacterID      int64                           `json:"character_id"`
+	ItemType         string                          `json:"item_type"`
+	ItemID           int64                           `json:"item_id"`
+	ItemName         string                          `json:"item_name"`
+	ItemWeight       float64                         `json:"item_weight"`  // Pounds each, or per bundle for ammunition
+	StackWeight      float64                         `json:"stack_weight"` // Pounds for the whole stack
+	Quantity         int64                           `json:"quantity"`
+	ContainerID      sql.NullInt64                   `json:"container_id"`
+	EquipmentSlotID  sql.NullInt64                   `json:"equipment_slot_id"`
+	SlotName         sql.NullString                  `json:"slot_name"`
+	CustomName       sql.NullString                  `json:"custom_name"`
+	CustomNotes      sql.NullString                  `json:"custom_notes"`
+	IsIdentified     bool                            `json:"is_identified"`
+	TrueName         string                          `json:"true_name"`
+	Hidden           bool                            `json:"hidden,omitempty"` // Name, bonus and charges hidden from the viewer
+	RefereeView      bool                            `json:"referee_view,omitempty"`
+	Charges          sql.NullInt64                   `json:"charges"`
+	Cursed           bool                            `json:"cursed,omitempty"` // Only shown once identified
+	CurseLabel       string                          `json:"curse_label,omitempty"`
+	CurseNotes       sql.NullString                  `json:"curse_notes"`
+	TreasureKind     string                          `json:"treasure_kind,omitempty"` // Gem, jewellery or art
+	AppraisedValue   sql.NullFloat64                 `json:"appraised_value"`         // Gold pieces each
+	Condition        string                          `json:"condition"`
+	Broken           bool                            `json:"broken,omitempty"`
+	ItemValue        float64                         `json:"item_value"`
+	SaleValue        float64                         `json:"sale_value"` // Gold pieces, after condition
+	Repair           *durability.Repair              `json:"repair,omitempty"`
+	Damage           sql.NullString                  `json:"damage"`
+	AttacksPerRound  sql.NullString                  `json:"attacks_per_round"`
+	MovementRate     sql.NullInt64                   `json:"movement_rate"`
+	DefenseBonus     sql.NullInt64                   `json:"defense_bonus"`
+	EnhancementBonus int64                           `json:"enhancement_bonus,omitempty"`
+	Notes            sql.NullString                  `json:"notes"`
+	StoredAt         sql.NullInt64                   `json:"stored_at"` // Storage location, when not on the character
+	Dropped          bool                            `json:"dropped,omitempty"`
+	DroppedLocation  string                          `json:"dropped_location,omitempty"`
+	Droppable        bool                            `json:"droppable,omitempty"` // A pack worn on the back
+	Properties       []db.CharacterInventoryProperty `json:"properties,omitempty"`
+	TwoHanded        bool                            `json:"two_handed,omitempty"`
+	SlotOptions      []equipment.Slot                `json:"slot_options,omitempty"`
+
+	// Set on containers only
+	ContainerLoad     float64         `json:"container_load,omitempty"`
+	ContainerCapacity float64         `json:"container_capacity,omitempty"`
+	ContainerMaxItems int64           `json:"container_max_items,omitempty"`
+	ContainerCount    int64           `json:"container_count,omitempty"`
+	ContainerOptions  []InventoryItem `json:"container_options,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+// DroppedPack is a pack set down for a fight and where it was left
+type DroppedPack struct {
+	ID       int64   `json:"id"`
+	Name     string  `json:"name"`
+	Location string  `json:"location,omitempty"`
+	Weight   float64 `json:"weight"` // Including its contents
+}
+
+// WeightLabel formats the weight of the pack and its contents in pounds
+func (p DroppedPack) WeightLabel() string {
+	return formatPounds(p.Weight)
+}
+
+// CoinHolding is how many coins of one denomination a character holds
+type CoinHolding struct {
+	currency.Coin
+	Quantity int64 `json:"quantity"`
+}
+
+// setPurse lists a character's coins in the campaign's coinage
+func (vm *CharacterViewModel) setPurse(coinage *currency.Coinage, purse currency.Purse) {
+	vm.Purse = purse
+	vm.Coins = nil
+	listed := make(map[currency.Denomination]bool, len(purse))
+	for _, coin := range coinage.Coins() {
+		listed[coin.Denomination] = true
+		vm.Coins = append(vm.Coins, CoinHolding{Coin: coin, Quantity: purse.Of(coin.Denomination)})
+	}
+	for denom, count := range purse {
+		if !listed[denom] {
+			vm.Coins = append(vm.Coins, CoinHolding{Coin: currency.Coin{Denomination: denom, Name: string(denom)}, Quantity: count})
+		}
+	}
+	others := vm.Coins[len(coinage.Coins()):]
+	sort.Slice(others, func(i, j int) bool {
+		return others[i].Denomination < others[j].Denomination
+	})
+}
+
+// TreasureLabel formats the worth of the character's appraised gems,
+// jewellery and art in gold pieces, noting any not yet appraised
+func (vm CharacterViewModel) TreasureLabel() string {
+	if vm.UnappraisedTreasure > 0 {
+		return fmt.Sprintf("%s + %d unappraised", formatGoldValue(vm.TreasureWorth), vm.UnappraisedTreasure)
+	}
+	return formatGoldValue(vm.TreasureWorth)
+}
+
+// NetWorthLabel formats the worth of the character's coins and appraised
+// treasure in gold pieces
+func (vm CharacterViewModel) NetWorthLabel() string {
+	if vm.UnappraisedTreasure > 0 {
+		return formatGoldValue(vm.NetWorth) + " + unappraised treasure"
+	}
+	return formatGoldValue(vm.NetWorth)
+}
+
+// revealItems shows the true names of unidentified items, for a referee
+func (vm *CharacterViewModel) revealItems() {
+	reveal := func(items []InventoryItem) {
+		for i := range items {
+			items[i].ItemName = items[i].TrueName
+			items[i].Hidden = false
+			items[i].RefereeView = true
+		}
+	}
+	reveal(vm.EquippedItems)
+	reveal(vm.CarriedItems)
+	for _, items := range vm.ContainerItems {
+		reveal(items)
+	}
+	for _, items := range vm.StoredItems {
+		reveal(items)
+	}
+}
+
+// itemDisplayName appends an owned item's enhancement to its catalog name,
+// e.g. "Axe, Battle +2"
+func itemDisplayName(name string, enhancement int64) string {
+	if enhancement == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s %+d", name, enhancement)
+}
+
+// itemCondition is where an owned item stands on the condition scale
+func itemCondition(item db.GetCharacterInventoryItemsRow) durability.Condition {
+	return durability.Condition{
+		Name:              item.Condition,
+		ValuePercent:      item.ConditionValuePercent,
+		ArmorClassPenalty: item.ConditionAcPenalty,
+		Usable:            item.ConditionUsable,
+	}
 }
 
 // Contains inventory statistics and calculated values
 type InventoryStats struct {
-	TotalWeight         int    `json:"total_weight"`
-	EquippedWeight      int    `json:"equipped_weight"`
-	CarriedWeight       int    `json:"carried_weight"`
-	ContainersWeight    int    `json:"containers_weight"`
-	CoinWeight          int    `json:"coin_weight"`
-	BaseEncumbered      int    `json:"base_encumbered"`
-	BaseHeavyEncumbered int    `json:"base_heavy_encumbered"`
-	MaximumCapacity     int    `json:"maximum_capacity"`
-	EncumbranceLevel    string `json:"encumbrance_level"`
+	TotalWeight         float64 `json:"total_weight"`
+	EquippedWeight      float64 `json:"equipped_weight"`
+	CarriedWeight       float64 `json:"carried_weight"`
+	ContainersWeight    float64 `json:"containers_weight"`
+	CoinWeight          float64 `json:"coin_weight"`
+	BaseEncumbered      int     `json:"base_encumbered"`
+	BaseHeavyEncumbered int     `json:"base_heavy_encumbered"`
+	MaximumCapacity     int     `json:"maximum_capacity"`
+	EncumbranceLevel    string  `json:"encumbrance_level"`
+}
+
+// TotalWeightLabel formats everything the character carries in pounds
+func (s InventoryStats) TotalWeightLabel() string {
+	return formatPounds(s.TotalWeight)
+}
+
+// CoinWeightLabel formats the weight of the character's coins in pounds
+func (s InventoryStats) CoinWeightLabel() string {
+	return formatPounds(s.CoinWeight)
+}
+
+// WeightLabel formats the weight of the whole stack in pounds
+func (i InventoryItem) WeightLabel() string {
+	return formatPounds(i.StackWeight)
+}
+
+// formatPounds shows a weight to the hundredth of a pound, without trailing
+// zeros
+func formatPounds(lbs float64) string {
+	return strconv.FormatFloat(math.Round(lbs*100)/100, 'f', -1, 64)
 }
 
 func classGetsFighterBonus(class string) bool {
@@ -268,6 +521,13 @@ type CharacterViewModel struct {
 	CurrentHp  int64  `json:"current_hp"`
 	ArmorClass int    `json:"armor_class"`
 
+	// Feet per round, and whether armour slows the character
+	MovementRate    int64 `json:"movement_rate"`
+	MovementReduced bool  `json:"movement_reduced"`
+
+	// Whether carrying too much slows the character further
+	MovementEncumbered bool `json:"movement_encumbered"`
+
 	// Ability scores and modifiers
 	Strength          int64                            `json:"strength"`
 	StrengthModifiers ability_scores.StrengthModifiers `json:"strength_modifiers"`
@@ -288,47 +548,82 @@ type CharacterViewModel struct {
 	CharismaModifiers ability_scores.CharismaModifiers `json:"charisma_modifiers"`
 
 	// Combat information
-	CombatMatrix []int64 `json:"combat_matrix"`
-	SavingThrow  int64   `json:"saving_throw"`
+	FightingAbility int64                `json:"fighting_ability"`
+	CombatMatrix    []int64              `json:"combat_matrix"`
+	SavingThrow     int64                `json:"saving_throw"`
+	SpellSlots      charRules.SpellSlots `json:"spell_slots"`
 
 	// Inventory organization
 	EquippedItems  []InventoryItem           `json:"equipped_items"`
 	CarriedItems   []InventoryItem           `json:"carried_items"`
 	ContainerItems map[int64][]InventoryItem `json:"container_items"`
 
+	// Items left off the character by storage location, what they weigh
+	// there, and the locations the character can use
+	StoredItems      map[int64][]InventoryItem `json:"stored_items"`
+	StoredWeight     map[int64]float64         `json:"stored_weight"`
+	StorageLocations []StorageLocationView     `json:"storage_locations"`
+	StorageKinds     []storage.Kind            `json:"-"`
+
+	// Packs dropped for a fight, which weigh nothing until picked up
+	DroppedPacks []DroppedPack `json:"dropped_packs"`
+
+	// Offers of items and coins waiting on a player, and who in the
+	// campaign the character can give things to
+	IncomingTransfers []TransferView   `json:"incoming_transfers"`
+	OutgoingTransfers []TransferView   `json:"outgoing_transfers"`
+	TransferTargets   []TransferTarget `json:"transfer_targets"`
+
 	// Calculated inventory statistics
 	InventoryStats InventoryStats `json:"inventory_stats"`
-	PlatinumPieces int64
-	GoldPieces     int64
-	ElectrumPieces int64
-	SilverPieces   int64
-	CopperPieces   int64
+
+	// Coins held, one entry for each coin in use in the campaign and any
+	// others still in the purse
+	Purse currency.Purse `json:"purse"`
+	Coins []CoinHolding  `json:"coins"`
+
+	// Worth of appraised gems, jewellery and art held, and of that and the
+	// purse together, in copper pieces, with how many treasure items are
+	// left out for want of an appraisal
+	TreasureWorth       int64 `json:"treasure_worth"`
+	NetWorth            int64 `json:"net_worth"`
+	UnappraisedTreasure int64 `json:"unappraised_treasure"`
 
 	// Experience points and level progression
 	ExperiencePoints int64 `json:"experience_points"`
 	NextLevelXP      int64 `json:"next_level_xp"`
 	XPNeeded         int64 `json:"xp_needed"`
 
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-}
+	// XP ledger history and chart
+	XPHistory   []XPHistoryEntry `json:"xp_history"`
+	XPChart     XPChart          `json:"xp_chart"`
+	LevelDrains []db.LevelDrain  `json:"level_drains"`
 
-// Helper function to safely convert interface{} to int64
-func interfaceToInt64(v interface{}) (int64, bool) {
-	if v == nil {
-		return 0, false
-	}
+	// Rest, daily resources and game time
+	RestModes []db.RestMode `json:"rest_modes"`
+	Resources []ResourceUse `json:"resources"`
+	GameTime  string        `json:"game_time"`
+	TrackTime bool          `json:"track_time"`
 
-	switch value := v.(type) {
-	case int64:
-		return value, true
-	case int:
-		return int64(value), true
-	case float64:
-		return int64(value), true
-	case sql.NullInt64:
-		return value.Int64, value.Valid
-	}
+	// Equipped missile weapons and the ammunition ready for them
+	Ranged *RangedDetails `json:"ranged,omitempty"`
+
+	// Passive bonuses from equipped items, worn items that give none, and
+	// lasting effects of used ones
+	ItemBonuses    []ItemBonus        `json:"item_bonuses"`
+	IdleMagicItems []magic.Idle       `json:"idle_magic_items"`
+	ItemEffects    []ActiveItemEffect `json:"item_effects"`
 
-	return 0, false
+	// What can wear an item down
+	WearEvents []db.ItemWearEvent `json:"wear_events"`
+
+	// Campaign membership, conditions and who may change the character
+	CampaignID   int64                   `json:"campaign_id"`
+	CampaignName string                  `json:"campaign_name"`
+	Conditions   []db.CharacterCondition `json:"conditions"`
+	IsOwner      bool                    `json:"is_owner"`
+	CanEdit      bool                    `json:"can_edit"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
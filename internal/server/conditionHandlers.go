@@ -0,0 +1,133 @@
+package server
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"go.uber.org/zap"
+)
+
+// HandleAddCondition marks a character with a condition such as poisoned
+// or blinded
+func (s *Server) HandleAddCondition(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.Form.Get("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("character_id")))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.Form.Get("name"))
+	if name == "" {
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: Condition name is required", characterID), http.StatusSeeOther)
+		return
+	}
+
+	var notes sql.NullString
+	if notesStr := strings.TrimSpace(r.Form.Get("notes")); notesStr != "" {
+		notes = sql.NullString{String: notesStr, Valid: true}
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Error fetching character", zap.Error(err))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	if _, err := queries.CreateCharacterCondition(r.Context(), db.CreateCharacterConditionParams{
+		CharacterID: characterID,
+		Name:        name,
+		Notes:       notes,
+	}); err != nil {
+		logger.Error("Failed to add condition",
+			zap.Error(err),
+			zap.Int64("character_id", characterID))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error adding condition", characterID), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s added", characterID, name), http.StatusSeeOther)
+}
+
+// HandleRemoveCondition clears a condition from a character
+func (s *Server) HandleRemoveCondition(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.Form.Get("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("character_id")))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	conditionID, err := strconv.ParseInt(r.Form.Get("condition_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid condition ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("condition_id")))
+		http.Error(w, "Invalid condition ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Error fetching character", zap.Error(err))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	if err := queries.DeleteCharacterCondition(r.Context(), db.DeleteCharacterConditionParams{
+		ID:          conditionID,
+		CharacterID: characterID,
+	}); err != nil {
+		logger.Error("Failed to remove condition",
+			zap.Error(err),
+			zap.Int64("condition_id", conditionID))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error removing condition", characterID), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Condition removed", characterID), http.StatusSeeOther)
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/rules/containers"
+)
+
+var errItemNotFound = errors.New("item not found")
+
+// containerSpec turns the containers columns of a row into rules, or nil
+// when the item isn't a container
+func containerSpec(capacityWeight sql.NullFloat64, capacityItems sql.NullInt64, multiplier sql.NullFloat64, allowedTags string) *containers.Spec {
+	if !capacityWeight.Valid {
+		return nil
+	}
+	spec := &containers.Spec{
+		CapacityWeight:   capacityWeight.Float64,
+		CapacityItems:    capacityItems.Int64,
+		AllowedTags:      splitTags(allowedTags),
+		WeightMultiplier: 1,
+	}
+	if multiplier.Valid {
+		spec.WeightMultiplier = multiplier.Float64
+	}
+	return spec
+}
+
+// splitTags reads a GROUP_CONCAT list of tags
+func splitTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	return strings.Split(tags, ",")
+}
+
+// loadContainerInventory reads a character's inventory for the container
+// rules
+func loadContainerInventory(ctx context.Context, queries *db.Queries, characterID int64) (*containers.Inventory, error) {
+	rows, err := queries.ListContainerRuleEntries(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]containers.Entry, 0, len(rows))
+	for _, row := range rows {
+		// Messages name unidentified items by how they look
+		name := row.ItemName
+		if !row.IsIdentified && row.Appearance.Valid {
+			name = row.Appearance.String
+		}
+		entries = append(entries, containers.Entry{
+			ID:          row.ID,
+			Name:        name,
+			ContainerID: row.ContainerID.Int64,
+			Weight:      row.ItemWeight,
+			Quantity:    row.Quantity,
+			Bundle:      row.BundleSize,
+			Used:        row.MissilesUsed,
+			Tags:        splitTags(row.Tags),
+			Container:   containerSpec(row.CapacityWeight, row.CapacityItems, row.WeightMultiplier, row.AllowedTags),
+		})
+	}
+	return containers.NewInventory(entries), nil
+}
+
+// checkNewItemPlacement applies the container rules to quantity of a catalog
+// item about to be added straight into a container
+func checkNewItemPlacement(ctx context.Context, queries *db.Queries, characterID, itemID, quantity int64, containerID sql.NullInt64) error {
+	if !containerID.Valid {
+		return nil
+	}
+
+	item, err := queries.GetCatalogItemRules(ctx, itemID)
+	if err != nil {
+		return err
+	}
+
+	inv, err := loadContainerInventory(ctx, queries, characterID)
+	if err != nil {
+		return err
+	}
+
+	return inv.CheckPlacement(containers.Entry{
+		Name:      item.Name,
+		Weight:    item.Weight,
+		Quantity:  quantity,
+		Bundle:    item.BundleSize,
+		Tags:      splitTags(item.Tags),
+		Container: containerSpec(item.CapacityWeight, item.CapacityItems, item.WeightMultiplier, item.AllowedTags),
+	}, containerID.Int64)
+}
+
+// checkItemPlacement applies the container rules to an owned item, with
+// quantity as its new stack size, going into a container
+func checkItemPlacement(ctx context.Context, queries *db.Queries, characterID, itemID, quantity int64, containerID sql.NullInt64) error {
+	if !containerID.Valid {
+		return nil
+	}
+
+	inv, err := loadContainerInventory(ctx, queries, characterID)
+	if err != nil {
+		return err
+	}
+
+	item, ok := inv.Entry(itemID)
+	if !ok {
+		return errItemNotFound
+	}
+	item.Quantity = quantity
+	return inv.CheckPlacement(item, containerID.Int64)
+}
+
+// moveInventoryItem puts an item into a container, or takes it out when
+// containerID is null. A quantity smaller than the stack splits it, moving
+// only that many and leaving the rest where it was.
+func (s *Server) moveInventoryItem(ctx context.Context, characterID, itemID, quantity int64, containerID sql.NullInt64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := db.New(s.db).WithTx(tx)
+	inv, err := loadContainerInventory(ctx, qtx, characterID)
+	if err != nil {
+		return err
+	}
+
+	item, ok := inv.Entry(itemID)
+	if !ok {
+		return errItemNotFound
+	}
+	split := quantity > 0 && quantity < item.Quantity
+
+	// Moving the whole stack takes it out of its slot
+	if !split {
+		if err := checkItemRelease(ctx, qtx, characterID, itemID, sql.NullInt64{}); err != nil {
+			return err
+		}
+	}
+
+	if containerID.Valid {
+		placed := item
+		if split {
+			// The split-off stack is new, so it brings no contents with it
+			placed.ID = 0
+			placed.Quantity = quantity
+		}
+		if !split || item.ContainerID != containerID.Int64 {
+			if err := inv.CheckPlacement(placed, containerID.Int64); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !split {
+		if err := qtx.MoveItemToContainer(ctx, db.MoveItemToContainerParams{
+			ContainerID: containerID,
+			ID:          itemID,
+			CharacterID: characterID,
+		}); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	newID, err := qtx.SplitStack(ctx, db.SplitStackParams{
+		Quantity:    quantity,
+		ContainerID: containerID,
+		ID:          itemID,
+		CharacterID: characterID,
+	})
+	if err != nil {
+		return err
+	}
+	if err := qtx.CopyItemProperties(ctx, db.CopyItemPropertiesParams{
+		InventoryID:   newID,
+		InventoryID_2: itemID,
+	}); err != nil {
+		return err
+	}
+	// Packed away, the split-off stack goes wherever its container goes
+	if containerID.Valid {
+		if err := qtx.MoveItemToContainer(ctx, db.MoveItemToContainerParams{
+			ContainerID: containerID,
+			ID:          newID,
+			CharacterID: characterID,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := qtx.ReduceStackQuantity(ctx, db.ReduceStackQuantityParams{
+		Quantity:    quantity,
+		ID:          itemID,
+		CharacterID: characterID,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
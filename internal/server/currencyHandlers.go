@@ -1,9 +1,14 @@
 package server
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/marbh56/mordezzan/internal/currency"
 	"github.com/marbh56/mordezzan/internal/db"
@@ -38,104 +43,90 @@ func (s *Server) HandleCurrencyUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	amount, err := strconv.ParseInt(r.Form.Get("amount"), 10, 64)
-	if err != nil {
-		logger.Warn("Invalid currency amount", zap.Error(err))
-		renderCurrencyError(w, "Invalid amount")
-		return
-	}
-
-	denomination := r.Form.Get("denomination")
-	if !isValidDenomination(denomination) {
-		logger.Warn("Invalid currency denomination", zap.String("denomination", denomination))
-		renderCurrencyError(w, "Invalid denomination")
-		return
+	action := r.Form.Get("action")
+	if action == "" {
+		action = "adjust"
 	}
 
 	// Fetch character
 	queries := db.New(s.db)
-	character, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Error fetching character", zap.Error(err))
 		renderCurrencyError(w, "Character not found")
 		return
 	}
 
-	// Create a purse from the character's current currency
-	purse := currency.Purse{
-		PlatinumPieces: character.PlatinumPieces,
-		GoldPieces:     character.GoldPieces,
-		ElectrumPieces: character.ElectrumPieces,
-		SilverPieces:   character.SilverPieces,
-		CopperPieces:   character.CopperPieces,
+	coinage, err := loadCoinage(r.Context(), queries, characterID)
+	if err != nil {
+		logger.Error("Failed to fetch coinage", zap.Error(err))
+		renderCurrencyError(w, "Error loading coins")
+		return
+	}
+	oldPurse, err := loadPurse(r.Context(), queries, characterID)
+	if err != nil {
+		logger.Error("Failed to fetch purse", zap.Error(err))
+		renderCurrencyError(w, "Error loading coins")
+		return
 	}
 
-	// Record old values for logging
-	oldPurse := purse
+	purse := oldPurse.Clone()
+	message, errMsg := applyCurrencyAction(r, action, coinage, purse)
+	if errMsg != "" {
+		logger.Warn("Currency action refused",
+			zap.Int64("character_id", characterID),
+			zap.String("action", action),
+			zap.String("reason", errMsg))
+		renderCurrencyError(w, errMsg)
+		return
+	}
 
-	// Update the currency based on denomination
-	var success bool
-	if amount >= 0 {
-		// Adding currency
-		currency.AddToPurse(&purse, amount, currency.Denomination(denomination))
-		success = true
-	} else {
-		// Removing currency (convert to positive for removal)
-		success = currency.RemoveFromPurse(&purse, -amount, currency.Denomination(denomination))
-		if !success {
-			renderCurrencyError(w, fmt.Sprintf("Not enough %s", getDenominationName(denomination)))
+	reason := "exchange"
+	if action == "adjust" || action == "pay" {
+		reason = r.Form.Get("reason")
+		if reason == "" {
+			reason = "adjustment"
+			if action == "pay" {
+				reason = "purchase"
+			}
+		}
+		if !isValidCurrencyReason(reason) {
+			renderCurrencyError(w, "Invalid reason")
 			return
 		}
 	}
 
-	// Create update params starting with current values
-	updateParams := db.UpdateCharacterParams{
-		ID:               characterID,
-		UserID:           user.UserID,
-		Name:             character.Name,
-		Class:            character.Class,
-		Level:            character.Level,
-		MaxHp:            character.MaxHp,
-		CurrentHp:        character.CurrentHp,
-		Strength:         character.Strength,
-		Dexterity:        character.Dexterity,
-		Constitution:     character.Constitution,
-		Intelligence:     character.Intelligence,
-		Wisdom:           character.Wisdom,
-		Charisma:         character.Charisma,
-		ExperiencePoints: character.ExperiencePoints,
-		PlatinumPieces:   purse.PlatinumPieces,
-		GoldPieces:       purse.GoldPieces,
-		ElectrumPieces:   purse.ElectrumPieces,
-		SilverPieces:     purse.SilverPieces,
-		CopperPieces:     purse.CopperPieces,
-	}
-
-	// Update character in database
-	updatedChar, err := queries.UpdateCharacter(r.Context(), updateParams)
+	tx, err := s.db.BeginTx(r.Context(), nil)
 	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		renderCurrencyError(w, "Error updating currency")
+		return
+	}
+	defer tx.Rollback()
+
+	if err := recordCurrency(r.Context(), queries.WithTx(tx), CurrencyEntry{
+		CharacterID: characterID,
+		Reason:      reason,
+		Coins:       currency.Diff(oldPurse, purse),
+		ActorID:     user.UserID,
+		Notes:       strings.TrimSpace(r.Form.Get("notes")),
+	}); err != nil {
 		logger.Error("Failed to update character currency", zap.Error(err))
 		renderCurrencyError(w, "Error updating currency")
 		return
 	}
 
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit currency update", zap.Error(err))
+		renderCurrencyError(w, "Error updating currency")
+		return
+	}
+
 	logger.Info("Character currency updated",
 		zap.Int64("character_id", characterID),
-		zap.String("denomination", denomination),
-		zap.Int64("old_platinum", oldPurse.PlatinumPieces),
-		zap.Int64("new_platinum", purse.PlatinumPieces),
-		zap.Int64("old_gold", oldPurse.GoldPieces),
-		zap.Int64("new_gold", purse.GoldPieces),
-		zap.Int64("old_electrum", oldPurse.ElectrumPieces),
-		zap.Int64("new_electrum", purse.ElectrumPieces),
-		zap.Int64("old_silver", oldPurse.SilverPieces),
-		zap.Int64("new_silver", purse.SilverPieces),
-		zap.Int64("old_copper", oldPurse.CopperPieces),
-		zap.Int64("new_copper", purse.CopperPieces),
-		zap.Int64("change", amount))
+		zap.String("action", action),
+		zap.String("old_purse", coinage.Format(oldPurse)),
+		zap.String("new_purse", coinage.Format(purse)))
 
 	// Fetch inventory for coin weight calculation
 	inventory, err := queries.GetCharacterInventoryItems(r.Context(), characterID)
@@ -147,20 +138,230 @@ func (s *Server) HandleCurrencyUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create view model for template
-	viewModel := NewSafeCharacterViewModel(updatedChar, inventory)
-
-	// Add message with proper currency name
-	var message string
-	if amount > 0 {
-		message = fmt.Sprintf("Added %d %s", amount, getDenominationName(denomination))
-	} else {
-		message = fmt.Sprintf("Removed %d %s", -amount, getDenominationName(denomination))
-	}
+	viewModel := NewSafeCharacterViewModel(character, inventory, coinage, purse)
 
 	// Render the updated currency section
 	renderCurrencySectionUpdate(w, viewModel, message)
 }
 
+// applyCurrencyAction changes a purse by one of the currency form's
+// actions: adjust adds or removes coins, pay spends an amount from any
+// coins with change, exchange has a money changer swap one denomination
+// for another and consolidate has one make the purse as compact or light as
+// possible. It returns a message for the player, or the reason it failed.
+func applyCurrencyAction(r *http.Request, action string, coinage *currency.Coinage, purse currency.Purse) (string, string) {
+	denomination := currency.Denomination(r.Form.Get("denomination"))
+	var amount int64
+	if action != "consolidate" {
+		var err error
+		if amount, err = strconv.ParseInt(r.Form.Get("amount"), 10, 64); err != nil {
+			return "", "Invalid amount"
+		}
+		if _, ok := coinage.Coin(denomination); !ok {
+			return "", "Invalid denomination"
+		}
+	}
+
+	feePercent := int64(currency.DefaultExchangeFeePercent)
+	if raw := r.Form.Get("fee_percent"); raw != "" {
+		var err error
+		if feePercent, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return "", "Invalid fee"
+		}
+	}
+
+	switch action {
+	case "adjust":
+		if amount >= 0 {
+			purse.Add(denomination, amount)
+			return fmt.Sprintf("Added %s", coinName(coinage, amount, denomination)), ""
+		}
+		if !coinage.Remove(purse, -amount, denomination) {
+			return "", fmt.Sprintf("Not enough coin for %s", coinName(coinage, -amount, denomination))
+		}
+		return fmt.Sprintf("Removed %s", coinName(coinage, -amount, denomination)), ""
+
+	case "pay":
+		if amount <= 0 {
+			return "", "Invalid amount"
+		}
+		value := coinage.Value(currency.Purse{denomination: amount})
+		paid, change, ok := coinage.Pay(purse, value)
+		if !ok {
+			return "", fmt.Sprintf("Not enough coin to pay %s", coinName(coinage, amount, denomination))
+		}
+		message := fmt.Sprintf("Paid %s with %s", coinName(coinage, amount, denomination), coinage.Format(paid))
+		if change.Count() > 0 {
+			message += fmt.Sprintf(", getting %s change", coinage.Format(change))
+		}
+		return message, ""
+
+	case "exchange":
+		to := currency.Denomination(r.Form.Get("to_denomination"))
+		if _, ok := coinage.Coin(to); !ok {
+			return "", "Invalid denomination"
+		}
+		exchange, err := coinage.ChangeCoins(purse, amount, denomination, to, feePercent)
+		if err != nil {
+			return "", err.Error()
+		}
+		return fmt.Sprintf("Changed %s into %s; the money changer kept %s", coinage.Format(exchange.Given), coinage.Format(exchange.Received), formatGoldValue(exchange.Fee)), ""
+
+	case "consolidate":
+		exchange, err := coinage.Consolidate(purse, r.Form.Get("mode"), feePercent)
+		if err != nil {
+			return "", err.Error()
+		}
+		return fmt.Sprintf("Consolidated %s into %s; the money changer kept %s", coinage.Format(exchange.Given), coinage.Format(exchange.Received), formatGoldValue(exchange.Fee)), ""
+	}
+
+	return "", "Unknown currency action"
+}
+
+// loadCoinage reads the coins in use in a character's campaign, or just
+// the standard coins for a character outside one
+func loadCoinage(ctx context.Context, queries *db.Queries, characterID int64) (*currency.Coinage, error) {
+	var campaignID sql.NullInt64
+	campaign, err := queries.GetCharacterCampaign(ctx, characterID)
+	switch {
+	case err == nil:
+		campaignID = sql.NullInt64{Int64: campaign.ID, Valid: true}
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+	return campaignCoinage(ctx, queries, campaignID)
+}
+
+// campaignCoinage reads the standard coins and any a campaign adds
+func campaignCoinage(ctx context.Context, queries *db.Queries, campaignID sql.NullInt64) (*currency.Coinage, error) {
+	rows, err := queries.ListCoinage(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	coins := make([]currency.Coin, 0, len(rows))
+	for _, row := range rows {
+		coins = append(coins, currency.Coin{
+			Denomination: currency.Denomination(row.Denomination),
+			Name:         row.Name,
+			Weight:       row.WeightPerCoin,
+			Value:        row.BaseValue,
+		})
+	}
+	return currency.NewCoinage(coins), nil
+}
+
+// loadPurse reads a character's coins
+func loadPurse(ctx context.Context, queries *db.Queries, characterID int64) (currency.Purse, error) {
+	rows, err := queries.ListCharacterCoins(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	purse := make(currency.Purse, len(rows))
+	for _, row := range rows {
+		purse.Add(currency.Denomination(row.Denomination), row.Quantity)
+	}
+	return purse, nil
+}
+
+// characterCoins reads a character's coinage and purse for display,
+// falling back to an empty purse when either can't be read
+func characterCoins(ctx context.Context, queries *db.Queries, characterID int64) (*currency.Coinage, currency.Purse) {
+	coinage, err := loadCoinage(ctx, queries, characterID)
+	if err != nil {
+		logger.Warn("Failed to fetch coinage",
+			zap.Error(err),
+			zap.Int64("character_id", characterID))
+		coinage = currency.NewCoinage(nil)
+	}
+	purse, err := loadPurse(ctx, queries, characterID)
+	if err != nil {
+		logger.Warn("Failed to fetch purse",
+			zap.Error(err),
+			zap.Int64("character_id", characterID))
+		purse = currency.Purse{}
+	}
+	return coinage, purse
+}
+
+// payFromPurse takes a fee in gold pieces out of a character's purse,
+// breaking larger coins if needed, and records it in the currency ledger as
+// a purchase. It reports false, changing nothing, when the character can't
+// pay.
+func payFromPurse(ctx context.Context, qtx *db.Queries, characterID int64, gp int64, notes string) (bool, error) {
+	coinage, err := loadCoinage(ctx, qtx, characterID)
+	if err != nil {
+		return false, err
+	}
+	purse, err := loadPurse(ctx, qtx, characterID)
+	if err != nil {
+		return false, err
+	}
+	before := purse.Clone()
+	if !coinage.Remove(purse, gp, currency.GoldPieces) {
+		return false, nil
+	}
+	err = recordCurrency(ctx, qtx, CurrencyEntry{
+		CharacterID: characterID,
+		Reason:      "purchase",
+		Coins:       currency.Diff(before, purse),
+		Notes:       notes,
+	})
+	return err == nil, err
+}
+
+// HandleSaveCampaignCoin lets the referee add a regional coin or trade bar
+// to a campaign, or change one already added
+func (s *Server) HandleSaveCampaignCoin(w http.ResponseWriter, r *http.Request) {
+	_, campaign, ok := s.refereeCampaignFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	coin := currency.Coin{
+		Denomination: currency.Denomination(strings.ToLower(strings.TrimSpace(r.Form.Get("denomination")))),
+		Name:         strings.TrimSpace(r.Form.Get("name")),
+	}
+	var err error
+	if coin.Weight, err = strconv.ParseFloat(r.Form.Get("weight"), 64); err != nil {
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Invalid weight", campaign.ID), http.StatusSeeOther)
+		return
+	}
+	if coin.Value, err = strconv.ParseInt(r.Form.Get("value"), 10, 64); err != nil {
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Invalid value", campaign.ID), http.StatusSeeOther)
+		return
+	}
+
+	queries := db.New(s.db)
+	standard, err := campaignCoinage(r.Context(), queries, sql.NullInt64{})
+	if err != nil {
+		logger.Error("Failed to fetch coinage", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if err := standard.CheckCoin(coin); err != nil {
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=%s", campaign.ID, url.QueryEscape("Error: "+err.Error())), http.StatusSeeOther)
+		return
+	}
+
+	saved, err := queries.SaveCampaignCoin(r.Context(), db.SaveCampaignCoinParams{
+		CampaignID:    sql.NullInt64{Int64: campaign.ID, Valid: true},
+		Denomination:  string(coin.Denomination),
+		Name:          coin.Name,
+		WeightPerCoin: coin.Weight,
+		BaseValue:     coin.Value,
+	})
+	if err != nil {
+		logger.Error("Failed to save campaign coin",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaign.ID))
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Error saving coin", campaign.ID), http.StatusSeeOther)
+		return
+	}
+
+	message := fmt.Sprintf("%s (%s) is worth %s and weighs %g lb", saved.Name, saved.Denomination, formatGoldValue(saved.BaseValue), saved.WeightPerCoin)
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=%s", campaign.ID, url.QueryEscape(message)), http.StatusSeeOther)
+}
+
 func renderCurrencyError(w http.ResponseWriter, errMsg string) {
 	w.Header().Set("HX-Retarget", "#currency-section")
 	w.Header().Set("HX-Reswap", "outerHTML")
@@ -193,51 +394,15 @@ func renderCurrencySectionUpdate(w http.ResponseWriter, character CharacterViewM
 	RenderTemplate(w, "templates/characters/_currency_section.html", "_currency_section", data)
 }
 
-// Helper to get denomination full name
-func getDenominationName(denom string) string {
-	switch denom {
-	case "pp":
-		return "platinum pieces"
-	case "gp":
-		return "gold pieces"
-	case "ep":
-		return "electrum pieces"
-	case "sp":
-		return "silver pieces"
-	case "cp":
-		return "copper pieces"
-	default:
-		return "coins"
-	}
-}
-
-// isValidDenomination checks if the denomination is valid
-func isValidDenomination(denom string) bool {
-	validDenoms := map[string]bool{
-		"pp": true,
-		"gp": true,
-		"ep": true,
-		"sp": true,
-		"cp": true,
+// coinName shows an amount of one denomination, such as "3 gold pieces"
+func coinName(coinage *currency.Coinage, amount int64, denom currency.Denomination) string {
+	coin, ok := coinage.Coin(denom)
+	if !ok {
+		return fmt.Sprintf("%d %s", amount, denom)
 	}
-	return validDenoms[denom]
-}
-
-// Helper function to calculate coin weight
-func calculateCoinWeight(pp, gp, ep, sp, cp int64) int {
-	// Count total coins
-	totalCoins := pp + gp + ep + sp + cp
-
-	// Using 50 coins per pound which is a common RPG standard
-	coinsPerPound := 50.0
-
-	// Calculate weight with proper rounding
-	weight := float64(totalCoins) / coinsPerPound
-
-	// Round to nearest pound, but ensure at least 1 pound if there are any coins
-	if totalCoins > 0 && weight < 0.5 {
-		return 1
+	name := strings.ToLower(coin.Name)
+	if amount != 1 {
+		name += "s"
 	}
-
-	return int(weight + 0.5) // Round to nearest integer
+	return fmt.Sprintf("%d %s", amount, name)
 }
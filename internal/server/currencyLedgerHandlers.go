@@ -0,0 +1,322 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/marbh56/mordezzan/internal/currency"
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"go.uber.org/zap"
+)
+
+// currencyReasons are the reasons a currency ledger entry may give
+var currencyReasons = []string{"opening", "adjustment", "loot", "purchase", "sale", "tax", "upkeep", "split", "exchange", "storage", "transfer"}
+
+// CurrencyEntry is one change to a character's purse as recorded in the
+// currency ledger
+type CurrencyEntry struct {
+	CharacterID int64
+	Reason      string
+	Coins       currency.Purse // Negative for coins paid out
+	ItemID      sql.NullInt64
+	XPAwardID   sql.NullInt64
+	ActorID     int64 // Taken from the request when zero
+	Notes       string
+}
+
+// CurrencyLedgerEntry is one row of a character's currency ledger with the
+// purse as it stood afterwards
+type CurrencyLedgerEntry struct {
+	ID           int64
+	CreatedAt    time.Time
+	Reason       string
+	Coins        currency.Purse
+	Change       string
+	Balance      string
+	BalanceValue string
+	Item         string
+	XPAwardID    int64
+	Actor        string
+	Notes        string
+}
+
+// CurrencyMismatch is a denomination where the ledger and the stored purse
+// disagree
+type CurrencyMismatch struct {
+	Denomination string
+	Ledger       int64
+	Stored       int64
+}
+
+// recordCurrency appends an entry to the currency ledger and brings the
+// character's stored coins in line with it. The ledger is the only way a
+// purse changes; callers check a character can pay before recording, and
+// paying out coins the character doesn't hold fails.
+func recordCurrency(ctx context.Context, qtx *db.Queries, entry CurrencyEntry) error {
+	if len(entry.Coins) == 0 {
+		return nil
+	}
+	if !slices.Contains(currencyReasons, entry.Reason) {
+		return fmt.Errorf("unknown currency ledger reason %q", entry.Reason)
+	}
+
+	if entry.ActorID == 0 {
+		user, ok := GetUserFromContext(ctx)
+		if !ok {
+			return errors.New("no one to record the currency change against")
+		}
+		entry.ActorID = user.UserID
+	}
+
+	transaction, err := qtx.CreateCurrencyTransaction(ctx, db.CreateCurrencyTransactionParams{
+		CharacterID: entry.CharacterID,
+		Reason:      entry.Reason,
+		ItemID:      entry.ItemID,
+		XpAwardID:   entry.XPAwardID,
+		ActorID:     entry.ActorID,
+		Notes:       sql.NullString{String: entry.Notes, Valid: entry.Notes != ""},
+	})
+	if err != nil {
+		return err
+	}
+
+	for denom, amount := range entry.Coins {
+		if amount == 0 {
+			continue
+		}
+		if err := qtx.CreateCurrencyTransactionCoin(ctx, db.CreateCurrencyTransactionCoinParams{
+			TransactionID: transaction.ID,
+			Denomination:  string(denom),
+			Amount:        amount,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return syncCoinsFromLedger(ctx, qtx, entry.CharacterID)
+}
+
+// syncCoinsFromLedger sets a character's stored coins from the currency
+// ledger sums
+func syncCoinsFromLedger(ctx context.Context, qtx *db.Queries, characterID int64) error {
+	if err := qtx.ClearCharacterCoins(ctx, characterID); err != nil {
+		return err
+	}
+	return qtx.SyncCharacterCoins(ctx, characterID)
+}
+
+// isValidCurrencyReason checks if the reason is one a player may give when
+// changing a purse by hand
+func isValidCurrencyReason(reason string) bool {
+	validReasons := map[string]bool{
+		"adjustment": true,
+		"loot":       true,
+		"purchase":   true,
+		"sale":       true,
+		"tax":        true,
+		"upkeep":     true,
+	}
+	return validReasons[reason]
+}
+
+// HandleCurrencyLedger shows a character's currency ledger with a running
+// balance, filtered by reason, denomination and date, and checks that the
+// ledger sums to the stored purse
+func (s *Server) HandleCurrencyLedger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	character, _, err := getReadableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Character not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to fetch character", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	coinage, err := loadCoinage(r.Context(), queries, characterID)
+	if err != nil {
+		logger.Error("Failed to fetch coinage", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	entries, err := loadCurrencyLedger(r.Context(), queries, characterID, coinage)
+	if err != nil {
+		logger.Error("Failed to fetch currency ledger",
+			zap.Error(err),
+			zap.Int64("character_id", characterID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := queries.ReconcileCharacterCoins(r.Context(), db.ReconcileCharacterCoinsParams{
+		CharacterID:   characterID,
+		CharacterID_2: characterID,
+	})
+	if err != nil {
+		logger.Error("Failed to reconcile currency ledger",
+			zap.Error(err),
+			zap.Int64("character_id", characterID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	mismatches := make([]CurrencyMismatch, 0, len(rows))
+	for _, row := range rows {
+		mismatches = append(mismatches, CurrencyMismatch{
+			Denomination: row.Denomination,
+			Ledger:       row.Ledger,
+			Stored:       row.Stored,
+		})
+	}
+	if len(mismatches) > 0 {
+		logger.Warn("Currency ledger does not match stored purse",
+			zap.Int64("character_id", characterID),
+			zap.Int("denominations", len(mismatches)))
+	}
+
+	filters := r.URL.Query()
+	filtered, err := filterCurrencyLedger(entries, filters)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data := struct {
+		IsAuthenticated bool
+		Username        string
+		Character       db.Character
+		Coins           []currency.Coin
+		Reasons         []string
+		Filters         url.Values
+		Entries         []CurrencyLedgerEntry
+		Total           int
+		Mismatches      []CurrencyMismatch
+		FlashMessage    string
+		CurrentYear     int
+	}{
+		IsAuthenticated: true,
+		Username:        user.Username,
+		Character:       character,
+		Coins:           coinage.Coins(),
+		Reasons:         currencyReasons,
+		Filters:         filters,
+		Entries:         filtered,
+		Total:           len(entries),
+		Mismatches:      mismatches,
+		CurrentYear:     time.Now().Year(),
+	}
+
+	RenderTemplate(w, "templates/characters/currency_ledger.html", "base.html", data)
+}
+
+// loadCurrencyLedger reads a character's currency ledger oldest first,
+// working out the purse after each entry
+func loadCurrencyLedger(ctx context.Context, queries *db.Queries, characterID int64, coinage *currency.Coinage) ([]CurrencyLedgerEntry, error) {
+	transactions, err := queries.ListCurrencyTransactionsByCharacter(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	coinRows, err := queries.ListCurrencyTransactionCoinsByCharacter(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	coins := make(map[int64]currency.Purse, len(transactions))
+	for _, row := range coinRows {
+		if coins[row.TransactionID] == nil {
+			coins[row.TransactionID] = currency.Purse{}
+		}
+		coins[row.TransactionID].Add(currency.Denomination(row.Denomination), row.Amount)
+	}
+
+	entries := make([]CurrencyLedgerEntry, 0, len(transactions))
+	balance := currency.Purse{}
+	for _, transaction := range transactions {
+		change := coins[transaction.ID]
+		for denom, amount := range change {
+			balance.Add(denom, amount)
+		}
+		entries = append(entries, CurrencyLedgerEntry{
+			ID:           transaction.ID,
+			CreatedAt:    transaction.CreatedAt,
+			Reason:       transaction.Reason,
+			Coins:        change,
+			Change:       coinage.FormatChange(change),
+			Balance:      coinage.Format(balance),
+			BalanceValue: formatGoldValue(coinage.Value(balance)),
+			Item:         transaction.ItemName.String,
+			XPAwardID:    transaction.XpAwardID.Int64,
+			Actor:        transaction.ActorUsername,
+			Notes:        transaction.Notes.String,
+		})
+	}
+	return entries, nil
+}
+
+// filterCurrencyLedger keeps the ledger entries matching the reason,
+// denomination and date range filters, newest first. Running balances are
+// worked out beforehand so they stay right whatever is filtered out.
+func filterCurrencyLedger(entries []CurrencyLedgerEntry, filters url.Values) ([]CurrencyLedgerEntry, error) {
+	reason := filters.Get("reason")
+	denomination := currency.Denomination(filters.Get("denomination"))
+
+	var from, to time.Time
+	var err error
+	if raw := filters.Get("from"); raw != "" {
+		if from, err = time.Parse("2006-01-02", raw); err != nil {
+			return nil, errors.New("invalid from date")
+		}
+	}
+	if raw := filters.Get("to"); raw != "" {
+		if to, err = time.Parse("2006-01-02", raw); err != nil {
+			return nil, errors.New("invalid to date")
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+
+	filtered := make([]CurrencyLedgerEntry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if reason != "" && entry.Reason != reason {
+			continue
+		}
+		if denomination != "" && entry.Coins.Of(denomination) == 0 {
+			continue
+		}
+		if !from.IsZero() && entry.CreatedAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !entry.CreatedAt.Before(to) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered, nil
+}
@@ -0,0 +1,249 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/magic"
+	"go.uber.org/zap"
+)
+
+// parseCurse reads the curse fields of a form, returning nil when the item
+// isn't cursed
+func parseCurse(r *http.Request) (*magic.Curse, error) {
+	if r.FormValue("cursed") != "1" {
+		return nil, nil
+	}
+
+	curse := &magic.Curse{
+		Target: r.FormValue("curse_target"),
+		Notes:  strings.TrimSpace(r.FormValue("curse_notes")),
+	}
+	if curse.Target == magic.TargetAbility {
+		curse.Ability = r.FormValue("curse_ability")
+	}
+	if raw := strings.TrimSpace(r.FormValue("curse_modifier")); raw != "" && curse.Target != "" {
+		modifier, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: penalty must be a number", magic.ErrBadCurse)
+		}
+		curse.Modifier = modifier
+	}
+	if err := curse.Check(); err != nil {
+		return nil, err
+	}
+	return curse, nil
+}
+
+// setItemCurse lays a curse on an owned item, or lifts it when curse is nil
+func setItemCurse(ctx context.Context, qtx *db.Queries, characterID, itemID int64, curse *magic.Curse) error {
+	params := db.SetItemCurseParams{
+		ID:          itemID,
+		CharacterID: characterID,
+	}
+	if curse != nil {
+		params.Cursed = true
+		params.CurseTarget = sql.NullString{String: curse.Target, Valid: curse.Target != ""}
+		params.CurseAbility = sql.NullString{String: curse.Ability, Valid: curse.Ability != ""}
+		params.CurseModifier = curse.Modifier
+		params.CurseNotes = sql.NullString{String: curse.Notes, Valid: curse.Notes != ""}
+	}
+	return qtx.SetItemCurse(ctx, params)
+}
+
+// HandleCurseItem lets the referee curse an item a character already owns.
+// The curse stays hidden from the player until the item is identified.
+func (s *Server) HandleCurseItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(r.FormValue("item_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid item ID", zap.Error(err))
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+	if character.UserID == user.UserID {
+		http.Error(w, "Only the referee can curse an item", http.StatusForbidden)
+		return
+	}
+
+	curse, err := parseCurse(r)
+	if err == nil && curse == nil {
+		err = fmt.Errorf("%w: tick cursed to lay a curse", magic.ErrBadCurse)
+	}
+	if err != nil {
+		renderInventoryWithMessage(w, r, characterID, "Error: "+err.Error())
+		return
+	}
+
+	identity, err := inventoryItemIdentity(r.Context(), queries, characterID, itemID)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Item not found", http.StatusNotFound)
+		return
+	}
+	if err == nil {
+		err = setItemCurse(r.Context(), queries, characterID, itemID, curse)
+	}
+	if err != nil {
+		logger.Error("Failed to curse item",
+			zap.Error(err),
+			zap.Int64("item_id", itemID),
+			zap.Int64("character_id", characterID))
+		renderInventoryWithMessage(w, r, characterID, "Error cursing item")
+		return
+	}
+
+	logger.Info("Item cursed",
+		zap.Int64("character_id", characterID),
+		zap.Int64("item_id", itemID),
+		zap.String("target", curse.Target),
+		zap.Int64("modifier", curse.Modifier))
+
+	identity.Identified = true
+	renderInventoryWithMessage(w, r, characterID, fmt.Sprintf("%s is now cursed: %s", identity.Shown(), curse.Label()))
+}
+
+// HandleRemoveCurse lifts the curse on an owned item, by a cleric's remove
+// curse or by the referee, so it can be taken off
+func (s *Server) HandleRemoveCurse(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(r.FormValue("item_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid item ID", zap.Error(err))
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	// A player needs a cleric's spell; only the referee can simply say so
+	method := r.FormValue("method")
+	if method == magic.UncurseByReferee && character.UserID == user.UserID {
+		renderInventoryWithMessage(w, r, characterID, "Error: "+magic.ErrCurseOwner.Error())
+		return
+	}
+
+	message, err := s.removeCurse(r.Context(), characterID, itemID, method)
+	if err != nil {
+		if magic.IsRuleError(err) {
+			renderInventoryWithMessage(w, r, characterID, "Error: "+err.Error())
+			return
+		}
+		if err == sql.ErrNoRows {
+			http.Error(w, "Item not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to remove curse",
+			zap.Error(err),
+			zap.Int64("item_id", itemID),
+			zap.Int64("character_id", characterID))
+		renderInventoryWithMessage(w, r, characterID, "Error removing curse")
+		return
+	}
+
+	logger.Info("Curse removed",
+		zap.Int64("character_id", characterID),
+		zap.Int64("item_id", itemID),
+		zap.String("method", method))
+
+	renderInventoryWithMessage(w, r, characterID, message)
+}
+
+// removeCurse clears an item's curse and its penalty. A negative
+// enhancement bonus stays; the item is simply free to come off.
+func (s *Server) removeCurse(ctx context.Context, characterID, itemID int64, method string) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	identity, err := inventoryItemIdentity(ctx, qtx, characterID, itemID)
+	if err != nil {
+		return "", err
+	}
+	if err := magic.RemoveCurse(identity.Cursed, method); err != nil {
+		return "", err
+	}
+	if err := setItemCurse(ctx, qtx, characterID, itemID, nil); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("The curse on %s is %s; it can be taken off", identity.Shown(), magic.UncurseLabel(method)), nil
+}
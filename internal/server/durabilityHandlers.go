@@ -0,0 +1,360 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/durability"
+	"github.com/marbh56/mordezzan/internal/rules/rest"
+	"go.uber.org/zap"
+)
+
+// loadConditionScale reads the item condition scale, best first
+func loadConditionScale(ctx context.Context, queries *db.Queries) (*durability.Scale, error) {
+	rows, err := queries.ListItemConditions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conditions := make([]durability.Condition, 0, len(rows))
+	for _, row := range rows {
+		conditions = append(conditions, durability.Condition{
+			Name:              row.Name,
+			Rank:              row.Rank,
+			ValuePercent:      row.ValuePercent,
+			ArmorClassPenalty: row.ArmorClassPenalty,
+			Usable:            row.Usable,
+		})
+	}
+	return durability.NewScale(conditions), nil
+}
+
+// checkItemUsable refuses an owned item that is broken
+func checkItemUsable(ctx context.Context, queries *db.Queries, characterID, itemID int64, name string) error {
+	item, err := queries.GetInventoryItemCondition(ctx, db.GetInventoryItemConditionParams{
+		ID:          itemID,
+		CharacterID: characterID,
+	})
+	if err != nil {
+		return err
+	}
+	scale, err := loadConditionScale(ctx, queries)
+	if err != nil {
+		return err
+	}
+	condition, err := scale.Get(item.Condition)
+	if err != nil {
+		return err
+	}
+	if !condition.Usable {
+		return fmt.Errorf("%w: %s needs repair before it can be used", durability.ErrBroken, name)
+	}
+	return nil
+}
+
+// HandleWearItem knocks an owned item down the condition scale after a
+// fumble, acid, fire or the like
+func (s *Server) HandleWearItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(r.FormValue("item_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid item ID", zap.Error(err))
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	message, err := s.wearItem(r.Context(), characterID, itemID, r.FormValue("event"))
+	if err != nil {
+		if durability.IsRuleError(err) {
+			renderInventoryWithMessage(w, r, characterID, "Error: "+err.Error())
+			return
+		}
+		if err == sql.ErrNoRows {
+			http.Error(w, "Item not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to wear item",
+			zap.Error(err),
+			zap.Int64("item_id", itemID),
+			zap.Int64("character_id", characterID))
+		renderInventoryWithMessage(w, r, characterID, "Error damaging item")
+		return
+	}
+
+	logger.Info("Item condition worsened",
+		zap.Int64("character_id", characterID),
+		zap.Int64("item_id", itemID),
+		zap.String("event", r.FormValue("event")))
+
+	renderInventoryWithMessage(w, r, characterID, message)
+}
+
+// wearItem applies a wear event to an owned item
+func (s *Server) wearItem(ctx context.Context, characterID, itemID int64, eventName string) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	row, err := qtx.GetItemWearEvent(ctx, eventName)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("%w: %q", durability.ErrUnknownEvent, eventName)
+	}
+	if err != nil {
+		return "", err
+	}
+	event := durability.Event{
+		Name:      row.Name,
+		Label:     row.Label,
+		Steps:     row.Steps,
+		ItemTypes: splitTags(row.ItemTypes),
+	}
+
+	item, err := qtx.GetInventoryItemCondition(ctx, db.GetInventoryItemConditionParams{
+		ID:          itemID,
+		CharacterID: characterID,
+	})
+	if err != nil {
+		return "", err
+	}
+	identity, err := inventoryItemIdentity(ctx, qtx, characterID, itemID)
+	if err != nil {
+		return "", err
+	}
+
+	scale, err := loadConditionScale(ctx, qtx)
+	if err != nil {
+		return "", err
+	}
+	condition, err := scale.Degrade(item.Condition, event, item.ItemType)
+	if err != nil {
+		return "", err
+	}
+
+	if err := qtx.SetItemCondition(ctx, db.SetItemConditionParams{
+		Condition:   condition.Name,
+		ID:          itemID,
+		CharacterID: characterID,
+	}); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	message := fmt.Sprintf("%s: %s is now %s", event.Label, identity.Shown(), condition.Name)
+	if !condition.Usable {
+		message += " and can't be used until repaired"
+	}
+	return message, nil
+}
+
+// HandleRepairItem has a smith bring an owned item back to good condition,
+// paid from the character's purse and taking game time
+func (s *Server) HandleRepairItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(r.FormValue("item_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid item ID", zap.Error(err))
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	message, err := s.repairItem(r.Context(), character, itemID)
+	if err != nil {
+		if durability.IsRuleError(err) {
+			renderInventoryWithMessage(w, r, characterID, "Error: "+err.Error())
+			return
+		}
+		if err == sql.ErrNoRows {
+			http.Error(w, "Item not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to repair item",
+			zap.Error(err),
+			zap.Int64("item_id", itemID),
+			zap.Int64("character_id", characterID))
+		renderInventoryWithMessage(w, r, characterID, "Error repairing item")
+		return
+	}
+
+	logger.Info("Item repaired",
+		zap.Int64("character_id", characterID),
+		zap.Int64("item_id", itemID))
+
+	renderInventoryWithMessage(w, r, characterID, message)
+}
+
+// repairItem pays for a repair, advances the game clock by the time it takes
+// and restores the item's condition, all in one transaction
+func (s *Server) repairItem(ctx context.Context, character db.Character, itemID int64) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	item, err := qtx.GetInventoryItemCondition(ctx, db.GetInventoryItemConditionParams{
+		ID:          itemID,
+		CharacterID: character.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	identity, err := inventoryItemIdentity(ctx, qtx, character.ID, itemID)
+	if err != nil {
+		return "", err
+	}
+
+	scale, err := loadConditionScale(ctx, qtx)
+	if err != nil {
+		return "", err
+	}
+	repair, err := scale.PlanRepair(item.Condition, item.Value)
+	if err != nil {
+		return "", err
+	}
+
+	paid, err := payFromPurse(ctx, qtx, character.ID, repair.Cost, "Repair: "+identity.Shown())
+	if err != nil {
+		return "", err
+	}
+	if !paid {
+		return "", fmt.Errorf("%w: the smith asks %d gp", durability.ErrCannotAfford, repair.Cost)
+	}
+
+	if err := qtx.EnsureGameClock(ctx, character.ID); err != nil {
+		return "", err
+	}
+	clock, err := qtx.AdvanceGameClock(ctx, db.AdvanceGameClockParams{
+		ElapsedHours: repair.Hours,
+		CharacterID:  character.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	if _, err := expireItemEffects(ctx, qtx, character.ID, clock.ElapsedHours); err != nil {
+		return "", err
+	}
+
+	if err := qtx.SetItemCondition(ctx, db.SetItemConditionParams{
+		Condition:   repair.To.Name,
+		ID:          itemID,
+		CharacterID: character.ID,
+	}); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	shown := identity.Shown()
+	return fmt.Sprintf("%s%s repaired from %s to %s for %d gp, taking %d hours; now %s",
+		strings.ToUpper(shown[:1]), shown[1:], repair.From.Name, repair.To.Name, repair.Cost, repair.Hours, rest.FormatGameTime(clock.ElapsedHours)), nil
+}
+
+// loadItemConditions attaches repair quotes to worn-down items and lists
+// the wear events that can befall them
+func (s *Server) loadItemConditions(ctx context.Context, queries *db.Queries, vm *CharacterViewModel) {
+	events, err := queries.ListItemWearEvents(ctx)
+	if err != nil {
+		logger.Warn("Failed to fetch wear events", zap.Error(err), zap.Int64("character_id", vm.ID))
+	}
+	vm.WearEvents = events
+
+	scale, err := loadConditionScale(ctx, queries)
+	if err != nil {
+		logger.Warn("Failed to fetch item conditions", zap.Error(err), zap.Int64("character_id", vm.ID))
+		return
+	}
+
+	attach := func(items []InventoryItem) {
+		for i := range items {
+			if repair, err := scale.PlanRepair(items[i].Condition, items[i].ItemValue); err == nil {
+				items[i].Repair = &repair
+			}
+		}
+	}
+	attach(vm.EquippedItems)
+	attach(vm.CarriedItems)
+	for _, items := range vm.ContainerItems {
+		attach(items)
+	}
+}
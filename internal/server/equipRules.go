@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/equipment"
+	"go.uber.org/zap"
+)
+
+// loadLoadout reads the equipment slots and a character's items for the
+// equipment rules
+func loadLoadout(ctx context.Context, queries *db.Queries, characterID int64) (*equipment.Loadout, error) {
+	slotRows, err := queries.GetEquipmentSlots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]equipment.Slot, 0, len(slotRows))
+	for _, row := range slotRows {
+		slots = append(slots, equipment.Slot{ID: row.ID, Name: row.Name, Group: row.SlotGroup})
+	}
+
+	itemRows, err := queries.ListEquipRuleEntries(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]equipment.Item, 0, len(itemRows))
+	for _, row := range itemRows {
+		// Messages name unidentified items by how they look
+		name := row.ItemName
+		if !row.IsIdentified && row.Appearance.Valid {
+			name = row.Appearance.String
+		}
+		items = append(items, equipment.Item{
+			ID:         row.ID,
+			Name:       name,
+			SlotID:     row.EquipmentSlotID.Int64,
+			TwoHanded:  row.TwoHanded,
+			SlotGroups: splitTags(row.SlotGroups),
+			Cursed:     row.Cursed,
+		})
+	}
+
+	return equipment.NewLoadout(slots, items), nil
+}
+
+// loadEquipOptions attaches the slots each unequipped item may go in, so the
+// equip menus only offer places it fits
+func (s *Server) loadEquipOptions(ctx context.Context, queries *db.Queries, vm *CharacterViewModel) {
+	loadout, err := loadLoadout(ctx, queries, vm.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch equipment rules",
+			zap.Error(err),
+			zap.Int64("character_id", vm.ID))
+		return
+	}
+
+	attach := func(items []InventoryItem) {
+		for i := range items {
+			item, ok := loadout.Item(items[i].ID)
+			if !ok {
+				continue
+			}
+			items[i].TwoHanded = item.TwoHanded
+			if item.SlotID == 0 {
+				items[i].SlotOptions = loadout.SlotsFor(item)
+			}
+		}
+	}
+	attach(vm.EquippedItems)
+	attach(vm.CarriedItems)
+	for _, items := range vm.ContainerItems {
+		attach(items)
+	}
+}
+
+// checkNewItemEquip applies the equipment rules to a catalog item about to
+// be added straight into a slot
+func checkNewItemEquip(ctx context.Context, queries *db.Queries, characterID, itemID int64, slotID sql.NullInt64) error {
+	if !slotID.Valid {
+		return nil
+	}
+
+	item, err := queries.GetItemSlotRules(ctx, itemID)
+	if err != nil {
+		return err
+	}
+
+	loadout, err := loadLoadout(ctx, queries, characterID)
+	if err != nil {
+		return err
+	}
+
+	return loadout.CheckEquip(equipment.Item{
+		Name:       item.Name,
+		TwoHanded:  item.TwoHanded,
+		SlotGroups: splitTags(item.SlotGroups),
+	}, slotID.Int64)
+}
+
+// checkItemEquip applies the equipment rules to an owned item going into a
+// slot
+func checkItemEquip(ctx context.Context, queries *db.Queries, characterID, itemID int64, slotID sql.NullInt64) error {
+	if !slotID.Valid {
+		return nil
+	}
+
+	loadout, err := loadLoadout(ctx, queries, characterID)
+	if err != nil {
+		return err
+	}
+
+	item, ok := loadout.Item(itemID)
+	if !ok {
+		return errItemNotFound
+	}
+	return loadout.CheckEquip(item, slotID.Int64)
+}
+
+// checkItemRelease refuses to take an equipped item out of its slot while
+// a curse holds it there. slotID is where the item is headed, if anywhere.
+func checkItemRelease(ctx context.Context, queries *db.Queries, characterID, itemID int64, slotID sql.NullInt64) error {
+	loadout, err := loadLoadout(ctx, queries, characterID)
+	if err != nil {
+		return err
+	}
+
+	item, ok := loadout.Item(itemID)
+	if !ok {
+		return errItemNotFound
+	}
+	if slotID.Valid && slotID.Int64 == item.SlotID {
+		return nil
+	}
+	return equipment.CheckRelease(item)
+}
+
+// equipItem puts an owned item into a slot. When other items are in the way
+// they are returned and nothing changes, unless swap is set, in which case
+// they are unequipped in the same transaction.
+func (s *Server) equipItem(ctx context.Context, characterID, itemID, slotID int64, swap bool) ([]equipment.Item, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := db.New(s.db).WithTx(tx)
+	loadout, err := loadLoadout(ctx, qtx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, ok := loadout.Item(itemID)
+	if !ok {
+		return nil, errItemNotFound
+	}
+	if err := loadout.CheckFits(item, slotID); err != nil {
+		return nil, err
+	}
+	if err := loadout.CheckStuck(item, slotID); err != nil {
+		return nil, err
+	}
+
+	blockers := loadout.Blockers(item, slotID)
+	if len(blockers) > 0 && !swap {
+		return blockers, nil
+	}
+
+	for _, blocker := range blockers {
+		if err := qtx.UnequipItem(ctx, db.UnequipItemParams{
+			ID:          blocker.ID,
+			CharacterID: characterID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := qtx.EquipItem(ctx, db.EquipItemParams{
+		EquipmentSlotID: sql.NullInt64{Int64: slotID, Valid: true},
+		ID:              itemID,
+		CharacterID:     characterID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return blockers, tx.Commit()
+}
@@ -154,6 +154,7 @@ func RenderTemplate(w http.ResponseWriter, templatePath string, templateName str
 			"templates/characters/_hp_display.html",
 			"templates/characters/_hp_section.html",
 			"templates/characters/_currency_section.html",
+			"templates/characters/_rest_section.html",
 			"templates/characters/_xp_section.html", // Make sure this is included
 			"templates/characters/inventory_modal.html",
 			"templates/characters/_container.html",
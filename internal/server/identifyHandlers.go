@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/magic"
+	"go.uber.org/zap"
+)
+
+// HandleIdentifyItem identifies an owned item by spell, sage, trial and
+// error, or the referee revealing it
+func (s *Server) HandleIdentifyItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(r.FormValue("item_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid item ID", zap.Error(err))
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	fee := int64(magic.DefaultSageFee)
+	if feeStr := r.FormValue("fee"); feeStr != "" {
+		fee, err = strconv.ParseInt(feeStr, 10, 64)
+		if err != nil || fee < 0 {
+			http.Error(w, "Invalid fee", http.StatusBadRequest)
+			return
+		}
+	}
+
+	queries := db.New(s.db)
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	// Only the referee of the character's campaign knows what an item is
+	method := r.FormValue("method")
+	if method == magic.IdentifyByReferee && character.UserID == user.UserID {
+		renderInventoryWithMessage(w, r, characterID, "Error: "+magic.ErrRefereeOnly.Error())
+		return
+	}
+
+	message, err := s.identifyItem(r.Context(), character, itemID, method, fee)
+	if err != nil {
+		if magic.IsRuleError(err) {
+			renderInventoryWithMessage(w, r, characterID, "Error: "+err.Error())
+			return
+		}
+		if err == sql.ErrNoRows {
+			http.Error(w, "Item not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to identify item",
+			zap.Error(err),
+			zap.Int64("item_id", itemID),
+			zap.Int64("character_id", characterID))
+		renderInventoryWithMessage(w, r, characterID, "Error identifying item")
+		return
+	}
+
+	logger.Info("Item identified",
+		zap.Int64("character_id", characterID),
+		zap.Int64("item_id", itemID),
+		zap.String("method", method))
+
+	renderInventoryWithMessage(w, r, characterID, message)
+}
+
+// identifyItem marks an item identified, paying the sage's fee from the
+// character's purse when a sage does the work
+func (s *Server) identifyItem(ctx context.Context, character db.Character, itemID int64, method string, fee int64) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	identity, err := inventoryItemIdentity(ctx, qtx, character.ID, itemID)
+	if err != nil {
+		return "", err
+	}
+	if err := magic.Identify(identity, method); err != nil {
+		return "", err
+	}
+
+	if method == magic.IdentifyBySage && fee > 0 {
+		paid, err := payFromPurse(ctx, qtx, character.ID, fee, "Sage: "+identity.Shown())
+		if err != nil {
+			return "", err
+		}
+		if !paid {
+			return "", fmt.Errorf("%w: the sage asks %d gp", magic.ErrCannotAfford, fee)
+		}
+	}
+
+	if err := markIdentified(ctx, qtx, character.ID, itemID, method); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	appearance := identity.Shown()
+	identity.Identified = true
+	message := fmt.Sprintf("%s%s is %s, %s", strings.ToUpper(appearance[:1]), appearance[1:], identity.Shown(), magic.MethodLabel(method))
+	if method == magic.IdentifyBySage && fee > 0 {
+		message += fmt.Sprintf(" for %d gp", fee)
+	}
+	return message, nil
+}
+
+// inventoryItemIdentity reads what an owned item is and how it appears
+func inventoryItemIdentity(ctx context.Context, queries *db.Queries, characterID, itemID int64) (magic.Identity, error) {
+	row, err := queries.GetInventoryItemIdentity(ctx, db.GetInventoryItemIdentityParams{
+		ID:          itemID,
+		CharacterID: characterID,
+	})
+	if err != nil {
+		return magic.Identity{}, err
+	}
+	return magic.Identity{
+		Name:        row.Name,
+		Appearance:  row.Appearance.String,
+		ItemType:    row.ItemType,
+		Enhancement: row.EnhancementBonus,
+		Properties:  int(row.PropertyCount),
+		Cursed:      row.Cursed,
+		Identified:  row.IsIdentified,
+	}, nil
+}
+
+func markIdentified(ctx context.Context, qtx *db.Queries, characterID, itemID int64, method string) error {
+	return qtx.SetItemIdentified(ctx, db.SetItemIdentifiedParams{
+		IsIdentified: true,
+		IdentifiedBy: sql.NullString{String: method, Valid: true},
+		ID:           itemID,
+		CharacterID:  characterID,
+	})
+}
@@ -2,8 +2,10 @@ package server
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"text/template"
 	"time"
@@ -11,6 +13,8 @@ import (
 	"github.com/marbh56/mordezzan/internal/db"
 	"github.com/marbh56/mordezzan/internal/logger"
 	charRules "github.com/marbh56/mordezzan/internal/rules/character"
+	"github.com/marbh56/mordezzan/internal/rules/containers"
+	"github.com/marbh56/mordezzan/internal/rules/equipment"
 	"go.uber.org/zap"
 )
 
@@ -33,10 +37,7 @@ func (s *Server) HandleAddInventoryItem(w http.ResponseWriter, r *http.Request)
 
 	// Verify character belongs to user
 	queries := db.New(s.db)
-	character, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Character not found or belongs to another user",
 			zap.Error(err), zap.Int64("character_id", characterID))
@@ -94,10 +95,7 @@ func (s *Server) HandleRemoveInventoryItem(w http.ResponseWriter, r *http.Reques
 
 	// Verify character belongs to user
 	queries := db.New(s.db)
-	_, err = queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	_, err = getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Character not found or belongs to another user",
 			zap.Error(err),
@@ -106,6 +104,28 @@ func (s *Server) HandleRemoveInventoryItem(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// A cursed item can't be discarded while it is equipped
+	if err := checkItemRelease(r.Context(), queries, characterID, itemID, sql.NullInt64{}); err != nil {
+		if equipment.IsRuleError(err) {
+			logger.Warn("Item removal refused",
+				zap.Error(err),
+				zap.Int64("character_id", characterID),
+				zap.Int64("item_id", itemID))
+			http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, url.QueryEscape("Error: "+err.Error())), http.StatusSeeOther)
+			return
+		}
+		if errors.Is(err, errItemNotFound) {
+			http.Error(w, "Item not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to check item before removal",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("item_id", itemID))
+		http.Error(w, "Error removing item", http.StatusInternalServerError)
+		return
+	}
+
 	// Remove item from inventory
 	err = queries.RemoveItemFromInventory(r.Context(), db.RemoveItemFromInventoryParams{
 		ID:          itemID,
@@ -174,10 +194,7 @@ func (s *Server) HandleUpdateInventoryItem(w http.ResponseWriter, r *http.Reques
 
 	// Verify the character belongs to the user
 	queries := db.New(s.db)
-	_, err = queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	_, err = getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Character not found or belongs to another user",
 			zap.Error(err),
@@ -199,19 +216,6 @@ func (s *Server) HandleUpdateInventoryItem(w http.ResponseWriter, r *http.Reques
 		id, err := strconv.ParseInt(containerIDStr, 10, 64)
 		if err == nil {
 			containerID = sql.NullInt64{Int64: id, Valid: true}
-
-			// Verify the container belongs to the character
-			if containerID.Valid {
-				// Here we need to check if the container belongs to the character
-				// and also ensure we're not creating a circular reference
-				if containerID.Int64 == itemID {
-					logger.Warn("Attempted to put a container inside itself",
-						zap.Int64("item_id", itemID),
-						zap.Int64("container_id", containerID.Int64))
-					http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Cannot put a container inside itself", characterID), http.StatusSeeOther)
-					return
-				}
-			}
 		}
 	}
 
@@ -220,24 +224,6 @@ func (s *Server) HandleUpdateInventoryItem(w http.ResponseWriter, r *http.Reques
 	if slotIDStr := r.FormValue("equipment_slot_id"); slotIDStr != "" {
 		id, err := strconv.ParseInt(slotIDStr, 10, 64)
 		if err == nil {
-			// Check if the slot is already occupied
-			isOccupied, err := queries.IsSlotOccupied(r.Context(), db.IsSlotOccupiedParams{
-				CharacterID:     characterID,
-				EquipmentSlotID: sql.NullInt64{Int64: id, Valid: true},
-			})
-			if err != nil {
-				logger.Error("Failed to check if equipment slot is occupied",
-					zap.Error(err),
-					zap.Int64("character_id", characterID),
-					zap.Int64("slot_id", id))
-			} else if isOccupied {
-				logger.Warn("Attempted to equip item to an occupied slot",
-					zap.Int64("character_id", characterID),
-					zap.Int64("slot_id", id))
-				http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Equipment slot is already occupied", characterID), http.StatusSeeOther)
-				return
-			}
-
 			equipmentSlotID = sql.NullInt64{Int64: id, Valid: true}
 		}
 	}
@@ -257,6 +243,31 @@ func (s *Server) HandleUpdateInventoryItem(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// The updated stack must still fit the container's rules
+	if err := checkItemPlacement(r.Context(), queries, characterID, itemID, quantity, containerID); err != nil {
+		logger.Warn("Inventory update refused",
+			zap.Error(err),
+			zap.Int64("item_id", itemID),
+			zap.Int64("character_id", characterID))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: %s", characterID, err), http.StatusSeeOther)
+		return
+	}
+
+	// A cursed item stays in its slot, and the item must fit the new slot,
+	// which has to be free
+	err = checkItemRelease(r.Context(), queries, characterID, itemID, equipmentSlotID)
+	if err == nil {
+		err = checkItemEquip(r.Context(), queries, characterID, itemID, equipmentSlotID)
+	}
+	if err != nil {
+		logger.Warn("Inventory update refused",
+			zap.Error(err),
+			zap.Int64("item_id", itemID),
+			zap.Int64("character_id", characterID))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, url.QueryEscape("Error: "+err.Error())), http.StatusSeeOther)
+		return
+	}
+
 	// Update the inventory item
 	updateParams := db.UpdateInventoryItemParams{
 		Quantity:        quantity,
@@ -341,10 +352,7 @@ func (s *Server) HandleEquipItem(w http.ResponseWriter, r *http.Request) {
 
 	// Validate character belongs to the user
 	queries := db.New(s.db)
-	_, err = queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Character not found or doesn't belong to user",
 			zap.Error(err),
@@ -354,34 +362,18 @@ func (s *Server) HandleEquipItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if the equipment slot is already occupied
-	slotOccupied, err := queries.IsSlotOccupied(r.Context(), db.IsSlotOccupiedParams{
-		CharacterID:     characterID,
-		EquipmentSlotID: sql.NullInt64{Int64: equipmentSlotID, Valid: true},
-	})
-	if err != nil {
-		logger.Error("Error checking if slot is occupied",
+	// Equip the item, offering to swap out whatever is in the way
+	swap := r.FormValue("swap") == "1"
+	blockers, err := s.equipItem(r.Context(), characterID, itemID, equipmentSlotID, swap)
+	if equipment.IsRuleError(err) || errors.Is(err, errItemNotFound) {
+		logger.Warn("Equip refused",
 			zap.Error(err),
 			zap.Int64("character_id", characterID),
+			zap.Int64("item_id", itemID),
 			zap.Int64("equipment_slot_id", equipmentSlotID))
-		http.Error(w, "Error checking equipment slot", http.StatusInternalServerError)
-		return
-	}
-
-	if slotOccupied {
-		logger.Warn("Attempted to equip item to an occupied slot",
-			zap.Int64("character_id", characterID),
-			zap.Int64("equipment_slot_id", equipmentSlotID))
-		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Equipment slot is already occupied", characterID), http.StatusSeeOther)
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, url.QueryEscape("Error: "+err.Error())), http.StatusSeeOther)
 		return
 	}
-
-	// Equip the item
-	err = queries.EquipItem(r.Context(), db.EquipItemParams{
-		EquipmentSlotID: sql.NullInt64{Int64: equipmentSlotID, Valid: true},
-		ID:              itemID,
-		CharacterID:     characterID,
-	})
 	if err != nil {
 		logger.Error("Failed to equip item",
 			zap.Error(err),
@@ -391,14 +383,60 @@ func (s *Server) HandleEquipItem(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Error equipping item", http.StatusInternalServerError)
 		return
 	}
+	if len(blockers) > 0 && !swap {
+		s.renderEquipSwap(w, r, user, character, itemID, equipmentSlotID, blockers)
+		return
+	}
 
 	logger.Info("Item equipped successfully",
 		zap.Int64("character_id", characterID),
 		zap.Int64("item_id", itemID),
-		zap.Int64("equipment_slot_id", equipmentSlotID))
+		zap.Int64("equipment_slot_id", equipmentSlotID),
+		zap.Int("swapped", len(blockers)))
+
+	message := "Item equipped successfully"
+	if len(blockers) > 0 {
+		message = fmt.Sprintf("Item equipped; unequipped %s", equipment.Names(blockers))
+	}
 
 	// Redirect back to character detail page
-	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Item equipped successfully", characterID), http.StatusSeeOther)
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, url.QueryEscape(message)), http.StatusSeeOther)
+}
+
+// renderEquipSwap asks the player to confirm taking off the items in the
+// way before equipping
+func (s *Server) renderEquipSwap(w http.ResponseWriter, r *http.Request, user *db.GetSessionRow, character db.Character, itemID, slotID int64, blockers []equipment.Item) {
+	loadout, err := loadLoadout(r.Context(), db.New(s.db), character.ID)
+	if err != nil {
+		logger.Error("Failed to load equipment for swap",
+			zap.Error(err),
+			zap.Int64("character_id", character.ID))
+		http.Error(w, "Error equipping item", http.StatusInternalServerError)
+		return
+	}
+	item, _ := loadout.Item(itemID)
+	slot, _ := loadout.Slot(slotID)
+
+	data := struct {
+		IsAuthenticated bool
+		Username        string
+		Character       db.Character
+		Item            equipment.Item
+		Slot            equipment.Slot
+		Blockers        []equipment.Item
+		FlashMessage    string
+		CurrentYear     int
+	}{
+		IsAuthenticated: true,
+		Username:        user.Username,
+		Character:       character,
+		Item:            item,
+		Slot:            slot,
+		Blockers:        blockers,
+		CurrentYear:     time.Now().Year(),
+	}
+
+	RenderTemplate(w, "templates/inventory/swap.html", "base.html", data)
 }
 
 // HandleUnequipItem handles unequipping items from equipment slots to inventory
@@ -452,10 +490,7 @@ func (s *Server) HandleUnequipItem(w http.ResponseWriter, r *http.Request) {
 
 	// Verify character belongs to user
 	queries := db.New(s.db)
-	_, err = queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	_, err = getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Character not found or belongs to another user",
 			zap.Error(err),
@@ -465,6 +500,20 @@ func (s *Server) HandleUnequipItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A cursed item won't come off until the curse is lifted
+	if err := checkItemRelease(r.Context(), queries, characterID, itemID, sql.NullInt64{}); err != nil {
+		logger.Warn("Unequip refused",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("item_id", itemID))
+		message := "Error unequipping item"
+		if equipment.IsRuleError(err) || errors.Is(err, errItemNotFound) {
+			message = "Error: " + err.Error()
+		}
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, url.QueryEscape(message)), http.StatusSeeOther)
+		return
+	}
+
 	// Unequip the item
 	err = queries.UnequipItem(r.Context(), db.UnequipItemParams{
 		ID:          itemID,
@@ -547,12 +596,19 @@ func (s *Server) HandleMoveToContainer(w http.ResponseWriter, r *http.Request) {
 		containerID = sql.NullInt64{Int64: id, Valid: true}
 	}
 
+	// Optionally move only part of a stack, splitting it
+	var quantity int64
+	if quantityStr := r.FormValue("quantity"); quantityStr != "" {
+		quantity, err = strconv.ParseInt(quantityStr, 10, 64)
+		if err != nil || quantity < 1 {
+			http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: Invalid quantity", characterID), http.StatusSeeOther)
+			return
+		}
+	}
+
 	// Verify character belongs to user
 	queries := db.New(s.db)
-	_, err = queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	_, err = getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Character not found or belongs to another user",
 			zap.Error(err),
@@ -561,45 +617,17 @@ func (s *Server) HandleMoveToContainer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// If a container was specified, verify it belongs to the character
-	if containerID.Valid {
-		// Check if the container exists and belongs to the character
-		inventory, err := queries.GetCharacterInventoryItems(r.Context(), characterID)
-		if err != nil {
-			logger.Error("Failed to fetch character inventory",
-				zap.Error(err),
-				zap.Int64("character_id", characterID))
-			http.Error(w, "Error retrieving inventory", http.StatusInternalServerError)
-			return
-		}
-
-		containerFound := false
-		for _, item := range inventory {
-			if item.ID == containerID.Int64 && item.ItemType == "container" {
-				containerFound = true
-				break
-			}
-		}
-
-		if !containerFound {
-			logger.Warn("Container not found or doesn't belong to character",
-				zap.Int64("character_id", characterID),
-				zap.Int64("container_id", containerID.Int64))
-			http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Container not found", characterID), http.StatusSeeOther)
-			return
-		}
-
-		// Check if the container has capacity for the item
-		// This would require additional logic to check weight and item counts
-		// For now, we'll skip detailed capacity checking
+	// Capacity, allowed tags and nesting are checked before anything moves
+	err = s.moveInventoryItem(r.Context(), characterID, itemID, quantity, containerID)
+	if containers.IsRuleError(err) || equipment.IsRuleError(err) || errors.Is(err, errItemNotFound) {
+		logger.Warn("Item move refused",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("item_id", itemID),
+			zap.Any("container_id", containerID))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, url.QueryEscape("Error: "+err.Error())), http.StatusSeeOther)
+		return
 	}
-
-	// Move the item to the container
-	err = queries.MoveItemToContainer(r.Context(), db.MoveItemToContainerParams{
-		ContainerID: containerID,
-		ID:          itemID,
-		CharacterID: characterID,
-	})
 	if err != nil {
 		logger.Error("Failed to move item to container",
 			zap.Error(err),
@@ -637,19 +665,6 @@ func handleAddItemForm(s *Server, w http.ResponseWriter, r *http.Request, charac
 		}
 	}
 
-	// Get enhancement query param with proper type conversion to int64
-	var enhancementBonus int64
-	if enhancementStr := r.URL.Query().Get("enhancement"); enhancementStr != "" {
-		var err error
-		enhancementBonus, err = strconv.ParseInt(enhancementStr, 10, 64)
-		if err != nil {
-			logger.Warn("Invalid enhancement bonus value",
-				zap.Error(err),
-				zap.String("raw_value", enhancementStr))
-			enhancementBonus = 0
-		}
-	}
-
 	// Get available containers for the character
 	containers, err := queries.GetCharacterInventoryItems(r.Context(), character.ID)
 	if err != nil {
@@ -693,7 +708,8 @@ func handleAddItemForm(s *Server, w http.ResponseWriter, r *http.Request, charac
 		ShowEquipmentSlots bool
 		FlashMessage       string
 		CurrentYear        int
-		Enhancement        int64 // Changed to int64
+		ShowEnhancement    bool
+		MaxEnhancement     int
 	}{
 		IsAuthenticated:    ok,
 		Username:           username,
@@ -704,7 +720,8 @@ func handleAddItemForm(s *Server, w http.ResponseWriter, r *http.Request, charac
 		ShowEquipmentSlots: itemType == "weapon" || itemType == "armor" || itemType == "shield",
 		FlashMessage:       r.URL.Query().Get("message"),
 		CurrentYear:        time.Now().Year(),
-		Enhancement:        enhancementBonus,
+		ShowEnhancement:    enhanceableItemTypes[itemType],
+		MaxEnhancement:     maxEnhancementBonus,
 	}
 
 	// Filter containers from inventory
@@ -717,58 +734,7 @@ func handleAddItemForm(s *Server, w http.ResponseWriter, r *http.Request, charac
 	// If a type is selected, fetch available items of that type
 	if itemType != "" {
 		var err error
-
-		switch itemType {
-		case "weapon":
-			if enhancementBonus > 0 {
-				// Fetch enhanced weapons with proper conversion to sql.NullInt64
-				data.Items, err = queries.GetEnhancedWeapons(r.Context(), sql.NullInt64{
-					Int64: enhancementBonus,
-					Valid: true,
-				})
-			} else {
-				// Fetch regular weapons
-				data.Items, err = queries.GetAllWeapons(r.Context())
-			}
-		case "armor":
-			if enhancementBonus > 0 {
-				// Fetch enhanced armor with proper conversion to sql.NullInt64
-				data.Items, err = queries.GetEnhancedArmor(r.Context(), sql.NullInt64{
-					Int64: enhancementBonus,
-					Valid: true,
-				})
-			} else {
-				// Fetch regular armor
-				data.Items, err = queries.GetAllArmor(r.Context())
-			}
-		case "shield":
-			if enhancementBonus > 0 {
-				// Fetch enhanced shields with proper conversion to sql.NullInt64
-				data.Items, err = queries.GetEnhancedShields(r.Context(), sql.NullInt64{
-					Int64: enhancementBonus,
-					Valid: true,
-				})
-			} else {
-				// Fetch regular shields
-				data.Items, err = queries.GetAllShields(r.Context())
-			}
-		case "ranged_weapon":
-			if enhancementBonus > 0 {
-				// Fetch enhanced ranged weapons with proper conversion to sql.NullInt64
-				data.Items, err = queries.GetEnhancedRangedWeapons(r.Context(), sql.NullInt64{
-					Int64: enhancementBonus,
-					Valid: true,
-				})
-			} else {
-				// Fetch regular ranged weapons
-				data.Items, err = queries.GetAllRangedWeapons(r.Context())
-			}
-		case "equipment":
-			data.Items, err = queries.GetAllEquipment(r.Context())
-		case "ammunition":
-			data.Items, err = queries.GetAllAmmunition(r.Context())
-		}
-
+		data.Items, err = queries.ListCatalogItems(r.Context(), itemType)
 		if err != nil {
 			logger.Error("Failed to fetch items",
 				zap.Error(err),
@@ -834,17 +800,39 @@ func handleAddItemSubmission(s *Server, w http.ResponseWriter, r *http.Request,
 		notesNull = sql.NullString{String: notes, Valid: true}
 	}
 
+	// Magic bonuses and properties are stored on the owned item
+	var enhancement int64
+	var properties []string
+	if enhanceableItemTypes[itemType] {
+		enhancement, err = parseEnhancement(r.FormValue("enhancement"))
+		if err != nil {
+			http.Redirect(w, r, fmt.Sprintf("/characters/inventory/add?character_id=%d&type=%s&message=Error: %s", character.ID, itemType, err), http.StatusSeeOther)
+			return
+		}
+		properties = parsePropertyNames(r.FormValue("magical_properties"))
+	}
+
+	curse, err := parseCurse(r)
+	if err != nil {
+		http.Redirect(w, r, fmt.Sprintf("/characters/inventory/add?character_id=%d&type=%s&message=%s", character.ID, itemType, url.QueryEscape("Error: "+err.Error())), http.StatusSeeOther)
+		return
+	}
+
 	// Add item to inventory
-	_, err = queries.AddItemToInventory(r.Context(), db.AddItemToInventoryParams{
-		CharacterID:     character.ID,
-		ItemID:          itemID,
-		ItemType:        itemType,
-		Quantity:        quantity,
-		ContainerID:     containerID,
-		EquipmentSlotID: equipmentSlotID,
-		Notes:           notesNull,
-	})
+	err = s.addInventoryItem(r.Context(), db.AddItemToInventoryParams{
+		CharacterID:      character.ID,
+		ItemID:           itemID,
+		Quantity:         quantity,
+		ContainerID:      containerID,
+		EquipmentSlotID:  equipmentSlotID,
+		EnhancementBonus: enhancement,
+		Notes:            notesNull,
+	}, properties, curse, r.FormValue("identified") == "1")
 
+	if containers.IsRuleError(err) || equipment.IsRuleError(err) {
+		http.Redirect(w, r, fmt.Sprintf("/characters/inventory/add?character_id=%d&type=%s&message=%s", character.ID, itemType, url.QueryEscape(err.Error())), http.StatusSeeOther)
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to add item to inventory",
 			zap.Error(err),
@@ -897,10 +885,7 @@ func (s *Server) HandleInventoryModal(w http.ResponseWriter, r *http.Request) {
 
 	// Verify character belongs to user
 	queries := db.New(s.db)
-	_, err = queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	_, err = getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Character not found or belongs to another user",
 			zap.Error(err), zap.Int64("character_id", characterID))
@@ -911,19 +896,6 @@ func (s *Server) HandleInventoryModal(w http.ResponseWriter, r *http.Request) {
 	// Get item type from query parameters (optional)
 	itemType := r.URL.Query().Get("type")
 
-	// Get enhancement bonus from query parameters (optional)
-	var enhancement int64
-	if enhancementStr := r.URL.Query().Get("enhancement"); enhancementStr != "" {
-		var err error
-		enhancement, err = strconv.ParseInt(enhancementStr, 10, 64)
-		if err != nil {
-			logger.Warn("Invalid enhancement bonus value",
-				zap.Error(err),
-				zap.String("raw_value", enhancementStr))
-			enhancement = 0
-		}
-	}
-
 	// Get container ID if adding to a container (optional)
 	var containerID sql.NullInt64
 	if containerIDStr := r.URL.Query().Get("container_id"); containerIDStr != "" {
@@ -935,30 +907,6 @@ func (s *Server) HandleInventoryModal(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// If we have a type but no enhancement selection yet for applicable item types,
-	// and we're not on an enhancement selection step, show the enhancement selection form
-	var enhancementStr string = r.URL.Query().Get("enhancement")
-	if enhancementStr != "" {
-		var err error
-		enhancement, err = strconv.ParseInt(enhancementStr, 10, 64)
-		if err != nil {
-			logger.Warn("Invalid enhancement bonus value",
-				zap.Error(err),
-				zap.String("raw_value", enhancementStr))
-			enhancement = 0
-		}
-	}
-
-	// If we have a type but no enhancement selection yet for applicable item types,
-	// and we're not on an enhancement selection step, show the enhancement selection form
-	if itemType != "" &&
-		enhancementStr == "" &&
-		r.URL.Query().Get("step") != "enhancement" &&
-		(itemType == "weapon" || itemType == "armor" || itemType == "shield" || itemType == "ranged_weapon") {
-		renderEnhancementSelectionForm(w, characterID, itemType, containerID)
-		return
-	}
-
 	// Get available containers for the character
 	containers, err := queries.GetCharacterInventoryItems(r.Context(), characterID)
 	if err != nil {
@@ -989,7 +937,8 @@ func (s *Server) HandleInventoryModal(w http.ResponseWriter, r *http.Request) {
 	data := struct {
 		CharacterID        int64
 		SelectedType       string
-		Enhancement        int64
+		ShowEnhancement    bool
+		MaxEnhancement     int
 		Items              interface{}
 		Containers         []db.GetCharacterInventoryItemsRow
 		EquipmentSlots     []db.EquipmentSlot
@@ -999,7 +948,8 @@ func (s *Server) HandleInventoryModal(w http.ResponseWriter, r *http.Request) {
 	}{
 		CharacterID:        characterID,
 		SelectedType:       itemType,
-		Enhancement:        enhancement,
+		ShowEnhancement:    enhanceableItemTypes[itemType],
+		MaxEnhancement:     maxEnhancementBonus,
 		Containers:         filteredContainers,
 		EquipmentSlots:     equipmentSlots,
 		ShowEquipmentSlots: itemType == "weapon" || itemType == "armor" || itemType == "shield" || itemType == "ranged_weapon",
@@ -1008,61 +958,9 @@ func (s *Server) HandleInventoryModal(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// If a type is selected, fetch available items of that type
-	if itemType != "" && (enhancementStr != "" ||
-		!(itemType == "weapon" || itemType == "armor" || itemType == "shield" || itemType == "ranged_weapon")) {
+	if itemType != "" {
 		var err error
-
-		switch itemType {
-		case "weapon":
-			if enhancement > 0 {
-				// Fetch enhanced weapons with proper sql.NullInt64
-				data.Items, err = queries.GetEnhancedWeapons(r.Context(), sql.NullInt64{
-					Int64: enhancement,
-					Valid: true,
-				})
-			} else {
-				// Fetch regular weapons
-				data.Items, err = queries.GetAllWeapons(r.Context())
-			}
-		case "armor":
-			if enhancement > 0 {
-				// Fetch enhanced armor with proper sql.NullInt64
-				data.Items, err = queries.GetEnhancedArmor(r.Context(), sql.NullInt64{
-					Int64: enhancement,
-					Valid: true,
-				})
-			} else {
-				// Fetch regular armor
-				data.Items, err = queries.GetAllArmor(r.Context())
-			}
-		case "shield":
-			if enhancement > 0 {
-				// Fetch enhanced shields with proper sql.NullInt64
-				data.Items, err = queries.GetEnhancedShields(r.Context(), sql.NullInt64{
-					Int64: enhancement,
-					Valid: true,
-				})
-			} else {
-				// Fetch regular shields
-				data.Items, err = queries.GetAllShields(r.Context())
-			}
-		case "ranged_weapon":
-			if enhancement > 0 {
-				// Fetch enhanced ranged weapons with proper sql.NullInt64
-				data.Items, err = queries.GetEnhancedRangedWeapons(r.Context(), sql.NullInt64{
-					Int64: enhancement,
-					Valid: true,
-				})
-			} else {
-				// Fetch regular ranged weapons
-				data.Items, err = queries.GetAllRangedWeapons(r.Context())
-			}
-		case "equipment":
-			data.Items, err = queries.GetAllEquipment(r.Context())
-		case "ammunition":
-			data.Items, err = queries.GetAllAmmunition(r.Context())
-		}
-
+		data.Items, err = queries.ListCatalogItems(r.Context(), itemType)
 		if err != nil {
 			logger.Error("Failed to fetch items",
 				zap.Error(err),
@@ -1086,6 +984,7 @@ func (s *Server) HandleInventoryModal(w http.ResponseWriter, r *http.Request) {
                 <option value="container">Container</option>
                 <option value="shield">Shield</option>
                 <option value="ranged_weapon">Ranged Weapon</option>
+                <option value="treasure">Gems, Jewellery &amp; Art</option>
             </select>
         </div>
         <div class="form-actions">
@@ -1093,38 +992,10 @@ func (s *Server) HandleInventoryModal(w http.ResponseWriter, r *http.Request) {
             <button type="button" class="button close-modal">Cancel</button>
         </div>
     </form>
-    {{else if and (or (eq .SelectedType "weapon") (eq .SelectedType "armor") (eq .SelectedType "shield") (eq .SelectedType "ranged_weapon")) (eq .Enhancement 0)}}
-    <!-- Enhancement selection form -->
-    <form hx-get="/characters/inventory/modal" hx-target="#add-item-form-container">
-        <input type="hidden" name="character_id" value="{{.CharacterID}}">
-        <input type="hidden" name="type" value="{{.SelectedType}}">
-        <input type="hidden" name="step" value="enhancement">
-        {{if .HasContainerID}}
-        <input type="hidden" name="container_id" value="{{.ContainerID.Int64}}">
-        {{end}}
-        
-        <div class="form-group">
-            <label for="enhancement">Enhancement Bonus:</label>
-            <select name="enhancement" id="enhancement" required>
-                <option value="0">No Enhancement (+0)</option>
-                <option value="1">Enhanced (+1)</option>
-                <option value="2">Enhanced (+2)</option>
-                <option value="3">Enhanced (+3)</option>
-            </select>
-        </div>
-        
-        <div class="form-actions">
-            <button type="submit" class="button primary">Next</button>
-            <button type="button" hx-get="/characters/inventory/modal?character_id={{.CharacterID}}"
-                hx-target="#add-item-form-container" class="button">Back</button>
-            <button type="button" class="button close-modal">Cancel</button>
-        </div>
-    </form>
     {{else}}
     <form hx-post="/characters/inventory/add-modal" hx-target="#character-sheet-container">
         <input type="hidden" name="character_id" value="{{.CharacterID}}">
         <input type="hidden" name="item_type" value="{{.SelectedType}}">
-        <input type="hidden" name="enhancement" value="{{.Enhancement}}">
         {{if .HasContainerID}}
         <input type="hidden" name="container_id" value="{{.ContainerID.Int64}}">
         {{end}}
@@ -1135,7 +1006,7 @@ func (s *Server) HandleInventoryModal(w http.ResponseWriter, r *http.Request) {
                 <option value="">-- Select Item --</option>
                 {{range .Items}}
                 <option value="{{.ID}}">
-                    {{.Name}} {{if gt .Weight 0}}({{.Weight}} lbs){{end}} {{if gt .CostGp 0}}({{.CostGp}} gp){{end}}
+                    {{.Name}} {{if gt .Weight 0.0}}({{.Weight}} lbs){{end}} {{if gt .CostGp 0.0}}({{.CostGp}} gp){{end}}
                 </option>
                 {{end}}
             </select>
@@ -1146,6 +1017,18 @@ func (s *Server) HandleInventoryModal(w http.ResponseWriter, r *http.Request) {
             <input type="number" name="quantity" id="quantity" value="1" min="1" required>
         </div>
  
+        {{if .ShowEnhancement}}
+        <div class="form-group">
+            <label for="enhancement">Enhancement Bonus:</label>
+            <input type="number" name="enhancement" id="enhancement" value="0" min="-{{.MaxEnhancement}}" max="{{.MaxEnhancement}}">
+        </div>
+ 
+        <div class="form-group">
+            <label for="magical_properties">Magical Properties (optional, comma separated):</label>
+            <input type="text" name="magical_properties" id="magical_properties" placeholder="flaming, of slaying dragons">
+        </div>
+        {{end}}
+ 
         {{if .Containers}}
         <div class="form-group">
             <label for="container_id">Store in Container (optional):</label>
@@ -1210,61 +1093,6 @@ func (s *Server) HandleInventoryModal(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func renderEnhancementSelectionForm(w http.ResponseWriter, characterID int64, itemType string, containerID sql.NullInt64) {
-	// Create and parse the template directly from a string
-	tmpl, err := template.New("enhancement_form").Parse(`
-	<form hx-get="/characters/inventory/modal" hx-target="#add-item-form-container">
-		<input type="hidden" name="character_id" value="{{.CharacterID}}">
-		<input type="hidden" name="type" value="{{.ItemType}}">
-		<input type="hidden" name="step" value="enhancement">
-		{{if .HasContainerID}}
-		<input type="hidden" name="container_id" value="{{.ContainerID}}">
-		{{end}}
-		
-		<div class="form-group">
-			<label for="enhancement">Enhancement Bonus:</label>
-			<select name="enhancement" id="enhancement" required>
-				<option value="0">No Enhancement (+0)</option>
-				<option value="1">Enhanced (+1)</option>
-				<option value="2">Enhanced (+2)</option>
-				<option value="3">Enhanced (+3)</option>
-			</select>
-		</div>
-		
-		<div class="form-actions">
-			<button type="submit" class="button primary">Next</button>
-			<button type="button" hx-get="/characters/inventory/modal?character_id={{.CharacterID}}"
-				hx-target="#add-item-form-container" class="button">Back</button>
-			<button type="button" class="button close-modal">Cancel</button>
-		</div>
-	</form>
-	`)
-
-	if err != nil {
-		logger.Error("Enhancement form template parsing error", zap.Error(err))
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	data := struct {
-		CharacterID    int64
-		ItemType       string
-		HasContainerID bool
-		ContainerID    int64
-	}{
-		CharacterID:    characterID,
-		ItemType:       itemType,
-		HasContainerID: containerID.Valid,
-		ContainerID:    containerID.Int64,
-	}
-
-	if err := tmpl.Execute(w, data); err != nil {
-		logger.Error("Enhancement form template execution error", zap.Error(err))
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-}
-
 func (s *Server) HandleAddItemModal(w http.ResponseWriter, r *http.Request) {
 	user, ok := GetUserFromContext(r.Context())
 	if !ok {
@@ -1288,10 +1116,7 @@ func (s *Server) HandleAddItemModal(w http.ResponseWriter, r *http.Request) {
 
 	// Fetch character to verify ownership
 	queries := db.New(s.db)
-	character, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Character not found or belongs to another user",
 			zap.Error(err), zap.Int64("character_id", characterID))
@@ -1308,11 +1133,16 @@ func (s *Server) HandleAddItemModal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse enhancement
-	enhancementStr := r.FormValue("enhancement")
+	// Magic bonuses and properties are stored on the owned item
 	var enhancement int64
-	if enhancementStr != "" {
-		enhancement, _ = strconv.ParseInt(enhancementStr, 10, 64)
+	var properties []string
+	if enhanceableItemTypes[itemType] {
+		enhancement, err = parseEnhancement(r.FormValue("enhancement"))
+		if err != nil {
+			renderCharacterWithMessage(s, w, r, character, "Error: "+err.Error())
+			return
+		}
+		properties = parsePropertyNames(r.FormValue("magical_properties"))
 	}
 
 	// Parse quantity (default to 1)
@@ -1335,18 +1165,6 @@ func (s *Server) HandleAddItemModal(w http.ResponseWriter, r *http.Request) {
 	var equipmentSlotID sql.NullInt64
 	if slotIDStr := r.FormValue("equipment_slot_id"); slotIDStr != "" {
 		if id, err := strconv.ParseInt(slotIDStr, 10, 64); err == nil {
-			// Check if the slot is already occupied
-			isOccupied, err := queries.IsSlotOccupied(r.Context(), db.IsSlotOccupiedParams{
-				CharacterID:     characterID,
-				EquipmentSlotID: sql.NullInt64{Int64: id, Valid: true},
-			})
-			if err != nil {
-				logger.Error("Failed to check if slot is occupied", zap.Error(err))
-			} else if isOccupied {
-				// Render character sheet with error message
-				renderCharacterWithMessage(s, w, r, character, "Equipment slot is already occupied")
-				return
-			}
 			equipmentSlotID = sql.NullInt64{Int64: id, Valid: true}
 		}
 	}
@@ -1357,30 +1175,27 @@ func (s *Server) HandleAddItemModal(w http.ResponseWriter, r *http.Request) {
 		notes = sql.NullString{String: notesStr, Valid: true}
 	}
 
-	// Add a note about enhancement if applicable
-	if enhancement > 0 && notes.String == "" {
-		notes = sql.NullString{
-			String: fmt.Sprintf("+%d enhancement", enhancement),
-			Valid:  true,
-		}
-	} else if enhancement > 0 {
-		notes = sql.NullString{
-			String: fmt.Sprintf("%s (+%d enhancement)", notes.String, enhancement),
-			Valid:  true,
-		}
+	curse, err := parseCurse(r)
+	if err != nil {
+		renderCharacterWithMessage(s, w, r, character, "Error: "+err.Error())
+		return
 	}
 
 	// Add item to inventory
-	_, err = queries.AddItemToInventory(r.Context(), db.AddItemToInventoryParams{
-		CharacterID:     characterID,
-		ItemID:          itemID,
-		ItemType:        itemType,
-		Quantity:        quantity,
-		ContainerID:     containerID,
-		EquipmentSlotID: equipmentSlotID,
-		Notes:           notes,
-	})
+	err = s.addInventoryItem(r.Context(), db.AddItemToInventoryParams{
+		CharacterID:      characterID,
+		ItemID:           itemID,
+		Quantity:         quantity,
+		ContainerID:      containerID,
+		EquipmentSlotID:  equipmentSlotID,
+		EnhancementBonus: enhancement,
+		Notes:            notes,
+	}, properties, curse, r.FormValue("identified") == "1")
 
+	if containers.IsRuleError(err) || equipment.IsRuleError(err) {
+		renderCharacterWithMessage(s, w, r, character, err.Error())
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to add item to inventory",
 			zap.Error(err),
@@ -1414,7 +1229,17 @@ func renderCharacterWithMessage(s *Server, w http.ResponseWriter, r *http.Reques
 	}
 
 	// Create view model
-	viewModel := NewSafeCharacterViewModel(character, inventory)
+	coinage, purse := characterCoins(r.Context(), queries, character.ID)
+	viewModel := NewSafeCharacterViewModel(character, inventory, coinage, purse)
+	// Callers have already checked write access with getWritableCharacter
+	viewModel.CanEdit = true
+	if user, ok := GetUserFromContext(r.Context()); ok {
+		viewModel.IsOwner = user.UserID == character.UserID
+	}
+	if !viewModel.IsOwner {
+		viewModel.revealItems()
+	}
+	s.loadCharacterDetails(r.Context(), queries, &viewModel)
 
 	// Render full character detail page
 	tmpl, err := template.New("detail-content").Funcs(template.FuncMap{
@@ -1508,6 +1333,15 @@ func renderCharacterWithMessage(s *Server, w http.ResponseWriter, r *http.Reques
 			}
 			return dict, nil
 		},
+		"percentage": func(current, total int64) int {
+			if total == 0 {
+				return 100
+			}
+			if current >= total {
+				return 100
+			}
+			return int((float64(current) / float64(total)) * 100)
+		},
 		"contains": containsString,
 	}).ParseFiles(
 		"templates/characters/details.html",
@@ -1521,6 +1355,9 @@ func renderCharacterWithMessage(s *Server, w http.ResponseWriter, r *http.Reques
 		"templates/characters/_hp_display.html",
 		"templates/characters/_hp_section.html",
 		"templates/characters/_currency_section.html",
+		"templates/characters/_rest_section.html",
+		"templates/characters/_xp_section.html",
+		"templates/characters/_container.html",
 	)
 
 	if err != nil {
@@ -1565,10 +1402,7 @@ func (s *Server) HandleAddMagicalItem(w http.ResponseWriter, r *http.Request) {
 
 	// Verify character belongs to user
 	queries := db.New(s.db)
-	character, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Character not found or belongs to another user",
 			zap.Error(err), zap.Int64("character_id", characterID))
@@ -1713,6 +1547,26 @@ func handleAddMagicalItemSubmission(s *Server, w http.ResponseWriter, r *http.Re
 		notes = sql.NullString{String: notesStr, Valid: true}
 	}
 
+	// Stored magic items follow the same container rules as anything else
+	if err := checkNewItemPlacement(r.Context(), queries, character.ID, itemID, 1, containerID); err != nil {
+		logger.Warn("Magical item refused by container",
+			zap.Error(err),
+			zap.Int64("character_id", character.ID),
+			zap.Int64("item_id", itemID))
+		http.Redirect(w, r, fmt.Sprintf("/characters/inventory/add-magical?character_id=%d&message=%s", character.ID, err), http.StatusSeeOther)
+		return
+	}
+
+	// And the same slot rules
+	if err := checkNewItemEquip(r.Context(), queries, character.ID, itemID, equipmentSlotID); err != nil {
+		logger.Warn("Magical item refused by equipment slot",
+			zap.Error(err),
+			zap.Int64("character_id", character.ID),
+			zap.Int64("item_id", itemID))
+		http.Redirect(w, r, fmt.Sprintf("/characters/inventory/add-magical?character_id=%d&message=%s", character.ID, url.QueryEscape(err.Error())), http.StatusSeeOther)
+		return
+	}
+
 	// Add item to inventory with charges
 	_, err = queries.AddMagicalItemToInventory(r.Context(), db.AddMagicalItemToInventoryParams{
 		CharacterID:     character.ID,
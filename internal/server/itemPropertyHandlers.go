@@ -0,0 +1,360 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/magic"
+	"go.uber.org/zap"
+)
+
+// maxEnhancementBonus bounds the magic bonus on an owned item in either
+// direction; negative bonuses are cursed gear
+const maxEnhancementBonus = 5
+
+// enhanceableItemTypes are the catalog types that can carry an enhancement
+// bonus and named magical properties
+var enhanceableItemTypes = map[string]bool{
+	"weapon":        true,
+	"armor":         true,
+	"shield":        true,
+	"ranged_weapon": true,
+	"ammunition":    true,
+}
+
+// parseEnhancement reads an enhancement bonus form value, treating an empty
+// value as no bonus
+func parseEnhancement(raw string) (int64, error) {
+	raw = strings.TrimPrefix(strings.TrimSpace(raw), "+")
+	if raw == "" {
+		return 0, nil
+	}
+	bonus, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if bonus < -maxEnhancementBonus || bonus > maxEnhancementBonus {
+		return 0, fmt.Errorf("enhancement bonus must be between -%d and %d", maxEnhancementBonus, maxEnhancementBonus)
+	}
+	return bonus, nil
+}
+
+// parsePropertyNames splits a comma separated list such as
+// "flaming, of slaying dragons" into property names
+func parsePropertyNames(raw string) []string {
+	var names []string
+	for _, name := range strings.Split(raw, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// addInventoryItem stores a new owned item together with its magical
+// properties and any curse, refusing it if it breaks the rules of its
+// container. Items with magic to hide start unidentified unless already known.
+func (s *Server) addInventoryItem(ctx context.Context, params db.AddItemToInventoryParams, properties []string, curse *magic.Curse, identified bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := db.New(s.db).WithTx(tx)
+	if _, err := insertInventoryItem(ctx, qtx, params, properties, curse, identified); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertInventoryItem adds an item within the caller's transaction,
+// returning the new inventory row's ID
+func insertInventoryItem(ctx context.Context, qtx *db.Queries, params db.AddItemToInventoryParams, properties []string, curse *magic.Curse, identified bool) (int64, error) {
+	if err := checkNewItemPlacement(ctx, qtx, params.CharacterID, params.ItemID, params.Quantity, params.ContainerID); err != nil {
+		return 0, err
+	}
+	if err := checkNewItemEquip(ctx, qtx, params.CharacterID, params.ItemID, params.EquipmentSlotID); err != nil {
+		return 0, err
+	}
+
+	item, err := qtx.AddItemToInventory(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+
+	// Magical items start fully charged
+	if err := qtx.FillItemCharges(ctx, db.FillItemChargesParams{
+		ID:          item.ID,
+		CharacterID: params.CharacterID,
+	}); err != nil {
+		return 0, err
+	}
+
+	for _, name := range properties {
+		if _, err := qtx.CreateItemProperty(ctx, db.CreateItemPropertyParams{
+			Name:        name,
+			ID:          item.ID,
+			CharacterID: params.CharacterID,
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	if curse != nil {
+		if err := setItemCurse(ctx, qtx, params.CharacterID, item.ID, curse); err != nil {
+			return 0, err
+		}
+	}
+
+	identity, err := inventoryItemIdentity(ctx, qtx, params.CharacterID, item.ID)
+	if err != nil {
+		return 0, err
+	}
+	if !identified && identity.Concealed() {
+		if err := qtx.SetItemIdentified(ctx, db.SetItemIdentifiedParams{
+			IsIdentified: false,
+			ID:           item.ID,
+			CharacterID:  params.CharacterID,
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	return item.ID, nil
+}
+
+// loadItemProperties attaches named magical properties to the items on a
+// view model
+func (s *Server) loadItemProperties(ctx context.Context, queries *db.Queries, vm *CharacterViewModel) {
+	properties, err := queries.ListCharacterItemProperties(ctx, vm.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch item properties",
+			zap.Error(err),
+			zap.Int64("character_id", vm.ID))
+		return
+	}
+
+	byItem := make(map[int64][]db.CharacterInventoryProperty)
+	for _, property := range properties {
+		byItem[property.InventoryID] = append(byItem[property.InventoryID], property)
+	}
+
+	attach := func(items []InventoryItem) {
+		for i := range items {
+			items[i].Properties = byItem[items[i].ID]
+		}
+	}
+	attach(vm.EquippedItems)
+	attach(vm.CarriedItems)
+	for _, items := range vm.ContainerItems {
+		attach(items)
+	}
+}
+
+// HandleSetItemEnhancement changes the magic bonus on an owned item
+func (s *Server) HandleSetItemEnhancement(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.Form.Get("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("character_id")))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(r.Form.Get("item_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid item ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("item_id")))
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	bonus, err := parseEnhancement(r.Form.Get("enhancement"))
+	if err != nil {
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: %s", characterID, err), http.StatusSeeOther)
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Error fetching character", zap.Error(err))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	if err := queries.SetItemEnhancement(r.Context(), db.SetItemEnhancementParams{
+		EnhancementBonus: bonus,
+		ID:               itemID,
+		CharacterID:      characterID,
+	}); err != nil {
+		logger.Error("Failed to set item enhancement",
+			zap.Error(err),
+			zap.Int64("item_id", itemID))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error updating enhancement", characterID), http.StatusSeeOther)
+		return
+	}
+
+	logger.Info("Item enhancement updated",
+		zap.Int64("character_id", characterID),
+		zap.Int64("item_id", itemID),
+		zap.Int64("enhancement", bonus))
+
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Enhancement updated", characterID), http.StatusSeeOther)
+}
+
+// HandleAddItemProperty gives an owned item a named magical property such
+// as flaming or of slaying dragons
+func (s *Server) HandleAddItemProperty(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.Form.Get("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("character_id")))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(r.Form.Get("item_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid item ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("item_id")))
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.Form.Get("name"))
+	if name == "" {
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: Property name is required", characterID), http.StatusSeeOther)
+		return
+	}
+
+	var notes sql.NullString
+	if notesStr := strings.TrimSpace(r.Form.Get("notes")); notesStr != "" {
+		notes = sql.NullString{String: notesStr, Valid: true}
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Error fetching character", zap.Error(err))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	if _, err := queries.CreateItemProperty(r.Context(), db.CreateItemPropertyParams{
+		Name:        name,
+		Notes:       notes,
+		ID:          itemID,
+		CharacterID: characterID,
+	}); err != nil {
+		logger.Error("Failed to add item property",
+			zap.Error(err),
+			zap.Int64("item_id", itemID))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error adding property", characterID), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s added", characterID, name), http.StatusSeeOther)
+}
+
+// HandleRemoveItemProperty strips a named magical property from an owned
+// item
+func (s *Server) HandleRemoveItemProperty(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.Form.Get("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("character_id")))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	propertyID, err := strconv.ParseInt(r.Form.Get("property_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid property ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("property_id")))
+		http.Error(w, "Invalid property ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Error fetching character", zap.Error(err))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	if err := queries.DeleteItemProperty(r.Context(), db.DeleteItemPropertyParams{
+		ID:          propertyID,
+		CharacterID: characterID,
+	}); err != nil {
+		logger.Error("Failed to remove item property",
+			zap.Error(err),
+			zap.Int64("property_id", propertyID))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error removing property", characterID), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Property removed", characterID), http.StatusSeeOther)
+}
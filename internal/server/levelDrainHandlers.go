@@ -0,0 +1,429 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	charRules "github.com/marbh56/mordezzan/internal/rules/character"
+	"go.uber.org/zap"
+)
+
+// HandleLevelDrain drops a character one or more levels, setting their XP
+// within the new level and removing the hit points gained at the lost levels
+func (s *Server) HandleLevelDrain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.Form.Get("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	levels := int64(1)
+	if levelsStr := r.Form.Get("levels"); levelsStr != "" {
+		levels, err = strconv.ParseInt(levelsStr, 10, 64)
+		if err != nil || levels < 1 {
+			logger.Warn("Invalid drain levels", zap.String("levels", levelsStr))
+			http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: Invalid number of levels", characterID), http.StatusSeeOther)
+			return
+		}
+	}
+
+	policyStr := r.Form.Get("xp_policy")
+	if policyStr != "" && !charRules.IsValidDrainXPPolicy(policyStr) {
+		logger.Warn("Invalid drain XP policy", zap.String("xp_policy", policyStr))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: Invalid XP setting", characterID), http.StatusSeeOther)
+		return
+	}
+
+	sessionDate, err := parseSessionDate(r.Form.Get("session_date"))
+	if err != nil {
+		logger.Warn("Invalid session date", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: Invalid session date", characterID), http.StatusSeeOther)
+		return
+	}
+
+	var notes sql.NullString
+	if notesStr := r.Form.Get("notes"); notesStr != "" {
+		notes = sql.NullString{String: notesStr, Valid: true}
+	}
+
+	queries := db.New(s.db)
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Error fetching character", zap.Error(err))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	// Without an explicit choice, follow the campaign's house rule
+	policy := charRules.DefaultDrainXPPolicy
+	if policyStr != "" {
+		policy = charRules.DrainXPPolicy(policyStr)
+	} else if campaign, err := queries.GetCharacterCampaign(r.Context(), characterID); err == nil {
+		policy = charRules.DrainXPPolicy(campaign.DrainXpPolicy)
+	}
+
+	newLevel := character.Level - levels
+	if newLevel < 1 {
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: Cannot drain below level 1", characterID), http.StatusSeeOther)
+		return
+	}
+
+	tx, err := s.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error draining levels", characterID), http.StatusSeeOther)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := queries.WithTx(tx)
+
+	currentXP, err := qtx.GetCharacterXPTotal(r.Context(), characterID)
+	if err != nil {
+		logger.Error("Failed to total XP ledger", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error draining levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	hpGained, err := qtx.GetHitPointsAboveLevel(r.Context(), db.GetHitPointsAboveLevelParams{
+		CharacterID: characterID,
+		Level:       newLevel,
+	})
+	if err != nil {
+		logger.Error("Failed to read level history", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error draining levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	progression := charRules.GetClassProgression(character.Class)
+	targetXP := progression.GetDrainedXP(newLevel, policy)
+
+	award, err := qtx.CreateXPAward(r.Context(), db.CreateXPAwardParams{
+		CharacterID: characterID,
+		Amount:      targetXP - currentXP,
+		Source:      "adjustment",
+		SessionDate: sessionDate,
+		AwardedBy:   user.UserID,
+		Notes: sql.NullString{
+			String: fmt.Sprintf("Energy drain: level %d to %d", character.Level, newLevel),
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		logger.Error("Failed to record drain XP", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error draining levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	newMaxHP := character.MaxHp - hpGained
+	if newMaxHP < 1 {
+		newMaxHP = 1
+	}
+	hpRemoved := character.MaxHp - newMaxHP
+
+	newCurrentHP := character.CurrentHp
+	if newCurrentHP > newMaxHP {
+		newCurrentHP = newMaxHP
+	}
+
+	drain, err := qtx.CreateLevelDrain(r.Context(), db.CreateLevelDrainParams{
+		CharacterID: characterID,
+		FromLevel:   character.Level,
+		ToLevel:     newLevel,
+		HpRemoved:   hpRemoved,
+		XpAwardID:   award.ID,
+		DrainedBy:   user.UserID,
+		Notes:       notes,
+	})
+	if err != nil {
+		logger.Error("Failed to record level drain", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error draining levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	if err := qtx.DrainLevelHistory(r.Context(), db.DrainLevelHistoryParams{
+		DrainID:     sql.NullInt64{Int64: drain.ID, Valid: true},
+		CharacterID: characterID,
+		Level:       newLevel,
+	}); err != nil {
+		logger.Error("Failed to mark drained levels", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error draining levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	// Sync only once the drained levels are marked, so it doesn't retire them
+	updatedChar, err := syncExperienceFromLedger(r.Context(), qtx, character)
+	if err != nil {
+		logger.Error("Failed to update character XP", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error draining levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	if _, err := qtx.UpdateCharacterHitPoints(r.Context(), db.UpdateCharacterHitPointsParams{
+		MaxHp:     newMaxHP,
+		CurrentHp: newCurrentHP,
+		ID:        characterID,
+		UserID:    character.UserID,
+	}); err != nil {
+		logger.Error("Failed to update hit points after drain", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error draining levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit level drain", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error draining levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	logger.Info("Character levels drained",
+		zap.Int64("character_id", characterID),
+		zap.Int64("drain_id", drain.ID),
+		zap.Int64("from_level", character.Level),
+		zap.Int64("to_level", updatedChar.Level),
+		zap.Int64("hp_removed", hpRemoved),
+		zap.Int64("new_xp", updatedChar.ExperiencePoints))
+
+	message := fmt.Sprintf("Drained to level %d, losing %d maximum HP", updatedChar.Level, hpRemoved)
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, message), http.StatusSeeOther)
+}
+
+// HandleLevelRestore undoes an energy drain, returning the drained XP and
+// the hit points recorded for the lost levels
+func (s *Server) HandleLevelRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.Form.Get("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	drainID, err := strconv.ParseInt(r.Form.Get("drain_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid drain ID", zap.Error(err))
+		http.Error(w, "Invalid drain ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Error fetching character", zap.Error(err))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	tx, err := s.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error restoring levels", characterID), http.StatusSeeOther)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := queries.WithTx(tx)
+
+	drain, err := qtx.GetLevelDrain(r.Context(), db.GetLevelDrainParams{
+		ID:          drainID,
+		CharacterID: characterID,
+	})
+	if err != nil {
+		logger.Error("Level drain not found",
+			zap.Error(err),
+			zap.Int64("drain_id", drainID),
+			zap.Int64("character_id", characterID))
+		http.Error(w, "Level drain not found", http.StatusNotFound)
+		return
+	}
+
+	if drain.RestoredAt.Valid {
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: This drain has already been restored", characterID), http.StatusSeeOther)
+		return
+	}
+
+	// Drains come back in the reverse of the order they were taken, so an
+	// earlier drain's levels aren't reopened under a later one
+	hasLater, err := qtx.HasLaterUnrestoredDrain(r.Context(), db.HasLaterUnrestoredDrainParams{
+		CharacterID: characterID,
+		ID:          drain.ID,
+	})
+	if err != nil {
+		logger.Error("Failed to read level drains", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error restoring levels", characterID), http.StatusSeeOther)
+		return
+	}
+	if hasLater {
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: Restore the most recent drain first", characterID), http.StatusSeeOther)
+		return
+	}
+
+	regained, err := qtx.CountActiveLevelsInRange(r.Context(), db.CountActiveLevelsInRangeParams{
+		CharacterID: characterID,
+		Level:       drain.ToLevel,
+		Level_2:     drain.FromLevel,
+	})
+	if err != nil {
+		logger.Error("Failed to read level history", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error restoring levels", characterID), http.StatusSeeOther)
+		return
+	}
+	if regained > 0 {
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: The drained levels have since been regained", characterID), http.StatusSeeOther)
+		return
+	}
+
+	award, err := qtx.GetXPAward(r.Context(), db.GetXPAwardParams{
+		ID:          drain.XpAwardID,
+		CharacterID: characterID,
+	})
+	if err != nil {
+		logger.Error("Drain XP award not found", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error restoring levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	// Bring the drained rows back before the XP sync opens fresh ones
+	if err := qtx.RestoreLevelHistory(r.Context(), sql.NullInt64{Int64: drain.ID, Valid: true}); err != nil {
+		logger.Error("Failed to restore level history", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error restoring levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	_, err = qtx.CreateXPAward(r.Context(), db.CreateXPAwardParams{
+		CharacterID:     characterID,
+		Amount:          -award.Amount,
+		Source:          award.Source,
+		SessionDate:     sessionToday(),
+		AwardedBy:       user.UserID,
+		ReversesAwardID: sql.NullInt64{Int64: award.ID, Valid: true},
+		Notes: sql.NullString{
+			String: fmt.Sprintf("Restoration: level %d to %d", drain.ToLevel, drain.FromLevel),
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		logger.Error("Failed to record restoration XP", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error restoring levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	updatedChar, err := syncExperienceFromLedger(r.Context(), qtx, character)
+	if err != nil {
+		logger.Error("Failed to update character XP", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error restoring levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	if _, err := qtx.UpdateCharacterHitPoints(r.Context(), db.UpdateCharacterHitPointsParams{
+		MaxHp:     updatedChar.MaxHp + drain.HpRemoved,
+		CurrentHp: updatedChar.CurrentHp,
+		ID:        characterID,
+		UserID:    character.UserID,
+	}); err != nil {
+		logger.Error("Failed to update hit points after restoration", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error restoring levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	if err := qtx.MarkLevelDrainRestored(r.Context(), db.MarkLevelDrainRestoredParams{
+		RestoredBy: sql.NullInt64{Int64: user.UserID, Valid: true},
+		ID:         drain.ID,
+	}); err != nil {
+		logger.Error("Failed to mark drain restored", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error restoring levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit restoration", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error restoring levels", characterID), http.StatusSeeOther)
+		return
+	}
+
+	logger.Info("Drained levels restored",
+		zap.Int64("character_id", characterID),
+		zap.Int64("drain_id", drain.ID),
+		zap.Int64("new_level", updatedChar.Level),
+		zap.Int64("hp_restored", drain.HpRemoved))
+
+	message := fmt.Sprintf("Restored to level %d, regaining %d maximum HP", updatedChar.Level, drain.HpRemoved)
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, message), http.StatusSeeOther)
+}
+
+// seedLevelHistory records the hit points of a new character, spreading
+// them across their starting levels with any remainder at 1st
+func seedLevelHistory(ctx context.Context, qtx *db.Queries, characterID, level, maxHP int64) error {
+	if level < 1 {
+		return nil
+	}
+	perLevel := maxHP / level
+	for l := int64(1); l <= level; l++ {
+		hp := perLevel
+		if l == 1 {
+			hp = maxHP - perLevel*(level-1)
+		}
+		if err := qtx.CreateLevelHistory(ctx, db.CreateLevelHistoryParams{
+			CharacterID: characterID,
+			Level:       l,
+			HpGained:    hp,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addLevelHitPoints credits a maximum HP change to the given level
+func addLevelHitPoints(ctx context.Context, qtx *db.Queries, characterID, level, hp int64) error {
+	if err := qtx.CreateLevelHistory(ctx, db.CreateLevelHistoryParams{
+		CharacterID: characterID,
+		Level:       level,
+	}); err != nil {
+		return err
+	}
+	return qtx.AddLevelHitPoints(ctx, db.AddLevelHitPointsParams{
+		HpGained:    hp,
+		CharacterID: characterID,
+		Level:       level,
+	})
+}
@@ -1,15 +1,38 @@
 package server
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/marbh56/mordezzan/internal/db"
 	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/ability_scores"
+	"github.com/marbh56/mordezzan/internal/rules/durability"
+	"github.com/marbh56/mordezzan/internal/rules/magic"
+	"github.com/marbh56/mordezzan/internal/rules/rest"
 	"go.uber.org/zap"
 )
 
+// ActiveItemEffect is a lasting effect from a used item, for display
+type ActiveItemEffect struct {
+	ID         int64
+	SourceName string
+	Label      string
+	Until      string // Empty when it lasts until ended
+}
+
+// ItemBonus is a passive bonus from an equipped item, for display
+type ItemBonus struct {
+	ItemName string
+	Label    string
+}
+
 func (s *Server) HandleUseMagicalItem(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -45,10 +68,7 @@ func (s *Server) HandleUseMagicalItem(w http.ResponseWriter, r *http.Request) {
 
 	// Verify character belongs to user
 	queries := db.New(s.db)
-	_, err = queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Character not found or doesn't belong to user",
 			zap.Error(err),
@@ -58,85 +78,466 @@ func (s *Server) HandleUseMagicalItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get item details to display appropriate message
-	item, err := queries.GetChargedItem(r.Context(), db.GetChargedItemParams{
-		ID:          itemID,
-		CharacterID: characterID,
-	})
+	message, err := s.useMagicalItem(r.Context(), character, itemID)
 	if err != nil {
-		logger.Error("Failed to fetch charged item details",
+		if magic.IsRuleError(err) || durability.IsRuleError(err) {
+			renderInventoryWithMessage(w, r, characterID, "Error: "+err.Error())
+			return
+		}
+		if err == sql.ErrNoRows {
+			http.Error(w, "Item not found or is not a charged item", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to use magical item",
 			zap.Error(err),
 			zap.Int64("item_id", itemID),
 			zap.Int64("character_id", characterID))
-		http.Error(w, "Item not found or is not a charged item", http.StatusNotFound)
+		renderInventoryWithMessage(w, r, characterID, "Error using item")
 		return
 	}
 
-	// Check if item has charges left
+	logger.Info("Magical item used",
+		zap.Int64("character_id", characterID),
+		zap.Int64("item_id", itemID))
+
+	renderInventoryWithMessage(w, r, characterID, message)
+}
+
+// useMagicalItem spends a charge and applies the item's effects in one
+// transaction, so a charge is never lost without its effect or the
+// other way round
+func (s *Server) useMagicalItem(ctx context.Context, character db.Character, itemID int64) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	item, err := qtx.GetChargedItem(ctx, db.GetChargedItemParams{
+		ID:          itemID,
+		CharacterID: character.ID,
+	})
+	if err != nil {
+		return "", err
+	}
 	if item.Charges.Valid && item.Charges.Int64 <= 0 {
-		logger.Warn("Attempt to use item with no charges",
-			zap.Int64("item_id", itemID),
-			zap.Int64("character_id", characterID))
-		renderInventoryWithMessage(w, r, characterID, "This item has no charges remaining")
-		return
+		return "", magic.ErrNoCharges
 	}
 
-	// Use the item by reducing charges - the parameter is a SQL query, not a Go struct
-	err = queries.UseChargedItem(r.Context(), db.UseChargedItemParams{
+	// Using an unknown item is one way to find out what it is
+	identity, err := inventoryItemIdentity(ctx, qtx, character.ID, itemID)
+	if err != nil {
+		return "", err
+	}
+	if err := checkItemUsable(ctx, qtx, character.ID, itemID, identity.Shown()); err != nil {
+		return "", err
+	}
+
+	rows, err := qtx.ListInventoryItemEffects(ctx, db.ListInventoryItemEffectsParams{
 		ID:          itemID,
+		CharacterID: character.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	effects := make([]magic.Effect, 0, len(rows))
+	for _, row := range rows {
+		effects = append(effects, magic.Effect{
+			Type:          row.EffectType,
+			Dice:          row.Dice.String,
+			SpellName:     row.SpellName.String,
+			SpellRange:    row.SpellRange.String,
+			SpellDuration: row.SpellDuration.String,
+			ConditionName: row.ConditionName.String,
+			Ability:       row.Ability.String,
+			BonusTarget:   row.BonusTarget.String,
+			Modifier:      row.Modifier,
+			DurationHours: row.DurationHours.Int64,
+			Timed:         row.DurationHours.Valid,
+		})
+	}
+	if !magic.Usable(effects) {
+		return "", magic.ErrPassiveOnly
+	}
+
+	if err := qtx.EnsureGameClock(ctx, character.ID); err != nil {
+		return "", err
+	}
+	clock, err := qtx.GetGameClock(ctx, character.ID)
+	if err != nil {
+		return "", err
+	}
+	if _, err := expireItemEffects(ctx, qtx, character.ID, clock.ElapsedHours); err != nil {
+		return "", err
+	}
+
+	var results []string
+	for _, effect := range effects {
+		result, err := applyItemEffect(ctx, qtx, character, item.Name, effect, clock.ElapsedHours)
+		if err != nil {
+			return "", err
+		}
+		if result != "" {
+			results = append(results, result)
+		}
+	}
+
+	if err := qtx.UseChargedItem(ctx, db.UseChargedItemParams{
+		ID:          itemID,
+		CharacterID: character.ID,
+	}); err != nil {
+		return "", err
+	}
+
+	if !identity.Identified {
+		if err := markIdentified(ctx, qtx, character.ID, itemID, magic.IdentifyByUse); err != nil {
+			return "", err
+		}
+	}
+
+	// One-time items like potions are gone once their last charge is used
+	remaining := item.Charges.Int64 - 1
+	if item.Charges.Valid && remaining <= 0 && (item.Category == "potion" || item.Category == "scroll") {
+		if err := qtx.RemoveItemFromInventory(ctx, db.RemoveItemFromInventoryParams{
+			ID:          itemID,
+			CharacterID: character.ID,
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	message := fmt.Sprintf("You used %s", identity.Shown())
+	if len(results) > 0 {
+		message += ": " + strings.Join(results, ", ")
+	} else if item.EffectDescription != "" {
+		message += ": " + item.EffectDescription
+	}
+	if !identity.Identified {
+		message += fmt.Sprintf(". It was %s", item.Name)
+	}
+	if item.Charges.Valid && item.Category != "potion" && item.Category != "scroll" {
+		message += fmt.Sprintf(" (%d charges left)", max(remaining, 0))
+	}
+	return message, nil
+}
+
+// applyItemEffect carries out one effect of a used item and describes it
+func applyItemEffect(ctx context.Context, qtx *db.Queries, character db.Character, source string, effect magic.Effect, now int64) (string, error) {
+	expiresAt, timed := effect.ExpiresAt(now)
+	expiry := sql.NullInt64{Int64: expiresAt, Valid: timed}
+
+	switch effect.Type {
+	case magic.EffectHeal:
+		newHP, healed, err := magic.Heal(effect.Dice, character.CurrentHp, character.MaxHp, func(sides int) int {
+			return rand.IntN(sides) + 1
+		})
+		if err != nil {
+			return "", err
+		}
+		if _, err := qtx.UpdateCharacterHitPoints(ctx, db.UpdateCharacterHitPointsParams{
+			MaxHp:     character.MaxHp,
+			CurrentHp: newHP,
+			ID:        character.ID,
+			UserID:    character.UserID,
+		}); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("healed %d hit points", healed), nil
+
+	case magic.EffectCastSpell:
+		return "cast " + magic.SpellLabel(effect), nil
+
+	case magic.EffectCondition:
+		notes := "From " + source
+		if timed {
+			notes += fmt.Sprintf(", until %s", rest.FormatGameTime(expiresAt))
+		}
+		condition, err := qtx.CreateCharacterCondition(ctx, db.CreateCharacterConditionParams{
+			CharacterID: character.ID,
+			Name:        effect.ConditionName,
+			Notes:       sql.NullString{String: notes, Valid: true},
+		})
+		if err != nil {
+			return "", err
+		}
+		if _, err := qtx.CreateCharacterItemEffect(ctx, db.CreateCharacterItemEffectParams{
+			CharacterID:   character.ID,
+			SourceName:    source,
+			EffectType:    magic.EffectCondition,
+			ConditionID:   sql.NullInt64{Int64: condition.ID, Valid: true},
+			ExpiresAtHour: expiry,
+		}); err != nil {
+			return "", err
+		}
+		return effect.ConditionName, nil
+
+	case magic.EffectAbilityModifier:
+		if _, err := qtx.CreateCharacterItemEffect(ctx, db.CreateCharacterItemEffectParams{
+			CharacterID:   character.ID,
+			SourceName:    source,
+			EffectType:    magic.EffectAbilityModifier,
+			Ability:       sql.NullString{String: effect.Ability, Valid: true},
+			Modifier:      effect.Modifier,
+			ExpiresAtHour: expiry,
+		}); err != nil {
+			return "", err
+		}
+		return magic.AbilityLabel(effect.Ability, effect.Modifier), nil
+	}
+	return "", nil
+}
+
+// expireItemEffects removes lasting item effects that have run out by the
+// given game hour, along with any conditions they gave
+func expireItemEffects(ctx context.Context, qtx *db.Queries, characterID, now int64) (int, error) {
+	expired, err := qtx.ListExpiredItemEffects(ctx, db.ListExpiredItemEffectsParams{
+		CharacterID:   characterID,
+		ExpiresAtHour: sql.NullInt64{Int64: now, Valid: true},
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, effect := range expired {
+		if err := endItemEffect(ctx, qtx, effect); err != nil {
+			return 0, err
+		}
+	}
+	return len(expired), nil
+}
+
+func endItemEffect(ctx context.Context, qtx *db.Queries, effect db.CharacterItemEffect) error {
+	if effect.ConditionID.Valid {
+		if err := qtx.DeleteCharacterCondition(ctx, db.DeleteCharacterConditionParams{
+			ID:          effect.ConditionID.Int64,
+			CharacterID: effect.CharacterID,
+		}); err != nil {
+			return err
+		}
+	}
+	return qtx.DeleteCharacterItemEffect(ctx, db.DeleteCharacterItemEffectParams{
+		ID:          effect.ID,
+		CharacterID: effect.CharacterID,
+	})
+}
+
+// HandleEndItemEffect ends a lasting item effect early
+func (s *Server) HandleEndItemEffect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	effectID, err := strconv.ParseInt(r.FormValue("effect_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid effect ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	tx, err := s.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error ending effect", characterID), http.StatusSeeOther)
+		return
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	effect, err := qtx.GetCharacterItemEffect(r.Context(), db.GetCharacterItemEffectParams{
+		ID:          effectID,
 		CharacterID: characterID,
 	})
 	if err != nil {
-		logger.Error("Failed to use charged item",
-			zap.Error(err),
-			zap.Int64("item_id", itemID),
-			zap.Int64("character_id", characterID))
-		renderInventoryWithMessage(w, r, characterID, "Error using item")
+		http.Error(w, "Effect not found", http.StatusNotFound)
 		return
 	}
 
-	// If it's a one-time use item like a potion and now has 0 charges, remove it
-	if item.Category == "potion" || item.Category == "scroll" {
-		// Check if charges are now at 0
-		updatedItem, err := queries.GetChargedItem(r.Context(), db.GetChargedItemParams{
-			ID:          itemID,
-			CharacterID: characterID,
+	if err := endItemEffect(r.Context(), qtx, effect); err != nil {
+		logger.Error("Failed to end item effect", zap.Error(err), zap.Int64("effect_id", effectID))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error ending effect", characterID), http.StatusSeeOther)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit ending effect", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error ending effect", characterID), http.StatusSeeOther)
+		return
+	}
+
+	renderInventoryWithMessage(w, r, characterID, fmt.Sprintf("Effect from %s ended", effect.SourceName))
+}
+
+// loadItemEffects applies passive bonuses from worn items that function
+// and lasting effects from used ones to a view model
+func (s *Server) loadItemEffects(ctx context.Context, queries *db.Queries, vm *CharacterViewModel) {
+	wornRows, err := queries.ListWornMagicItems(ctx, vm.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch worn magic items", zap.Error(err), zap.Int64("character_id", vm.ID))
+	}
+	worn := make([]magic.Worn, 0, len(wornRows))
+	for _, row := range wornRows {
+		worn = append(worn, magic.Worn{
+			InventoryID: row.InventoryID,
+			Name:        row.ItemName,
+			Category:    row.Category,
+			SlotGroup:   row.SlotGroup,
+			WornIn:      row.CategorySlotGroup.String,
+			Limit:       row.MaxFunctioning.Int64,
+			Limited:     row.MaxFunctioning.Valid,
 		})
+	}
+	working, idle := magic.Functioning(worn)
 
-		if err == nil && updatedItem.Charges.Valid && updatedItem.Charges.Int64 <= 0 {
-			// Remove the item as it's been consumed
-			err = queries.RemoveItemFromInventory(r.Context(), db.RemoveItemFromInventoryParams{
-				ID:          itemID,
-				CharacterID: characterID,
-			})
-			if err != nil {
-				logger.Error("Failed to remove consumed item",
-					zap.Error(err),
-					zap.Int64("item_id", itemID),
-					zap.Int64("character_id", characterID))
-				// Continue anyway, we'll just show the item with 0 charges
-			}
+	// Unidentified items still work, but the player can't tell which one
+	// is doing what
+	hidden := make(map[int64]bool)
+	for _, item := range vm.EquippedItems {
+		if item.Hidden {
+			hidden[item.ID] = true
+		}
+	}
+	vm.IdleMagicItems = nil
+	for _, item := range idle {
+		if !hidden[item.InventoryID] {
+			vm.IdleMagicItems = append(vm.IdleMagicItems, item)
 		}
 	}
 
-	message := fmt.Sprintf("You used %s: %s", item.Name, item.EffectDescription)
-	var remainingCharges int64 = 0
-	if item.Charges.Valid {
-		remainingCharges = item.Charges.Int64 - 1
-		if remainingCharges < 0 {
-			remainingCharges = 0
+	bonusRows, err := queries.ListEquippedItemBonuses(ctx, vm.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch item bonuses", zap.Error(err), zap.Int64("character_id", vm.ID))
+	}
+	var bonuses magic.Bonuses
+	vm.ItemBonuses = nil
+	for _, row := range bonusRows {
+		if !working[row.InventoryID] {
+			continue
+		}
+		bonuses.Add(row.BonusTarget.String, row.Ability.String, row.Modifier)
+		if hidden[row.InventoryID] {
+			continue
 		}
+		vm.ItemBonuses = append(vm.ItemBonuses, ItemBonus{
+			ItemName: row.ItemName,
+			Label:    magic.BonusLabel(row.BonusTarget.String, row.Ability.String, row.Modifier),
+		})
 	}
 
-	logger.Info("Magical item used",
-		zap.Int64("character_id", characterID),
-		zap.Int64("item_id", itemID),
-		zap.String("item_name", item.Name),
-		zap.Int64("charges_remaining", remainingCharges))
+	// A curse bites wherever the item is worn, known or not
+	curseRows, err := queries.ListEquippedCursePenalties(ctx, vm.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch curse penalties", zap.Error(err), zap.Int64("character_id", vm.ID))
+	}
+	for _, row := range curseRows {
+		bonuses.Add(row.CurseTarget.String, row.CurseAbility.String, row.CurseModifier)
+		if hidden[row.InventoryID] {
+			continue
+		}
+		vm.ItemBonuses = append(vm.ItemBonuses, ItemBonus{
+			ItemName: row.ItemName,
+			Label:    "cursed, " + magic.BonusLabel(row.CurseTarget.String, row.CurseAbility.String, row.CurseModifier),
+		})
+	}
 
-	renderInventoryWithMessage(w, r, characterID, message)
+	vm.ArmorClass -= int(bonuses.ArmorClass)
+	vm.SavingThrow -= bonuses.SavingThrow
+	vm.MovementRate += bonuses.Movement
+	for ability, modifier := range bonuses.Abilities {
+		vm.applyAbilityModifier(ability, modifier)
+	}
+
+	var now int64
+	if clock, err := queries.GetGameClock(ctx, vm.ID); err == nil {
+		now = clock.ElapsedHours
+	}
+
+	effects, err := queries.ListCharacterItemEffects(ctx, vm.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch item effects", zap.Error(err), zap.Int64("character_id", vm.ID))
+	}
+	vm.ItemEffects = nil
+	for _, effect := range effects {
+		if effect.ExpiresAtHour.Valid && effect.ExpiresAtHour.Int64 <= now {
+			continue
+		}
+		active := ActiveItemEffect{ID: effect.ID, SourceName: effect.SourceName}
+		if effect.ExpiresAtHour.Valid {
+			active.Until = rest.FormatGameTime(effect.ExpiresAtHour.Int64)
+		}
+		switch effect.EffectType {
+		case magic.EffectAbilityModifier:
+			vm.applyAbilityModifier(effect.Ability.String, effect.Modifier)
+			active.Label = magic.AbilityLabel(effect.Ability.String, effect.Modifier)
+		case magic.EffectCondition:
+			// Conditions are loaded with the campaign details
+			for _, condition := range vm.Conditions {
+				if condition.ID == effect.ConditionID.Int64 {
+					active.Label = condition.Name
+				}
+			}
+		}
+		vm.ItemEffects = append(vm.ItemEffects, active)
+	}
+}
+
+// applyAbilityModifier raises or lowers an ability score and works out its
+// modifiers again
+func (vm *CharacterViewModel) applyAbilityModifier(ability string, modifier int64) {
+	switch ability {
+	case "strength":
+		vm.Strength += modifier
+		vm.StrengthModifiers = ability_scores.CalculateStrengthModifiers(vm.Strength)
+		if classGetsFighterBonus(vm.Class) {
+			vm.StrengthModifiers.ExtraordinaryFeat += 8
+		}
+	case "dexterity":
+		oldDefense := vm.DexterityModifiers.DefenseAdj
+		vm.Dexterity += modifier
+		vm.DexterityModifiers = ability_scores.CalculateDexterityModifiers(vm.Dexterity)
+		vm.ArmorClass -= vm.DexterityModifiers.DefenseAdj - oldDefense
+	case "constitution":
+		vm.Constitution += modifier
+		vm.ConstitutionModifiers = ability_scores.CalculateConstitutionModifiers(vm.Constitution)
+	case "intelligence":
+		vm.Intelligence += modifier
+		vm.IntelligenceModifiers = ability_scores.CalculateIntelligenceModifiers(vm.Intelligence)
+	case "wisdom":
+		vm.Wisdom += modifier
+		vm.WisdomModifiers = ability_scores.CalculateWisdomModifiers(vm.Wisdom)
+	case "charisma":
+		vm.Charisma += modifier
+		vm.CharismaModifiers = ability_scores.CalculateCharismaModifiers(vm.Charisma)
+	}
 }
 
 func renderInventoryWithMessage(w http.ResponseWriter, r *http.Request, characterID int64, message string) {
-	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, message), http.StatusSeeOther)
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, url.QueryEscape(message)), http.StatusSeeOther)
 }
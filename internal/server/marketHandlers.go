@@ -0,0 +1,784 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marbh56/mordezzan/internal/currency"
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/equipment"
+	"github.com/marbh56/mordezzan/internal/rules/market"
+	"github.com/marbh56/mordezzan/internal/rules/treasure"
+	"go.uber.org/zap"
+)
+
+// ShopItem is a catalog item for sale at its local price
+type ShopItem struct {
+	ID       int64
+	Name     string
+	ItemType string
+	Weight   float64
+	Price    string
+}
+
+// ShopLine is one item in a character's cart
+type ShopLine struct {
+	market.Line
+	Price string
+	Total string
+}
+
+// SaleOffer is what a merchant will pay for one of a character's items
+type SaleOffer struct {
+	ID       int64
+	Name     string
+	Quantity int64
+	Price    string // Each
+	Reason   string // Why nobody will buy it, if so
+}
+
+// shopPriceList finds the price list a character is shopping under, which
+// must belong to the character's campaign. An ID of zero means catalog
+// prices.
+func shopPriceList(ctx context.Context, queries *db.Queries, characterID, priceListID int64) (db.PriceList, error) {
+	if priceListID == 0 {
+		return db.PriceList{}, nil
+	}
+	priceList, err := queries.GetPriceList(ctx, priceListID)
+	if err != nil {
+		return db.PriceList{}, err
+	}
+	campaign, err := queries.GetCharacterCampaign(ctx, characterID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return db.PriceList{}, err
+	}
+	if err != nil || campaign.ID != priceList.CampaignID {
+		return db.PriceList{}, market.ErrWrongCampaign
+	}
+	return priceList, nil
+}
+
+// sellPercent is the share of an item's worth merchants pay under a price
+// list
+func sellPercent(priceList db.PriceList) int64 {
+	if priceList.ID == 0 {
+		return market.DefaultSellPercent
+	}
+	return priceList.SellPercent
+}
+
+// treasureFeePercent is the share of a treasure item's worth merchants keep
+// when buying it under a price list
+func treasureFeePercent(priceList db.PriceList) int64 {
+	if priceList.ID == 0 {
+		return market.DefaultTreasureFeePercent
+	}
+	return priceList.TreasureFeePercent
+}
+
+// offerPrice is what a merchant pays for one of an owned item in copper
+// pieces. Gems, jewellery and art fetch their true worth less a fee,
+// whatever the character was told they were worth; anything else goes by
+// its condition.
+func offerPrice(itemType string, value float64, conditionPercent int64, priceList db.PriceList) int64 {
+	if itemType == treasure.ItemType {
+		return market.TreasurePrice(value, treasureFeePercent(priceList))
+	}
+	return market.SalePrice(value, conditionPercent, sellPercent(priceList))
+}
+
+// shopForm handles the shared checks for shop POST handlers
+func (s *Server) shopForm(w http.ResponseWriter, r *http.Request) (db.Character, int64, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return db.Character{}, 0, false
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return db.Character{}, 0, false
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return db.Character{}, 0, false
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return db.Character{}, 0, false
+	}
+
+	var priceListID int64
+	if raw := r.FormValue("price_list_id"); raw != "" {
+		if priceListID, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			logger.Error("Invalid price list ID", zap.Error(err))
+			http.Error(w, "Invalid price list ID", http.StatusBadRequest)
+			return db.Character{}, 0, false
+		}
+	}
+
+	character, err := getWritableCharacter(r.Context(), db.New(s.db), characterID, user.UserID)
+	if err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return db.Character{}, 0, false
+	}
+
+	return character, priceListID, true
+}
+
+// renderShopWithMessage redirects back to the shop with a flash message
+func renderShopWithMessage(w http.ResponseWriter, r *http.Request, characterID, priceListID int64, message string) {
+	target := fmt.Sprintf("/characters/shop?character_id=%d&message=%s", characterID, url.QueryEscape(message))
+	if priceListID != 0 {
+		target += fmt.Sprintf("&price_list_id=%d", priceListID)
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+// HandleShop shows the catalog at local prices, the character's cart and
+// what merchants will pay for the character's gear
+func (s *Server) HandleShop(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.URL.Query().Get("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID",
+			zap.Error(err),
+			zap.String("raw_id", r.URL.Query().Get("character_id")))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	var priceListID int64
+	if raw := r.URL.Query().Get("price_list_id"); raw != "" {
+		if priceListID, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			http.Error(w, "Invalid price list ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	queries := db.New(s.db)
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	priceList, err := shopPriceList(r.Context(), queries, characterID, priceListID)
+	if err != nil {
+		if errors.Is(err, market.ErrWrongCampaign) || errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Price list not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to fetch price list", zap.Error(err), zap.Int64("price_list_id", priceListID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	listID := sql.NullInt64{Int64: priceList.ID, Valid: priceList.ID != 0}
+
+	var priceLists []db.PriceList
+	if campaign, err := queries.GetCharacterCampaign(r.Context(), characterID); err == nil {
+		if priceLists, err = queries.ListCampaignPriceLists(r.Context(), campaign.ID); err != nil {
+			logger.Warn("Failed to fetch price lists", zap.Error(err), zap.Int64("campaign_id", campaign.ID))
+		}
+	}
+
+	var items []ShopItem
+	catalog, err := queries.ListShopItems(r.Context(), listID)
+	if err != nil {
+		logger.Error("Failed to fetch shop items", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	for _, row := range catalog {
+		if !row.Available {
+			continue
+		}
+		items = append(items, ShopItem{
+			ID:       row.ID,
+			Name:     row.Name,
+			ItemType: row.ItemType,
+			Weight:   row.Weight,
+			Price:    formatGoldValue(market.Price(row.Value, row.MarkupPercent)),
+		})
+	}
+
+	cart, lines, err := loadShopCart(r.Context(), queries, characterID, listID)
+	if err != nil {
+		logger.Error("Failed to fetch shop cart", zap.Error(err), zap.Int64("character_id", characterID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	var cartTotal int64
+	for _, line := range lines {
+		cartTotal += line.Total()
+	}
+
+	var offers []SaleOffer
+	owned, err := queries.GetCharacterInventoryItems(r.Context(), characterID)
+	if err != nil {
+		logger.Warn("Failed to fetch inventory", zap.Error(err), zap.Int64("character_id", characterID))
+	}
+	for _, row := range owned {
+		offer := SaleOffer{ID: row.ID, Name: row.ItemName, Quantity: row.Quantity}
+		price := offerPrice(row.ItemType, row.ItemValue, row.ConditionValuePercent, priceList)
+		switch {
+		case !row.IsIdentified:
+			if row.Appearance.Valid {
+				offer.Name = row.Appearance.String
+			}
+			offer.Reason = "unidentified"
+		case price == 0:
+			offer.Reason = "worthless"
+		default:
+			offer.Price = formatGoldValue(price)
+		}
+		offers = append(offers, offer)
+	}
+
+	coinage, purse := characterCoins(r.Context(), queries, characterID)
+	data := struct {
+		IsAuthenticated bool
+		Username        string
+		Character       db.Character
+		Purse           string
+		PurseValue      string
+		PriceLists      []db.PriceList
+		PriceList       db.PriceList
+		SellPercent     int64
+		TreasureFee     int64
+		Items           []ShopItem
+		Cart            []ShopLine
+		CartTotal       string
+		CanAfford       bool
+		Offers          []SaleOffer
+		FlashMessage    string
+		CurrentYear     int
+	}{
+		IsAuthenticated: true,
+		Username:        user.Username,
+		Character:       character,
+		Purse:           coinage.Format(purse),
+		PurseValue:      formatGoldValue(coinage.Value(purse)),
+		PriceLists:      priceLists,
+		PriceList:       priceList,
+		SellPercent:     sellPercent(priceList),
+		TreasureFee:     treasureFeePercent(priceList),
+		Items:           items,
+		Cart:            cart,
+		CartTotal:       formatGoldValue(cartTotal),
+		CanAfford:       coinage.Value(purse) >= cartTotal,
+		Offers:          offers,
+		FlashMessage:    r.URL.Query().Get("message"),
+		CurrentYear:     time.Now().Year(),
+	}
+
+	RenderTemplate(w, "templates/inventory/shop.html", "base.html", data)
+}
+
+// loadShopCart prices a character's cart under a price list
+func loadShopCart(ctx context.Context, queries *db.Queries, characterID int64, priceListID sql.NullInt64) ([]ShopLine, []market.Line, error) {
+	rows, err := queries.ListShopCart(ctx, db.ListShopCartParams{
+		PriceListID: priceListID,
+		CharacterID: characterID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	cart := make([]ShopLine, 0, len(rows))
+	lines := make([]market.Line, 0, len(rows))
+	for _, row := range rows {
+		line := market.Line{
+			ItemID:    row.ItemID,
+			Name:      row.Name,
+			Quantity:  row.Quantity,
+			UnitPrice: market.Price(row.Value, row.MarkupPercent),
+			Available: row.Available,
+		}
+		lines = append(lines, line)
+		cart = append(cart, ShopLine{
+			Line:  line,
+			Price: formatGoldValue(line.UnitPrice),
+			Total: formatGoldValue(line.Total()),
+		})
+	}
+	return cart, lines, nil
+}
+
+// HandleShopCart adds catalog items to a character's cart or takes them out
+func (s *Server) HandleShopCart(w http.ResponseWriter, r *http.Request) {
+	character, priceListID, ok := s.shopForm(w, r)
+	if !ok {
+		return
+	}
+
+	itemID, err := strconv.ParseInt(r.FormValue("item_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid item ID", zap.Error(err))
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	if r.FormValue("action") == "remove" {
+		err = queries.RemoveShopCartItem(r.Context(), db.RemoveShopCartItemParams{
+			CharacterID: character.ID,
+			ItemID:      itemID,
+		})
+	} else {
+		quantity, parseErr := strconv.ParseInt(r.FormValue("quantity"), 10, 64)
+		if parseErr != nil || quantity <= 0 {
+			renderShopWithMessage(w, r, character.ID, priceListID, "Error: "+market.ErrBadQuantity.Error())
+			return
+		}
+		err = queries.AddShopCartItem(r.Context(), db.AddShopCartItemParams{
+			CharacterID: character.ID,
+			ItemID:      itemID,
+			Quantity:    quantity,
+		})
+	}
+	if err != nil {
+		logger.Error("Failed to update shop cart",
+			zap.Error(err),
+			zap.Int64("character_id", character.ID),
+			zap.Int64("item_id", itemID))
+		renderShopWithMessage(w, r, character.ID, priceListID, "Error updating cart")
+		return
+	}
+
+	renderShopWithMessage(w, r, character.ID, priceListID, "Cart updated")
+}
+
+// HandleShopCheckout buys everything in a character's cart
+func (s *Server) HandleShopCheckout(w http.ResponseWriter, r *http.Request) {
+	character, priceListID, ok := s.shopForm(w, r)
+	if !ok {
+		return
+	}
+
+	message, err := s.checkout(r.Context(), character, priceListID)
+	if err != nil {
+		if market.IsRuleError(err) || equipment.IsRuleError(err) {
+			renderShopWithMessage(w, r, character.ID, priceListID, "Error: "+err.Error())
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Price list not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to check out",
+			zap.Error(err),
+			zap.Int64("character_id", character.ID),
+			zap.Int64("price_list_id", priceListID))
+		renderShopWithMessage(w, r, character.ID, priceListID, "Error buying items")
+		return
+	}
+
+	logger.Info("Shop checkout completed",
+		zap.Int64("character_id", character.ID),
+		zap.Int64("price_list_id", priceListID))
+
+	renderInventoryWithMessage(w, r, character.ID, message)
+}
+
+// checkout prices the cart exactly in copper, pays from the purse with any
+// change given back and adds the goods, all in one transaction
+func (s *Server) checkout(ctx context.Context, character db.Character, priceListID int64) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	priceList, err := shopPriceList(ctx, qtx, character.ID, priceListID)
+	if err != nil {
+		return "", err
+	}
+	rows, err := qtx.ListShopCart(ctx, db.ListShopCartParams{
+		PriceListID: sql.NullInt64{Int64: priceList.ID, Valid: priceList.ID != 0},
+		CharacterID: character.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	lines := make([]market.Line, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, market.Line{
+			ItemID:    row.ItemID,
+			Name:      row.Name,
+			Quantity:  row.Quantity,
+			UnitPrice: market.Price(row.Value, row.MarkupPercent),
+			Available: row.Available,
+		})
+	}
+	total, err := market.Checkout(lines)
+	if err != nil {
+		return "", err
+	}
+
+	coinage, err := loadCoinage(ctx, qtx, character.ID)
+	if err != nil {
+		return "", err
+	}
+	purse, err := loadPurse(ctx, qtx, character.ID)
+	if err != nil {
+		return "", err
+	}
+	paid, change, ok := coinage.Pay(purse, total)
+	if !ok {
+		return "", fmt.Errorf("%w: the bill is %s", market.ErrCannotAfford, formatGoldValue(total))
+	}
+	var boughtID sql.NullInt64
+	if len(lines) == 1 {
+		boughtID = sql.NullInt64{Int64: lines[0].ItemID, Valid: true}
+	}
+	bought := make([]string, len(lines))
+	for i, line := range lines {
+		bought[i] = fmt.Sprintf("%d × %s", line.Quantity, line.Name)
+	}
+	if err := recordCurrency(ctx, qtx, CurrencyEntry{
+		CharacterID: character.ID,
+		Reason:      "purchase",
+		Coins:       currency.Diff(paid, change),
+		ItemID:      boughtID,
+		Notes:       "Bought " + strings.Join(bought, ", "),
+	}); err != nil {
+		return "", err
+	}
+
+	// Stackable goods come as one stack, anything else one at a time
+	var count int64
+	for i, row := range rows {
+		stacks, quantity := lines[i].Quantity, int64(1)
+		if row.Stackable {
+			stacks, quantity = 1, lines[i].Quantity
+		}
+		for n := int64(0); n < stacks; n++ {
+			id, err := insertInventoryItem(ctx, qtx, db.AddItemToInventoryParams{
+				CharacterID: character.ID,
+				ItemID:      row.ItemID,
+				Quantity:    quantity,
+			}, nil, nil, true)
+			if err != nil {
+				return "", err
+			}
+			// New from the merchant's shelf
+			if err := qtx.SetItemCondition(ctx, db.SetItemConditionParams{
+				Condition:   "pristine",
+				ID:          id,
+				CharacterID: character.ID,
+			}); err != nil {
+				return "", err
+			}
+		}
+		count += lines[i].Quantity
+	}
+
+	if err := qtx.ClearShopCart(ctx, character.ID); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	noun := "items"
+	if count == 1 {
+		noun = "item"
+	}
+	message := fmt.Sprintf("Bought %d %s for %s, paying %s", count, noun, formatGoldValue(total), coinage.Format(paid))
+	if change.Count() > 0 {
+		message += fmt.Sprintf(" and getting %s change", coinage.Format(change))
+	}
+	return message, nil
+}
+
+// HandleShopSell sells some or all of an owned item to a merchant
+func (s *Server) HandleShopSell(w http.ResponseWriter, r *http.Request) {
+	character, priceListID, ok := s.shopForm(w, r)
+	if !ok {
+		return
+	}
+
+	itemID, err := strconv.ParseInt(r.FormValue("item_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid item ID", zap.Error(err))
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	var quantity int64
+	if raw := strings.TrimSpace(r.FormValue("quantity")); raw != "" {
+		if quantity, err = strconv.ParseInt(raw, 10, 64); err != nil || quantity <= 0 {
+			renderShopWithMessage(w, r, character.ID, priceListID, "Error: "+market.ErrBadQuantity.Error())
+			return
+		}
+	}
+
+	// A cursed item won't leave its owner while it is equipped
+	queries := db.New(s.db)
+	if err := checkItemRelease(r.Context(), queries, character.ID, itemID, sql.NullInt64{}); err != nil {
+		if equipment.IsRuleError(err) {
+			renderShopWithMessage(w, r, character.ID, priceListID, "Error: "+err.Error())
+			return
+		}
+		if errors.Is(err, errItemNotFound) {
+			http.Error(w, "Item not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to check item before sale",
+			zap.Error(err),
+			zap.Int64("character_id", character.ID),
+			zap.Int64("item_id", itemID))
+		renderShopWithMessage(w, r, character.ID, priceListID, "Error selling item")
+		return
+	}
+
+	message, err := s.sellItem(r.Context(), character, priceListID, itemID, quantity)
+	if err != nil {
+		if market.IsRuleError(err) {
+			renderShopWithMessage(w, r, character.ID, priceListID, "Error: "+err.Error())
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Item not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to sell item",
+			zap.Error(err),
+			zap.Int64("character_id", character.ID),
+			zap.Int64("item_id", itemID))
+		renderShopWithMessage(w, r, character.ID, priceListID, "Error selling item")
+		return
+	}
+
+	logger.Info("Item sold",
+		zap.Int64("character_id", character.ID),
+		zap.Int64("item_id", itemID),
+		zap.Int64("quantity", quantity))
+
+	renderShopWithMessage(w, r, character.ID, priceListID, message)
+}
+
+// sellItem takes an item, or part of a stack, out of the inventory and puts
+// the merchant's coins in the purse. A quantity of zero sells the lot.
+func (s *Server) sellItem(ctx context.Context, character db.Character, priceListID, itemID, quantity int64) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	priceList, err := shopPriceList(ctx, qtx, character.ID, priceListID)
+	if err != nil {
+		return "", err
+	}
+	item, err := qtx.GetInventoryItemSale(ctx, db.GetInventoryItemSaleParams{
+		ID:          itemID,
+		CharacterID: character.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	identity, err := inventoryItemIdentity(ctx, qtx, character.ID, itemID)
+	if err != nil {
+		return "", err
+	}
+	if !identity.Identified {
+		return "", fmt.Errorf("%w: %s", market.ErrUnidentified, identity.Shown())
+	}
+	if quantity == 0 {
+		quantity = item.Quantity
+	}
+	if quantity > item.Quantity {
+		return "", fmt.Errorf("%w: only %d to sell", market.ErrBadQuantity, item.Quantity)
+	}
+
+	if item.ItemType == "container" {
+		contents, err := qtx.GetContainerContents(ctx, db.GetContainerContentsParams{
+			ContainerID: sql.NullInt64{Int64: itemID, Valid: true},
+			CharacterID: character.ID,
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(contents) > 0 {
+			return "", fmt.Errorf("%w: %s still holds %d items", market.ErrNotEmpty, identity.Shown(), len(contents))
+		}
+	}
+
+	scale, err := loadConditionScale(ctx, qtx)
+	if err != nil {
+		return "", err
+	}
+	condition, err := scale.Get(item.Condition)
+	if err != nil {
+		return "", err
+	}
+	price := offerPrice(item.ItemType, item.Value, condition.ValuePercent, priceList) * quantity
+	if price == 0 {
+		return "", fmt.Errorf("%w: %s", market.ErrWorthless, identity.Shown())
+	}
+
+	if quantity == item.Quantity {
+		err = qtx.RemoveItemFromInventory(ctx, db.RemoveItemFromInventoryParams{
+			ID:          itemID,
+			CharacterID: character.ID,
+		})
+	} else {
+		err = qtx.ReduceStackQuantity(ctx, db.ReduceStackQuantityParams{
+			Quantity:    quantity,
+			ID:          itemID,
+			CharacterID: character.ID,
+		})
+	}
+	if err != nil {
+		return "", err
+	}
+
+	coinage, err := loadCoinage(ctx, qtx, character.ID)
+	if err != nil {
+		return "", err
+	}
+	coins := coinage.MakeChange(price)
+	if err := recordCurrency(ctx, qtx, CurrencyEntry{
+		CharacterID: character.ID,
+		Reason:      "sale",
+		Coins:       coins,
+		ItemID:      sql.NullInt64{Int64: item.ItemID, Valid: true},
+		Notes:       fmt.Sprintf("Sold %d × %s", quantity, identity.Shown()),
+	}); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Sold %d × %s for %s", quantity, identity.Shown(), coinage.Format(coins)), nil
+}
+
+// HandleSavePriceList lets the referee set up a settlement's prices
+func (s *Server) HandleSavePriceList(w http.ResponseWriter, r *http.Request) {
+	_, campaign, ok := s.refereeCampaignFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	settlement := strings.TrimSpace(r.Form.Get("settlement"))
+	markup, err := strconv.ParseInt(r.Form.Get("markup_percent"), 10, 64)
+	if err != nil {
+		markup = market.DefaultMarkupPercent
+	}
+	sell, err := strconv.ParseInt(r.Form.Get("sell_percent"), 10, 64)
+	if err != nil {
+		sell = market.DefaultSellPercent
+	}
+	treasureFee, err := strconv.ParseInt(r.Form.Get("treasure_fee_percent"), 10, 64)
+	if err != nil {
+		treasureFee = market.DefaultTreasureFeePercent
+	}
+	if err := market.CheckPriceList(settlement, markup, sell, treasureFee); err != nil {
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=%s", campaign.ID, url.QueryEscape("Error: "+err.Error())), http.StatusSeeOther)
+		return
+	}
+
+	priceList, err := db.New(s.db).SavePriceList(r.Context(), db.SavePriceListParams{
+		CampaignID:         campaign.ID,
+		Settlement:         settlement,
+		MarkupPercent:      markup,
+		SellPercent:        sell,
+		TreasureFeePercent: treasureFee,
+	})
+	if err != nil {
+		logger.Error("Failed to save price list",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaign.ID))
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Error saving price list", campaign.ID), http.StatusSeeOther)
+		return
+	}
+
+	message := fmt.Sprintf("Prices in %s: %d%% of value to buy, %d%% paid for goods, %d%% kept on treasure", priceList.Settlement, priceList.MarkupPercent, priceList.SellPercent, priceList.TreasureFeePercent)
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=%s", campaign.ID, url.QueryEscape(message)), http.StatusSeeOther)
+}
+
+// HandleSetPriceListItem lets the referee stock or withhold a catalog item
+// in a settlement and give it its own markup
+func (s *Server) HandleSetPriceListItem(w http.ResponseWriter, r *http.Request) {
+	_, campaign, ok := s.refereeCampaignFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	priceListID, err := strconv.ParseInt(r.Form.Get("price_list_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid price list ID", http.StatusBadRequest)
+		return
+	}
+	itemID, err := strconv.ParseInt(r.Form.Get("item_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	priceList, err := queries.GetPriceList(r.Context(), priceListID)
+	if err != nil || priceList.CampaignID != campaign.ID {
+		http.Error(w, "Price list not found", http.StatusNotFound)
+		return
+	}
+
+	var markup sql.NullInt64
+	if raw := strings.TrimSpace(r.Form.Get("markup_percent")); raw != "" {
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || value <= 0 {
+			http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=%s", campaign.ID, url.QueryEscape("Error: markup must be above 0%")), http.StatusSeeOther)
+			return
+		}
+		markup = sql.NullInt64{Int64: value, Valid: true}
+	}
+
+	err = queries.SetPriceListItem(r.Context(), db.SetPriceListItemParams{
+		PriceListID:   priceList.ID,
+		ItemID:        itemID,
+		Available:     r.Form.Get("available") == "1",
+		MarkupPercent: markup,
+	})
+	if err != nil {
+		logger.Error("Failed to set price list item",
+			zap.Error(err),
+			zap.Int64("price_list_id", priceList.ID),
+			zap.Int64("item_id", itemID))
+		http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Error saving price list", campaign.ID), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=%s", campaign.ID, url.QueryEscape("Price list updated for "+priceList.Settlement)), http.StatusSeeOther)
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/containers"
+	"github.com/marbh56/mordezzan/internal/rules/equipment"
+	"go.uber.org/zap"
+)
+
+// HandleDropPack sets down a pack worn on the back, with everything in it,
+// noting where it was left
+func (s *Server) HandleDropPack(w http.ResponseWriter, r *http.Request) {
+	s.handlePack(w, r, false)
+}
+
+// HandlePickUpPack picks a dropped pack up again
+func (s *Server) HandlePickUpPack(w http.ResponseWriter, r *http.Request) {
+	s.handlePack(w, r, true)
+}
+
+// handlePack reads a drop or pick-up form and applies it
+func (s *Server) handlePack(w http.ResponseWriter, r *http.Request, pickUp bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(r.FormValue("item_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid item ID", zap.Error(err))
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	location := strings.TrimSpace(r.FormValue("location"))
+	message, err := s.dropPack(r.Context(), characterID, itemID, location, pickUp)
+	if containers.IsRuleError(err) || equipment.IsRuleError(err) || errors.Is(err, errItemNotFound) {
+		logger.Warn("Pack drop refused",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("item_id", itemID))
+		renderInventoryWithMessage(w, r, characterID, "Error: "+err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to drop pack",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("item_id", itemID),
+			zap.Bool("pick_up", pickUp))
+		renderInventoryWithMessage(w, r, characterID, "Error dropping pack")
+		return
+	}
+
+	logger.Info("Pack dropped or picked up",
+		zap.Int64("character_id", characterID),
+		zap.Int64("item_id", itemID),
+		zap.Bool("pick_up", pickUp),
+		zap.String("location", location))
+
+	renderInventoryWithMessage(w, r, characterID, message)
+}
+
+// dropPack marks a pack worn on the back as dropped at location, or picks
+// it up again. A cursed pack can't be set down.
+func (s *Server) dropPack(ctx context.Context, characterID, itemID int64, location string, pickUp bool) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	inv, err := loadContainerInventory(ctx, qtx, characterID)
+	if err != nil {
+		return "", err
+	}
+	pack, ok := inv.Entry(itemID)
+	if !ok {
+		return "", errItemNotFound
+	}
+
+	if pickUp {
+		if err := qtx.PickUpPack(ctx, db.PickUpPackParams{
+			ID:          itemID,
+			CharacterID: characterID,
+		}); err != nil {
+			return "", err
+		}
+		if err := tx.Commit(); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Picked up %s", pack.Name), nil
+	}
+
+	rows, err := qtx.GetCharacterInventoryItems(ctx, characterID)
+	if err != nil {
+		return "", err
+	}
+	var slot string
+	for _, row := range rows {
+		if row.ID == itemID && row.EquipmentSlotID.Valid {
+			slot = row.SlotName.String
+		}
+	}
+	if err := containers.CheckDrop(pack, slot); err != nil {
+		return "", err
+	}
+	if err := checkItemRelease(ctx, qtx, characterID, itemID, sql.NullInt64{}); err != nil {
+		return "", err
+	}
+
+	if err := qtx.DropPack(ctx, db.DropPackParams{
+		DroppedLocation: sql.NullString{String: location, Valid: location != ""},
+		ID:              itemID,
+		CharacterID:     characterID,
+	}); err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	if location == "" {
+		return fmt.Sprintf("Dropped %s", pack.Name), nil
+	}
+	return fmt.Sprintf("Dropped %s %s", pack.Name, location), nil
+}
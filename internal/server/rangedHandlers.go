@@ -0,0 +1,466 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/ammunition"
+	"github.com/marbh56/mordezzan/internal/rules/containers"
+	"github.com/marbh56/mordezzan/internal/rules/durability"
+	"go.uber.org/zap"
+)
+
+// RangedDetails is what the detail page shows for missile fire
+type RangedDetails struct {
+	Launchers       []ammunition.Launcher `json:"launchers"`
+	Ready           []ReadyAmmunition     `json:"ready"`
+	MissilesFired   int64                 `json:"missiles_fired"`
+	RecoveryPercent int                   `json:"recovery_percent"`
+}
+
+// ReadyAmmunition is a stack that an equipped launcher can shoot
+type ReadyAmmunition struct {
+	ID        int64  `json:"id"`
+	Label     string `json:"label"`
+	Remaining int64  `json:"remaining"`
+	Launcher  string `json:"launcher"`
+}
+
+// rangedLoadout is a character's equipped launchers, ammunition stacks and
+// equipped quivers, cases and pouches
+type rangedLoadout struct {
+	launchers []ammunition.Launcher
+	broken    []string // Equipped launchers too broken to shoot
+	stacks    []ammunition.Stack
+	holders   []ammunition.Holder
+}
+
+func loadRangedLoadout(ctx context.Context, queries *db.Queries, characterID int64) (rangedLoadout, error) {
+	rows, err := queries.ListRangedEntries(ctx, characterID)
+	if err != nil {
+		return rangedLoadout{}, err
+	}
+
+	var loadout rangedLoadout
+	for _, row := range rows {
+		switch row.ItemType {
+		case "ranged_weapon":
+			if row.EquipmentSlotID.Valid && row.AmmunitionTag.Valid && !row.Usable {
+				loadout.broken = append(loadout.broken, itemDisplayName(row.ItemName, row.EnhancementBonus))
+			} else if row.EquipmentSlotID.Valid && row.AmmunitionTag.Valid {
+				loadout.launchers = append(loadout.launchers, ammunition.Launcher{
+					ID:          row.ID,
+					Name:        itemDisplayName(row.ItemName, row.EnhancementBonus),
+					AmmoTag:     row.AmmunitionTag.String,
+					Enhancement: row.EnhancementBonus,
+				})
+			}
+		case "container":
+			if row.EquipmentSlotID.Valid && row.AllowedTags != "" {
+				loadout.holders = append(loadout.holders, ammunition.Holder{
+					ID:          row.ID,
+					AllowedTags: splitTags(row.AllowedTags),
+				})
+			}
+		case "ammunition":
+			loadout.stacks = append(loadout.stacks, ammunition.Stack{
+				ID:          row.ID,
+				ItemID:      row.ItemID,
+				Name:        row.ItemName,
+				Tags:        splitTags(row.Tags),
+				Quantity:    row.Quantity,
+				PerUnit:     row.PerUnit,
+				Used:        row.MissilesUsed,
+				Enhancement: row.EnhancementBonus,
+				ContainerID: row.ContainerID.Int64,
+			})
+		}
+	}
+	return loadout, nil
+}
+
+// aim picks the launcher and stack for a shot. With no stack chosen the
+// first ordinary ready stack is used, so magic missiles are only spent on
+// purpose.
+func (l rangedLoadout) aim(stackID int64) (ammunition.Launcher, ammunition.Stack, error) {
+	if len(l.launchers) == 0 {
+		if len(l.broken) > 0 {
+			return ammunition.Launcher{}, ammunition.Stack{}, fmt.Errorf("%w: %s needs repair before it can shoot", durability.ErrBroken, strings.Join(l.broken, " and "))
+		}
+		return ammunition.Launcher{}, ammunition.Stack{}, ammunition.ErrNoLauncher
+	}
+
+	if stackID == 0 {
+		for _, launcher := range l.launchers {
+			for _, stack := range ammunition.Ready(launcher, l.stacks, l.holders) {
+				if stack.Enhancement == 0 {
+					return launcher, stack, nil
+				}
+			}
+		}
+		return ammunition.Launcher{}, ammunition.Stack{}, ammunition.ErrNoAmmunition
+	}
+
+	var stack ammunition.Stack
+	found := false
+	for _, s := range l.stacks {
+		if s.ID == stackID {
+			stack, found = s, true
+		}
+	}
+	if !found {
+		return ammunition.Launcher{}, ammunition.Stack{}, errItemNotFound
+	}
+
+	for _, launcher := range l.launchers {
+		for _, ready := range ammunition.Ready(launcher, l.stacks, l.holders) {
+			if ready.ID == stackID {
+				return launcher, stack, nil
+			}
+		}
+	}
+	for _, launcher := range l.launchers {
+		if launcher.Shoots(stack) {
+			return ammunition.Launcher{}, ammunition.Stack{}, fmt.Errorf("%w: %s must be in an equipped quiver, case or pouch", ammunition.ErrNotReady, stack.Label())
+		}
+	}
+	return ammunition.Launcher{}, ammunition.Stack{}, fmt.Errorf("%w: no equipped weapon shoots %s", ammunition.ErrWrongAmmo, stack.Label())
+}
+
+// fireMissiles spends ammunition on a ranged attack and notes the missiles
+// so they can be looked for afterwards
+func (s *Server) fireMissiles(ctx context.Context, characterID, stackID, missiles int64) (ammunition.Shot, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ammunition.Shot{}, err
+	}
+	defer tx.Rollback()
+
+	qtx := db.New(s.db).WithTx(tx)
+	loadout, err := loadRangedLoadout(ctx, qtx, characterID)
+	if err != nil {
+		return ammunition.Shot{}, err
+	}
+
+	launcher, stack, err := loadout.aim(stackID)
+	if err != nil {
+		return ammunition.Shot{}, err
+	}
+
+	shot, err := ammunition.Fire(launcher, stack, missiles)
+	if err != nil {
+		return ammunition.Shot{}, err
+	}
+
+	if shot.Quantity == 0 {
+		err = qtx.RemoveItemFromInventory(ctx, db.RemoveItemFromInventoryParams{
+			ID:          stack.ID,
+			CharacterID: characterID,
+		})
+	} else {
+		err = qtx.SetAmmunitionCount(ctx, db.SetAmmunitionCountParams{
+			Quantity:     shot.Quantity,
+			MissilesUsed: shot.Used,
+			ID:           stack.ID,
+			CharacterID:  characterID,
+		})
+	}
+	if err != nil {
+		return ammunition.Shot{}, err
+	}
+
+	if err := qtx.RecordMissilesFired(ctx, db.RecordMissilesFiredParams{
+		CharacterID:      characterID,
+		InventoryID:      sql.NullInt64{Int64: stack.ID, Valid: true},
+		ItemID:           stack.ItemID,
+		EnhancementBonus: stack.Enhancement,
+		Count:            shot.Missiles,
+	}); err != nil {
+		return ammunition.Shot{}, err
+	}
+
+	return shot, tx.Commit()
+}
+
+// recoverMissiles rolls for every missile shot since the last search.
+// Found missiles go back to the stack they came from, or are carried loose
+// when that stack is gone or has no room.
+func (s *Server) recoverMissiles(ctx context.Context, characterID int64, percent int) (found, fired int64, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	roll := func(sides int) int { return rand.IntN(sides) + 1 }
+	if _, err := ammunition.Recover(0, percent, roll); err != nil {
+		return 0, 0, err
+	}
+
+	qtx := db.New(s.db).WithTx(tx)
+	shots, err := qtx.ListMissilesFired(ctx, characterID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	loadout, err := loadRangedLoadout(ctx, qtx, characterID)
+	if err != nil {
+		return 0, 0, err
+	}
+	stacks := make(map[int64]ammunition.Stack, len(loadout.stacks))
+	for _, stack := range loadout.stacks {
+		stacks[stack.ID] = stack
+	}
+
+	for _, shot := range shots {
+		fired += shot.Count
+		n, err := ammunition.Recover(shot.Count, percent, roll)
+		if err != nil {
+			return 0, 0, err
+		}
+		if n == 0 {
+			continue
+		}
+		found += n
+
+		stack, ok := stacks[shot.InventoryID.Int64]
+		if ok && stack.ItemID == shot.ItemID && stack.Enhancement == shot.EnhancementBonus {
+			quantity, used := ammunition.Pack(stack.Remaining()+n, stack.PerUnit)
+			placed := checkItemPlacement(ctx, qtx, characterID, stack.ID, quantity, sql.NullInt64{Int64: stack.ContainerID, Valid: stack.ContainerID != 0})
+			if placed != nil && !containers.IsRuleError(placed) {
+				return 0, 0, placed
+			}
+			if placed == nil {
+				if err := qtx.SetAmmunitionCount(ctx, db.SetAmmunitionCountParams{
+					Quantity:     quantity,
+					MissilesUsed: used,
+					ID:           stack.ID,
+					CharacterID:  characterID,
+				}); err != nil {
+					return 0, 0, err
+				}
+				stack.Quantity, stack.Used = quantity, used
+				stacks[stack.ID] = stack
+				continue
+			}
+		}
+
+		perUnit, err := qtx.GetAmmunitionPerUnit(ctx, shot.ItemID)
+		if err != nil {
+			return 0, 0, err
+		}
+		quantity, used := ammunition.Pack(n, perUnit)
+		added, err := qtx.AddItemToInventory(ctx, db.AddItemToInventoryParams{
+			CharacterID:      characterID,
+			ItemID:           shot.ItemID,
+			Quantity:         quantity,
+			EnhancementBonus: shot.EnhancementBonus,
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+		if err := qtx.SetAmmunitionCount(ctx, db.SetAmmunitionCountParams{
+			Quantity:     quantity,
+			MissilesUsed: used,
+			ID:           added.ID,
+			CharacterID:  characterID,
+		}); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if err := qtx.ClearMissilesFired(ctx, characterID); err != nil {
+		return 0, 0, err
+	}
+
+	return found, fired, tx.Commit()
+}
+
+// loadRangedDetails attaches equipped launchers, ready ammunition and
+// missiles waiting to be recovered to a view model
+func (s *Server) loadRangedDetails(ctx context.Context, queries *db.Queries, vm *CharacterViewModel) {
+	loadout, err := loadRangedLoadout(ctx, queries, vm.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch ranged weapons",
+			zap.Error(err),
+			zap.Int64("character_id", vm.ID))
+		return
+	}
+
+	shots, err := queries.ListMissilesFired(ctx, vm.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch missiles fired",
+			zap.Error(err),
+			zap.Int64("character_id", vm.ID))
+	}
+
+	details := RangedDetails{
+		Launchers:       loadout.launchers,
+		RecoveryPercent: ammunition.DefaultRecoveryPercent,
+	}
+	for _, shot := range shots {
+		details.MissilesFired += shot.Count
+	}
+	for _, launcher := range loadout.launchers {
+		for _, stack := range ammunition.Ready(launcher, loadout.stacks, loadout.holders) {
+			details.Ready = append(details.Ready, ReadyAmmunition{
+				ID:        stack.ID,
+				Label:     stack.Label(),
+				Remaining: stack.Remaining(),
+				Launcher:  launcher.Name,
+			})
+		}
+	}
+
+	if len(details.Launchers) > 0 || details.MissilesFired > 0 {
+		vm.Ranged = &details
+	}
+}
+
+// HandleFireMissiles spends ammunition from an equipped quiver, case or
+// pouch for a ranged attack
+func (s *Server) HandleFireMissiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logger.Warn("Invalid method for firing missiles",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path))
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Character not found or belongs to another user",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	// Both are optional: any ready ordinary ammunition, one missile
+	stackID, _ := strconv.ParseInt(r.FormValue("ammunition_id"), 10, 64)
+	missiles, _ := strconv.ParseInt(r.FormValue("missiles"), 10, 64)
+
+	shot, err := s.fireMissiles(r.Context(), characterID, stackID, missiles)
+	if ammunition.IsRuleError(err) || durability.IsRuleError(err) || errors.Is(err, errItemNotFound) {
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, url.QueryEscape("Error: "+err.Error())), http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to fire missiles",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("ammunition_id", stackID))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error firing missiles", characterID), http.StatusSeeOther)
+		return
+	}
+
+	logger.Info("Missiles fired",
+		zap.Int64("character_id", characterID),
+		zap.Int64("ammunition_id", shot.Stack.ID),
+		zap.Int64("missiles", shot.Missiles),
+		zap.Int64("bonus", shot.Bonus))
+
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, url.QueryEscape(shot.Describe())), http.StatusSeeOther)
+}
+
+// HandleRecoverMissiles searches for missiles shot since the last search
+func (s *Server) HandleRecoverMissiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logger.Warn("Invalid method for recovering missiles",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path))
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Character not found or belongs to another user",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	percent := ammunition.DefaultRecoveryPercent
+	if raw := r.FormValue("percent"); raw != "" {
+		percent, err = strconv.Atoi(raw)
+		if err != nil {
+			http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, url.QueryEscape("Error: "+ammunition.ErrBadPercent.Error())), http.StatusSeeOther)
+			return
+		}
+	}
+
+	found, fired, err := s.recoverMissiles(r.Context(), characterID, percent)
+	if ammunition.IsRuleError(err) {
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, url.QueryEscape("Error: "+err.Error())), http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to recover missiles",
+			zap.Error(err),
+			zap.Int64("character_id", characterID))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error recovering missiles", characterID), http.StatusSeeOther)
+		return
+	}
+
+	logger.Info("Missiles recovered",
+		zap.Int64("character_id", characterID),
+		zap.Int("percent", percent),
+		zap.Int64("found", found),
+		zap.Int64("fired", fired))
+
+	message := "No missiles to recover"
+	if fired > 0 {
+		message = fmt.Sprintf("Recovered %d of %d missiles", found, fired)
+	}
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, url.QueryEscape(message)), http.StatusSeeOther)
+}
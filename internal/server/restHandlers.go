@@ -0,0 +1,412 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/ability_scores"
+	charRules "github.com/marbh56/mordezzan/internal/rules/character"
+	"github.com/marbh56/mordezzan/internal/rules/rest"
+	"go.uber.org/zap"
+)
+
+// ResourceUse pairs a daily resource with how much of it has been spent
+type ResourceUse struct {
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	Max       int    `json:"max"`
+	Used      int    `json:"used"`
+	Remaining int    `json:"remaining"`
+}
+
+// HandleRest rests a character using one of the configured rest modes,
+// healing them, restoring daily resources and advancing their game clock
+func (s *Server) HandleRest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		logger.Error("Invalid method for rest handler",
+			zap.String("method", r.Method))
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		logger.Error("Unauthorized access attempt to rest handler")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID in rest handler",
+			zap.Error(err),
+			zap.String("raw_id", r.FormValue("character_id")))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	modeCode := r.FormValue("mode")
+	if modeCode == "" {
+		modeCode = "night"
+	}
+
+	queries := db.New(s.db)
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Failed to fetch character for rest",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	mode, err := queries.GetRestMode(r.Context(), modeCode)
+	if err != nil {
+		logger.Warn("Unknown rest mode",
+			zap.Error(err),
+			zap.String("mode", modeCode))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: Unknown rest mode", characterID), http.StatusSeeOther)
+		return
+	}
+
+	conMods := ability_scores.CalculateConstitutionModifiers(character.Constitution)
+	healing, err := rest.EvaluateHealing(mode.HealingFormula, character.Level, conMods.HitPointMod,
+		func(sides int) int { return rand.IntN(sides) + 1 })
+	if err != nil {
+		logger.Error("Invalid healing formula",
+			zap.Error(err),
+			zap.String("mode", mode.Code),
+			zap.String("formula", mode.HealingFormula))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: Invalid healing formula for %s", characterID, mode.Name), http.StatusSeeOther)
+		return
+	}
+
+	newHP := character.CurrentHp + healing.Amount
+	if healing.Full || newHP > character.MaxHp {
+		newHP = character.MaxHp
+	}
+	if newHP < character.CurrentHp {
+		newHP = character.CurrentHp
+	}
+
+	tx, err := s.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error during rest", characterID), http.StatusSeeOther)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := queries.WithTx(tx)
+
+	_, err = qtx.UpdateCharacterHitPoints(r.Context(), db.UpdateCharacterHitPointsParams{
+		MaxHp:     character.MaxHp,
+		CurrentHp: newHP,
+		ID:        characterID,
+		UserID:    character.UserID,
+	})
+	if err != nil {
+		logger.Error("Failed to update character HP after rest",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("new_hp", newHP))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error during rest", characterID), http.StatusSeeOther)
+		return
+	}
+
+	if mode.ResetsResources {
+		if err := qtx.ResetCharacterResourceUses(r.Context(), characterID); err != nil {
+			logger.Error("Failed to reset daily resources", zap.Error(err))
+			http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error during rest", characterID), http.StatusSeeOther)
+			return
+		}
+	}
+
+	if err := qtx.EnsureGameClock(r.Context(), characterID); err != nil {
+		logger.Error("Failed to create game clock", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error during rest", characterID), http.StatusSeeOther)
+		return
+	}
+
+	clock, err := qtx.AdvanceGameClock(r.Context(), db.AdvanceGameClockParams{
+		ElapsedHours: mode.DurationHours,
+		CharacterID:  characterID,
+	})
+	if err != nil {
+		logger.Error("Failed to advance game clock", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error during rest", characterID), http.StatusSeeOther)
+		return
+	}
+
+	if _, err := expireItemEffects(r.Context(), qtx, characterID, clock.ElapsedHours); err != nil {
+		logger.Error("Failed to expire item effects", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error during rest", characterID), http.StatusSeeOther)
+		return
+	}
+
+	var rationsEaten, rationsShort int64
+	if clock.TrackTime && mode.RationsConsumed > 0 {
+		rationsEaten, err = consumeRations(r.Context(), qtx, characterID, mode.RationsConsumed)
+		if err != nil {
+			logger.Error("Failed to consume rations", zap.Error(err))
+			http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error during rest", characterID), http.StatusSeeOther)
+			return
+		}
+		rationsShort = mode.RationsConsumed - rationsEaten
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit rest", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error during rest", characterID), http.StatusSeeOther)
+		return
+	}
+
+	logger.Info("Character rest successful",
+		zap.Int64("character_id", characterID),
+		zap.String("mode", mode.Code),
+		zap.Int64("healing", newHP-character.CurrentHp),
+		zap.Int64("new_hp", newHP),
+		zap.Int64("elapsed_hours", clock.ElapsedHours),
+		zap.Int64("rations_eaten", rationsEaten))
+
+	parts := []string{fmt.Sprintf("%s complete! Healed %d HP", mode.Name, newHP-character.CurrentHp)}
+	if mode.ResetsResources {
+		parts = append(parts, "spells and abilities restored")
+	}
+	if rationsEaten > 0 {
+		parts = append(parts, fmt.Sprintf("rations eaten: %d", rationsEaten))
+	}
+	if rationsShort > 0 {
+		parts = append(parts, fmt.Sprintf("rations short: %d", rationsShort))
+	}
+	parts = append(parts, "now "+rest.FormatGameTime(clock.ElapsedHours))
+
+	message := strings.Join(parts, ", ")
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, message), http.StatusSeeOther)
+}
+
+// HandleUseResource spends one use of a spell slot or class feature
+func (s *Server) HandleUseResource(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	resourceKey := r.FormValue("resource")
+
+	queries := db.New(s.db)
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Error fetching character", zap.Error(err))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	var resource *charRules.DailyResource
+	for _, res := range charRules.GetDailyResources(character.Class, character.Level) {
+		if res.Key == resourceKey {
+			resource = &res
+			break
+		}
+	}
+	if resource == nil {
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: Unknown ability", characterID), http.StatusSeeOther)
+		return
+	}
+
+	tx, err := s.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error using ability", characterID), http.StatusSeeOther)
+		return
+	}
+	defer tx.Rollback()
+
+	use, err := queries.WithTx(tx).UseCharacterResource(r.Context(), db.UseCharacterResourceParams{
+		CharacterID: characterID,
+		Resource:    resource.Key,
+	})
+	if err != nil {
+		logger.Error("Failed to record resource use", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error using ability", characterID), http.StatusSeeOther)
+		return
+	}
+
+	if use.Used > int64(resource.Max) {
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error: No %s uses remaining", characterID, resource.Name), http.StatusSeeOther)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit resource use", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error using ability", characterID), http.StatusSeeOther)
+		return
+	}
+
+	message := fmt.Sprintf("Used %s (%d of %d remaining)", resource.Name, int64(resource.Max)-use.Used, resource.Max)
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, message), http.StatusSeeOther)
+}
+
+// HandleTimeTracking turns game time tracking on or off for a character
+func (s *Server) HandleTimeTracking(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	trackTime := r.FormValue("track_time") == "1"
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Error fetching character", zap.Error(err))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	if err := queries.EnsureGameClock(r.Context(), characterID); err != nil {
+		logger.Error("Failed to create game clock", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error updating time tracking", characterID), http.StatusSeeOther)
+		return
+	}
+
+	if err := queries.SetGameClockTracking(r.Context(), db.SetGameClockTrackingParams{
+		TrackTime:   trackTime,
+		CharacterID: characterID,
+	}); err != nil {
+		logger.Error("Failed to update time tracking", zap.Error(err))
+		http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=Error updating time tracking", characterID), http.StatusSeeOther)
+		return
+	}
+
+	message := "Time tracking disabled"
+	if trackTime {
+		message = "Time tracking enabled"
+	}
+	http.Redirect(w, r, fmt.Sprintf("/characters/detail?id=%d&message=%s", characterID, message), http.StatusSeeOther)
+}
+
+// consumeRations eats up to count rations, standard before iron, and
+// returns how many were found
+func consumeRations(ctx context.Context, qtx *db.Queries, characterID, count int64) (int64, error) {
+	rations, err := qtx.ListCharacterRations(ctx, characterID)
+	if err != nil {
+		return 0, err
+	}
+
+	var eaten int64
+	for _, ration := range rations {
+		if eaten == count {
+			break
+		}
+		take := count - eaten
+		if take >= ration.Quantity {
+			take = ration.Quantity
+			err = qtx.RemoveItemFromInventory(ctx, db.RemoveItemFromInventoryParams{
+				ID:          ration.ID,
+				CharacterID: characterID,
+			})
+		} else {
+			err = qtx.UpdateItemQuantity(ctx, db.UpdateItemQuantityParams{
+				Quantity:    ration.Quantity - take,
+				ID:          ration.ID,
+				CharacterID: characterID,
+			})
+		}
+		if err != nil {
+			return eaten, err
+		}
+		eaten += take
+	}
+	return eaten, nil
+}
+
+// loadCharacterDetails attaches everything the detail page shows beyond
+// the character row and inventory
+func (s *Server) loadCharacterDetails(ctx context.Context, queries *db.Queries, vm *CharacterViewModel) {
+	s.loadXPHistory(ctx, queries, vm)
+	s.loadRestDetails(ctx, queries, vm)
+	s.loadCampaignDetails(ctx, queries, vm)
+	s.loadStorageDetails(ctx, queries, vm)
+	s.loadTransferDetails(ctx, queries, vm)
+	s.loadItemProperties(ctx, queries, vm)
+	s.loadEquipOptions(ctx, queries, vm)
+	s.loadRangedDetails(ctx, queries, vm)
+	s.loadItemEffects(ctx, queries, vm)
+	s.loadItemConditions(ctx, queries, vm)
+}
+
+// loadRestDetails attaches rest modes, daily resource uses and the game
+// clock to a view model
+func (s *Server) loadRestDetails(ctx context.Context, queries *db.Queries, vm *CharacterViewModel) {
+	modes, err := queries.ListRestModes(ctx)
+	if err != nil {
+		logger.Warn("Failed to fetch rest modes", zap.Error(err))
+	}
+	vm.RestModes = modes
+
+	uses, err := queries.ListCharacterResourceUses(ctx, vm.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch resource uses",
+			zap.Error(err),
+			zap.Int64("character_id", vm.ID))
+	}
+	used := make(map[string]int64, len(uses))
+	for _, use := range uses {
+		used[use.Resource] = use.Used
+	}
+
+	vm.Resources = nil
+	for _, res := range charRules.GetDailyResources(vm.Class, vm.Level) {
+		spent := int(used[res.Key])
+		if spent > res.Max {
+			spent = res.Max
+		}
+		vm.Resources = append(vm.Resources, ResourceUse{
+			Key:       res.Key,
+			Name:      res.Name,
+			Max:       res.Max,
+			Used:      spent,
+			Remaining: res.Max - spent,
+		})
+	}
+
+	vm.GameTime = rest.FormatGameTime(0)
+	clock, err := queries.GetGameClock(ctx, vm.ID)
+	if err == nil {
+		vm.GameTime = rest.FormatGameTime(clock.ElapsedHours)
+		vm.TrackTime = clock.TrackTime
+	}
+}
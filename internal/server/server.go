@@ -44,12 +44,21 @@ func (s *Server) Routes() http.Handler {
 	mux.Handle("/characters/maxhp/update", s.AuthMiddleware(http.HandlerFunc(s.HandleUpdateMaxHP)))
 	mux.Handle("/characters/maxhp/form", s.AuthMiddleware(http.HandlerFunc(s.HandleMaxHPForm)))
 	mux.Handle("/characters/rest", s.AuthMiddleware(http.HandlerFunc(s.HandleRest)))
+	mux.Handle("/characters/rest/tracking", s.AuthMiddleware(http.HandlerFunc(s.HandleTimeTracking)))
+	mux.Handle("/characters/resources/use", s.AuthMiddleware(http.HandlerFunc(s.HandleUseResource)))
+
+	mux.Handle("/characters/conditions/add", s.AuthMiddleware(http.HandlerFunc(s.HandleAddCondition)))
+	mux.Handle("/characters/conditions/remove", s.AuthMiddleware(http.HandlerFunc(s.HandleRemoveCondition)))
 
 	// Currency routes (protected)
 	mux.Handle("/characters/currency/update", s.AuthMiddleware(http.HandlerFunc(s.HandleCurrencyUpdate)))
+	mux.Handle("/characters/currency/ledger", s.AuthMiddleware(http.HandlerFunc(s.HandleCurrencyLedger)))
 
 	// XP management routes (protected)
 	mux.Handle("/characters/xp/update", s.AuthMiddleware(http.HandlerFunc(s.HandleXPUpdate)))
+	mux.Handle("/characters/xp/reverse", s.AuthMiddleware(http.HandlerFunc(s.HandleXPAwardReverse)))
+	mux.Handle("/characters/xp/drain", s.AuthMiddleware(http.HandlerFunc(s.HandleLevelDrain)))
+	mux.Handle("/characters/xp/restore", s.AuthMiddleware(http.HandlerFunc(s.HandleLevelRestore)))
 
 	// Inventory management routes (protected)
 	mux.Handle("/characters/inventory/add", s.AuthMiddleware(http.HandlerFunc(s.HandleAddInventoryItem)))
@@ -58,6 +67,11 @@ func (s *Server) Routes() http.Handler {
 	mux.Handle("/characters/inventory/equip", s.AuthMiddleware(http.HandlerFunc(s.HandleEquipItem)))
 	mux.Handle("/characters/inventory/unequip", s.AuthMiddleware(http.HandlerFunc(s.HandleUnequipItem)))
 	mux.Handle("/characters/inventory/move", s.AuthMiddleware(http.HandlerFunc(s.HandleMoveToContainer)))
+	mux.Handle("/characters/inventory/enhancement", s.AuthMiddleware(http.HandlerFunc(s.HandleSetItemEnhancement)))
+	mux.Handle("/characters/inventory/properties/add", s.AuthMiddleware(http.HandlerFunc(s.HandleAddItemProperty)))
+	mux.Handle("/characters/inventory/properties/remove", s.AuthMiddleware(http.HandlerFunc(s.HandleRemoveItemProperty)))
+	mux.Handle("/characters/ranged/fire", s.AuthMiddleware(http.HandlerFunc(s.HandleFireMissiles)))
+	mux.Handle("/characters/ranged/recover", s.AuthMiddleware(http.HandlerFunc(s.HandleRecoverMissiles)))
 
 	// New modal inventory routes
 	mux.Handle("/characters/inventory/modal", s.AuthMiddleware(http.HandlerFunc(s.HandleInventoryModal)))
@@ -65,6 +79,51 @@ func (s *Server) Routes() http.Handler {
 
 	// Use magical item routes (protected)
 	mux.Handle("/characters/item/use", s.AuthMiddleware(http.HandlerFunc(s.HandleUseMagicalItem)))
+	mux.Handle("/characters/effects/end", s.AuthMiddleware(http.HandlerFunc(s.HandleEndItemEffect)))
+	mux.Handle("/characters/inventory/identify", s.AuthMiddleware(http.HandlerFunc(s.HandleIdentifyItem)))
+	mux.Handle("/characters/inventory/appraise", s.AuthMiddleware(http.HandlerFunc(s.HandleAppraiseItem)))
+	mux.Handle("/characters/inventory/drop", s.AuthMiddleware(http.HandlerFunc(s.HandleDropPack)))
+	mux.Handle("/characters/inventory/pickup", s.AuthMiddleware(http.HandlerFunc(s.HandlePickUpPack)))
+	mux.Handle("/characters/transfers/offer", s.AuthMiddleware(http.HandlerFunc(s.HandleOfferTransfer)))
+	mux.Handle("/characters/transfers/accept", s.AuthMiddleware(http.HandlerFunc(s.HandleAcceptTransfer)))
+	mux.Handle("/characters/transfers/decline", s.AuthMiddleware(http.HandlerFunc(s.HandleDeclineTransfer)))
+	mux.Handle("/characters/transfers/cancel", s.AuthMiddleware(http.HandlerFunc(s.HandleCancelTransfer)))
+	mux.Handle("/characters/storage", s.AuthMiddleware(http.HandlerFunc(s.HandleStorageManifest)))
+	mux.Handle("/characters/storage/create", s.AuthMiddleware(http.HandlerFunc(s.HandleCreateStorageLocation)))
+	mux.Handle("/characters/storage/delete", s.AuthMiddleware(http.HandlerFunc(s.HandleDeleteStorageLocation)))
+	mux.Handle("/characters/storage/store", s.AuthMiddleware(http.HandlerFunc(s.HandleStoreItem)))
+	mux.Handle("/characters/storage/coins", s.AuthMiddleware(http.HandlerFunc(s.HandleStoreCoins)))
+	mux.Handle("/characters/inventory/curse", s.AuthMiddleware(http.HandlerFunc(s.HandleCurseItem)))
+	mux.Handle("/characters/inventory/uncurse", s.AuthMiddleware(http.HandlerFunc(s.HandleRemoveCurse)))
+	mux.Handle("/characters/inventory/wear", s.AuthMiddleware(http.HandlerFunc(s.HandleWearItem)))
+	mux.Handle("/characters/inventory/repair", s.AuthMiddleware(http.HandlerFunc(s.HandleRepairItem)))
+	mux.Handle("/characters/shop", s.AuthMiddleware(http.HandlerFunc(s.HandleShop)))
+	mux.Handle("/characters/shop/cart", s.AuthMiddleware(http.HandlerFunc(s.HandleShopCart)))
+	mux.Handle("/characters/shop/checkout", s.AuthMiddleware(http.HandlerFunc(s.HandleShopCheckout)))
+	mux.Handle("/characters/shop/sell", s.AuthMiddleware(http.HandlerFunc(s.HandleShopSell)))
+
+	// Campaign routes (protected)
+	mux.Handle("/campaigns", s.AuthMiddleware(http.HandlerFunc(s.HandleCampaignList)))
+	mux.Handle("/campaigns/create", s.AuthMiddleware(http.HandlerFunc(s.HandleCampaignCreate)))
+	mux.Handle("/campaigns/detail", s.AuthMiddleware(http.HandlerFunc(s.HandleCampaignDetail)))
+	mux.Handle("/campaigns/invite", s.AuthMiddleware(http.HandlerFunc(s.HandleCampaignInvite)))
+	mux.Handle("/campaigns/invite-code", s.AuthMiddleware(http.HandlerFunc(s.HandleCampaignInviteCode)))
+	mux.Handle("/campaigns/join", s.AuthMiddleware(http.HandlerFunc(s.HandleCampaignJoin)))
+	mux.Handle("/campaigns/accept", s.AuthMiddleware(http.HandlerFunc(s.HandleCampaignAccept)))
+	mux.Handle("/campaigns/leave", s.AuthMiddleware(http.HandlerFunc(s.HandleCampaignLeave)))
+	mux.Handle("/campaigns/members/remove", s.AuthMiddleware(http.HandlerFunc(s.HandleCampaignRemoveMember)))
+	mux.Handle("/campaigns/characters/add", s.AuthMiddleware(http.HandlerFunc(s.HandleCampaignAddCharacter)))
+	mux.Handle("/campaigns/characters/remove", s.AuthMiddleware(http.HandlerFunc(s.HandleCampaignRemoveCharacter)))
+	mux.Handle("/campaigns/settings", s.AuthMiddleware(http.HandlerFunc(s.HandleCampaignSettings)))
+	mux.Handle("/campaigns/treasure", s.AuthMiddleware(http.HandlerFunc(s.HandleTreasureSplit)))
+	mux.Handle("/campaigns/treasure/preview", s.AuthMiddleware(http.HandlerFunc(s.HandleTreasurePreview)))
+	mux.Handle("/campaigns/treasure/apply", s.AuthMiddleware(http.HandlerFunc(s.HandleTreasureApply)))
+	mux.Handle("/campaigns/stash", s.AuthMiddleware(http.HandlerFunc(s.HandlePartyStash)))
+	mux.Handle("/campaigns/stash/deposit", s.AuthMiddleware(http.HandlerFunc(s.HandleStashDeposit)))
+	mux.Handle("/campaigns/stash/claim", s.AuthMiddleware(http.HandlerFunc(s.HandleStashClaim)))
+	mux.Handle("/campaigns/prices", s.AuthMiddleware(http.HandlerFunc(s.HandleSavePriceList)))
+	mux.Handle("/campaigns/prices/item", s.AuthMiddleware(http.HandlerFunc(s.HandleSetPriceListItem)))
+	mux.Handle("/campaigns/coins", s.AuthMiddleware(http.HandlerFunc(s.HandleSaveCampaignCoin)))
 
 	// User settings routes (protected)
 	mux.Handle("/settings", s.AuthMiddleware(http.HandlerFunc(s.HandleSettings)))
@@ -0,0 +1,616 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marbh56/mordezzan/internal/currency"
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/containers"
+	"github.com/marbh56/mordezzan/internal/rules/equipment"
+	"github.com/marbh56/mordezzan/internal/rules/stash"
+	"github.com/marbh56/mordezzan/internal/rules/treasure"
+	"go.uber.org/zap"
+)
+
+// StashItem is a stack of loot in the party stash
+type StashItem struct {
+	db.ListStashItemsRow
+}
+
+// ValueGP is what one of the items is worth, and whether the party knows:
+// the referee's appraisal, or the catalog value for anything but treasure,
+// whose worth is unknown until appraised
+func (i StashItem) ValueGP() (float64, bool) {
+	if i.AppraisedValue.Valid {
+		return i.AppraisedValue.Float64, true
+	}
+	if i.ItemType == treasure.ItemType {
+		return 0, false
+	}
+	return i.ItemValue, true
+}
+
+// ValueLabel formats the worth of one of the items
+func (i StashItem) ValueLabel() string {
+	value, known := i.ValueGP()
+	if !known {
+		return "unappraised"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64) + " gp"
+}
+
+// HandlePartyStash shows a campaign's stash of undivided loot and its log
+// to the referee and the players
+func (s *Server) HandlePartyStash(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	campaignID, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid campaign ID",
+			zap.Error(err),
+			zap.String("raw_id", r.URL.Query().Get("id")))
+		http.Error(w, "Invalid campaign ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	campaign, isReferee, err := getCampaignForUser(r.Context(), queries, campaignID, user.UserID)
+	if err != nil {
+		logger.Error("Campaign not found or user is not a member",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Campaign not found", http.StatusNotFound)
+		return
+	}
+
+	rows, err := queries.ListStashItems(r.Context(), campaignID)
+	if err != nil {
+		logger.Error("Failed to fetch stash items",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	items := make([]StashItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, StashItem{ListStashItemsRow: row})
+	}
+
+	coinage, err := campaignCoinage(r.Context(), queries, sql.NullInt64{Int64: campaignID, Valid: true})
+	if err != nil {
+		logger.Error("Failed to fetch coinage",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	coins, err := stashCoins(r.Context(), queries, campaignID)
+	if err != nil {
+		logger.Error("Failed to fetch stash coins",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	log, err := queries.ListStashLog(r.Context(), campaignID)
+	if err != nil {
+		logger.Warn("Failed to fetch stash log",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID))
+	}
+
+	// Characters the user can claim for: their own, and every character in
+	// the party when the referee may edit them
+	party, err := queries.ListCampaignCharacters(r.Context(), campaignID)
+	if err != nil {
+		logger.Warn("Failed to fetch campaign characters",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID))
+	}
+	var claimants []db.ListCampaignCharactersRow
+	for _, character := range party {
+		if character.UserID == user.UserID || (isReferee && campaign.RefereeCanEdit) {
+			claimants = append(claimants, character)
+		}
+	}
+
+	var catalog []db.ListShopItemsRow
+	if isReferee {
+		if catalog, err = queries.ListShopItems(r.Context(), sql.NullInt64{}); err != nil {
+			logger.Warn("Failed to fetch catalog",
+				zap.Error(err),
+				zap.Int64("campaign_id", campaignID))
+		}
+	}
+
+	data := struct {
+		IsAuthenticated bool
+		Username        string
+		Campaign        db.Campaign
+		IsReferee       bool
+		Items           []StashItem
+		Coins           string
+		HasCoins        bool
+		Denominations   []currency.Coin
+		Log             []db.ListStashLogRow
+		Claimants       []db.ListCampaignCharactersRow
+		Catalog         []db.ListShopItemsRow
+		FlashMessage    string
+		CurrentYear     int
+	}{
+		IsAuthenticated: true,
+		Username:        user.Username,
+		Campaign:        campaign,
+		IsReferee:       isReferee,
+		Items:           items,
+		Coins:           coinage.Format(coins),
+		HasCoins:        coins.Count() > 0,
+		Denominations:   coinage.Coins(),
+		Log:             log,
+		Claimants:       claimants,
+		Catalog:         catalog,
+		FlashMessage:    r.URL.Query().Get("message"),
+		CurrentYear:     time.Now().Year(),
+	}
+
+	RenderTemplate(w, "templates/campaigns/stash.html", "base.html", data)
+}
+
+// stashCoins reads the coins in a campaign's stash
+func stashCoins(ctx context.Context, queries *db.Queries, campaignID int64) (currency.Purse, error) {
+	rows, err := queries.ListStashCoins(ctx, campaignID)
+	if err != nil {
+		return currency.Purse{}, err
+	}
+	coins := make(currency.Purse, len(rows))
+	for _, row := range rows {
+		coins.Add(currency.Denomination(row.Denomination), row.Amount)
+	}
+	return coins, nil
+}
+
+// redirectToStash shows the stash again with a message
+func redirectToStash(w http.ResponseWriter, r *http.Request, campaignID int64, message string) {
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/stash?id=%d&message=%s", campaignID, url.QueryEscape(message)), http.StatusSeeOther)
+}
+
+// HandleStashDeposit puts treasure the party found into the stash. Only
+// the referee deposits.
+func (s *Server) HandleStashDeposit(w http.ResponseWriter, r *http.Request) {
+	user, campaign, ok := s.refereeCampaignFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	var deposit stashDeposit
+	if itemStr := r.Form.Get("item_id"); itemStr != "" {
+		itemID, err := strconv.ParseInt(itemStr, 10, 64)
+		if err != nil {
+			logger.Error("Invalid item ID", zap.Error(err))
+			http.Error(w, "Invalid item ID", http.StatusBadRequest)
+			return
+		}
+		deposit.ItemID = itemID
+		deposit.Quantity = 1
+		if quantityStr := r.Form.Get("quantity"); quantityStr != "" {
+			if deposit.Quantity, err = strconv.ParseInt(quantityStr, 10, 64); err != nil {
+				redirectToStash(w, r, campaign.ID, "Error: "+stash.ErrBadQuantity.Error())
+				return
+			}
+		}
+		if valueStr := strings.TrimSpace(r.Form.Get("appraised_value")); valueStr != "" {
+			value, err := strconv.ParseFloat(valueStr, 64)
+			if err != nil || value < 0 {
+				redirectToStash(w, r, campaign.ID, "Error: value must be a number of gold pieces")
+				return
+			}
+			deposit.AppraisedValue = sql.NullFloat64{Float64: value, Valid: true}
+		}
+	}
+
+	queries := db.New(s.db)
+	coinage, err := campaignCoinage(r.Context(), queries, sql.NullInt64{Int64: campaign.ID, Valid: true})
+	if err != nil {
+		logger.Error("Failed to fetch coinage", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	deposit.Coins = currency.Purse{}
+	for _, coin := range coinage.Coins() {
+		amount, err := parseTreasureAmount(r.Form.Get("coins_" + string(coin.Denomination)))
+		if err != nil {
+			redirectToStash(w, r, campaign.ID, fmt.Sprintf("Error: invalid number of %s", strings.ToLower(coin.Name)))
+			return
+		}
+		if amount > 0 {
+			deposit.Coins.Add(coin.Denomination, amount)
+		}
+	}
+	deposit.Notes = strings.TrimSpace(r.Form.Get("notes"))
+
+	if err := stash.CheckDeposit(deposit.ItemID != 0, deposit.Quantity, deposit.Coins.Count()); err != nil {
+		redirectToStash(w, r, campaign.ID, "Error: "+err.Error())
+		return
+	}
+
+	if err := s.depositInStash(r.Context(), campaign.ID, user.UserID, coinage, deposit); err != nil {
+		logger.Error("Failed to deposit in stash",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaign.ID))
+		redirectToStash(w, r, campaign.ID, "Error depositing in the stash")
+		return
+	}
+
+	logger.Info("Stash deposit",
+		zap.Int64("campaign_id", campaign.ID),
+		zap.Int64("item_id", deposit.ItemID),
+		zap.Int64("quantity", deposit.Quantity),
+		zap.Int64("coins", deposit.Coins.Count()))
+
+	redirectToStash(w, r, campaign.ID, "Added to the party stash")
+}
+
+// stashDeposit is loot the referee puts in the stash
+type stashDeposit struct {
+	ItemID         int64
+	Quantity       int64
+	AppraisedValue sql.NullFloat64
+	Coins          currency.Purse
+	Notes          string
+}
+
+// depositInStash adds an item and coins to the stash and logs each
+func (s *Server) depositInStash(ctx context.Context, campaignID, userID int64, coinage *currency.Coinage, deposit stashDeposit) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	notes := sql.NullString{String: deposit.Notes, Valid: deposit.Notes != ""}
+	if deposit.ItemID != 0 {
+		if _, err := qtx.AddStashItem(ctx, db.AddStashItemParams{
+			CampaignID:     campaignID,
+			ItemID:         deposit.ItemID,
+			Quantity:       deposit.Quantity,
+			AppraisedValue: deposit.AppraisedValue,
+			Notes:          notes,
+		}); err != nil {
+			return err
+		}
+		if err := qtx.LogStashChange(ctx, db.LogStashChangeParams{
+			CampaignID: campaignID,
+			Action:     stash.ActionDeposit,
+			ItemID:     sql.NullInt64{Int64: deposit.ItemID, Valid: true},
+			Quantity:   deposit.Quantity,
+			UserID:     userID,
+			Notes:      notes,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if deposit.Coins.Count() > 0 {
+		for denom, amount := range deposit.Coins {
+			if err := qtx.AddStashCoins(ctx, db.AddStashCoinsParams{
+				CampaignID:   campaignID,
+				Denomination: string(denom),
+				Amount:       amount,
+			}); err != nil {
+				return err
+			}
+		}
+		if err := qtx.LogStashChange(ctx, db.LogStashChangeParams{
+			CampaignID: campaignID,
+			Action:     stash.ActionDeposit,
+			Coins:      sql.NullString{String: coinage.FormatChange(deposit.Coins), Valid: true},
+			UserID:     userID,
+			Notes:      notes,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// HandleStashClaim moves items from the stash into the inventory of a
+// character in the campaign
+func (s *Server) HandleStashClaim(w http.ResponseWriter, r *http.Request) {
+	user, campaignID, ok := campaignIDFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, _, err := getCampaignForUser(r.Context(), queries, campaignID, user.UserID); err != nil {
+		logger.Error("Campaign not found or user is not a member",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Campaign not found", http.StatusNotFound)
+		return
+	}
+
+	stashItemID, err := strconv.ParseInt(r.Form.Get("stash_item_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid stash item ID", zap.Error(err))
+		http.Error(w, "Invalid stash item ID", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.Form.Get("character_id"), 10, 64)
+	if err != nil {
+		redirectToStash(w, r, campaignID, "Error: choose who claims it")
+		return
+	}
+
+	quantity := int64(1)
+	if quantityStr := r.Form.Get("quantity"); quantityStr != "" {
+		if quantity, err = strconv.ParseInt(quantityStr, 10, 64); err != nil {
+			redirectToStash(w, r, campaignID, "Error: "+stash.ErrBadQuantity.Error())
+			return
+		}
+	}
+
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	message, err := s.claimFromStash(r.Context(), campaignID, character, user.UserID, stashItemID, quantity)
+	if stash.IsRuleError(err) || containers.IsRuleError(err) || equipment.IsRuleError(err) || errors.Is(err, sql.ErrNoRows) {
+		logger.Warn("Stash claim refused",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID),
+			zap.Int64("stash_item_id", stashItemID),
+			zap.Int64("character_id", characterID))
+		redirectToStash(w, r, campaignID, "Error: "+stashErrorMessage(err))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to claim from stash",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID),
+			zap.Int64("stash_item_id", stashItemID),
+			zap.Int64("character_id", characterID))
+		redirectToStash(w, r, campaignID, "Error claiming from the stash")
+		return
+	}
+
+	logger.Info("Stash claim",
+		zap.Int64("campaign_id", campaignID),
+		zap.Int64("stash_item_id", stashItemID),
+		zap.Int64("character_id", characterID),
+		zap.Int64("quantity", quantity))
+
+	redirectToStash(w, r, campaignID, message)
+}
+
+// claimFromStash takes quantity of a stash entry into a character's
+// inventory, carrying over the referee's appraisal
+func (s *Server) claimFromStash(ctx context.Context, campaignID int64, character db.Character, userID, stashItemID, quantity int64) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	characterCampaign, err := characterCampaignID(ctx, qtx, character.ID)
+	if err != nil {
+		return "", err
+	}
+	if err := stash.CheckClaimant(campaignID, characterCampaign); err != nil {
+		return "", err
+	}
+
+	item, err := qtx.GetStashItem(ctx, db.GetStashItemParams{
+		ID:         stashItemID,
+		CampaignID: campaignID,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := takeFromStash(ctx, qtx, item, quantity); err != nil {
+		return "", err
+	}
+
+	inventoryID, err := insertInventoryItem(ctx, qtx, db.AddItemToInventoryParams{
+		CharacterID: character.ID,
+		ItemID:      item.ItemID,
+		Quantity:    quantity,
+	}, nil, nil, true)
+	if err != nil {
+		return "", err
+	}
+	if item.AppraisedValue.Valid {
+		if err := qtx.SetItemAppraisal(ctx, db.SetItemAppraisalParams{
+			AppraisedValue: item.AppraisedValue,
+			ID:             inventoryID,
+			CharacterID:    character.ID,
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	if err := qtx.LogStashChange(ctx, db.LogStashChangeParams{
+		CampaignID:  campaignID,
+		Action:      stash.ActionClaim,
+		ItemID:      sql.NullInt64{Int64: item.ItemID, Valid: true},
+		Quantity:    -quantity,
+		CharacterID: sql.NullInt64{Int64: character.ID, Valid: true},
+		UserID:      userID,
+	}); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s claimed %d × %s", character.Name, quantity, item.ItemName), nil
+}
+
+// takeFromStash removes quantity of a stash entry, dropping the entry once
+// it is used up
+func takeFromStash(ctx context.Context, qtx *db.Queries, item db.GetStashItemRow, quantity int64) error {
+	if err := stash.CheckTake(item.ItemName, item.Quantity, quantity); err != nil {
+		return err
+	}
+	if quantity == item.Quantity {
+		return qtx.DeleteStashItem(ctx, db.DeleteStashItemParams{
+			ID:         item.ID,
+			CampaignID: item.CampaignID,
+		})
+	}
+	return qtx.TakeStashItems(ctx, db.TakeStashItemsParams{
+		Quantity:   quantity,
+		ID:         item.ID,
+		CampaignID: item.CampaignID,
+	})
+}
+
+// stashErrorMessage explains a refused stash change to the player
+func stashErrorMessage(err error) string {
+	if errors.Is(err, sql.ErrNoRows) {
+		return "that is no longer in the stash"
+	}
+	return err.Error()
+}
+
+// fillSplitFromStash sets up the treasure split form with everything in
+// the stash: its coins as the hoard and each item as a valuable to keep
+func fillSplitFromStash(ctx context.Context, queries *db.Queries, campaignID int64, form url.Values) error {
+	coins, err := stashCoins(ctx, queries, campaignID)
+	if err != nil {
+		return err
+	}
+	rows, err := queries.ListStashItems(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+
+	if form.Get("description") == "" {
+		form.Set("description", "Party stash")
+	}
+	for denom, amount := range coins {
+		form.Set(string(denom), strconv.FormatInt(amount, 10))
+	}
+	for _, row := range rows {
+		item := StashItem{ListStashItemsRow: row}
+		for n := int64(0); n < row.Quantity; n++ {
+			form.Add("valuable_stash", strconv.FormatInt(row.ID, 10))
+			form.Add("valuable_item", strconv.FormatInt(row.ItemID, 10))
+			form.Add("valuable_name", row.ItemName)
+			// Unappraised treasure is left for the referee to value
+			var value string
+			if valueGP, known := item.ValueGP(); known {
+				value = strconv.FormatFloat(valueGP, 'f', 0, 64)
+			}
+			form.Add("valuable_value", value)
+			form.Add("valuable_holder", "")
+		}
+	}
+	return nil
+}
+
+// takeSplitFromStash removes what a posted split handed out from the stash:
+// its valuables and the hoard's coins, leaving the undivided remainder
+func takeSplitFromStash(ctx context.Context, qtx *db.Queries, campaignID, userID int64, plan TreasurePlan) error {
+	for _, valuable := range plan.Valuables {
+		if valuable.StashID == 0 {
+			continue
+		}
+		item, err := qtx.GetStashItem(ctx, db.GetStashItemParams{
+			ID:         valuable.StashID,
+			CampaignID: campaignID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", stash.ErrNotEnough, valuable.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if err := takeFromStash(ctx, qtx, item, 1); err != nil {
+			return err
+		}
+		if err := qtx.LogStashChange(ctx, db.LogStashChangeParams{
+			CampaignID:  campaignID,
+			Action:      stash.ActionSplit,
+			ItemID:      sql.NullInt64{Int64: item.ItemID, Valid: true},
+			Quantity:    -1,
+			CharacterID: sql.NullInt64{Int64: valuable.HolderID, Valid: true},
+			UserID:      userID,
+			Notes:       sql.NullString{String: "Treasure split: " + plan.Description, Valid: true},
+		}); err != nil {
+			return err
+		}
+	}
+
+	held, err := stashCoins(ctx, qtx, campaignID)
+	if err != nil {
+		return err
+	}
+	for denom, amount := range plan.Hoard {
+		if amount > 0 {
+			if err := stash.CheckTake(string(denom), held.Of(denom), amount); err != nil {
+				return err
+			}
+		}
+	}
+	// Henchmen's coins stay in the stash until the referee pays them out
+	left := plan.Remainder.Clone()
+	for denom, amount := range plan.HenchmanCoins {
+		left.Add(denom, amount)
+	}
+	change := currency.Diff(plan.Hoard, left)
+	if len(change) == 0 {
+		return nil
+	}
+	for denom, amount := range change {
+		if amount < 0 {
+			err = qtx.TakeStashCoins(ctx, db.TakeStashCoinsParams{
+				Amount:       -amount,
+				CampaignID:   campaignID,
+				Denomination: string(denom),
+			})
+		} else {
+			err = qtx.AddStashCoins(ctx, db.AddStashCoinsParams{
+				CampaignID:   campaignID,
+				Denomination: string(denom),
+				Amount:       amount,
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return qtx.LogStashChange(ctx, db.LogStashChangeParams{
+		CampaignID: campaignID,
+		Action:     stash.ActionSplit,
+		Coins:      sql.NullString{String: plan.coinage.FormatChange(change), Valid: true},
+		UserID:     userID,
+		Notes:      sql.NullString{String: "Treasure split: " + plan.Description, Valid: true},
+	})
+}
@@ -0,0 +1,873 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marbh56/mordezzan/internal/currency"
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/containers"
+	"github.com/marbh56/mordezzan/internal/rules/equipment"
+	"github.com/marbh56/mordezzan/internal/rules/magic"
+	"github.com/marbh56/mordezzan/internal/rules/storage"
+	"go.uber.org/zap"
+)
+
+// StorageLocationView is a storage location as shown on the character
+// sheet, with what the character keeps there
+type StorageLocationView struct {
+	ID        int64           `json:"id"`
+	Name      string          `json:"name"`
+	Kind      string          `json:"kind"`
+	KindLabel string          `json:"kind_label"`
+	Notes     string          `json:"notes,omitempty"`
+	Shared    bool            `json:"shared,omitempty"` // Belongs to the campaign rather than the character
+	Items     []InventoryItem `json:"items"`            // Top-level items only; contents go with their container
+	Weight    float64         `json:"weight"`
+	Purse     currency.Purse  `json:"purse"`
+	Coins     string          `json:"coins"`
+}
+
+// WeightLabel formats the weight of the character's items there in pounds
+func (l StorageLocationView) WeightLabel() string {
+	return formatPounds(l.Weight)
+}
+
+// StorageManifestItem is one stack at a storage location, indented under
+// the container holding it
+type StorageManifestItem struct {
+	ID          int64
+	Name        string
+	Owner       string
+	OwnerID     int64
+	Quantity    int64
+	Depth       int
+	WeightLabel string // Including contents
+}
+
+// StorageManifestCoins is what one character has left at a location
+type StorageManifestCoins struct {
+	Owner string
+	Coins string
+}
+
+// loadStorageDetails attaches the storage locations a character can use
+// and what they keep at each, counting stored coins towards net worth. It
+// must run after the campaign is known.
+func (s *Server) loadStorageDetails(ctx context.Context, queries *db.Queries, vm *CharacterViewModel) {
+	vm.StorageKinds = storage.Kinds
+
+	locations, err := queries.ListStorageLocationsForCharacter(ctx, db.ListStorageLocationsForCharacterParams{
+		CharacterID: sql.NullInt64{Int64: vm.ID, Valid: true},
+		CampaignID:  sql.NullInt64{Int64: vm.CampaignID, Valid: vm.CampaignID != 0},
+	})
+	if err != nil {
+		logger.Warn("Failed to fetch storage locations",
+			zap.Error(err),
+			zap.Int64("character_id", vm.ID))
+		return
+	}
+
+	coins, err := queries.ListCharacterStoredCoins(ctx, vm.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch stored coins",
+			zap.Error(err),
+			zap.Int64("character_id", vm.ID))
+	}
+	purses := make(map[int64]currency.Purse)
+	for _, coin := range coins {
+		if purses[coin.StorageLocationID] == nil {
+			purses[coin.StorageLocationID] = currency.Purse{}
+		}
+		purses[coin.StorageLocationID].Add(currency.Denomination(coin.Denomination), coin.Amount)
+	}
+
+	// Things left at a shared location stay listed after the character
+	// leaves its campaign, so they can be fetched back
+	listed := make(map[int64]bool, len(locations))
+	for _, location := range locations {
+		listed[location.ID] = true
+	}
+	var stranded []int64
+	for id := range vm.StoredItems {
+		if !listed[id] {
+			listed[id] = true
+			stranded = append(stranded, id)
+		}
+	}
+	for id := range purses {
+		if !listed[id] {
+			listed[id] = true
+			stranded = append(stranded, id)
+		}
+	}
+	sort.Slice(stranded, func(i, j int) bool { return stranded[i] < stranded[j] })
+	for _, id := range stranded {
+		location, err := queries.GetStorageLocation(ctx, id)
+		if err != nil {
+			logger.Warn("Failed to fetch storage location",
+				zap.Error(err),
+				zap.Int64("storage_location_id", id))
+			continue
+		}
+		locations = append(locations, location)
+	}
+
+	coinage, _ := characterCoins(ctx, queries, vm.ID)
+	vm.StorageLocations = make([]StorageLocationView, 0, len(locations))
+	for _, location := range locations {
+		purse := purses[location.ID]
+		vm.StorageLocations = append(vm.StorageLocations, StorageLocationView{
+			ID:        location.ID,
+			Name:      location.Name,
+			Kind:      location.Kind,
+			KindLabel: storage.KindLabel(location.Kind),
+			Notes:     location.Notes.String,
+			Shared:    location.CampaignID.Valid,
+			Items:     vm.StoredItems[location.ID],
+			Weight:    vm.StoredWeight[location.ID],
+			Purse:     purse,
+			Coins:     coinage.Format(purse),
+		})
+		vm.NetWorth += coinage.Value(purse)
+	}
+}
+
+// storageLocationFor returns a location the character may use: one of
+// their own, or one shared by their campaign
+func storageLocationFor(ctx context.Context, queries *db.Queries, characterID, locationID int64) (db.StorageLocation, error) {
+	location, err := queries.GetStorageLocation(ctx, locationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.StorageLocation{}, storage.ErrNotYours
+	}
+	if err != nil {
+		return db.StorageLocation{}, err
+	}
+
+	if location.CharacterID.Valid && location.CharacterID.Int64 == characterID {
+		return location, nil
+	}
+	if location.CampaignID.Valid {
+		campaign, err := queries.GetCharacterCampaign(ctx, characterID)
+		switch {
+		case err == nil && campaign.ID == location.CampaignID.Int64:
+			return location, nil
+		case err != nil && !errors.Is(err, sql.ErrNoRows):
+			return db.StorageLocation{}, err
+		}
+	}
+	return db.StorageLocation{}, fmt.Errorf("%w: %s", storage.ErrNotYours, location.Name)
+}
+
+// storageFormLocation reads the location a form names, which is null for
+// the character's person
+func storageFormLocation(r *http.Request) (sql.NullInt64, error) {
+	value := r.FormValue("location_id")
+	if value == "" {
+		return sql.NullInt64{}, nil
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return sql.NullInt64{}, err
+	}
+	return sql.NullInt64{Int64: id, Valid: true}, nil
+}
+
+// HandleCreateStorageLocation adds a place for a character to keep things,
+// shared with the party when asked and the character is in a campaign
+func (s *Server) HandleCreateStorageLocation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	kind := r.FormValue("kind")
+	if err := storage.CheckLocation(name, kind); err != nil {
+		renderInventoryWithMessage(w, r, characterID, "Error: "+err.Error())
+		return
+	}
+
+	notes := strings.TrimSpace(r.FormValue("notes"))
+	params := db.CreateStorageLocationParams{
+		CharacterID: sql.NullInt64{Int64: character.ID, Valid: true},
+		Name:        name,
+		Kind:        kind,
+		Notes:       sql.NullString{String: notes, Valid: notes != ""},
+	}
+	if r.FormValue("shared") != "" {
+		campaign, err := queries.GetCharacterCampaign(r.Context(), character.ID)
+		if err != nil {
+			renderInventoryWithMessage(w, r, characterID, "Error: only a character in a campaign can share a storage location with the party")
+			return
+		}
+		params.CharacterID = sql.NullInt64{}
+		params.CampaignID = sql.NullInt64{Int64: campaign.ID, Valid: true}
+	}
+
+	location, err := queries.CreateStorageLocation(r.Context(), params)
+	if err != nil {
+		logger.Error("Failed to create storage location",
+			zap.Error(err),
+			zap.Int64("character_id", characterID))
+		renderInventoryWithMessage(w, r, characterID, "Error creating storage location")
+		return
+	}
+
+	logger.Info("Storage location created",
+		zap.Int64("character_id", characterID),
+		zap.Int64("storage_location_id", location.ID),
+		zap.String("kind", location.Kind))
+
+	renderInventoryWithMessage(w, r, characterID, fmt.Sprintf("%s added", location.Name))
+}
+
+// HandleDeleteStorageLocation removes a storage location once nothing is
+// left there
+func (s *Server) HandleDeleteStorageLocation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	locationID, err := strconv.ParseInt(r.FormValue("location_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid storage location ID", zap.Error(err))
+		http.Error(w, "Invalid storage location ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	message, err := s.deleteStorageLocation(r.Context(), characterID, locationID)
+	if err != nil {
+		if storage.IsRuleError(err) {
+			renderInventoryWithMessage(w, r, characterID, "Error: "+err.Error())
+			return
+		}
+		logger.Error("Failed to delete storage location",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("storage_location_id", locationID))
+		renderInventoryWithMessage(w, r, characterID, "Error removing storage location")
+		return
+	}
+
+	logger.Info("Storage location deleted",
+		zap.Int64("character_id", characterID),
+		zap.Int64("storage_location_id", locationID))
+
+	renderInventoryWithMessage(w, r, characterID, message)
+}
+
+// deleteStorageLocation removes an empty location the character may use
+func (s *Server) deleteStorageLocation(ctx context.Context, characterID, locationID int64) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	location, err := storageLocationFor(ctx, qtx, characterID, locationID)
+	if err != nil {
+		return "", err
+	}
+	contents, err := qtx.CountStorageLocationContents(ctx, db.CountStorageLocationContentsParams{
+		StorageLocationID:   sql.NullInt64{Int64: location.ID, Valid: true},
+		StorageLocationID_2: location.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := storage.CheckEmpty(location.Name, contents); err != nil {
+		return "", err
+	}
+	// Emptied coin purses are left behind at zero
+	if err := qtx.ClearStorageLocationCoins(ctx, location.ID); err != nil {
+		return "", err
+	}
+	if err := qtx.DeleteStorageLocation(ctx, location.ID); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s removed", location.Name), nil
+}
+
+// HandleStoreItem leaves an item at a storage location, moves it between
+// locations, or brings it back onto the character when no location is
+// given. A quantity smaller than the stack moves only that many.
+func (s *Server) HandleStoreItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(r.FormValue("item_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid item ID", zap.Error(err))
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	locationID, err := storageFormLocation(r)
+	if err != nil {
+		logger.Error("Invalid storage location ID", zap.Error(err))
+		http.Error(w, "Invalid storage location ID", http.StatusBadRequest)
+		return
+	}
+
+	var quantity int64
+	if quantityStr := r.FormValue("quantity"); quantityStr != "" {
+		quantity, err = strconv.ParseInt(quantityStr, 10, 64)
+		if err != nil || quantity < 1 {
+			http.Error(w, "Invalid quantity", http.StatusBadRequest)
+			return
+		}
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	message, err := s.storeInventoryItem(r.Context(), characterID, itemID, quantity, locationID)
+	if storage.IsRuleError(err) || equipment.IsRuleError(err) || errors.Is(err, errItemNotFound) {
+		logger.Warn("Item storage refused",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("item_id", itemID),
+			zap.Any("storage_location_id", locationID))
+		renderInventoryWithMessage(w, r, characterID, "Error: "+err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to store item",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("item_id", itemID),
+			zap.Any("storage_location_id", locationID))
+		renderInventoryWithMessage(w, r, characterID, "Error moving item")
+		return
+	}
+
+	logger.Info("Item storage changed",
+		zap.Int64("character_id", characterID),
+		zap.Int64("item_id", itemID),
+		zap.Any("storage_location_id", locationID))
+
+	renderInventoryWithMessage(w, r, characterID, message)
+}
+
+// storeInventoryItem moves an item, with anything packed inside it, to a
+// storage location or back onto the character. The item leaves any slot or
+// container it was in.
+func (s *Server) storeInventoryItem(ctx context.Context, characterID, itemID, quantity int64, locationID sql.NullInt64) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	var location db.StorageLocation
+	if locationID.Valid {
+		location, err = storageLocationFor(ctx, qtx, characterID, locationID.Int64)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	inv, err := loadContainerInventory(ctx, qtx, characterID)
+	if err != nil {
+		return "", err
+	}
+	item, ok := inv.Entry(itemID)
+	if !ok {
+		return "", errItemNotFound
+	}
+	identity, err := inventoryItemIdentity(ctx, qtx, characterID, itemID)
+	if err != nil {
+		return "", err
+	}
+
+	split := quantity > 0 && quantity < item.Quantity
+	if !split {
+		if err := checkItemRelease(ctx, qtx, characterID, itemID, sql.NullInt64{}); err != nil {
+			return "", err
+		}
+		if err := qtx.StoreInventoryItem(ctx, db.StoreInventoryItemParams{
+			StorageLocationID: locationID,
+			ID:                itemID,
+			CharacterID:       characterID,
+		}); err != nil {
+			return "", err
+		}
+	} else {
+		// The split-off stack starts out where the stack is, then goes
+		newID, err := qtx.SplitStack(ctx, db.SplitStackParams{
+			Quantity:    quantity,
+			ContainerID: sql.NullInt64{},
+			ID:          itemID,
+			CharacterID: characterID,
+		})
+		if err != nil {
+			return "", err
+		}
+		if err := qtx.CopyItemProperties(ctx, db.CopyItemPropertiesParams{
+			InventoryID:   newID,
+			InventoryID_2: itemID,
+		}); err != nil {
+			return "", err
+		}
+		if err := qtx.ReduceStackQuantity(ctx, db.ReduceStackQuantityParams{
+			Quantity:    quantity,
+			ID:          itemID,
+			CharacterID: characterID,
+		}); err != nil {
+			return "", err
+		}
+		if err := qtx.StoreInventoryItem(ctx, db.StoreInventoryItemParams{
+			StorageLocationID: locationID,
+			ID:                newID,
+			CharacterID:       characterID,
+		}); err != nil {
+			return "", err
+		}
+		item.Quantity = quantity
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	if !locationID.Valid {
+		return fmt.Sprintf("Took back %d × %s", item.Quantity, identity.Shown()), nil
+	}
+	return fmt.Sprintf("Left %d × %s at %s", item.Quantity, identity.Shown(), location.Name), nil
+}
+
+// HandleStoreCoins moves coins between a character's purse and a storage
+// location, recording the change in the currency ledger
+func (s *Server) HandleStoreCoins(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	locationID, err := strconv.ParseInt(r.FormValue("location_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid storage location ID", zap.Error(err))
+		http.Error(w, "Invalid storage location ID", http.StatusBadRequest)
+		return
+	}
+
+	amount, err := strconv.ParseInt(r.FormValue("amount"), 10, 64)
+	if err != nil {
+		renderInventoryWithMessage(w, r, characterID, "Error: "+storage.ErrBadAmount.Error())
+		return
+	}
+
+	action := r.FormValue("action")
+	if action != "deposit" && action != "withdraw" {
+		http.Error(w, "Unknown storage action", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	denom := currency.Denomination(r.FormValue("denomination"))
+	message, err := s.storeCoins(r.Context(), characterID, locationID, denom, amount, action == "withdraw")
+	if err != nil {
+		if storage.IsRuleError(err) {
+			renderInventoryWithMessage(w, r, characterID, "Error: "+err.Error())
+			return
+		}
+		logger.Error("Failed to move stored coins",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("storage_location_id", locationID))
+		renderInventoryWithMessage(w, r, characterID, "Error moving coins")
+		return
+	}
+
+	logger.Info("Stored coins changed",
+		zap.Int64("character_id", characterID),
+		zap.Int64("storage_location_id", locationID),
+		zap.String("action", action),
+		zap.String("denomination", string(denom)),
+		zap.Int64("amount", amount))
+
+	renderInventoryWithMessage(w, r, characterID, message)
+}
+
+// storeCoins leaves coins from the purse at a location, or takes coins
+// left there back into it
+func (s *Server) storeCoins(ctx context.Context, characterID, locationID int64, denom currency.Denomination, amount int64, withdraw bool) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	location, err := storageLocationFor(ctx, qtx, characterID, locationID)
+	if err != nil {
+		return "", err
+	}
+	coinage, err := loadCoinage(ctx, qtx, characterID)
+	if err != nil {
+		return "", err
+	}
+	name := coinName(coinage, amount, denom)
+
+	if withdraw {
+		stored, err := qtx.ListCharacterStoredCoins(ctx, characterID)
+		if err != nil {
+			return "", err
+		}
+		var held int64
+		for _, coin := range stored {
+			if coin.StorageLocationID == location.ID && coin.Denomination == string(denom) {
+				held = coin.Amount
+			}
+		}
+		if err := storage.CheckCoins(held, amount, string(denom)); err != nil {
+			return "", err
+		}
+		if err := qtx.TakeStoredCoins(ctx, db.TakeStoredCoinsParams{
+			Amount:            amount,
+			StorageLocationID: location.ID,
+			CharacterID:       characterID,
+			Denomination:      string(denom),
+		}); err != nil {
+			return "", err
+		}
+		if err := recordCurrency(ctx, qtx, CurrencyEntry{
+			CharacterID: characterID,
+			Reason:      "storage",
+			Coins:       currency.Purse{denom: amount},
+			Notes:       "Taken from " + location.Name,
+		}); err != nil {
+			return "", err
+		}
+	} else {
+		purse, err := loadPurse(ctx, qtx, characterID)
+		if err != nil {
+			return "", err
+		}
+		if err := storage.CheckCoins(purse.Of(denom), amount, string(denom)); err != nil {
+			return "", err
+		}
+		if err := recordCurrency(ctx, qtx, CurrencyEntry{
+			CharacterID: characterID,
+			Reason:      "storage",
+			Coins:       currency.Purse{denom: -amount},
+			Notes:       "Left at " + location.Name,
+		}); err != nil {
+			return "", err
+		}
+		if err := qtx.AddStoredCoins(ctx, db.AddStoredCoinsParams{
+			StorageLocationID: location.ID,
+			CharacterID:       characterID,
+			Denomination:      string(denom),
+			Amount:            amount,
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	if withdraw {
+		return fmt.Sprintf("Took %s from %s", name, location.Name), nil
+	}
+	return fmt.Sprintf("Left %s at %s", name, location.Name), nil
+}
+
+// HandleStorageManifest lists everything kept at a storage location: each
+// stack with what it holds beneath it, who owns it and what it weighs, and
+// the coins left there
+func (s *Server) HandleStorageManifest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	locationID, err := strconv.ParseInt(r.URL.Query().Get("location"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid storage location ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	character, canEdit, err := getReadableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Character not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to fetch character", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	location, err := storageLocationFor(r.Context(), queries, characterID, locationID)
+	if err != nil {
+		if storage.IsRuleError(err) {
+			http.Error(w, "Storage location not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to fetch storage location", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := queries.ListStorageLocationItems(r.Context(), sql.NullInt64{Int64: location.ID, Valid: true})
+	if err != nil {
+		logger.Error("Failed to fetch stored items",
+			zap.Error(err),
+			zap.Int64("storage_location_id", location.ID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	items, weight := storageManifest(rows)
+
+	coinRows, err := queries.ListStorageLocationCoins(r.Context(), location.ID)
+	if err != nil {
+		logger.Error("Failed to fetch stored coins",
+			zap.Error(err),
+			zap.Int64("storage_location_id", location.ID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	coinage, _ := characterCoins(r.Context(), queries, characterID)
+	var coins []StorageManifestCoins
+	for i := 0; i < len(coinRows); {
+		purse := currency.Purse{}
+		owner := coinRows[i]
+		for ; i < len(coinRows) && coinRows[i].CharacterID == owner.CharacterID; i++ {
+			purse.Add(currency.Denomination(coinRows[i].Denomination), coinRows[i].Amount)
+		}
+		coins = append(coins, StorageManifestCoins{Owner: owner.CharacterName, Coins: coinage.Format(purse)})
+	}
+
+	data := struct {
+		IsAuthenticated bool
+		Username        string
+		Character       db.Character
+		CanEdit         bool
+		Location        db.StorageLocation
+		KindLabel       string
+		Items           []StorageManifestItem
+		WeightLabel     string
+		Coins           []StorageManifestCoins
+		FlashMessage    string
+		CurrentYear     int
+	}{
+		IsAuthenticated: true,
+		Username:        user.Username,
+		Character:       character,
+		CanEdit:         canEdit,
+		Location:        location,
+		KindLabel:       storage.KindLabel(location.Kind),
+		Items:           items,
+		WeightLabel:     formatPounds(weight),
+		Coins:           coins,
+		FlashMessage:    r.URL.Query().Get("message"),
+		CurrentYear:     time.Now().Year(),
+	}
+
+	RenderTemplate(w, "templates/characters/storage.html", "base.html", data)
+}
+
+// storageManifest orders stored stacks so each container is followed by
+// what it holds, and works out what everything there weighs
+func storageManifest(rows []db.ListStorageLocationItemsRow) ([]StorageManifestItem, float64) {
+	entries := make([]containers.Entry, 0, len(rows))
+	byID := make(map[int64]db.ListStorageLocationItemsRow, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = row
+		entries = append(entries, containers.Entry{
+			ID:          row.ID,
+			ContainerID: row.ContainerID.Int64,
+			Weight:      row.ItemWeight,
+			Quantity:    row.Quantity,
+			Bundle:      row.BundleSize,
+			Used:        row.MissilesUsed,
+			Container:   containerSpec(row.CapacityWeight, row.CapacityItems, row.WeightMultiplier, ""),
+		})
+	}
+	packed := containers.NewInventory(entries)
+
+	children := make(map[int64][]db.ListStorageLocationItemsRow)
+	var top []db.ListStorageLocationItemsRow
+	for _, row := range rows {
+		if _, inside := byID[row.ContainerID.Int64]; row.ContainerID.Valid && inside {
+			children[row.ContainerID.Int64] = append(children[row.ContainerID.Int64], row)
+		} else {
+			top = append(top, row)
+		}
+	}
+
+	var items []StorageManifestItem
+	var add func(row db.ListStorageLocationItemsRow, depth int)
+	add = func(row db.ListStorageLocationItemsRow, depth int) {
+		identity := magic.Identity{
+			Name:        row.ItemName,
+			Appearance:  row.Appearance.String,
+			Enhancement: row.EnhancementBonus,
+			Identified:  row.IsIdentified,
+		}
+		items = append(items, StorageManifestItem{
+			ID:          row.ID,
+			Name:        identity.Shown(),
+			Owner:       row.CharacterName,
+			OwnerID:     row.CharacterID,
+			Quantity:    row.Quantity,
+			Depth:       depth,
+			WeightLabel: formatPounds(packed.CarriedWeight(row.ID)),
+		})
+		for _, child := range children[row.ID] {
+			add(child, depth+1)
+		}
+	}
+
+	var weight float64
+	for _, row := range top {
+		weight += packed.CarriedWeight(row.ID)
+		add(row, 0)
+	}
+	return items, weight
+}
@@ -0,0 +1,737 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marbh56/mordezzan/internal/currency"
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/equipment"
+	"github.com/marbh56/mordezzan/internal/rules/transfer"
+	"go.uber.org/zap"
+)
+
+// TransferView is an offer of an item or coins between two characters that
+// is waiting on the receiving player
+type TransferView struct {
+	ID        int64     `json:"id"`
+	FromID    int64     `json:"from_id"`
+	FromName  string    `json:"from_name"`
+	ToID      int64     `json:"to_id"`
+	ToName    string    `json:"to_name"`
+	Item      string    `json:"item,omitempty"`
+	Quantity  int64     `json:"quantity,omitempty"`
+	Coins     string    `json:"coins,omitempty"`
+	Notes     string    `json:"notes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TransferTarget is a character in the same campaign who can be given things
+type TransferTarget struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Player string `json:"player"`
+}
+
+// loadTransferDetails attaches offers made to and by a character, and who
+// in the campaign they could give things to. It must run after the campaign
+// is known.
+func (s *Server) loadTransferDetails(ctx context.Context, queries *db.Queries, vm *CharacterViewModel) {
+	if vm.CampaignID != 0 {
+		members, err := queries.ListCampaignCharacters(ctx, vm.CampaignID)
+		if err != nil {
+			logger.Warn("Failed to fetch campaign characters",
+				zap.Error(err),
+				zap.Int64("campaign_id", vm.CampaignID))
+		}
+		vm.TransferTargets = nil
+		for _, member := range members {
+			if member.ID != vm.ID {
+				vm.TransferTargets = append(vm.TransferTargets, TransferTarget{
+					ID:     member.ID,
+					Name:   member.Name,
+					Player: member.PlayerUsername,
+				})
+			}
+		}
+	}
+
+	rows, err := queries.ListPendingTransfers(ctx, db.ListPendingTransfersParams{
+		FromCharacterID: vm.ID,
+		ToCharacterID:   vm.ID,
+	})
+	if err != nil {
+		logger.Warn("Failed to fetch transfers",
+			zap.Error(err),
+			zap.Int64("character_id", vm.ID))
+		return
+	}
+
+	coinage, _ := characterCoins(ctx, queries, vm.ID)
+	vm.IncomingTransfers = nil
+	vm.OutgoingTransfers = nil
+	for _, row := range rows {
+		coins, err := transferCoins(ctx, queries, row.ID)
+		if err != nil {
+			logger.Warn("Failed to fetch transfer coins",
+				zap.Error(err),
+				zap.Int64("transfer_id", row.ID))
+		}
+		view := TransferView{
+			ID:        row.ID,
+			FromID:    row.FromCharacterID,
+			FromName:  row.FromName,
+			ToID:      row.ToCharacterID,
+			ToName:    row.ToName,
+			Item:      row.ItemName.String,
+			Quantity:  row.Quantity,
+			Notes:     row.Notes.String,
+			CreatedAt: row.CreatedAt,
+		}
+		if row.InventoryID.Valid && !row.ItemName.Valid {
+			view.Item = "an item no longer held"
+		}
+		if coins.Count() > 0 {
+			view.Coins = coinage.Format(coins)
+		}
+		if row.ToCharacterID == vm.ID {
+			vm.IncomingTransfers = append(vm.IncomingTransfers, view)
+		} else {
+			vm.OutgoingTransfers = append(vm.OutgoingTransfers, view)
+		}
+	}
+}
+
+// transferCoins reads the coins offered in a transfer
+func transferCoins(ctx context.Context, queries *db.Queries, transferID int64) (currency.Purse, error) {
+	rows, err := queries.ListTransferCoins(ctx, transferID)
+	if err != nil {
+		return currency.Purse{}, err
+	}
+	coins := make(currency.Purse, len(rows))
+	for _, row := range rows {
+		coins.Add(currency.Denomination(row.Denomination), row.Amount)
+	}
+	return coins, nil
+}
+
+// characterCampaignID is the campaign a character is in, or 0 outside one
+func characterCampaignID(ctx context.Context, queries *db.Queries, characterID int64) (int64, error) {
+	campaign, err := queries.GetCharacterCampaign(ctx, characterID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return campaign.ID, nil
+}
+
+// HandleOfferTransfer offers an item, or part of a stack, and coins to
+// another character in the same campaign. Nothing moves until the
+// receiving player accepts.
+func (s *Server) HandleOfferTransfer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	toID, err := strconv.ParseInt(r.FormValue("to_character_id"), 10, 64)
+	if err != nil {
+		renderInventoryWithMessage(w, r, characterID, "Error: choose who to give to")
+		return
+	}
+
+	var itemID sql.NullInt64
+	if itemStr := r.FormValue("item_id"); itemStr != "" {
+		id, err := strconv.ParseInt(itemStr, 10, 64)
+		if err != nil {
+			logger.Error("Invalid item ID", zap.Error(err))
+			http.Error(w, "Invalid item ID", http.StatusBadRequest)
+			return
+		}
+		itemID = sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	var quantity int64
+	if quantityStr := r.FormValue("quantity"); quantityStr != "" {
+		quantity, err = strconv.ParseInt(quantityStr, 10, 64)
+		if err != nil || quantity < 1 {
+			renderInventoryWithMessage(w, r, characterID, "Error: "+transfer.ErrBadQuantity.Error())
+			return
+		}
+	}
+
+	queries := db.New(s.db)
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	// Coins come in one field per denomination in use
+	coinage, err := loadCoinage(r.Context(), queries, characterID)
+	if err != nil {
+		logger.Error("Failed to fetch coinage", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	coins := currency.Purse{}
+	for _, coin := range coinage.Coins() {
+		value := r.FormValue("coins_" + string(coin.Denomination))
+		if value == "" {
+			continue
+		}
+		amount, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || amount < 0 {
+			renderInventoryWithMessage(w, r, characterID, "Error: coin amounts must be whole numbers")
+			return
+		}
+		if amount > 0 {
+			coins.Add(coin.Denomination, amount)
+		}
+	}
+
+	offer := transferOffer{
+		ToID:     toID,
+		ItemID:   itemID,
+		Quantity: quantity,
+		Coins:    coins,
+		Notes:    strings.TrimSpace(r.FormValue("notes")),
+	}
+	message, err := s.offerTransfer(r.Context(), character, user.UserID, offer)
+	if transfer.IsRuleError(err) || equipment.IsRuleError(err) || errors.Is(err, errItemNotFound) {
+		logger.Warn("Transfer offer refused",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("to_character_id", toID))
+		renderInventoryWithMessage(w, r, characterID, "Error: "+err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to offer transfer",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("to_character_id", toID))
+		renderInventoryWithMessage(w, r, characterID, "Error making offer")
+		return
+	}
+
+	logger.Info("Transfer offered",
+		zap.Int64("character_id", characterID),
+		zap.Int64("to_character_id", toID),
+		zap.Any("item_id", itemID),
+		zap.Int64("quantity", quantity))
+
+	renderInventoryWithMessage(w, r, characterID, message)
+}
+
+// transferOffer is what one character offers another
+type transferOffer struct {
+	ToID     int64
+	ItemID   sql.NullInt64
+	Quantity int64 // 0 for the whole stack
+	Coins    currency.Purse
+	Notes    string
+}
+
+// offerTransfer records an offer after checking the giver has what they
+// are offering and both characters share a campaign
+func (s *Server) offerTransfer(ctx context.Context, from db.Character, userID int64, offer transferOffer) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	fromCampaign, err := characterCampaignID(ctx, qtx, from.ID)
+	if err != nil {
+		return "", err
+	}
+	toCampaign, err := characterCampaignID(ctx, qtx, offer.ToID)
+	if err != nil {
+		return "", err
+	}
+	if err := transfer.CheckParties(from.ID, offer.ToID, fromCampaign, toCampaign); err != nil {
+		return "", err
+	}
+
+	var offered []string
+	if offer.ItemID.Valid {
+		inv, err := loadContainerInventory(ctx, qtx, from.ID)
+		if err != nil {
+			return "", err
+		}
+		item, ok := inv.Entry(offer.ItemID.Int64)
+		if !ok {
+			return "", errItemNotFound
+		}
+		identity, err := inventoryItemIdentity(ctx, qtx, from.ID, item.ID)
+		if err != nil {
+			return "", err
+		}
+		if offer.Quantity == 0 {
+			offer.Quantity = item.Quantity
+		}
+		if err := transfer.CheckHeld(identity.Shown(), item.Quantity, offer.Quantity); err != nil {
+			return "", err
+		}
+		if offer.Quantity == item.Quantity {
+			if err := checkItemRelease(ctx, qtx, from.ID, item.ID, sql.NullInt64{}); err != nil {
+				return "", err
+			}
+		}
+		offered = append(offered, fmt.Sprintf("%d × %s", offer.Quantity, identity.Shown()))
+	}
+	if err := transfer.CheckOffer(offer.ItemID.Valid, offer.Quantity, offer.Coins.Count()); err != nil {
+		return "", err
+	}
+
+	coinage, err := loadCoinage(ctx, qtx, from.ID)
+	if err != nil {
+		return "", err
+	}
+	if offer.Coins.Count() > 0 {
+		purse, err := loadPurse(ctx, qtx, from.ID)
+		if err != nil {
+			return "", err
+		}
+		for denom, amount := range offer.Coins {
+			if err := transfer.CheckHeld(string(denom), purse.Of(denom), amount); err != nil {
+				return "", err
+			}
+		}
+		offered = append(offered, coinage.Format(offer.Coins))
+	}
+
+	created, err := qtx.CreateTransfer(ctx, db.CreateTransferParams{
+		CampaignID:      fromCampaign,
+		FromCharacterID: from.ID,
+		ToCharacterID:   offer.ToID,
+		InventoryID:     offer.ItemID,
+		Quantity:        offer.Quantity,
+		Notes:           sql.NullString{String: offer.Notes, Valid: offer.Notes != ""},
+		OfferedBy:       userID,
+	})
+	if err != nil {
+		return "", err
+	}
+	for denom, amount := range offer.Coins {
+		if err := qtx.AddTransferCoins(ctx, db.AddTransferCoinsParams{
+			TransferID:   created.ID,
+			Denomination: string(denom),
+			Amount:       amount,
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Offered %s; waiting for the other player to accept", strings.Join(offered, " and ")), nil
+}
+
+// HandleAcceptTransfer hands over everything in an offer to the receiving
+// character, whose player must be the one accepting
+func (s *Server) HandleAcceptTransfer(w http.ResponseWriter, r *http.Request) {
+	s.handleTransferAnswer(w, r, true)
+}
+
+// HandleDeclineTransfer turns down an offer, leaving everything with the
+// character who offered it
+func (s *Server) HandleDeclineTransfer(w http.ResponseWriter, r *http.Request) {
+	s.handleTransferAnswer(w, r, false)
+}
+
+// handleTransferAnswer reads an accept or decline form and applies it
+func (s *Server) handleTransferAnswer(w http.ResponseWriter, r *http.Request, accept bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	transferID, err := strconv.ParseInt(r.FormValue("transfer_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid transfer ID", zap.Error(err))
+		http.Error(w, "Invalid transfer ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	pending, err := queries.GetTransfer(r.Context(), transferID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Transfer not found", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to fetch transfer", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	var message string
+	if accept {
+		message, err = s.acceptTransfer(r.Context(), pending, user.UserID)
+	} else {
+		message, err = s.declineTransfer(r.Context(), pending, user.UserID)
+	}
+	if transfer.IsRuleError(err) || equipment.IsRuleError(err) {
+		logger.Warn("Transfer answer refused",
+			zap.Error(err),
+			zap.Int64("transfer_id", transferID),
+			zap.Bool("accept", accept))
+		renderInventoryWithMessage(w, r, pending.ToCharacterID, "Error: "+err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to answer transfer",
+			zap.Error(err),
+			zap.Int64("transfer_id", transferID),
+			zap.Bool("accept", accept))
+		renderInventoryWithMessage(w, r, pending.ToCharacterID, "Error answering offer")
+		return
+	}
+
+	logger.Info("Transfer answered",
+		zap.Int64("transfer_id", transferID),
+		zap.Int64("from_character_id", pending.FromCharacterID),
+		zap.Int64("to_character_id", pending.ToCharacterID),
+		zap.Bool("accept", accept))
+
+	renderInventoryWithMessage(w, r, pending.ToCharacterID, message)
+}
+
+// receivingCharacter checks the user plays the character an offer is made
+// to
+func receivingCharacter(ctx context.Context, queries *db.Queries, pending db.CharacterTransfer, userID int64) (db.Character, error) {
+	character, err := queries.GetCharacter(ctx, db.GetCharacterParams{
+		ID:     pending.ToCharacterID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.Character{}, transfer.ErrNotRecipient
+	}
+	return character, err
+}
+
+// acceptTransfer moves the offered item, with anything packed in it, and
+// the coins from one character to the other in a single transaction. The
+// giver must still have everything offered.
+func (s *Server) acceptTransfer(ctx context.Context, pending db.CharacterTransfer, userID int64) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	qtx := db.New(s.db).WithTx(tx)
+
+	// Read again inside the transaction so an offer is only settled once
+	pending, err = qtx.GetTransfer(ctx, pending.ID)
+	if err != nil {
+		return "", err
+	}
+	if err := transfer.CheckPending(pending.Status); err != nil {
+		return "", err
+	}
+	to, err := receivingCharacter(ctx, qtx, pending, userID)
+	if err != nil {
+		return "", err
+	}
+
+	fromCampaign, err := characterCampaignID(ctx, qtx, pending.FromCharacterID)
+	if err != nil {
+		return "", err
+	}
+	toCampaign, err := characterCampaignID(ctx, qtx, to.ID)
+	if err != nil {
+		return "", err
+	}
+	if err := transfer.CheckParties(pending.FromCharacterID, to.ID, fromCampaign, toCampaign); err != nil {
+		return "", err
+	}
+	fromName := fmt.Sprintf("character %d", pending.FromCharacterID)
+	members, err := qtx.ListCampaignCharacters(ctx, fromCampaign)
+	if err != nil {
+		return "", err
+	}
+	for _, member := range members {
+		if member.ID == pending.FromCharacterID {
+			fromName = member.Name
+		}
+	}
+
+	var received []string
+	if pending.InventoryID.Valid {
+		name, err := giveInventoryItem(ctx, qtx, pending.FromCharacterID, to.ID, pending.InventoryID.Int64, pending.Quantity)
+		if err != nil {
+			return "", err
+		}
+		received = append(received, fmt.Sprintf("%d × %s", pending.Quantity, name))
+	}
+
+	coins, err := transferCoins(ctx, qtx, pending.ID)
+	if err != nil {
+		return "", err
+	}
+	if coins.Count() > 0 {
+		coinage, err := loadCoinage(ctx, qtx, pending.FromCharacterID)
+		if err != nil {
+			return "", err
+		}
+		purse, err := loadPurse(ctx, qtx, pending.FromCharacterID)
+		if err != nil {
+			return "", err
+		}
+		paid := currency.Purse{}
+		for denom, amount := range coins {
+			if err := transfer.CheckHeld(string(denom), purse.Of(denom), amount); err != nil {
+				return "", fmt.Errorf("%s has %w", fromName, err)
+			}
+			paid.Add(denom, -amount)
+		}
+		if err := recordCurrency(ctx, qtx, CurrencyEntry{
+			CharacterID: pending.FromCharacterID,
+			Reason:      "transfer",
+			Coins:       paid,
+			Notes:       "Given to " + to.Name,
+		}); err != nil {
+			return "", err
+		}
+		if err := recordCurrency(ctx, qtx, CurrencyEntry{
+			CharacterID: to.ID,
+			Reason:      "transfer",
+			Coins:       coins,
+			Notes:       "Received from " + fromName,
+		}); err != nil {
+			return "", err
+		}
+		received = append(received, coinage.Format(coins))
+	}
+
+	if err := qtx.ResolveTransfer(ctx, db.ResolveTransferParams{
+		Status:     transfer.StatusAccepted,
+		ResolvedBy: sql.NullInt64{Int64: userID, Valid: true},
+		ID:         pending.ID,
+	}); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Received %s from %s", strings.Join(received, " and "), fromName), nil
+}
+
+// giveInventoryItem hands quantity of a stack to another character,
+// splitting it when only part is given. A container takes its contents
+// along. The item arrives carried, out of any slot, container or storage.
+func giveInventoryItem(ctx context.Context, qtx *db.Queries, fromID, toID, itemID, quantity int64) (string, error) {
+	inv, err := loadContainerInventory(ctx, qtx, fromID)
+	if err != nil {
+		return "", err
+	}
+	item, ok := inv.Entry(itemID)
+	if !ok {
+		return "", fmt.Errorf("%w: the item offered", transfer.ErrNotHeld)
+	}
+	identity, err := inventoryItemIdentity(ctx, qtx, fromID, itemID)
+	if err != nil {
+		return "", err
+	}
+	if err := transfer.CheckHeld(identity.Shown(), item.Quantity, quantity); err != nil {
+		return "", err
+	}
+
+	given := itemID
+	if quantity < item.Quantity {
+		newID, err := qtx.SplitStack(ctx, db.SplitStackParams{
+			Quantity:    quantity,
+			ContainerID: sql.NullInt64{},
+			ID:          itemID,
+			CharacterID: fromID,
+		})
+		if err != nil {
+			return "", err
+		}
+		if err := qtx.CopyItemProperties(ctx, db.CopyItemPropertiesParams{
+			InventoryID:   newID,
+			InventoryID_2: itemID,
+		}); err != nil {
+			return "", err
+		}
+		if err := qtx.ReduceStackQuantity(ctx, db.ReduceStackQuantityParams{
+			Quantity:    quantity,
+			ID:          itemID,
+			CharacterID: fromID,
+		}); err != nil {
+			return "", err
+		}
+		given = newID
+	} else if err := checkItemRelease(ctx, qtx, fromID, itemID, sql.NullInt64{}); err != nil {
+		return "", err
+	}
+
+	if err := qtx.GiveInventoryItem(ctx, db.GiveInventoryItemParams{
+		ID:            given,
+		CharacterID:   fromID,
+		CharacterID_2: toID,
+	}); err != nil {
+		return "", err
+	}
+	if err := qtx.StoreInventoryItem(ctx, db.StoreInventoryItemParams{
+		StorageLocationID: sql.NullInt64{},
+		ID:                given,
+		CharacterID:       toID,
+	}); err != nil {
+		return "", err
+	}
+	return identity.Shown(), nil
+}
+
+// declineTransfer turns down an offer for the receiving player
+func (s *Server) declineTransfer(ctx context.Context, pending db.CharacterTransfer, userID int64) (string, error) {
+	if err := transfer.CheckPending(pending.Status); err != nil {
+		return "", err
+	}
+	queries := db.New(s.db)
+	if _, err := receivingCharacter(ctx, queries, pending, userID); err != nil {
+		return "", err
+	}
+	if err := queries.ResolveTransfer(ctx, db.ResolveTransferParams{
+		Status:     transfer.StatusDeclined,
+		ResolvedBy: sql.NullInt64{Int64: userID, Valid: true},
+		ID:         pending.ID,
+	}); err != nil {
+		return "", err
+	}
+	return "Offer declined", nil
+}
+
+// HandleCancelTransfer withdraws an offer the character made that hasn't
+// been answered yet
+func (s *Server) HandleCancelTransfer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.FormValue("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		return
+	}
+
+	transferID, err := strconv.ParseInt(r.FormValue("transfer_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid transfer ID", zap.Error(err))
+		http.Error(w, "Invalid transfer ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	if _, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID); err != nil {
+		logger.Error("Character not found or not writable",
+			zap.Error(err),
+			zap.Int64("character_id", characterID),
+			zap.Int64("user_id", user.UserID))
+		http.Error(w, "Character not found", http.StatusNotFound)
+		return
+	}
+
+	pending, err := queries.GetTransfer(r.Context(), transferID)
+	if err == nil && pending.FromCharacterID != characterID {
+		err = transfer.ErrNotSender
+	}
+	if err == nil {
+		err = transfer.CheckPending(pending.Status)
+	}
+	if err == nil {
+		err = queries.ResolveTransfer(r.Context(), db.ResolveTransferParams{
+			Status:     transfer.StatusCancelled,
+			ResolvedBy: sql.NullInt64{Int64: user.UserID, Valid: true},
+			ID:         transferID,
+		})
+	}
+	if transfer.IsRuleError(err) || errors.Is(err, sql.ErrNoRows) {
+		renderInventoryWithMessage(w, r, characterID, "Error: "+transferErrorMessage(err))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to cancel transfer",
+			zap.Error(err),
+			zap.Int64("transfer_id", transferID))
+		renderInventoryWithMessage(w, r, characterID, "Error withdrawing offer")
+		return
+	}
+
+	logger.Info("Transfer cancelled",
+		zap.Int64("character_id", characterID),
+		zap.Int64("transfer_id", transferID))
+
+	renderInventoryWithMessage(w, r, characterID, "Offer withdrawn")
+}
+
+// transferErrorMessage explains a refused transfer change to the player
+func transferErrorMessage(err error) string {
+	if errors.Is(err, sql.ErrNoRows) {
+		return "no such offer"
+	}
+	return err.Error()
+}
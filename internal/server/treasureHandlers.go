@@ -0,0 +1,705 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marbh56/mordezzan/internal/currency"
+	"github.com/marbh56/mordezzan/internal/db"
+	"github.com/marbh56/mordezzan/internal/logger"
+	"github.com/marbh56/mordezzan/internal/rules/stash"
+	"go.uber.org/zap"
+)
+
+// Share weights are stored in hundredths so half and custom shares stay
+// whole numbers
+const (
+	FullShareWeight = 100
+	HalfShareWeight = 50
+)
+
+// treasureValuableRows is how many gem and item lines the split form offers
+const treasureValuableRows = 5
+
+// TreasureValuable is a gem or item in a hoard, kept whole by one character.
+// Valuables picked from the treasure catalog or taken from the party stash
+// go into the holder's inventory.
+type TreasureValuable struct {
+	ItemID   int64
+	StashID  int64
+	Name     string
+	ValueGP  int64
+	HolderID int64
+}
+
+// TreasureShare is what one character receives from a split
+type TreasureShare struct {
+	CharacterID int64
+	Name        string
+	Player      string
+	Weight      int64
+	Valuables   []TreasureValuable
+	Coins       currency.Purse
+	ValueCP     int64 // Coins plus valuables kept
+	XP          int64
+	BonusXP     int64
+
+	coinage *currency.Coinage
+}
+
+// TreasurePlan is a worked-out split, shown as a preview before posting
+type TreasurePlan struct {
+	Description    string
+	Hoard          currency.Purse
+	Valuables      []TreasureValuable
+	TotalValueCP   int64
+	Shares         []TreasureShare
+	HenchmanShares int64
+	HenchmanCoins  currency.Purse
+	Remainder      currency.Purse
+	Conversions    []currency.Conversion
+	ApplyBonus     bool
+	FromStash      bool // Hoard and stash valuables come out of the party stash
+
+	coinage    *currency.Coinage
+	characters map[int64]db.Character
+}
+
+// TreasureSplitEntry is a past split on the campaign page
+type TreasureSplitEntry struct {
+	db.TreasureSplit
+}
+
+// ValueLabel formats the worth of the hoard in gold pieces
+func (e TreasureSplitEntry) ValueLabel() string {
+	return formatGoldValue(e.TotalValueCp)
+}
+
+// CoinsLabel formats the coins in a share
+func (s TreasureShare) CoinsLabel() string {
+	return s.coinage.Format(s.Coins)
+}
+
+// ShareLabel describes the size of a share
+func (s TreasureShare) ShareLabel() string {
+	switch s.Weight {
+	case FullShareWeight:
+		return "Full"
+	case HalfShareWeight:
+		return "Half"
+	}
+	return strconv.FormatFloat(float64(s.Weight)/FullShareWeight, 'f', -1, 64) + " shares"
+}
+
+// ValueLabel formats the worth of a share in gold pieces
+func (s TreasureShare) ValueLabel() string {
+	return formatGoldValue(s.ValueCP)
+}
+
+// TotalLabel formats the worth of the whole hoard in gold pieces
+func (p TreasurePlan) TotalLabel() string {
+	return formatGoldValue(p.TotalValueCP)
+}
+
+// HenchmanLabel formats the coins set aside for henchmen
+func (p TreasurePlan) HenchmanLabel() string {
+	return p.coinage.Format(p.HenchmanCoins)
+}
+
+// RemainderLabel formats the coins left over after the split
+func (p TreasurePlan) RemainderLabel() string {
+	return p.coinage.Format(p.Remainder)
+}
+
+// HandleTreasureSplit shows the treasure split form. A POST re-shows it
+// filled in, so the referee can go back and change a preview.
+func (s *Server) HandleTreasureSplit(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	campaignID, err := strconv.ParseInt(r.Form.Get("campaign_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid campaign ID",
+			zap.Error(err),
+			zap.String("raw_id", r.Form.Get("campaign_id")))
+		http.Error(w, "Invalid campaign ID", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.db)
+	campaign, err := queries.GetCampaign(r.Context(), campaignID)
+	if err != nil || campaign.RefereeID != user.UserID {
+		logger.Error("Campaign not found or user is not the referee",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaignID))
+		http.Error(w, "Campaign not found", http.StatusNotFound)
+		return
+	}
+
+	// Start from whatever the party has in its stash
+	if r.Method == http.MethodGet && r.Form.Get("from_stash") == "1" {
+		if err := fillSplitFromStash(r.Context(), queries, campaign.ID, r.Form); err != nil {
+			logger.Error("Failed to fetch party stash",
+				zap.Error(err),
+				zap.Int64("campaign_id", campaign.ID))
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	s.renderTreasureSplit(w, r, user, campaign, nil, "")
+}
+
+// HandleTreasurePreview works out a split without changing anything
+func (s *Server) HandleTreasurePreview(w http.ResponseWriter, r *http.Request) {
+	user, campaign, ok := s.refereeCampaignFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	queries := db.New(s.db)
+	plan, err := s.planTreasureSplit(r, queries, campaign.ID)
+	if err != nil {
+		s.renderTreasureSplit(w, r, user, campaign, nil, "Error: "+err.Error())
+		return
+	}
+
+	s.renderTreasureSplit(w, r, user, campaign, &plan, "")
+}
+
+// HandleTreasureApply posts a split to every character's purse and XP
+// ledger in one transaction. Handing out treasure is a referee action, so
+// it does not depend on the campaign allowing referee edits.
+func (s *Server) HandleTreasureApply(w http.ResponseWriter, r *http.Request) {
+	user, campaign, ok := s.refereeCampaignFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	queries := db.New(s.db)
+	plan, err := s.planTreasureSplit(r, queries, campaign.ID)
+	if err != nil {
+		s.renderTreasureSplit(w, r, user, campaign, nil, "Error: "+err.Error())
+		return
+	}
+
+	tx, err := s.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	// Henchmen's coins are kept on the split for the referee to hand out
+	var henchmanCoins string
+	if plan.HenchmanShares > 0 {
+		henchmanCoins = plan.HenchmanLabel()
+	}
+
+	qtx := queries.WithTx(tx)
+	split, err := qtx.CreateTreasureSplit(r.Context(), db.CreateTreasureSplitParams{
+		CampaignID:    campaign.ID,
+		RefereeID:     user.UserID,
+		Description:   plan.Description,
+		TotalValueCp:  plan.TotalValueCP,
+		Remainder:     plan.RemainderLabel(),
+		HenchmanCoins: henchmanCoins,
+	})
+	if err != nil {
+		logger.Error("Failed to record treasure split", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	sessionDate := sessionToday()
+	for _, share := range plan.Shares {
+		var awardID sql.NullInt64
+		if share.XP > 0 {
+			award, err := qtx.CreateXPAward(r.Context(), db.CreateXPAwardParams{
+				CharacterID: share.CharacterID,
+				Amount:      share.XP,
+				Source:      "treasure",
+				SessionDate: sessionDate,
+				AwardedBy:   user.UserID,
+				Notes:       sql.NullString{String: "Treasure split: " + plan.Description, Valid: true},
+			})
+			if err != nil {
+				logger.Error("Failed to record treasure XP",
+					zap.Error(err),
+					zap.Int64("character_id", share.CharacterID))
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+			awardID = sql.NullInt64{Int64: award.ID, Valid: true}
+
+			if _, err := syncExperienceFromLedger(r.Context(), qtx, plan.characters[share.CharacterID]); err != nil {
+				logger.Error("Failed to update character XP",
+					zap.Error(err),
+					zap.Int64("character_id", share.CharacterID))
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		if err := recordCurrency(r.Context(), qtx, CurrencyEntry{
+			CharacterID: share.CharacterID,
+			Reason:      "split",
+			Coins:       share.Coins,
+			XPAwardID:   awardID,
+			ActorID:     user.UserID,
+			Notes:       "Treasure split: " + plan.Description,
+		}); err != nil {
+			logger.Error("Failed to add treasure coins",
+				zap.Error(err),
+				zap.Int64("character_id", share.CharacterID))
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		for _, valuable := range share.Valuables {
+			if valuable.ItemID == 0 {
+				continue
+			}
+			if err := addTreasureItem(r.Context(), qtx, share.CharacterID, valuable); err != nil {
+				logger.Error("Failed to add treasure item",
+					zap.Error(err),
+					zap.Int64("character_id", share.CharacterID),
+					zap.Int64("item_id", valuable.ItemID))
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		if err := qtx.CreateTreasureSplitShare(r.Context(), db.CreateTreasureSplitShareParams{
+			SplitID:     split.ID,
+			CharacterID: share.CharacterID,
+			Weight:      share.Weight,
+			ValueCp:     share.ValueCP,
+			Coins:       share.CoinsLabel(),
+			XpAwardID:   awardID,
+		}); err != nil {
+			logger.Error("Failed to record treasure share",
+				zap.Error(err),
+				zap.Int64("character_id", share.CharacterID))
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if plan.FromStash {
+		err := takeSplitFromStash(r.Context(), qtx, campaign.ID, user.UserID, plan)
+		if stash.IsRuleError(err) {
+			s.renderTreasureSplit(w, r, user, campaign, nil, "Error: "+err.Error())
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to take treasure from the party stash",
+				zap.Error(err),
+				zap.Int64("campaign_id", campaign.ID))
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit treasure split", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("Treasure split posted",
+		zap.Int64("campaign_id", campaign.ID),
+		zap.Int64("split_id", split.ID),
+		zap.Int64("total_value_cp", plan.TotalValueCP),
+		zap.Int("shares", len(plan.Shares)),
+		zap.String("henchman_coins", henchmanCoins))
+
+	http.Redirect(w, r, fmt.Sprintf("/campaigns/detail?id=%d&message=Treasure divided among %d characters", campaign.ID, len(plan.Shares)), http.StatusSeeOther)
+}
+
+// planTreasureSplit reads the split form and works out every share
+func (s *Server) planTreasureSplit(r *http.Request, queries *db.Queries, campaignID int64) (TreasurePlan, error) {
+	plan := TreasurePlan{
+		Description: strings.TrimSpace(r.Form.Get("description")),
+		ApplyBonus:  r.Form.Get("apply_bonus") == "1",
+		FromStash:   r.Form.Get("from_stash") == "1",
+	}
+	if plan.Description == "" {
+		return plan, fmt.Errorf("describe the hoard")
+	}
+
+	coinage, err := campaignCoinage(r.Context(), queries, sql.NullInt64{Int64: campaignID, Valid: true})
+	if err != nil {
+		return plan, fmt.Errorf("could not load the campaign's coins")
+	}
+	plan.coinage = coinage
+	plan.Hoard = currency.Purse{}
+	for _, coin := range coinage.Coins() {
+		amount, err := parseTreasureAmount(r.Form.Get(string(coin.Denomination)))
+		if err != nil {
+			return plan, fmt.Errorf("invalid number of %s", strings.ToLower(coin.Name))
+		}
+		plan.Hoard.Add(coin.Denomination, amount)
+	}
+
+	// A split of the party stash can't hand out more than is in it
+	var stashItems map[int64]StashItem
+	if plan.FromStash {
+		held, err := stashCoins(r.Context(), queries, campaignID)
+		if err != nil {
+			return plan, fmt.Errorf("could not load the party stash")
+		}
+		for denom, amount := range plan.Hoard {
+			if err := stash.CheckTake(string(denom), held.Of(denom), amount); err != nil {
+				return plan, err
+			}
+		}
+		rows, err := queries.ListStashItems(r.Context(), campaignID)
+		if err != nil {
+			return plan, fmt.Errorf("could not load the party stash")
+		}
+		stashItems = make(map[int64]StashItem, len(rows))
+		for _, row := range rows {
+			stashItems[row.ID] = StashItem{ListStashItemsRow: row}
+		}
+	}
+
+	henchmen, err := parseTreasureAmount(r.Form.Get("henchman_shares"))
+	if err != nil {
+		return plan, fmt.Errorf("invalid number of henchman shares")
+	}
+	plan.HenchmanShares = henchmen
+
+	party, err := queries.ListCampaignCharacters(r.Context(), campaignID)
+	if err != nil {
+		return plan, fmt.Errorf("could not load the party")
+	}
+
+	// Work out each character's weight
+	plan.characters = make(map[int64]db.Character, len(party))
+	for _, row := range party {
+		weight, err := parseShareWeight(r.Form.Get(fmt.Sprintf("share_%d", row.ID)), r.Form.Get(fmt.Sprintf("weight_%d", row.ID)))
+		if err != nil {
+			return plan, fmt.Errorf("invalid share for %s", row.Name)
+		}
+		if weight == 0 {
+			continue
+		}
+		plan.characters[row.ID] = campaignCharacter(row)
+		plan.Shares = append(plan.Shares, TreasureShare{
+			CharacterID: row.ID,
+			Name:        row.Name,
+			Player:      row.PlayerUsername,
+			Weight:      weight,
+			coinage:     coinage,
+		})
+	}
+	if len(plan.Shares) == 0 {
+		return plan, fmt.Errorf("give at least one character a share")
+	}
+
+	catalog, err := queries.ListTreasureItems(r.Context())
+	if err != nil {
+		return plan, fmt.Errorf("could not load the treasure catalog")
+	}
+	treasureItems := make(map[int64]db.ListTreasureItemsRow, len(catalog))
+	for _, item := range catalog {
+		treasureItems[item.ID] = item
+	}
+
+	// Gems and items stay whole and count against their holder's share.
+	// A catalog or stash item fills in its own name when left blank, and a
+	// stash item its worth when the party knows it. Treasure nobody has
+	// appraised needs a value from the referee, which becomes its keeper's
+	// appraisal.
+	names := r.Form["valuable_name"]
+	values := r.Form["valuable_value"]
+	holders := r.Form["valuable_holder"]
+	items := r.Form["valuable_item"]
+	stashIDs := r.Form["valuable_stash"]
+	stashUsed := make(map[int64]int64)
+	held := make([]int64, len(plan.Shares))
+	var valuablesCP int64
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		var item db.ListTreasureItemsRow
+		var stashID int64
+		var valueKnown bool
+		if i < len(stashIDs) && stashIDs[i] != "" {
+			stashID, _ = strconv.ParseInt(stashIDs[i], 10, 64)
+			stashItem, ok := stashItems[stashID]
+			// Clearing the name of a stash item leaves it in the stash
+			if name == "" {
+				continue
+			}
+			if !ok {
+				return plan, fmt.Errorf("%w: %s", stash.ErrNotEnough, name)
+			}
+			stashUsed[stashID]++
+			if err := stash.CheckTake(stashItem.ItemName, stashItem.Quantity, stashUsed[stashID]); err != nil {
+				return plan, err
+			}
+			item = db.ListTreasureItemsRow{ID: stashItem.ItemID, Name: stashItem.ItemName}
+			item.Value, valueKnown = stashItem.ValueGP()
+		} else if i < len(items) && items[i] != "" {
+			itemID, err := strconv.ParseInt(items[i], 10, 64)
+			if err != nil || treasureItems[itemID].ID == 0 {
+				return plan, fmt.Errorf("unknown treasure item")
+			}
+			item = treasureItems[itemID]
+			if name == "" {
+				name = item.Name
+			}
+		}
+		if name == "" {
+			continue
+		}
+		if i >= len(values) || i >= len(holders) {
+			return plan, fmt.Errorf("incomplete entry for %s", name)
+		}
+		valueGP, err := parseTreasureAmount(values[i])
+		if err != nil {
+			return plan, fmt.Errorf("invalid value for %s", name)
+		}
+		if item.ID != 0 && strings.TrimSpace(values[i]) == "" {
+			if !valueKnown {
+				return plan, fmt.Errorf("give %s a value, as it hasn't been appraised", name)
+			}
+			valueGP = int64(math.Round(item.Value))
+		}
+		holderID, _ := strconv.ParseInt(holders[i], 10, 64)
+		holder := -1
+		for j, share := range plan.Shares {
+			if share.CharacterID == holderID {
+				holder = j
+			}
+		}
+		if holder < 0 {
+			return plan, fmt.Errorf("choose a character with a share to keep %s", name)
+		}
+
+		valuable := TreasureValuable{ItemID: item.ID, StashID: stashID, Name: name, ValueGP: valueGP, HolderID: holderID}
+		valueCP, _ := coinage.Convert(valueGP, currency.GoldPieces, currency.CopperPieces)
+		plan.Valuables = append(plan.Valuables, valuable)
+		plan.Shares[holder].Valuables = append(plan.Shares[holder].Valuables, valuable)
+		held[holder] += valueCP
+		valuablesCP += valueCP
+	}
+
+	coinValue := coinage.Value(plan.Hoard)
+	plan.TotalValueCP = coinValue + valuablesCP
+
+	// Henchmen take half shares of the coins, handed out by the referee
+	weights := make([]int64, 0, len(plan.Shares)+1)
+	for _, share := range plan.Shares {
+		weights = append(weights, share.Weight)
+	}
+	weights = append(weights, plan.HenchmanShares*HalfShareWeight)
+
+	// Anyone whose valuables already exceed their share takes no coins,
+	// and the rest of the party splits the coins between them
+	active := make([]bool, len(plan.Shares))
+	for i := range active {
+		active[i] = true
+	}
+	var entitlements []int64
+	for {
+		pool := coinValue
+		activeWeights := make([]int64, len(weights))
+		for i := range plan.Shares {
+			if active[i] {
+				pool += held[i]
+				activeWeights[i] = weights[i]
+			}
+		}
+		activeWeights[len(weights)-1] = weights[len(weights)-1]
+
+		entitlements = currency.Apportion(pool, activeWeights)
+		changed := false
+		for i := range plan.Shares {
+			if active[i] && held[i] > entitlements[i] {
+				active[i] = false
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	for i := range plan.Shares {
+		if active[i] {
+			entitlements[i] -= held[i]
+		} else {
+			entitlements[i] = 0
+		}
+	}
+
+	result := coinage.Split(plan.Hoard, entitlements)
+	plan.Remainder = result.Remainder
+	plan.Conversions = result.Conversions
+	plan.HenchmanCoins = result.Shares[len(plan.Shares)]
+
+	for i := range plan.Shares {
+		share := &plan.Shares[i]
+		share.Coins = result.Shares[i]
+		share.ValueCP = coinage.Value(share.Coins) + held[i]
+
+		// Treasure is worth 1 XP per gold piece
+		share.XP, _ = coinage.Convert(share.ValueCP, currency.CopperPieces, currency.GoldPieces)
+		if plan.ApplyBonus && share.XP > 0 {
+			if bonus := calculateXPBonus(plan.characters[share.CharacterID].Class, plan.characters[share.CharacterID]); bonus > 0 {
+				share.BonusXP = share.XP * bonus / 100
+				share.XP += share.BonusXP
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// renderTreasureSplit shows the split form, with a preview when plan is set
+func (s *Server) renderTreasureSplit(w http.ResponseWriter, r *http.Request, user *db.GetSessionRow, campaign db.Campaign, plan *TreasurePlan, message string) {
+	queries := db.New(s.db)
+	party, err := queries.ListCampaignCharacters(r.Context(), campaign.ID)
+	if err != nil {
+		logger.Error("Failed to fetch party for treasure split",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaign.ID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	// Keep whatever the referee already entered in the valuable rows
+	valuables := make([]TreasureValuable, max(treasureValuableRows, len(r.Form["valuable_name"])))
+	for i := range valuables {
+		if i < len(r.Form["valuable_name"]) {
+			valuables[i].Name = r.Form["valuable_name"][i]
+		}
+		if i < len(r.Form["valuable_value"]) {
+			valuables[i].ValueGP, _ = strconv.ParseInt(r.Form["valuable_value"][i], 10, 64)
+		}
+		if i < len(r.Form["valuable_holder"]) {
+			valuables[i].HolderID, _ = strconv.ParseInt(r.Form["valuable_holder"][i], 10, 64)
+		}
+		if i < len(r.Form["valuable_item"]) {
+			valuables[i].ItemID, _ = strconv.ParseInt(r.Form["valuable_item"][i], 10, 64)
+		}
+		if i < len(r.Form["valuable_stash"]) {
+			valuables[i].StashID, _ = strconv.ParseInt(r.Form["valuable_stash"][i], 10, 64)
+		}
+	}
+
+	treasureItems, err := queries.ListTreasureItems(r.Context())
+	if err != nil {
+		logger.Error("Failed to fetch treasure catalog", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	coinage, err := campaignCoinage(r.Context(), queries, sql.NullInt64{Int64: campaign.ID, Valid: true})
+	if err != nil {
+		logger.Error("Failed to fetch coinage for treasure split",
+			zap.Error(err),
+			zap.Int64("campaign_id", campaign.ID))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	data := struct {
+		IsAuthenticated bool
+		Username        string
+		Campaign        db.Campaign
+		Coins           []currency.Coin
+		Party           []db.ListCampaignCharactersRow
+		Fields          url.Values
+		ValuableRows    []TreasureValuable
+		TreasureItems   []db.ListTreasureItemsRow
+		Plan            *TreasurePlan
+		FlashMessage    string
+		CurrentYear     int
+	}{
+		IsAuthenticated: true,
+		Username:        user.Username,
+		Campaign:        campaign,
+		Coins:           coinage.Coins(),
+		Party:           party,
+		Fields:          r.Form,
+		ValuableRows:    valuables,
+		TreasureItems:   treasureItems,
+		Plan:            plan,
+		FlashMessage:    message,
+		CurrentYear:     time.Now().Year(),
+	}
+
+	RenderTemplate(w, "templates/campaigns/treasure.html", "base.html", data)
+}
+
+// addTreasureItem puts a valuable from the split into its holder's
+// inventory, appraised at the worth the split gave it
+func addTreasureItem(ctx context.Context, qtx *db.Queries, characterID int64, valuable TreasureValuable) error {
+	inventoryID, err := insertInventoryItem(ctx, qtx, db.AddItemToInventoryParams{
+		CharacterID: characterID,
+		ItemID:      valuable.ItemID,
+		Quantity:    1,
+	}, nil, nil, true)
+	if err != nil {
+		return err
+	}
+	return qtx.SetItemAppraisal(ctx, db.SetItemAppraisalParams{
+		AppraisedValue: sql.NullFloat64{Float64: float64(valuable.ValueGP), Valid: true},
+		ID:             inventoryID,
+		CharacterID:    characterID,
+	})
+}
+
+// parseShareWeight turns a share choice into a weight in hundredths
+func parseShareWeight(share, custom string) (int64, error) {
+	switch share {
+	case "", "full":
+		return FullShareWeight, nil
+	case "half":
+		return HalfShareWeight, nil
+	case "none":
+		return 0, nil
+	case "custom":
+		weight, err := strconv.ParseFloat(custom, 64)
+		if err != nil || weight < 0 {
+			return 0, fmt.Errorf("invalid custom share %q", custom)
+		}
+		return int64(math.Round(weight * FullShareWeight)), nil
+	}
+	return 0, fmt.Errorf("unknown share %q", share)
+}
+
+func parseTreasureAmount(value string) (int64, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+	amount, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+// formatGoldValue shows a copper amount as gold pieces
+func formatGoldValue(cp int64) string {
+	return fmt.Sprintf("%.2f gp", float64(cp)/100)
+}
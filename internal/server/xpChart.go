@@ -0,0 +1,154 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	charRules "github.com/marbh56/mordezzan/internal/rules/character"
+)
+
+// XPHistoryEntry is a single XP ledger row as shown on the character sheet
+type XPHistoryEntry struct {
+	ID           int64     `json:"id"`
+	Amount       int64     `json:"amount"`
+	RunningTotal int64     `json:"running_total"`
+	Source       string    `json:"source"`
+	SessionDate  time.Time `json:"session_date"`
+	AwardedBy    string    `json:"awarded_by"`
+	Notes        string    `json:"notes"`
+	IsReversal   bool      `json:"is_reversal"`
+	IsReversed   bool      `json:"is_reversed"`
+	IsDrain      bool      `json:"is_drain"`
+}
+
+// XPChart holds precomputed SVG geometry for the XP history chart
+type XPChart struct {
+	Width      int                `json:"width"`
+	Height     int                `json:"height"`
+	Points     string             `json:"points"`
+	Markers    []XPChartPoint     `json:"markers"`
+	Thresholds []XPChartThreshold `json:"thresholds"`
+	StartLabel string             `json:"start_label"`
+	EndLabel   string             `json:"end_label"`
+	MaxLabel   int64              `json:"max_label"`
+}
+
+// XPChartPoint is the plotted total after a session
+type XPChartPoint struct {
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Total int64   `json:"total"`
+	Date  string  `json:"date"`
+}
+
+// XPChartThreshold is a horizontal line marking the XP needed for a level
+type XPChartThreshold struct {
+	Y     float64 `json:"y"`
+	Level int64   `json:"level"`
+	XP    int64   `json:"xp"`
+}
+
+const (
+	xpChartWidth        = 600
+	xpChartHeight       = 220
+	xpChartPaddingLeft  = 50
+	xpChartPaddingRight = 30
+	xpChartPaddingTop   = 10
+	xpChartPaddingBot   = 25
+)
+
+// buildXPChart plots the running XP total per session date as a step line,
+// with the class level thresholds drawn up to the next level
+func buildXPChart(class string, history []XPHistoryEntry) XPChart {
+	chart := XPChart{Width: xpChartWidth, Height: xpChartHeight}
+	if len(history) == 0 {
+		return chart
+	}
+
+	// Collapse entries to one total per session date
+	type sessionTotal struct {
+		date  time.Time
+		total int64
+	}
+	var sessions []sessionTotal
+	for _, entry := range history {
+		if n := len(sessions); n > 0 && sessions[n-1].date.Equal(entry.SessionDate) {
+			sessions[n-1].total = entry.RunningTotal
+			continue
+		}
+		sessions = append(sessions, sessionTotal{date: entry.SessionDate, total: entry.RunningTotal})
+	}
+
+	// Scale to the highest total seen, or the next level if that is higher
+	var maxXP int64
+	for _, session := range sessions {
+		if session.total > maxXP {
+			maxXP = session.total
+		}
+	}
+	progression := charRules.GetClassProgression(class)
+	for _, level := range progression.Levels {
+		if level.XPRequired > maxXP {
+			maxXP = level.XPRequired
+			break
+		}
+	}
+	if maxXP <= 0 {
+		maxXP = 1
+	}
+	chart.MaxLabel = maxXP
+
+	plotWidth := float64(xpChartWidth - xpChartPaddingLeft - xpChartPaddingRight)
+	plotHeight := float64(xpChartHeight - xpChartPaddingTop - xpChartPaddingBot)
+
+	yFor := func(xp int64) float64 {
+		return float64(xpChartPaddingTop) + plotHeight - (float64(xp)/float64(maxXP))*plotHeight
+	}
+
+	first := sessions[0].date
+	span := sessions[len(sessions)-1].date.Sub(first)
+	xFor := func(i int) float64 {
+		if len(sessions) == 1 {
+			return float64(xpChartPaddingLeft) + plotWidth
+		}
+		// Fall back to even spacing when every session shares a date
+		if span <= 0 {
+			return float64(xpChartPaddingLeft) + plotWidth*float64(i)/float64(len(sessions)-1)
+		}
+		return float64(xpChartPaddingLeft) + plotWidth*float64(sessions[i].date.Sub(first))/float64(span)
+	}
+
+	var points []string
+	prevY := yFor(0)
+	points = append(points, fmt.Sprintf("%.1f,%.1f", float64(xpChartPaddingLeft), prevY))
+	for i, session := range sessions {
+		x := xFor(i)
+		y := yFor(session.total)
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, prevY), fmt.Sprintf("%.1f,%.1f", x, y))
+		chart.Markers = append(chart.Markers, XPChartPoint{
+			X:     x,
+			Y:     y,
+			Total: session.total,
+			Date:  session.date.Format("2006-01-02"),
+		})
+		prevY = y
+	}
+	chart.Points = strings.Join(points, " ")
+
+	for _, level := range progression.Levels {
+		if level.XPRequired <= 0 || level.XPRequired > maxXP {
+			continue
+		}
+		chart.Thresholds = append(chart.Thresholds, XPChartThreshold{
+			Y:     yFor(level.XPRequired),
+			Level: level.Level,
+			XP:    level.XPRequired,
+		})
+	}
+
+	chart.StartLabel = first.Format("2006-01-02")
+	chart.EndLabel = sessions[len(sessions)-1].date.Format("2006-01-02")
+
+	return chart
+}
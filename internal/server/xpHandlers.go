@@ -1,9 +1,12 @@
 package server
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/marbh56/mordezzan/internal/db"
 	"github.com/marbh56/mordezzan/internal/logger"
@@ -11,7 +14,7 @@ import (
 	"go.uber.org/zap"
 )
 
-// HandleXPUpdate handles updating a character's experience points
+// HandleXPUpdate records an experience award in the character's XP ledger
 func (s *Server) HandleXPUpdate(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -47,12 +50,31 @@ func (s *Server) HandleXPUpdate(w http.ResponseWriter, r *http.Request) {
 
 	calculateBonus := r.Form.Get("calculate_bonus") == "1"
 
+	source := r.Form.Get("source")
+	if source == "" {
+		source = "adjustment"
+	}
+	if !isValidXPSource(source) {
+		logger.Warn("Invalid XP source", zap.String("source", source))
+		renderXPError(w, "Invalid XP source")
+		return
+	}
+
+	sessionDate, err := parseSessionDate(r.Form.Get("session_date"))
+	if err != nil {
+		logger.Warn("Invalid session date", zap.Error(err))
+		renderXPError(w, "Invalid session date")
+		return
+	}
+
+	var notes sql.NullString
+	if notesStr := r.Form.Get("notes"); notesStr != "" {
+		notes = sql.NullString{String: notesStr, Valid: true}
+	}
+
 	// Fetch character
 	queries := db.New(s.db)
-	character, err := queries.GetCharacter(r.Context(), db.GetCharacterParams{
-		ID:     characterID,
-		UserID: user.UserID,
-	})
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
 	if err != nil {
 		logger.Error("Error fetching character", zap.Error(err))
 		renderXPError(w, "Character not found")
@@ -71,96 +93,335 @@ func (s *Server) HandleXPUpdate(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Calculate new XP total
-	newXP := character.ExperiencePoints + finalXPChange
-	if newXP < 0 {
-		newXP = 0 // Prevent negative XP
-	}
-
-	// Create update params starting with current values
-	updateParams := db.UpdateCharacterParams{
-		ID:               characterID,
-		UserID:           user.UserID,
-		Name:             character.Name,
-		Class:            character.Class,
-		Level:            character.Level,
-		MaxHp:            character.MaxHp,
-		CurrentHp:        character.CurrentHp,
-		Strength:         character.Strength,
-		Dexterity:        character.Dexterity,
-		Constitution:     character.Constitution,
-		Intelligence:     character.Intelligence,
-		Wisdom:           character.Wisdom,
-		Charisma:         character.Charisma,
-		ExperiencePoints: newXP,
-		PlatinumPieces:   character.PlatinumPieces,
-		GoldPieces:       character.GoldPieces,
-		ElectrumPieces:   character.ElectrumPieces,
-		SilverPieces:     character.SilverPieces,
-		CopperPieces:     character.CopperPieces,
-	}
-
-	// Update character in database
-	updatedChar, err := queries.UpdateCharacter(r.Context(), updateParams)
+	tx, err := s.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		renderXPError(w, "Error updating XP")
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := queries.WithTx(tx)
+
+	// Never let an adjustment take the ledger below zero
+	currentXP, err := qtx.GetCharacterXPTotal(r.Context(), characterID)
+	if err != nil {
+		logger.Error("Failed to total XP ledger", zap.Error(err))
+		renderXPError(w, "Error updating XP")
+		return
+	}
+	if currentXP+finalXPChange < 0 {
+		finalXPChange = -currentXP
+	}
+
+	award, err := qtx.CreateXPAward(r.Context(), db.CreateXPAwardParams{
+		CharacterID: characterID,
+		Amount:      finalXPChange,
+		Source:      source,
+		SessionDate: sessionDate,
+		AwardedBy:   user.UserID,
+		Notes:       notes,
+	})
+	if err != nil {
+		logger.Error("Failed to record XP award", zap.Error(err))
+		renderXPError(w, "Error updating XP")
+		return
+	}
+
+	updatedChar, err := syncExperienceFromLedger(r.Context(), qtx, character)
 	if err != nil {
 		logger.Error("Failed to update character XP", zap.Error(err))
 		renderXPError(w, "Error updating XP")
 		return
 	}
 
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit XP award", zap.Error(err))
+		renderXPError(w, "Error updating XP")
+		return
+	}
+
 	logger.Info("Character XP updated",
 		zap.Int64("character_id", characterID),
+		zap.Int64("award_id", award.ID),
+		zap.String("source", source),
 		zap.Int64("old_xp", character.ExperiencePoints),
-		zap.Int64("new_xp", newXP),
+		zap.Int64("new_xp", updatedChar.ExperiencePoints),
 		zap.Int64("change", finalXPChange),
 		zap.Bool("bonus_applied", calculateBonus))
 
-	// Check if character level should change based on new XP
-	progression := charRules.GetClassProgression(character.Class)
-	newLevel := progression.GetLevelForXP(newXP)
 	levelMessage := ""
+	if updatedChar.Level > character.Level {
+		levelMessage = fmt.Sprintf(" Level increased to %d!", updatedChar.Level)
+		logger.Info("Character level increased",
+			zap.Int64("character_id", characterID),
+			zap.Int64("old_level", character.Level),
+			zap.Int64("new_level", updatedChar.Level))
+	} else if updatedChar.Level < character.Level {
+		levelMessage = fmt.Sprintf(" Level decreased to %d.", updatedChar.Level)
+	}
+
+	// Add message based on XP change
+	var message string
+	if finalXPChange >= 0 {
+		message = fmt.Sprintf("Added %d XP%s%s", finalXPChange, bonusMessage, levelMessage)
+	} else {
+		message = fmt.Sprintf("Removed %d XP%s", -finalXPChange, levelMessage)
+	}
+
+	s.renderXPSectionForCharacter(w, r, updatedChar, message)
+}
 
-	if newLevel != character.Level {
-		// Update character level in a separate update
-		levelUpdateParams := updateParams
-		levelUpdateParams.Level = newLevel
-
-		_, err := queries.UpdateCharacter(r.Context(), levelUpdateParams)
-		if err != nil {
-			logger.Error("Failed to update character level", zap.Error(err))
-			// Continue anyway, we'll just show the XP update
-		} else {
-			levelMessage = fmt.Sprintf(" Level increased to %d!", newLevel)
-			logger.Info("Character level increased",
-				zap.Int64("character_id", characterID),
-				zap.Int64("old_level", character.Level),
-				zap.Int64("new_level", newLevel))
+// HandleXPAwardReverse reverses a ledger entry by writing a compensating award
+func (s *Server) HandleXPAwardReverse(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("Failed to parse form", zap.Error(err))
+		renderXPError(w, "Failed to parse form")
+		return
+	}
+
+	characterID, err := strconv.ParseInt(r.Form.Get("character_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid character ID", zap.Error(err))
+		renderXPError(w, "Invalid character ID")
+		return
+	}
+
+	awardID, err := strconv.ParseInt(r.Form.Get("award_id"), 10, 64)
+	if err != nil {
+		logger.Error("Invalid award ID", zap.Error(err))
+		renderXPError(w, "Invalid award ID")
+		return
+	}
+
+	queries := db.New(s.db)
+	character, err := getWritableCharacter(r.Context(), queries, characterID, user.UserID)
+	if err != nil {
+		logger.Error("Error fetching character", zap.Error(err))
+		renderXPError(w, "Character not found")
+		return
+	}
+
+	tx, err := s.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		renderXPError(w, "Error reversing award")
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := queries.WithTx(tx)
+
+	award, err := qtx.GetXPAward(r.Context(), db.GetXPAwardParams{
+		ID:          awardID,
+		CharacterID: characterID,
+	})
+	if err != nil {
+		logger.Error("XP award not found",
+			zap.Error(err),
+			zap.Int64("award_id", awardID),
+			zap.Int64("character_id", characterID))
+		renderXPError(w, "Award not found")
+		return
+	}
+
+	if award.ReversesAwardID.Valid {
+		s.renderXPSectionForCharacter(w, r, character, "Error: A reversal cannot itself be reversed")
+		return
+	}
+
+	// Drains carry hit point and level history changes that only a
+	// restoration undoes
+	isDrain, err := qtx.IsLevelDrainAward(r.Context(), award.ID)
+	if err != nil {
+		logger.Error("Failed to check for level drain", zap.Error(err))
+		renderXPError(w, "Error reversing award")
+		return
+	}
+	if isDrain {
+		s.renderXPSectionForCharacter(w, r, character, "Error: Use restoration to reverse an energy drain")
+		return
+	}
+
+	reversed, err := qtx.IsXPAwardReversed(r.Context(), sql.NullInt64{Int64: award.ID, Valid: true})
+	if err != nil {
+		logger.Error("Failed to check award reversal", zap.Error(err))
+		renderXPError(w, "Error reversing award")
+		return
+	}
+	if reversed {
+		s.renderXPSectionForCharacter(w, r, character, "Error: This award has already been reversed")
+		return
+	}
+
+	currentXP, err := qtx.GetCharacterXPTotal(r.Context(), characterID)
+	if err != nil {
+		logger.Error("Failed to total XP ledger", zap.Error(err))
+		renderXPError(w, "Error reversing award")
+		return
+	}
+	if currentXP-award.Amount < 0 {
+		s.renderXPSectionForCharacter(w, r, character, "Error: Reversing this award would leave negative XP")
+		return
+	}
+
+	// The compensating entry shares the original session date so the
+	// history chart nets it out at the point the mistake was made
+	_, err = qtx.CreateXPAward(r.Context(), db.CreateXPAwardParams{
+		CharacterID:     characterID,
+		Amount:          -award.Amount,
+		Source:          award.Source,
+		SessionDate:     award.SessionDate,
+		AwardedBy:       user.UserID,
+		ReversesAwardID: sql.NullInt64{Int64: award.ID, Valid: true},
+		Notes:           sql.NullString{String: fmt.Sprintf("Reversal of award #%d", award.ID), Valid: true},
+	})
+	if err != nil {
+		logger.Error("Failed to record XP reversal", zap.Error(err))
+		renderXPError(w, "Error reversing award")
+		return
+	}
+
+	updatedChar, err := syncExperienceFromLedger(r.Context(), qtx, character)
+	if err != nil {
+		logger.Error("Failed to update character XP", zap.Error(err))
+		renderXPError(w, "Error reversing award")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit XP reversal", zap.Error(err))
+		renderXPError(w, "Error reversing award")
+		return
+	}
+
+	logger.Info("XP award reversed",
+		zap.Int64("character_id", characterID),
+		zap.Int64("award_id", award.ID),
+		zap.Int64("amount", award.Amount),
+		zap.Int64("new_xp", updatedChar.ExperiencePoints))
+
+	s.renderXPSectionForCharacter(w, r, updatedChar, fmt.Sprintf("Reversed award of %d XP", award.Amount))
+}
+
+// syncExperienceFromLedger sets the character's XP to the ledger total and
+// recalculates their level from it
+func syncExperienceFromLedger(ctx context.Context, qtx *db.Queries, character db.Character) (db.Character, error) {
+	totalXP, err := qtx.GetCharacterXPTotal(ctx, character.ID)
+	if err != nil {
+		return db.Character{}, err
+	}
+
+	progression := charRules.GetClassProgression(character.Class)
+	newLevel := progression.GetLevelForXP(totalXP)
+
+	// Open a level history row for each level gained; hit points rolled
+	// for it are added when the maximum is raised
+	for level := character.Level + 1; level <= newLevel; level++ {
+		if err := qtx.CreateLevelHistory(ctx, db.CreateLevelHistoryParams{
+			CharacterID: character.ID,
+			Level:       level,
+		}); err != nil {
+			return db.Character{}, err
 		}
 	}
 
+	// Levels lost to a reversal or a negative adjustment no longer count
+	// towards drains or restorations. Drained levels keep their rows so the
+	// drain can be restored.
+	if newLevel < character.Level {
+		if err := qtx.RetireLevelHistory(ctx, db.RetireLevelHistoryParams{
+			CharacterID: character.ID,
+			Level:       newLevel,
+		}); err != nil {
+			return db.Character{}, err
+		}
+	}
+
+	return qtx.UpdateCharacterExperience(ctx, db.UpdateCharacterExperienceParams{
+		ExperiencePoints: totalXP,
+		Level:            newLevel,
+		ID:               character.ID,
+		UserID:           character.UserID,
+	})
+}
+
+// renderXPSectionForCharacter builds the view model, including XP history,
+// and renders the XP section
+func (s *Server) renderXPSectionForCharacter(w http.ResponseWriter, r *http.Request, character db.Character, message string) {
+	queries := db.New(s.db)
+
 	// Fetch inventory for view model creation
-	inventory, err := queries.GetCharacterInventoryItems(r.Context(), characterID)
+	inventory, err := queries.GetCharacterInventoryItems(r.Context(), character.ID)
 	if err != nil {
 		logger.Warn("Failed to fetch inventory for XP update",
 			zap.Error(err),
-			zap.Int64("character_id", characterID))
+			zap.Int64("character_id", character.ID))
 		// Continue anyway, we'll just show the XP update with empty inventory
 		inventory = []db.GetCharacterInventoryItemsRow{}
 	}
 
-	// Create view model for template
-	viewModel := NewSafeCharacterViewModel(updatedChar, inventory)
+	coinage, purse := characterCoins(r.Context(), queries, character.ID)
+	viewModel := NewSafeCharacterViewModel(character, inventory, coinage, purse)
+	s.loadXPHistory(r.Context(), queries, &viewModel)
 
-	// Add message based on XP change
-	var message string
-	if finalXPChange > 0 {
-		message = fmt.Sprintf("Added %d XP%s%s", finalXPChange, bonusMessage, levelMessage)
-	} else {
-		message = fmt.Sprintf("Removed %d XP", -finalXPChange)
+	renderXPSection(w, viewModel, message)
+}
+
+// loadXPHistory attaches the XP ledger, history chart and level drains to a
+// view model
+func (s *Server) loadXPHistory(ctx context.Context, queries *db.Queries, vm *CharacterViewModel) {
+	drains, err := queries.ListLevelDrainsByCharacter(ctx, vm.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch level drains",
+			zap.Error(err),
+			zap.Int64("character_id", vm.ID))
 	}
+	vm.LevelDrains = drains
 
-	// Render the updated XP section
-	renderXPSection(w, viewModel, message)
+	drainAwards := make(map[int64]bool, len(drains))
+	for _, drain := range drains {
+		drainAwards[drain.XpAwardID] = true
+	}
+
+	awards, err := queries.ListXPAwardsByCharacter(ctx, vm.ID)
+	if err != nil {
+		logger.Warn("Failed to fetch XP history",
+			zap.Error(err),
+			zap.Int64("character_id", vm.ID))
+		return
+	}
+
+	vm.XPHistory = make([]XPHistoryEntry, 0, len(awards))
+	var runningTotal int64
+	for _, award := range awards {
+		runningTotal += award.Amount
+		vm.XPHistory = append(vm.XPHistory, XPHistoryEntry{
+			ID:           award.ID,
+			Amount:       award.Amount,
+			RunningTotal: runningTotal,
+			Source:       award.Source,
+			SessionDate:  award.SessionDate,
+			AwardedBy:    award.AwardedByUsername,
+			Notes:        award.Notes.String,
+			IsReversal:   award.ReversesAwardID.Valid,
+			IsReversed:   award.ReversedByID.Valid,
+			IsDrain:      drainAwards[award.ID],
+		})
+	}
+
+	vm.XPChart = buildXPChart(vm.Class, vm.XPHistory)
 }
 
 // Helper function to render XP errors
@@ -232,3 +493,30 @@ func calculateXPBonus(class string, character db.Character) int64 {
 
 	return 0
 }
+
+// isValidXPSource checks if the source is one the XP ledger accepts
+func isValidXPSource(source string) bool {
+	validSources := map[string]bool{
+		"monsters":   true,
+		"treasure":   true,
+		"story":      true,
+		"bonus":      true,
+		"adjustment": true,
+		"starting":   true,
+	}
+	return validSources[source]
+}
+
+// parseSessionDate parses a date input value, defaulting to today when empty
+func parseSessionDate(value string) (time.Time, error) {
+	if value == "" {
+		return sessionToday(), nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// sessionToday is today's date as a session date
+func sessionToday() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
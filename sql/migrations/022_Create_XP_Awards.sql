@@ -0,0 +1,59 @@
+-- +goose Up
+-- Append-only ledger of experience awards. The sum of a character's awards
+-- is the source of truth for characters.experience_points.
+CREATE TABLE xp_awards (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    amount INTEGER NOT NULL,
+    source TEXT NOT NULL CHECK (
+        source IN (
+            'monsters',
+            'treasure',
+            'story',
+            'bonus',
+            'adjustment',
+            'starting'
+        )
+    ),
+    session_date DATE NOT NULL DEFAULT CURRENT_DATE,
+    awarded_by INTEGER NOT NULL,
+    -- Set on the compensating entry written when an award is reversed
+    reverses_award_id INTEGER,
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE,
+    FOREIGN KEY (awarded_by) REFERENCES users (id),
+    FOREIGN KEY (reverses_award_id) REFERENCES xp_awards (id)
+);
+
+CREATE INDEX idx_xp_awards_character_id ON xp_awards (character_id);
+
+-- An award can only be reversed once
+CREATE UNIQUE INDEX idx_xp_awards_reverses_award_id ON xp_awards (reverses_award_id);
+
+-- Carry existing experience across as an opening balance
+INSERT INTO
+    xp_awards (
+        character_id,
+        amount,
+        source,
+        session_date,
+        awarded_by,
+        notes
+    )
+SELECT
+    id,
+    experience_points,
+    'starting',
+    DATE(created_at),
+    user_id,
+    'Opening balance'
+FROM
+    characters
+WHERE
+    experience_points > 0;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_xp_awards_reverses_award_id;
+DROP INDEX IF EXISTS idx_xp_awards_character_id;
+DROP TABLE IF EXISTS xp_awards;
@@ -0,0 +1,80 @@
+-- +goose Up
+-- Each energy drain event. Restoration reverses the XP award and clears
+-- the drain from the level history.
+CREATE TABLE level_drains (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    from_level INTEGER NOT NULL,
+    to_level INTEGER NOT NULL,
+    hp_removed INTEGER NOT NULL DEFAULT 0,
+    xp_award_id INTEGER NOT NULL,
+    drained_by INTEGER NOT NULL,
+    restored_by INTEGER,
+    restored_at TIMESTAMP,
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE,
+    FOREIGN KEY (xp_award_id) REFERENCES xp_awards (id),
+    FOREIGN KEY (drained_by) REFERENCES users (id),
+    FOREIGN KEY (restored_by) REFERENCES users (id),
+    CHECK (to_level >= 1 AND to_level < from_level)
+);
+
+CREATE INDEX idx_level_drains_character_id ON level_drains (character_id);
+
+-- Hit points gained at each level. Rows lost to an energy drain keep their
+-- values and point at the drain so restoration can bring them back.
+CREATE TABLE character_level_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    level INTEGER NOT NULL CHECK (level >= 1),
+    hp_gained INTEGER NOT NULL DEFAULT 0,
+    drain_id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE,
+    FOREIGN KEY (drain_id) REFERENCES level_drains (id)
+);
+
+CREATE UNIQUE INDEX idx_character_level_history_active ON character_level_history (character_id, level)
+WHERE
+    drain_id IS NULL;
+
+-- Existing characters have no record of what they rolled, so spread their
+-- current maximum evenly across their levels with the remainder at 1st
+WITH RECURSIVE
+    levels (character_id, level, max_level, max_hp) AS (
+        SELECT
+            id,
+            1,
+            level,
+            max_hp
+        FROM
+            characters
+        UNION ALL
+        SELECT
+            character_id,
+            level + 1,
+            max_level,
+            max_hp
+        FROM
+            levels
+        WHERE
+            level < max_level
+    )
+INSERT INTO
+    character_level_history (character_id, level, hp_gained)
+SELECT
+    character_id,
+    level,
+    CASE
+        WHEN level = 1 THEN max_hp - (max_level - 1) * (max_hp / max_level)
+        ELSE max_hp / max_level
+    END
+FROM
+    levels;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_character_level_history_active;
+DROP TABLE IF EXISTS character_level_history;
+DROP INDEX IF EXISTS idx_level_drains_character_id;
+DROP TABLE IF EXISTS level_drains;
@@ -0,0 +1,92 @@
+-- +goose Up
+-- Rest modes and their healing formulas. Formulas are sums of terms such
+-- as 1d3, 2, level or con (the Constitution HP modifier), terms may be
+-- multiplied (2*level), and "max" heals fully.
+CREATE TABLE rest_modes (
+    code TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    duration_hours INTEGER NOT NULL CHECK (duration_hours > 0),
+    healing_formula TEXT NOT NULL DEFAULT '0',
+    resets_resources BOOLEAN NOT NULL DEFAULT 0,
+    rations_consumed INTEGER NOT NULL DEFAULT 0 CHECK (rations_consumed >= 0),
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO
+    rest_modes (
+        code,
+        name,
+        description,
+        duration_hours,
+        healing_formula,
+        resets_resources,
+        rations_consumed,
+        sort_order
+    )
+VALUES
+    (
+        'short',
+        'Short Rest',
+        'An hour catching breath and binding wounds.',
+        1,
+        '0',
+        0,
+        0,
+        1
+    ),
+    (
+        'night',
+        'Full Night''s Rest',
+        'Eight hours of sleep. Restores spells and daily abilities.',
+        8,
+        '1',
+        1,
+        1,
+        2
+    ),
+    (
+        'bed_rest',
+        'Full Day of Bed Rest',
+        'A full day abed with no travel or exertion.',
+        24,
+        '1d3+con',
+        1,
+        1,
+        3
+    ),
+    (
+        'convalescence',
+        'Extended Convalescence',
+        'A week of care and bed rest.',
+        168,
+        'max',
+        1,
+        7,
+        4
+    );
+
+-- Daily uses spent, keyed by resource (spell_slot_1, turn_undead, ...).
+-- A rest that resets resources deletes the character's rows.
+CREATE TABLE character_resource_uses (
+    character_id INTEGER NOT NULL,
+    resource TEXT NOT NULL,
+    used INTEGER NOT NULL DEFAULT 0 CHECK (used >= 0),
+    PRIMARY KEY (character_id, resource),
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE
+);
+
+-- Hours of game time elapsed for a character. Rations are only eaten
+-- while time tracking is on.
+CREATE TABLE character_game_clocks (
+    character_id INTEGER PRIMARY KEY,
+    elapsed_hours INTEGER NOT NULL DEFAULT 0,
+    track_time BOOLEAN NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS character_game_clocks;
+DROP TABLE IF EXISTS character_resource_uses;
+DROP TABLE IF EXISTS rest_modes;
@@ -0,0 +1,63 @@
+-- +goose Up
+CREATE TABLE campaigns (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    referee_id INTEGER NOT NULL,
+    invite_code TEXT NOT NULL UNIQUE,
+    -- Referees can always view party characters; this lets them change them too
+    referee_can_edit BOOLEAN NOT NULL DEFAULT 0,
+    drain_xp_policy TEXT NOT NULL DEFAULT 'midpoint' CHECK (drain_xp_policy IN ('midpoint', 'minimum')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (referee_id) REFERENCES users (id)
+);
+
+CREATE INDEX idx_campaigns_referee_id ON campaigns (referee_id);
+
+-- Players in a campaign. The referee is recorded on the campaign itself.
+CREATE TABLE campaign_members (
+    campaign_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'invited' CHECK (status IN ('invited', 'active')),
+    invited_by INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (campaign_id, user_id),
+    FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (invited_by) REFERENCES users (id)
+);
+
+CREATE INDEX idx_campaign_members_user_id ON campaign_members (user_id);
+
+-- A character adventures in at most one campaign at a time
+CREATE TABLE campaign_characters (
+    character_id INTEGER PRIMARY KEY,
+    campaign_id INTEGER NOT NULL,
+    added_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE,
+    FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_campaign_characters_campaign_id ON campaign_characters (campaign_id);
+
+CREATE TABLE character_conditions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_character_conditions_character_id ON character_conditions (character_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_character_conditions_character_id;
+DROP TABLE IF EXISTS character_conditions;
+DROP INDEX IF EXISTS idx_campaign_characters_campaign_id;
+DROP TABLE IF EXISTS campaign_characters;
+DROP INDEX IF EXISTS idx_campaign_members_user_id;
+DROP TABLE IF EXISTS campaign_members;
+DROP INDEX IF EXISTS idx_campaigns_referee_id;
+DROP TABLE IF EXISTS campaigns;
@@ -0,0 +1,41 @@
+-- +goose Up
+-- A hoard divided among a campaign party by its referee
+CREATE TABLE treasure_splits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign_id INTEGER NOT NULL,
+    referee_id INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    total_value_cp INTEGER NOT NULL,
+    -- Coins that could not be divided, formatted for display
+    remainder TEXT NOT NULL DEFAULT '',
+    -- Coins set aside for henchmen's half shares, which the referee hands
+    -- out, formatted for display
+    henchman_coins TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON DELETE CASCADE,
+    FOREIGN KEY (referee_id) REFERENCES users (id)
+);
+
+CREATE INDEX idx_treasure_splits_campaign_id ON treasure_splits (campaign_id);
+
+CREATE TABLE treasure_split_shares (
+    split_id INTEGER NOT NULL,
+    character_id INTEGER NOT NULL,
+    weight INTEGER NOT NULL,
+    value_cp INTEGER NOT NULL,
+    platinum_pieces INTEGER NOT NULL DEFAULT 0,
+    gold_pieces INTEGER NOT NULL DEFAULT 0,
+    electrum_pieces INTEGER NOT NULL DEFAULT 0,
+    silver_pieces INTEGER NOT NULL DEFAULT 0,
+    copper_pieces INTEGER NOT NULL DEFAULT 0,
+    xp_award_id INTEGER,
+    PRIMARY KEY (split_id, character_id),
+    FOREIGN KEY (split_id) REFERENCES treasure_splits (id) ON DELETE CASCADE,
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE,
+    FOREIGN KEY (xp_award_id) REFERENCES xp_awards (id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS treasure_split_shares;
+DROP INDEX IF EXISTS idx_treasure_splits_campaign_id;
+DROP TABLE IF EXISTS treasure_splits;
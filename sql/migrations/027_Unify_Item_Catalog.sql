@@ -0,0 +1,242 @@
+-- +goose Up
+-- Every catalog entry gets a base row in items holding what all items share.
+-- The per-type tables keep only their type-specific details and point back
+-- at their item, and inventory rows reference items directly.
+ALTER TABLE items ADD COLUMN item_type TEXT NOT NULL DEFAULT 'equipment' CHECK (
+    item_type IN (
+        'equipment',
+        'weapon',
+        'armor',
+        'ammunition',
+        'container',
+        'shield',
+        'ranged_weapon',
+        'magical_item',
+        'treasure'
+    )
+);
+
+-- Old per-type id, only used while moving rows across
+ALTER TABLE items ADD COLUMN legacy_id INTEGER;
+
+INSERT INTO items (name, weight, value, item_type, legacy_id)
+SELECT name, weight, cost_gp, 'weapon', id FROM weapons ORDER BY id;
+
+INSERT INTO items (name, weight, value, item_type, legacy_id)
+SELECT name, weight, cost_gp, 'armor', id FROM armor ORDER BY id;
+
+INSERT INTO items (name, weight, value, item_type, legacy_id)
+SELECT name, weight, cost_gp, 'shield', id FROM shields ORDER BY id;
+
+INSERT INTO items (name, weight, value, item_type, legacy_id)
+SELECT name, weight, COALESCE(cost_gp, 0), 'ranged_weapon', id FROM ranged_weapons ORDER BY id;
+
+INSERT INTO items (name, weight, value, stackable, item_type, legacy_id)
+SELECT name, COALESCE(weight, 0), cost_gp, 1, 'ammunition', id FROM ammunition ORDER BY id;
+
+INSERT INTO items (name, description, weight, value, stackable, item_type, legacy_id)
+SELECT name, description, COALESCE(weight, 0), cost_gp, 1, 'equipment', id FROM equipment ORDER BY id;
+
+INSERT INTO items (name, description, weight, value, item_type, legacy_id)
+SELECT name, description, weight, cost_gp, 'magical_item', id FROM magical_items ORDER BY id;
+
+ALTER TABLE weapons ADD COLUMN item_id INTEGER REFERENCES items (id) ON DELETE CASCADE;
+ALTER TABLE armor ADD COLUMN item_id INTEGER REFERENCES items (id) ON DELETE CASCADE;
+ALTER TABLE shields ADD COLUMN item_id INTEGER REFERENCES items (id) ON DELETE CASCADE;
+ALTER TABLE ranged_weapons ADD COLUMN item_id INTEGER REFERENCES items (id) ON DELETE CASCADE;
+ALTER TABLE ammunition ADD COLUMN item_id INTEGER REFERENCES items (id) ON DELETE CASCADE;
+ALTER TABLE equipment ADD COLUMN item_id INTEGER REFERENCES items (id) ON DELETE CASCADE;
+ALTER TABLE magical_items ADD COLUMN item_id INTEGER REFERENCES items (id) ON DELETE CASCADE;
+
+UPDATE weapons SET item_id = i.id FROM items i WHERE i.item_type = 'weapon' AND i.legacy_id = weapons.id;
+UPDATE armor SET item_id = i.id FROM items i WHERE i.item_type = 'armor' AND i.legacy_id = armor.id;
+UPDATE shields SET item_id = i.id FROM items i WHERE i.item_type = 'shield' AND i.legacy_id = shields.id;
+UPDATE ranged_weapons SET item_id = i.id FROM items i WHERE i.item_type = 'ranged_weapon' AND i.legacy_id = ranged_weapons.id;
+UPDATE ammunition SET item_id = i.id FROM items i WHERE i.item_type = 'ammunition' AND i.legacy_id = ammunition.id;
+UPDATE equipment SET item_id = i.id FROM items i WHERE i.item_type = 'equipment' AND i.legacy_id = equipment.id;
+UPDATE magical_items SET item_id = i.id FROM items i WHERE i.item_type = 'magical_item' AND i.legacy_id = magical_items.id;
+
+CREATE UNIQUE INDEX idx_weapons_item_id ON weapons (item_id);
+CREATE UNIQUE INDEX idx_armor_item_id ON armor (item_id);
+CREATE UNIQUE INDEX idx_shields_item_id ON shields (item_id);
+CREATE UNIQUE INDEX idx_ranged_weapons_item_id ON ranged_weapons (item_id);
+CREATE UNIQUE INDEX idx_ammunition_item_id ON ammunition (item_id);
+CREATE UNIQUE INDEX idx_equipment_item_id ON equipment (item_id);
+CREATE UNIQUE INDEX idx_magical_items_item_id ON magical_items (item_id);
+
+-- Consumables never had a catalog table. Each one carried gets a plain
+-- equipment entry, named as its owner named it, keyed by its negated old id
+-- so it can't be mistaken for an equipment row.
+INSERT INTO items (name, stackable, item_type, legacy_id)
+SELECT COALESCE(MAX(custom_name), 'Consumable'), 1, 'equipment', -item_id
+FROM character_inventory
+WHERE item_type = 'consumable'
+GROUP BY item_id
+ORDER BY item_id;
+
+UPDATE character_inventory
+SET item_id = i.id
+FROM items i
+WHERE character_inventory.item_type = 'consumable'
+    AND i.legacy_id = -character_inventory.item_id;
+
+-- Containers were stored against their equipment row
+UPDATE character_inventory
+SET item_id = i.id
+FROM items i
+WHERE i.legacy_id = character_inventory.item_id
+    AND i.item_type = CASE character_inventory.item_type
+        WHEN 'container' THEN 'equipment'
+        ELSE character_inventory.item_type
+    END;
+
+-- Equipment that holds other items becomes a container with its capacity
+UPDATE items
+SET item_type = 'container', stackable = 0
+WHERE item_type = 'equipment'
+    AND legacy_id > 0
+    AND name IN ('Bow Case', 'Pouch, Hard Leather', 'Pouch, Soft Leather', 'Sack, Large', 'Sack, Small');
+
+INSERT INTO containers (base_item_id, capacity_weight, capacity_items, container_type)
+SELECT
+    id,
+    CASE name
+        WHEN 'Bow Case' THEN 5
+        WHEN 'Pouch, Hard Leather' THEN 3
+        WHEN 'Pouch, Soft Leather' THEN 1
+        WHEN 'Sack, Large' THEN 40
+        WHEN 'Sack, Small' THEN 20
+    END,
+    CASE name
+        WHEN 'Bow Case' THEN 1
+        WHEN 'Pouch, Hard Leather' THEN 6
+    END,
+    CASE name
+        WHEN 'Bow Case' THEN 'case'
+        WHEN 'Pouch, Hard Leather' THEN 'pouch'
+        WHEN 'Pouch, Soft Leather' THEN 'pouch'
+        ELSE 'sack'
+    END
+FROM items
+WHERE item_type = 'container';
+
+CREATE UNIQUE INDEX idx_containers_base_item_id ON containers (base_item_id);
+
+DELETE FROM equipment WHERE item_id IN (SELECT id FROM items WHERE item_type = 'container');
+
+-- The item now carries its type, name, weight and value
+DROP INDEX idx_character_inventory_item_type;
+ALTER TABLE character_inventory DROP COLUMN item_type;
+
+ALTER TABLE weapons DROP COLUMN name;
+ALTER TABLE weapons DROP COLUMN cost_gp;
+ALTER TABLE weapons DROP COLUMN weight;
+ALTER TABLE armor DROP COLUMN name;
+ALTER TABLE armor DROP COLUMN cost_gp;
+ALTER TABLE armor DROP COLUMN weight;
+ALTER TABLE shields DROP COLUMN name;
+ALTER TABLE shields DROP COLUMN cost_gp;
+ALTER TABLE shields DROP COLUMN weight;
+ALTER TABLE ranged_weapons DROP COLUMN name;
+ALTER TABLE ranged_weapons DROP COLUMN cost_gp;
+ALTER TABLE ranged_weapons DROP COLUMN weight;
+ALTER TABLE ammunition DROP COLUMN name;
+ALTER TABLE ammunition DROP COLUMN cost_gp;
+ALTER TABLE ammunition DROP COLUMN weight;
+ALTER TABLE equipment DROP COLUMN name;
+ALTER TABLE equipment DROP COLUMN cost_gp;
+ALTER TABLE equipment DROP COLUMN weight;
+ALTER TABLE equipment DROP COLUMN description;
+ALTER TABLE magical_items DROP COLUMN name;
+ALTER TABLE magical_items DROP COLUMN description;
+ALTER TABLE magical_items DROP COLUMN weight;
+ALTER TABLE magical_items DROP COLUMN cost_gp;
+
+ALTER TABLE items DROP COLUMN legacy_id;
+
+CREATE INDEX idx_items_item_type ON items (item_type);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_items_item_type;
+
+ALTER TABLE weapons ADD COLUMN name TEXT NOT NULL DEFAULT '';
+ALTER TABLE weapons ADD COLUMN cost_gp INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE weapons ADD COLUMN weight INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE armor ADD COLUMN name TEXT NOT NULL DEFAULT '';
+ALTER TABLE armor ADD COLUMN cost_gp INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE armor ADD COLUMN weight INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE shields ADD COLUMN name TEXT NOT NULL DEFAULT '';
+ALTER TABLE shields ADD COLUMN cost_gp INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE shields ADD COLUMN weight INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE ranged_weapons ADD COLUMN name TEXT NOT NULL DEFAULT '';
+ALTER TABLE ranged_weapons ADD COLUMN cost_gp INTEGER;
+ALTER TABLE ranged_weapons ADD COLUMN weight INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE ammunition ADD COLUMN name TEXT NOT NULL DEFAULT '';
+ALTER TABLE ammunition ADD COLUMN cost_gp DECIMAL(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE ammunition ADD COLUMN weight INTEGER;
+ALTER TABLE equipment ADD COLUMN name TEXT NOT NULL DEFAULT '';
+ALTER TABLE equipment ADD COLUMN cost_gp DECIMAL(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE equipment ADD COLUMN weight INTEGER;
+ALTER TABLE equipment ADD COLUMN description TEXT;
+ALTER TABLE magical_items ADD COLUMN name TEXT NOT NULL DEFAULT '';
+ALTER TABLE magical_items ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE magical_items ADD COLUMN weight INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE magical_items ADD COLUMN cost_gp INTEGER NOT NULL DEFAULT 0;
+
+-- Containers go back to being plain equipment
+INSERT INTO equipment (item_id) SELECT id FROM items WHERE item_type = 'container';
+DELETE FROM containers;
+DROP INDEX IF EXISTS idx_containers_base_item_id;
+
+UPDATE weapons SET name = i.name, cost_gp = i.value, weight = i.weight FROM items i WHERE i.id = weapons.item_id;
+UPDATE armor SET name = i.name, cost_gp = i.value, weight = i.weight FROM items i WHERE i.id = armor.item_id;
+UPDATE shields SET name = i.name, cost_gp = i.value, weight = i.weight FROM items i WHERE i.id = shields.item_id;
+UPDATE ranged_weapons SET name = i.name, cost_gp = i.value, weight = i.weight FROM items i WHERE i.id = ranged_weapons.item_id;
+UPDATE ammunition SET name = i.name, cost_gp = i.value, weight = i.weight FROM items i WHERE i.id = ammunition.item_id;
+UPDATE equipment SET name = i.name, cost_gp = i.value, weight = i.weight, description = i.description FROM items i WHERE i.id = equipment.item_id;
+UPDATE magical_items SET name = i.name, description = COALESCE(i.description, ''), weight = i.weight, cost_gp = i.value FROM items i WHERE i.id = magical_items.item_id;
+
+ALTER TABLE character_inventory ADD COLUMN item_type TEXT NOT NULL DEFAULT 'equipment' CHECK (
+    item_type IN (
+        'equipment',
+        'weapon',
+        'armor',
+        'ammunition',
+        'container',
+        'shield',
+        'ranged_weapon',
+        'consumable',
+        'magical_item'
+    )
+);
+
+UPDATE character_inventory SET item_type = i.item_type FROM items i WHERE i.id = character_inventory.item_id;
+
+UPDATE character_inventory SET item_id = d.id FROM weapons d WHERE character_inventory.item_type = 'weapon' AND d.item_id = character_inventory.item_id;
+UPDATE character_inventory SET item_id = d.id FROM armor d WHERE character_inventory.item_type = 'armor' AND d.item_id = character_inventory.item_id;
+UPDATE character_inventory SET item_id = d.id FROM shields d WHERE character_inventory.item_type = 'shield' AND d.item_id = character_inventory.item_id;
+UPDATE character_inventory SET item_id = d.id FROM ranged_weapons d WHERE character_inventory.item_type = 'ranged_weapon' AND d.item_id = character_inventory.item_id;
+UPDATE character_inventory SET item_id = d.id FROM ammunition d WHERE character_inventory.item_type = 'ammunition' AND d.item_id = character_inventory.item_id;
+UPDATE character_inventory SET item_id = d.id FROM equipment d WHERE character_inventory.item_type IN ('equipment', 'container') AND d.item_id = character_inventory.item_id;
+UPDATE character_inventory SET item_id = d.id FROM magical_items d WHERE character_inventory.item_type = 'magical_item' AND d.item_id = character_inventory.item_id;
+
+CREATE INDEX idx_character_inventory_item_type ON character_inventory (item_type);
+
+DROP INDEX IF EXISTS idx_weapons_item_id;
+DROP INDEX IF EXISTS idx_armor_item_id;
+DROP INDEX IF EXISTS idx_shields_item_id;
+DROP INDEX IF EXISTS idx_ranged_weapons_item_id;
+DROP INDEX IF EXISTS idx_ammunition_item_id;
+DROP INDEX IF EXISTS idx_equipment_item_id;
+DROP INDEX IF EXISTS idx_magical_items_item_id;
+
+ALTER TABLE weapons DROP COLUMN item_id;
+ALTER TABLE armor DROP COLUMN item_id;
+ALTER TABLE shields DROP COLUMN item_id;
+ALTER TABLE ranged_weapons DROP COLUMN item_id;
+ALTER TABLE ammunition DROP COLUMN item_id;
+ALTER TABLE equipment DROP COLUMN item_id;
+ALTER TABLE magical_items DROP COLUMN item_id;
+
+DELETE FROM items;
+ALTER TABLE items DROP COLUMN item_type;
@@ -0,0 +1,184 @@
+-- +goose Up
+-- Magic bonuses belong to the item a character owns rather than to
+-- duplicated "+1", "+2" and "+3" catalog rows
+ALTER TABLE character_inventory ADD COLUMN enhancement_bonus INTEGER NOT NULL DEFAULT 0;
+
+-- Named magical properties on an owned item, e.g. flaming or of slaying
+CREATE TABLE character_inventory_properties (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    inventory_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (inventory_id) REFERENCES character_inventory (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_character_inventory_properties_inventory_id ON character_inventory_properties (inventory_id);
+
+-- Each "Name +N" catalog row and the base item it copies
+CREATE TEMP TABLE enhanced_items AS
+SELECT
+    e.id AS item_id,
+    b.id AS base_item_id,
+    CAST(substr(e.name, -1) AS INTEGER) AS bonus
+FROM
+    items e
+    JOIN items b ON b.item_type = e.item_type
+    AND e.name = b.name || ' +' || substr(e.name, -1)
+WHERE
+    e.item_type IN ('weapon', 'armor', 'shield', 'ranged_weapon', 'ammunition')
+    AND e.name GLOB '* +[1-9]';
+
+UPDATE character_inventory
+SET item_id = e.base_item_id, enhancement_bonus = e.bonus
+FROM enhanced_items e
+WHERE e.item_id = character_inventory.item_id;
+
+-- The old add flow recorded the bonus in the notes as well
+UPDATE character_inventory
+SET notes = NULL
+WHERE enhancement_bonus > 0
+    AND notes = '+' || enhancement_bonus || ' enhancement';
+
+UPDATE character_inventory
+SET notes = substr(notes, 1, length(notes) - length(' (+' || enhancement_bonus || ' enhancement)'))
+WHERE enhancement_bonus > 0
+    AND notes LIKE '% (+' || enhancement_bonus || ' enhancement)';
+
+-- Mastery of a magic sword is mastery of the sword
+UPDATE OR IGNORE character_weapon_masteries
+SET weapon_id = bw.id
+FROM
+    weapons ew
+    JOIN enhanced_items e ON e.item_id = ew.item_id
+    JOIN weapons bw ON bw.item_id = e.base_item_id
+WHERE ew.id = character_weapon_masteries.weapon_id;
+
+DELETE FROM character_weapon_masteries
+WHERE weapon_id IN (SELECT w.id FROM weapons w JOIN enhanced_items e ON e.item_id = w.item_id);
+
+DELETE FROM weapon_property_links
+WHERE weapon_id IN (SELECT w.id FROM weapons w JOIN enhanced_items e ON e.item_id = w.item_id);
+
+DELETE FROM weapons WHERE item_id IN (SELECT item_id FROM enhanced_items);
+DELETE FROM armor WHERE item_id IN (SELECT item_id FROM enhanced_items);
+DELETE FROM shields WHERE item_id IN (SELECT item_id FROM enhanced_items);
+DELETE FROM ranged_weapons WHERE item_id IN (SELECT item_id FROM enhanced_items);
+DELETE FROM ammunition WHERE item_id IN (SELECT item_id FROM enhanced_items);
+DELETE FROM items WHERE id IN (SELECT item_id FROM enhanced_items);
+
+DROP TABLE enhanced_items;
+
+ALTER TABLE weapons DROP COLUMN enhancement_bonus;
+ALTER TABLE weapons DROP COLUMN magical_properties;
+ALTER TABLE armor DROP COLUMN enhancement_bonus;
+ALTER TABLE shields DROP COLUMN enhancement_bonus;
+ALTER TABLE ranged_weapons DROP COLUMN enhancement_bonus;
+ALTER TABLE ammunition DROP COLUMN enhancement_bonus;
+
+-- +goose Down
+-- Enhanced items go back to "Name +N" catalog rows, made the way the magic
+-- item migration made them
+ALTER TABLE weapons ADD COLUMN enhancement_bonus INTEGER DEFAULT NULL;
+ALTER TABLE weapons ADD COLUMN magical_properties TEXT DEFAULT NULL;
+ALTER TABLE armor ADD COLUMN enhancement_bonus INTEGER DEFAULT 0;
+ALTER TABLE shields ADD COLUMN enhancement_bonus INTEGER DEFAULT 0;
+ALTER TABLE ranged_weapons ADD COLUMN enhancement_bonus INTEGER DEFAULT 0;
+ALTER TABLE ammunition ADD COLUMN enhancement_bonus INTEGER DEFAULT 0;
+
+-- Every weapon and armor had +1 to +3 rows, and any other bonus an owned
+-- item carries gets one too
+CREATE TEMP TABLE enhanced_items AS
+SELECT
+    b.id AS base_item_id,
+    n.bonus,
+    CAST(NULL AS INTEGER) AS item_id
+FROM
+    items b
+    JOIN (SELECT 1 AS bonus UNION SELECT 2 UNION SELECT 3) n
+WHERE
+    b.item_type IN ('weapon', 'armor')
+UNION
+SELECT
+    item_id,
+    enhancement_bonus,
+    NULL
+FROM
+    character_inventory
+WHERE
+    enhancement_bonus > 0;
+
+INSERT INTO items (name, description, weight, value, stackable, max_stack, item_type)
+SELECT
+    b.name || ' +' || e.bonus,
+    b.description,
+    b.weight,
+    b.value + 2000 * e.bonus * e.bonus,
+    b.stackable,
+    b.max_stack,
+    b.item_type
+FROM
+    enhanced_items e
+    JOIN items b ON b.id = e.base_item_id
+ORDER BY
+    e.base_item_id,
+    e.bonus;
+
+UPDATE enhanced_items
+SET item_id = (
+    SELECT i.id
+    FROM items i JOIN items b ON b.id = enhanced_items.base_item_id
+    WHERE i.item_type = b.item_type
+        AND i.name = b.name || ' +' || enhanced_items.bonus
+);
+
+INSERT INTO weapons (reach, range_short, range_medium, range_long, attacks_per_round, damage, enhancement_bonus, item_id)
+SELECT w.reach, w.range_short, w.range_medium, w.range_long, w.attacks_per_round, w.damage, e.bonus, e.item_id
+FROM enhanced_items e JOIN weapons w ON w.item_id = e.base_item_id;
+
+INSERT INTO weapon_property_links (weapon_id, property_id)
+SELECT nw.id, l.property_id
+FROM
+    enhanced_items e
+    JOIN weapons bw ON bw.item_id = e.base_item_id
+    JOIN weapon_property_links l ON l.weapon_id = bw.id
+    JOIN weapons nw ON nw.item_id = e.item_id;
+
+INSERT INTO armor (armor_class, damage_reduction, armor_type, movement_rate, enhancement_bonus, item_id)
+SELECT a.armor_class - e.bonus, a.damage_reduction, a.armor_type, a.movement_rate, e.bonus, e.item_id
+FROM enhanced_items e JOIN armor a ON a.item_id = e.base_item_id;
+
+INSERT INTO shields (defense_bonus, enhancement_bonus, item_id)
+SELECT s.defense_bonus, e.bonus, e.item_id
+FROM enhanced_items e JOIN shields s ON s.item_id = e.base_item_id;
+
+INSERT INTO ranged_weapons (weapon_type, rate_of_fire, range_short, range_medium, range_long, damage, enhancement_bonus, item_id)
+SELECT r.weapon_type, r.rate_of_fire, r.range_short, r.range_medium, r.range_long, r.damage, e.bonus, e.item_id
+FROM enhanced_items e JOIN ranged_weapons r ON r.item_id = e.base_item_id;
+
+INSERT INTO ammunition (quantity, enhancement_bonus, item_id)
+SELECT a.quantity, e.bonus, e.item_id
+FROM enhanced_items e JOIN ammunition a ON a.item_id = e.base_item_id;
+
+UPDATE character_inventory
+SET item_id = e.item_id
+FROM enhanced_items e
+WHERE e.base_item_id = character_inventory.item_id
+    AND e.bonus = character_inventory.enhancement_bonus;
+
+DROP TABLE enhanced_items;
+
+-- Named properties had nowhere of their own, so they go in the notes
+UPDATE character_inventory
+SET notes = COALESCE(notes || '; ', '') || p.names
+FROM (
+    SELECT inventory_id, group_concat(name, ', ') AS names
+    FROM character_inventory_properties
+    GROUP BY inventory_id
+) p
+WHERE p.inventory_id = character_inventory.id;
+
+DROP INDEX IF EXISTS idx_character_inventory_properties_inventory_id;
+DROP TABLE IF EXISTS character_inventory_properties;
+
+ALTER TABLE character_inventory DROP COLUMN enhancement_bonus;
@@ -0,0 +1,94 @@
+-- +goose Up
+-- Contents of a weight-reducing container, such as a bag of holding,
+-- count at this fraction of their weight
+ALTER TABLE containers ADD COLUMN weight_multiplier REAL NOT NULL DEFAULT 1.0;
+
+-- Tags that containers can restrict their contents to
+INSERT INTO item_tags (item_id, tag)
+SELECT id, 'bow' FROM items WHERE item_type = 'ranged_weapon' AND name GLOB 'Bow,*';
+
+INSERT INTO item_tags (item_id, tag)
+SELECT id, 'arrow' FROM items WHERE item_type = 'ammunition' AND name GLOB 'Arrow*';
+
+INSERT INTO item_tags (item_id, tag)
+SELECT id, 'bolt' FROM items WHERE item_type = 'ammunition' AND name GLOB 'Bolt*';
+
+INSERT INTO item_tags (item_id, tag)
+SELECT id, 'sling_bullet' FROM items WHERE item_type = 'ammunition' AND name GLOB 'Bullet*';
+
+INSERT INTO items (name, description, weight, value, item_type)
+VALUES
+    ('Quiver', 'Holds up to 20 arrows', 1, 1, 'container'),
+    ('Bolt Case', 'Holds up to 20 crossbow bolts', 1, 1, 'container'),
+    ('Bag of Holding', 'Holds 250 lbs; its contents add nothing to its weight', 15, 5000, 'container');
+
+INSERT INTO containers (base_item_id, capacity_weight, capacity_items, container_type, weight_multiplier)
+SELECT
+    id,
+    CASE name
+        WHEN 'Quiver' THEN 3
+        WHEN 'Bolt Case' THEN 5
+        ELSE 250
+    END,
+    CASE name
+        WHEN 'Bag of Holding' THEN NULL
+        ELSE 20
+    END,
+    CASE name
+        WHEN 'Quiver' THEN 'quiver'
+        WHEN 'Bolt Case' THEN 'case'
+        ELSE 'bag'
+    END,
+    CASE name
+        WHEN 'Bag of Holding' THEN 0
+        ELSE 1
+    END
+FROM
+    items
+WHERE
+    item_type = 'container'
+    AND name IN ('Quiver', 'Bolt Case', 'Bag of Holding');
+
+INSERT INTO container_allowed_tags (container_id, tag)
+SELECT
+    c.id,
+    CASE i.name
+        WHEN 'Bow Case' THEN 'bow'
+        WHEN 'Quiver' THEN 'arrow'
+        ELSE 'bolt'
+    END
+FROM
+    containers c
+    JOIN items i ON i.id = c.base_item_id
+WHERE
+    i.name IN ('Bow Case', 'Quiver', 'Bolt Case');
+
+-- +goose Down
+DELETE FROM container_allowed_tags;
+
+DELETE FROM containers
+WHERE base_item_id IN (
+    SELECT id FROM items
+    WHERE item_type = 'container' AND name IN ('Quiver', 'Bolt Case', 'Bag of Holding')
+);
+
+UPDATE character_inventory
+SET container_id = NULL
+WHERE container_id IN (
+    SELECT ci.id FROM character_inventory ci
+    JOIN items i ON i.id = ci.item_id
+    WHERE i.item_type = 'container' AND i.name IN ('Quiver', 'Bolt Case', 'Bag of Holding')
+);
+
+DELETE FROM character_inventory
+WHERE item_id IN (
+    SELECT id FROM items
+    WHERE item_type = 'container' AND name IN ('Quiver', 'Bolt Case', 'Bag of Holding')
+);
+
+DELETE FROM items
+WHERE item_type = 'container' AND name IN ('Quiver', 'Bolt Case', 'Bag of Holding');
+
+DELETE FROM item_tags WHERE tag IN ('bow', 'arrow', 'bolt', 'sling_bullet');
+
+ALTER TABLE containers DROP COLUMN weight_multiplier;
@@ -0,0 +1,108 @@
+-- +goose Up
+-- Slots are grouped so rules can say "a hand" or "a ring finger" rather
+-- than naming each slot
+ALTER TABLE equipment_slots ADD COLUMN slot_group TEXT NOT NULL DEFAULT '';
+
+UPDATE equipment_slots
+SET slot_group = CASE
+    WHEN name IN ('right_hand', 'left_hand') THEN 'hand'
+    WHEN name GLOB '*_ring_*' THEN 'ring'
+    ELSE name
+END;
+
+CREATE INDEX idx_equipment_slots_slot_group ON equipment_slots (slot_group);
+
+-- Two-handed items fill both hands when equipped
+ALTER TABLE items ADD COLUMN two_handed BOOLEAN NOT NULL DEFAULT 0;
+
+UPDATE items
+SET two_handed = 1
+WHERE id IN (
+    SELECT w.item_id
+    FROM weapons w
+    JOIN weapon_property_links l ON l.weapon_id = w.id
+    JOIN weapon_properties p ON p.id = l.property_id
+    WHERE p.symbol = '+'
+);
+
+UPDATE items
+SET two_handed = 1
+WHERE item_type = 'ranged_weapon'
+    AND (name GLOB 'Bow,*' OR name GLOB 'Crossbow,*');
+
+-- Which slot groups an item may be equipped to. Rules for a catalog row
+-- replace the rules for its item type.
+CREATE TABLE equipment_slot_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    item_type TEXT,
+    item_id INTEGER,
+    slot_group TEXT NOT NULL,
+    FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE,
+    CHECK ((item_type IS NULL) <> (item_id IS NULL))
+);
+
+CREATE INDEX idx_equipment_slot_rules_item_type ON equipment_slot_rules (item_type);
+CREATE INDEX idx_equipment_slot_rules_item_id ON equipment_slot_rules (item_id);
+
+INSERT INTO equipment_slot_rules (item_type, slot_group)
+VALUES
+    ('weapon', 'hand'),
+    ('ranged_weapon', 'hand'),
+    ('shield', 'hand'),
+    ('armor', 'body'),
+    ('equipment', 'hand'),
+    ('magical_item', 'hand'),
+    ('container', 'back'),
+    ('container', 'waist');
+
+INSERT INTO equipment_slot_rules (item_id, slot_group)
+SELECT
+    id,
+    CASE
+        WHEN name = 'Helmet' OR name GLOB 'Hat,*' OR name GLOB 'Mask,*' THEN 'head'
+        WHEN name GLOB 'Holy Symbol,*' OR name GLOB 'Prayer Beads,*' THEN 'neck'
+        WHEN name GLOB 'Cape*' OR name GLOB 'Cloak*' THEN 'back'
+        WHEN name = 'Belt' THEN 'waist'
+        WHEN name GLOB 'Leggings*' THEN 'legs'
+        WHEN name GLOB 'Boots*' OR name IN ('Shoes', 'Sandals') THEN 'feet'
+        WHEN name GLOB 'Robe*' THEN 'body'
+        WHEN name = 'Ring, Signet' THEN 'ring'
+    END
+FROM
+    items
+WHERE
+    item_type = 'equipment'
+    AND (
+        name = 'Helmet' OR name GLOB 'Hat,*' OR name GLOB 'Mask,*'
+        OR name GLOB 'Holy Symbol,*' OR name GLOB 'Prayer Beads,*'
+        OR name GLOB 'Cape*' OR name GLOB 'Cloak*'
+        OR name = 'Belt' OR name GLOB 'Leggings*'
+        OR name GLOB 'Boots*' OR name IN ('Shoes', 'Sandals')
+        OR name GLOB 'Robe*' OR name = 'Ring, Signet'
+    );
+
+-- Items already equipped somewhere they can't go are taken off
+UPDATE character_inventory
+SET equipment_slot_id = NULL
+WHERE equipment_slot_id IS NOT NULL
+    AND NOT EXISTS (
+        SELECT 1
+        FROM items i
+        JOIN equipment_slots es ON es.id = character_inventory.equipment_slot_id
+        JOIN equipment_slot_rules r ON r.slot_group = es.slot_group
+        WHERE i.id = character_inventory.item_id
+            AND (
+                r.item_id = i.id
+                OR (r.item_type = i.item_type AND NOT EXISTS (SELECT 1 FROM equipment_slot_rules ir WHERE ir.item_id = i.id))
+            )
+    );
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_equipment_slot_rules_item_id;
+DROP INDEX IF EXISTS idx_equipment_slot_rules_item_type;
+DROP TABLE IF EXISTS equipment_slot_rules;
+
+ALTER TABLE items DROP COLUMN two_handed;
+
+DROP INDEX IF EXISTS idx_equipment_slots_slot_group;
+ALTER TABLE equipment_slots DROP COLUMN slot_group;
@@ -0,0 +1,75 @@
+-- +goose Up
+-- The item tag of the missiles a launcher shoots
+ALTER TABLE ranged_weapons ADD COLUMN ammunition_tag TEXT;
+
+UPDATE ranged_weapons
+SET ammunition_tag = CASE
+    WHEN i.name GLOB 'Bow,*' THEN 'arrow'
+    WHEN i.name GLOB 'Crossbow,*' THEN 'bolt'
+    ELSE 'sling_bullet'
+END
+FROM items i
+WHERE i.id = ranged_weapons.item_id
+    AND (i.name GLOB 'Bow,*' OR i.name GLOB 'Crossbow,*' OR i.name = 'Sling');
+
+-- Slingers need somewhere ready to draw bullets from, as archers have quivers
+INSERT INTO items (name, description, weight, value, item_type)
+VALUES ('Sling Pouch', 'Holds up to 20 sling bullets', 0.5, 1, 'container');
+
+INSERT INTO containers (base_item_id, capacity_weight, capacity_items, container_type)
+SELECT id, 5, 20, 'pouch' FROM items WHERE item_type = 'container' AND name = 'Sling Pouch';
+
+INSERT INTO container_allowed_tags (container_id, tag)
+SELECT c.id, 'sling_bullet'
+FROM containers c JOIN items i ON i.id = c.base_item_id
+WHERE i.name = 'Sling Pouch';
+
+-- Ammunition is bought in bundles (ammunition.quantity missiles each).
+-- Missiles shot from the first bundle of a stack are counted here until the
+-- whole bundle is gone.
+ALTER TABLE character_inventory ADD COLUMN missiles_used INTEGER NOT NULL DEFAULT 0;
+
+-- Missiles shot since the character last tried to recover them
+CREATE TABLE missiles_fired (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    inventory_id INTEGER,
+    item_id INTEGER NOT NULL,
+    enhancement_bonus INTEGER NOT NULL DEFAULT 0,
+    count INTEGER NOT NULL CHECK (count > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items (id)
+);
+
+CREATE INDEX idx_missiles_fired_character_id ON missiles_fired (character_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_missiles_fired_character_id;
+DROP TABLE IF EXISTS missiles_fired;
+
+ALTER TABLE character_inventory DROP COLUMN missiles_used;
+
+UPDATE character_inventory
+SET container_id = NULL
+WHERE container_id IN (
+    SELECT ci.id FROM character_inventory ci
+    JOIN items i ON i.id = ci.item_id
+    WHERE i.item_type = 'container' AND i.name = 'Sling Pouch'
+);
+
+DELETE FROM character_inventory
+WHERE item_id IN (SELECT id FROM items WHERE item_type = 'container' AND name = 'Sling Pouch');
+
+DELETE FROM container_allowed_tags
+WHERE container_id IN (
+    SELECT c.id FROM containers c JOIN items i ON i.id = c.base_item_id
+    WHERE i.name = 'Sling Pouch'
+);
+
+DELETE FROM containers
+WHERE base_item_id IN (SELECT id FROM items WHERE item_type = 'container' AND name = 'Sling Pouch');
+
+DELETE FROM items WHERE item_type = 'container' AND name = 'Sling Pouch';
+
+ALTER TABLE ranged_weapons DROP COLUMN ammunition_tag;
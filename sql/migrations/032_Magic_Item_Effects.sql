@@ -0,0 +1,136 @@
+-- +goose Up
+-- What a magical item does, in place of free text. An item may have several
+-- effects; passive bonuses apply while it is equipped and the rest when it
+-- is used.
+CREATE TABLE magical_item_effects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    magical_item_id INTEGER NOT NULL,
+    effect_type TEXT NOT NULL CHECK (effect_type IN ('heal', 'cast_spell', 'condition', 'ability_modifier', 'passive_bonus')),
+    dice TEXT,
+    spell_id INTEGER,
+    condition_name TEXT,
+    ability TEXT CHECK (ability IS NULL OR ability IN ('strength', 'dexterity', 'constitution', 'intelligence', 'wisdom', 'charisma')),
+    bonus_target TEXT CHECK (bonus_target IS NULL OR bonus_target IN ('armor_class', 'saving_throw')),
+    modifier INTEGER NOT NULL DEFAULT 0,
+    duration_hours INTEGER,
+    FOREIGN KEY (magical_item_id) REFERENCES magical_items (id) ON DELETE CASCADE,
+    FOREIGN KEY (spell_id) REFERENCES spells (id),
+    CHECK (effect_type <> 'heal' OR dice IS NOT NULL),
+    CHECK (effect_type <> 'cast_spell' OR spell_id IS NOT NULL),
+    CHECK (effect_type <> 'condition' OR condition_name IS NOT NULL),
+    CHECK (effect_type <> 'ability_modifier' OR ability IS NOT NULL),
+    CHECK (effect_type <> 'passive_bonus' OR bonus_target IS NOT NULL)
+);
+
+CREATE INDEX idx_magical_item_effects_magical_item_id ON magical_item_effects (magical_item_id);
+
+-- Effects from used items that last a while. Conditions also get a
+-- character_conditions row, removed when the effect runs out. A null expiry
+-- lasts until removed by hand.
+CREATE TABLE character_item_effects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    source_name TEXT NOT NULL,
+    effect_type TEXT NOT NULL CHECK (effect_type IN ('condition', 'ability_modifier')),
+    ability TEXT,
+    modifier INTEGER NOT NULL DEFAULT 0,
+    condition_id INTEGER,
+    expires_at_hour INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE,
+    FOREIGN KEY (condition_id) REFERENCES character_conditions (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_character_item_effects_character_id ON character_item_effects (character_id);
+
+-- A spell for wands to cast
+INSERT OR IGNORE INTO spells (name, description, range, duration)
+VALUES ('Magic Missile', 'A missile of magical energy unerringly strikes a target for 1d4+1 damage.', '150 feet', 'Instantaneous');
+
+INSERT OR IGNORE INTO spell_levels (spell_id, class, level)
+SELECT id, 'Magician', 1 FROM spells WHERE name = 'Magic Missile';
+
+INSERT INTO items (name, description, weight, value, item_type)
+VALUES
+    ('Potion of Healing', 'Heals 1d8 hit points', 0.5, 400, 'magical_item'),
+    ('Potion of Extra-Healing', 'Heals 3d8 hit points', 0.5, 800, 'magical_item'),
+    ('Potion of Heroism', 'Fills the drinker with courage for an hour', 0.5, 500, 'magical_item'),
+    ('Potion of Giant Strength', 'Strength +4 for an hour', 0.5, 1000, 'magical_item'),
+    ('Wand of Magic Missiles', 'Casts magic missile', 1, 4000, 'magical_item'),
+    ('Ring of Protection +1', '+1 to armour class and saving throws while worn', 0, 2000, 'magical_item');
+
+INSERT INTO magical_items (item_id, max_charges, category, effect_description)
+SELECT
+    id,
+    CASE name WHEN 'Wand of Magic Missiles' THEN 20 ELSE 1 END,
+    CASE
+        WHEN name GLOB 'Potion*' THEN 'potion'
+        WHEN name GLOB 'Wand*' THEN 'wand'
+        ELSE 'other'
+    END,
+    description
+FROM
+    items
+WHERE
+    item_type = 'magical_item'
+    AND name IN ('Potion of Healing', 'Potion of Extra-Healing', 'Potion of Heroism',
+        'Potion of Giant Strength', 'Wand of Magic Missiles', 'Ring of Protection +1');
+
+INSERT INTO magical_item_effects (magical_item_id, effect_type, dice, spell_id, condition_name, ability, bonus_target, modifier, duration_hours)
+SELECT m.id, e.effect_type, e.dice, s.id, e.condition_name, e.ability, e.bonus_target, e.modifier, e.duration_hours
+FROM
+    (
+        SELECT 'Potion of Healing' AS item, 'heal' AS effect_type, '1d8' AS dice, NULL AS spell, NULL AS condition_name,
+            NULL AS ability, NULL AS bonus_target, 0 AS modifier, NULL AS duration_hours
+        UNION ALL SELECT 'Potion of Extra-Healing', 'heal', '3d8', NULL, NULL, NULL, NULL, 0, NULL
+        UNION ALL SELECT 'Potion of Heroism', 'condition', NULL, NULL, 'Heroism', NULL, NULL, 0, 1
+        UNION ALL SELECT 'Potion of Giant Strength', 'ability_modifier', NULL, NULL, NULL, 'strength', NULL, 4, 1
+        UNION ALL SELECT 'Wand of Magic Missiles', 'cast_spell', NULL, 'Magic Missile', NULL, NULL, NULL, 0, NULL
+        UNION ALL SELECT 'Ring of Protection +1', 'passive_bonus', NULL, NULL, NULL, NULL, 'armor_class', 1, NULL
+        UNION ALL SELECT 'Ring of Protection +1', 'passive_bonus', NULL, NULL, NULL, NULL, 'saving_throw', 1, NULL
+    ) e
+    JOIN items i ON i.name = e.item AND i.item_type = 'magical_item'
+    JOIN magical_items m ON m.item_id = i.id
+    LEFT JOIN spells s ON s.name = e.spell;
+
+-- Magical items added without charges get their full charges
+UPDATE character_inventory
+SET
+    charges = (SELECT m.max_charges FROM magical_items m WHERE m.item_id = character_inventory.item_id)
+WHERE
+    charges IS NULL
+    AND item_id IN (SELECT item_id FROM magical_items);
+
+-- Rings go on fingers, not in a hand
+INSERT INTO equipment_slot_rules (item_id, slot_group)
+SELECT id, 'ring' FROM items WHERE item_type = 'magical_item' AND name = 'Ring of Protection +1';
+
+-- +goose Down
+DELETE FROM equipment_slot_rules
+WHERE item_id IN (SELECT id FROM items WHERE item_type = 'magical_item' AND name = 'Ring of Protection +1');
+
+DROP INDEX IF EXISTS idx_character_item_effects_character_id;
+DROP TABLE IF EXISTS character_item_effects;
+DROP INDEX IF EXISTS idx_magical_item_effects_magical_item_id;
+DROP TABLE IF EXISTS magical_item_effects;
+
+DELETE FROM character_inventory
+WHERE item_id IN (
+    SELECT id FROM items
+    WHERE item_type = 'magical_item'
+        AND name IN ('Potion of Healing', 'Potion of Extra-Healing', 'Potion of Heroism',
+            'Potion of Giant Strength', 'Wand of Magic Missiles', 'Ring of Protection +1')
+);
+
+DELETE FROM magical_items
+WHERE item_id IN (
+    SELECT id FROM items
+    WHERE item_type = 'magical_item'
+        AND name IN ('Potion of Healing', 'Potion of Extra-Healing', 'Potion of Heroism',
+            'Potion of Giant Strength', 'Wand of Magic Missiles', 'Ring of Protection +1')
+);
+
+DELETE FROM items
+WHERE item_type = 'magical_item'
+    AND name IN ('Potion of Healing', 'Potion of Extra-Healing', 'Potion of Heroism',
+        'Potion of Giant Strength', 'Wand of Magic Missiles', 'Ring of Protection +1');
@@ -0,0 +1,208 @@
+-- +goose Up
+-- Magical item categories, including ones worn for a passive benefit.
+-- Worn categories name the slot group they go in, and may cap how many
+-- items of the category function at once.
+CREATE TABLE magical_item_categories (
+    name TEXT PRIMARY KEY,
+    slot_group TEXT,
+    max_functioning INTEGER CHECK (max_functioning IS NULL OR max_functioning >= 0),
+    description TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO magical_item_categories (name, slot_group, max_functioning, description)
+VALUES
+    ('wand', NULL, NULL, 'Wands, used for their charges'),
+    ('potion', NULL, NULL, 'Potions, gone once drunk'),
+    ('rod', NULL, NULL, 'Rods and staves'),
+    ('scroll', NULL, NULL, 'Scrolls, gone once read'),
+    ('other', NULL, NULL, 'Anything else'),
+    ('ring', 'ring', 2, 'Magic rings; only two function at once'),
+    ('amulet', 'neck', 1, 'Amulets, necklaces and periapts'),
+    ('cloak', 'back', 1, 'Cloaks and capes'),
+    ('belt', 'waist', 1, 'Belts and girdles'),
+    ('boots', 'feet', 1, 'Boots and slippers'),
+    ('helm', 'head', 1, 'Helms, hats and circlets');
+
+-- Rebuilt to swap the fixed category list for the categories table
+CREATE TABLE magical_items_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    max_charges INTEGER NOT NULL DEFAULT 1,
+    category TEXT NOT NULL REFERENCES magical_item_categories (name),
+    effect_description TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    item_id INTEGER REFERENCES items (id) ON DELETE CASCADE
+);
+
+INSERT INTO magical_items_new (id, max_charges, category, effect_description, created_at, updated_at, item_id)
+SELECT id, max_charges, category, effect_description, created_at, updated_at, item_id FROM magical_items;
+
+DROP INDEX IF EXISTS idx_magical_items_item_id;
+DROP TABLE magical_items;
+ALTER TABLE magical_items_new RENAME TO magical_items;
+CREATE UNIQUE INDEX idx_magical_items_item_id ON magical_items (item_id);
+
+-- Passive bonuses may also raise movement or an ability score
+CREATE TABLE magical_item_effects_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    magical_item_id INTEGER NOT NULL,
+    effect_type TEXT NOT NULL CHECK (effect_type IN ('heal', 'cast_spell', 'condition', 'ability_modifier', 'passive_bonus')),
+    dice TEXT,
+    spell_id INTEGER,
+    condition_name TEXT,
+    ability TEXT CHECK (ability IS NULL OR ability IN ('strength', 'dexterity', 'constitution', 'intelligence', 'wisdom', 'charisma')),
+    bonus_target TEXT CHECK (bonus_target IS NULL OR bonus_target IN ('armor_class', 'saving_throw', 'movement', 'ability')),
+    modifier INTEGER NOT NULL DEFAULT 0,
+    duration_hours INTEGER,
+    FOREIGN KEY (magical_item_id) REFERENCES magical_items (id) ON DELETE CASCADE,
+    FOREIGN KEY (spell_id) REFERENCES spells (id),
+    CHECK (effect_type <> 'heal' OR dice IS NOT NULL),
+    CHECK (effect_type <> 'cast_spell' OR spell_id IS NOT NULL),
+    CHECK (effect_type <> 'condition' OR condition_name IS NOT NULL),
+    CHECK (effect_type <> 'ability_modifier' OR ability IS NOT NULL),
+    CHECK (effect_type <> 'passive_bonus' OR bonus_target IS NOT NULL),
+    CHECK (bonus_target IS NOT 'ability' OR ability IS NOT NULL)
+);
+
+INSERT INTO magical_item_effects_new
+SELECT id, magical_item_id, effect_type, dice, spell_id, condition_name, ability, bonus_target, modifier, duration_hours
+FROM magical_item_effects;
+
+DROP INDEX IF EXISTS idx_magical_item_effects_magical_item_id;
+DROP TABLE magical_item_effects;
+ALTER TABLE magical_item_effects_new RENAME TO magical_item_effects;
+CREATE INDEX idx_magical_item_effects_magical_item_id ON magical_item_effects (magical_item_id);
+
+-- The ring now gets its slot from its category
+UPDATE magical_items
+SET category = 'ring'
+WHERE item_id IN (SELECT id FROM items WHERE item_type = 'magical_item' AND name = 'Ring of Protection +1');
+
+DELETE FROM equipment_slot_rules
+WHERE item_id IN (SELECT id FROM items WHERE item_type = 'magical_item' AND name = 'Ring of Protection +1');
+
+INSERT INTO items (name, description, weight, value, item_type)
+VALUES
+    ('Ring of Protection +2', '+2 to armour class and saving throws while worn', 0, 4000, 'magical_item'),
+    ('Cloak of Protection +1', '+1 to armour class and saving throws while worn', 1, 2500, 'magical_item'),
+    ('Amulet of Warding', '+2 to saving throws while worn', 0, 3000, 'magical_item'),
+    ('Belt of Strength', 'Strength +2 while worn', 1, 3500, 'magical_item'),
+    ('Boots of Striding', 'Movement +10 feet while worn', 1, 2000, 'magical_item');
+
+INSERT INTO magical_items (item_id, max_charges, category, effect_description)
+SELECT
+    id,
+    1,
+    CASE
+        WHEN name GLOB 'Ring*' THEN 'ring'
+        WHEN name GLOB 'Cloak*' THEN 'cloak'
+        WHEN name GLOB 'Amulet*' THEN 'amulet'
+        WHEN name GLOB 'Belt*' THEN 'belt'
+        WHEN name GLOB 'Boots*' THEN 'boots'
+    END,
+    description
+FROM
+    items
+WHERE
+    item_type = 'magical_item'
+    AND name IN ('Ring of Protection +2', 'Cloak of Protection +1', 'Amulet of Warding', 'Belt of Strength', 'Boots of Striding');
+
+INSERT INTO magical_item_effects (magical_item_id, effect_type, ability, bonus_target, modifier)
+SELECT m.id, 'passive_bonus', e.ability, e.bonus_target, e.modifier
+FROM
+    (
+        SELECT 'Ring of Protection +2' AS item, NULL AS ability, 'armor_class' AS bonus_target, 2 AS modifier
+        UNION ALL SELECT 'Ring of Protection +2', NULL, 'saving_throw', 2
+        UNION ALL SELECT 'Cloak of Protection +1', NULL, 'armor_class', 1
+        UNION ALL SELECT 'Cloak of Protection +1', NULL, 'saving_throw', 1
+        UNION ALL SELECT 'Amulet of Warding', NULL, 'saving_throw', 2
+        UNION ALL SELECT 'Belt of Strength', 'strength', 'ability', 2
+        UNION ALL SELECT 'Boots of Striding', NULL, 'movement', 10
+    ) e
+    JOIN items i ON i.name = e.item AND i.item_type = 'magical_item'
+    JOIN magical_items m ON m.item_id = i.id;
+
+-- +goose Down
+DELETE FROM character_inventory
+WHERE item_id IN (
+    SELECT id FROM items
+    WHERE item_type = 'magical_item'
+        AND name IN ('Ring of Protection +2', 'Cloak of Protection +1', 'Amulet of Warding', 'Belt of Strength', 'Boots of Striding')
+);
+
+DELETE FROM magical_item_effects
+WHERE magical_item_id IN (
+    SELECT m.id FROM magical_items m JOIN items i ON i.id = m.item_id
+    WHERE i.name IN ('Ring of Protection +2', 'Cloak of Protection +1', 'Amulet of Warding', 'Belt of Strength', 'Boots of Striding')
+);
+
+DELETE FROM magical_items
+WHERE item_id IN (
+    SELECT id FROM items
+    WHERE item_type = 'magical_item'
+        AND name IN ('Ring of Protection +2', 'Cloak of Protection +1', 'Amulet of Warding', 'Belt of Strength', 'Boots of Striding')
+);
+
+DELETE FROM items
+WHERE item_type = 'magical_item'
+    AND name IN ('Ring of Protection +2', 'Cloak of Protection +1', 'Amulet of Warding', 'Belt of Strength', 'Boots of Striding');
+
+INSERT INTO equipment_slot_rules (item_id, slot_group)
+SELECT item_id, 'ring' FROM magical_items WHERE category = 'ring';
+
+DELETE FROM magical_item_effects WHERE bonus_target IN ('movement', 'ability');
+
+CREATE TABLE magical_item_effects_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    magical_item_id INTEGER NOT NULL,
+    effect_type TEXT NOT NULL CHECK (effect_type IN ('heal', 'cast_spell', 'condition', 'ability_modifier', 'passive_bonus')),
+    dice TEXT,
+    spell_id INTEGER,
+    condition_name TEXT,
+    ability TEXT CHECK (ability IS NULL OR ability IN ('strength', 'dexterity', 'constitution', 'intelligence', 'wisdom', 'charisma')),
+    bonus_target TEXT CHECK (bonus_target IS NULL OR bonus_target IN ('armor_class', 'saving_throw')),
+    modifier INTEGER NOT NULL DEFAULT 0,
+    duration_hours INTEGER,
+    FOREIGN KEY (magical_item_id) REFERENCES magical_items (id) ON DELETE CASCADE,
+    FOREIGN KEY (spell_id) REFERENCES spells (id),
+    CHECK (effect_type <> 'heal' OR dice IS NOT NULL),
+    CHECK (effect_type <> 'cast_spell' OR spell_id IS NOT NULL),
+    CHECK (effect_type <> 'condition' OR condition_name IS NOT NULL),
+    CHECK (effect_type <> 'ability_modifier' OR ability IS NOT NULL),
+    CHECK (effect_type <> 'passive_bonus' OR bonus_target IS NOT NULL)
+);
+
+INSERT INTO magical_item_effects_old SELECT * FROM magical_item_effects;
+DROP INDEX IF EXISTS idx_magical_item_effects_magical_item_id;
+DROP TABLE magical_item_effects;
+ALTER TABLE magical_item_effects_old RENAME TO magical_item_effects;
+CREATE INDEX idx_magical_item_effects_magical_item_id ON magical_item_effects (magical_item_id);
+
+CREATE TABLE magical_items_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    max_charges INTEGER NOT NULL DEFAULT 1,
+    category TEXT NOT NULL CHECK (category IN ('wand', 'potion', 'rod', 'scroll', 'other')),
+    effect_description TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    item_id INTEGER REFERENCES items (id) ON DELETE CASCADE
+);
+
+INSERT INTO magical_items_old (id, max_charges, category, effect_description, created_at, updated_at, item_id)
+SELECT
+    id,
+    max_charges,
+    CASE WHEN category IN ('wand', 'potion', 'rod', 'scroll') THEN category ELSE 'other' END,
+    effect_description,
+    created_at,
+    updated_at,
+    item_id
+FROM
+    magical_items;
+
+DROP INDEX IF EXISTS idx_magical_items_item_id;
+DROP TABLE magical_items;
+ALTER TABLE magical_items_old RENAME TO magical_items;
+CREATE UNIQUE INDEX idx_magical_items_item_id ON magical_items (item_id);
+
+DROP TABLE IF EXISTS magical_item_categories;
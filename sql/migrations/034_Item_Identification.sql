@@ -0,0 +1,45 @@
+-- +goose Up
+-- What an item looks like before it is identified. Items without one are
+-- known by their catalog name, less any bonus.
+ALTER TABLE items ADD COLUMN appearance TEXT;
+
+-- How an owned item was identified: spell, sage, use or referee
+ALTER TABLE character_inventory ADD COLUMN identified_by TEXT;
+
+UPDATE items
+SET appearance = CASE name
+    WHEN 'Potion of Healing' THEN 'a blue glass vial'
+    WHEN 'Potion of Extra-Healing' THEN 'a tall vial of cloudy blue liquid'
+    WHEN 'Potion of Heroism' THEN 'a flask of red liquid'
+    WHEN 'Potion of Giant Strength' THEN 'a stone flask of thick brown liquid'
+    WHEN 'Wand of Magic Missiles' THEN 'a slender ivory wand'
+    WHEN 'Ring of Protection +1' THEN 'a plain silver ring'
+    WHEN 'Ring of Protection +2' THEN 'a silver ring set with a moonstone'
+    WHEN 'Cloak of Protection +1' THEN 'a grey wool cloak'
+    WHEN 'Amulet of Warding' THEN 'a bronze amulet'
+    WHEN 'Belt of Strength' THEN 'a wide leather belt'
+    WHEN 'Boots of Striding' THEN 'a pair of soft leather boots'
+END
+WHERE
+    item_type = 'magical_item'
+    AND name IN ('Potion of Healing', 'Potion of Extra-Healing', 'Potion of Heroism', 'Potion of Giant Strength',
+        'Wand of Magic Missiles', 'Ring of Protection +1', 'Ring of Protection +2', 'Cloak of Protection +1',
+        'Amulet of Warding', 'Belt of Strength', 'Boots of Striding');
+
+-- Other magical items at least look like what they are
+UPDATE items
+SET appearance = CASE
+    WHEN name GLOB 'Potion*' THEN 'a small vial'
+    WHEN name GLOB 'Scroll*' THEN 'a rolled scroll'
+    WHEN name GLOB 'Wand*' THEN 'a wand'
+    WHEN name GLOB 'Rod*' THEN 'a rod'
+    WHEN name GLOB 'Ring*' THEN 'a ring'
+    ELSE 'a curious object'
+END
+WHERE
+    item_type = 'magical_item'
+    AND appearance IS NULL;
+
+-- +goose Down
+ALTER TABLE character_inventory DROP COLUMN identified_by;
+ALTER TABLE items DROP COLUMN appearance;
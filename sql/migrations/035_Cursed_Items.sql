@@ -0,0 +1,17 @@
+-- +goose Up
+-- A curse lies on an owned item, not on its kind. Once a cursed item is
+-- equipped it won't come off until the curse is removed, and it may carry a
+-- penalty to armour class, saving throws, movement or an ability score on
+-- top of any negative enhancement bonus.
+ALTER TABLE character_inventory ADD COLUMN cursed BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE character_inventory ADD COLUMN curse_target TEXT CHECK (curse_target IS NULL OR curse_target IN ('armor_class', 'saving_throw', 'movement', 'ability'));
+ALTER TABLE character_inventory ADD COLUMN curse_ability TEXT CHECK (curse_ability IS NULL OR curse_ability IN ('strength', 'dexterity', 'constitution', 'intelligence', 'wisdom', 'charisma'));
+ALTER TABLE character_inventory ADD COLUMN curse_modifier INTEGER NOT NULL DEFAULT 0 CHECK (curse_modifier <= 0);
+ALTER TABLE character_inventory ADD COLUMN curse_notes TEXT;
+
+-- +goose Down
+ALTER TABLE character_inventory DROP COLUMN curse_notes;
+ALTER TABLE character_inventory DROP COLUMN curse_modifier;
+ALTER TABLE character_inventory DROP COLUMN curse_ability;
+ALTER TABLE character_inventory DROP COLUMN curse_target;
+ALTER TABLE character_inventory DROP COLUMN cursed;
@@ -0,0 +1,47 @@
+-- +goose Up
+-- The condition scale for owned items, best first. Damaged armour and
+-- shields protect less, broken items can't be used, and worse condition
+-- lowers what an item fetches when sold.
+CREATE TABLE item_conditions (
+    name TEXT PRIMARY KEY,
+    rank INTEGER NOT NULL UNIQUE,
+    value_percent INTEGER NOT NULL CHECK (value_percent BETWEEN 0 AND 100),
+    armor_class_penalty INTEGER NOT NULL DEFAULT 0 CHECK (armor_class_penalty >= 0),
+    usable BOOLEAN NOT NULL DEFAULT 1,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO item_conditions (name, rank, value_percent, armor_class_penalty, usable, description)
+VALUES
+    ('pristine', 0, 100, 0, 1, 'Unused, as it left the smith'),
+    ('good', 1, 90, 0, 1, 'Serviceable, with the usual scuffs'),
+    ('worn', 2, 75, 0, 1, 'Nicked, frayed or tarnished'),
+    ('damaged', 3, 50, 1, 1, 'Dented or cracked; armour and shields protect 1 point less'),
+    ('broken', 4, 10, 0, 0, 'Useless until repaired');
+
+-- Things that happen to gear. Each knocks an item down the scale by some
+-- steps; item types limits which items it can strike, empty for any.
+CREATE TABLE item_wear_events (
+    name TEXT PRIMARY KEY,
+    label TEXT NOT NULL,
+    steps INTEGER NOT NULL CHECK (steps > 0),
+    item_types TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO item_wear_events (name, label, steps, item_types, description)
+VALUES
+    ('fumble', 'Fumble', 1, 'weapon,ranged_weapon', 'A fumbled attack notches the blade or cracks the stave'),
+    ('crushing_blow', 'Crushing blow', 1, 'armor,shield', 'A heavy hit dents armour or splits a shield'),
+    ('acid', 'Acid', 2, '', 'Splashed or spat acid eats into the item'),
+    ('fire', 'Fire', 1, '', 'Scorched by flame or burning oil'),
+    ('hard_use', 'Hard use', 1, '', 'Weeks of travel and weather take their toll');
+
+-- Anything outside the scale is taken as good
+UPDATE character_inventory
+SET condition = 'good'
+WHERE condition NOT IN (SELECT name FROM item_conditions);
+
+-- +goose Down
+DROP TABLE IF EXISTS item_wear_events;
+DROP TABLE IF EXISTS item_conditions;
@@ -0,0 +1,42 @@
+-- +goose Up
+-- Price lists a referee keeps for the settlements of a campaign. Markup is
+-- a percentage of catalog value applied to everything bought there, and
+-- sell percent the share of an item's worth merchants pay for it.
+CREATE TABLE price_lists (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign_id INTEGER NOT NULL,
+    settlement TEXT NOT NULL,
+    markup_percent INTEGER NOT NULL DEFAULT 100 CHECK (markup_percent > 0),
+    sell_percent INTEGER NOT NULL DEFAULT 50 CHECK (sell_percent BETWEEN 0 AND 100),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (campaign_id, settlement),
+    FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON DELETE CASCADE
+);
+
+-- Per-item exceptions to a price list. Magical items are only for sale
+-- where a list says so; markup overrides the list's when set.
+CREATE TABLE price_list_items (
+    price_list_id INTEGER NOT NULL,
+    item_id INTEGER NOT NULL,
+    available BOOLEAN NOT NULL DEFAULT 1,
+    markup_percent INTEGER CHECK (markup_percent > 0),
+    PRIMARY KEY (price_list_id, item_id),
+    FOREIGN KEY (price_list_id) REFERENCES price_lists (id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE
+);
+
+-- Catalog items a character means to buy
+CREATE TABLE shop_cart_items (
+    character_id INTEGER NOT NULL,
+    item_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (character_id, item_id),
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS shop_cart_items;
+DROP TABLE IF EXISTS price_list_items;
+DROP TABLE IF EXISTS price_lists;
@@ -0,0 +1,123 @@
+-- +goose Up
+-- The coins table is the one place coin weight and value come from. Coins
+-- without a campaign are used everywhere; a campaign may add regional
+-- coins or trade bars of its own.
+CREATE TABLE coinage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign_id INTEGER,
+    denomination TEXT NOT NULL CHECK (denomination <> ''),
+    name TEXT NOT NULL,
+    weight_per_coin DECIMAL(10, 3) NOT NULL CHECK (weight_per_coin >= 0), -- Weight in pounds
+    base_value INTEGER NOT NULL CHECK (base_value > 0), -- Value in copper pieces
+    FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON DELETE CASCADE
+);
+
+INSERT INTO
+    coinage (denomination, name, weight_per_coin, base_value)
+SELECT
+    denomination,
+    name,
+    weight_per_coin,
+    base_value
+FROM
+    coins;
+
+DROP TABLE coins;
+
+ALTER TABLE coinage RENAME TO coins;
+
+CREATE UNIQUE INDEX idx_coins_denomination ON coins (COALESCE(campaign_id, 0), denomination);
+
+-- A character's coins, one row per denomination held
+CREATE TABLE character_coins (
+    character_id INTEGER NOT NULL,
+    denomination TEXT NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    PRIMARY KEY (character_id, denomination),
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE
+);
+
+INSERT INTO character_coins (character_id, denomination, quantity)
+SELECT id, 'pp', platinum_pieces FROM characters WHERE platinum_pieces > 0
+UNION ALL
+SELECT id, 'gp', gold_pieces FROM characters WHERE gold_pieces > 0
+UNION ALL
+SELECT id, 'ep', electrum_pieces FROM characters WHERE electrum_pieces > 0
+UNION ALL
+SELECT id, 'sp', silver_pieces FROM characters WHERE silver_pieces > 0
+UNION ALL
+SELECT id, 'cp', copper_pieces FROM characters WHERE copper_pieces > 0;
+
+ALTER TABLE characters DROP COLUMN platinum_pieces;
+ALTER TABLE characters DROP COLUMN gold_pieces;
+ALTER TABLE characters DROP COLUMN electrum_pieces;
+ALTER TABLE characters DROP COLUMN silver_pieces;
+ALTER TABLE characters DROP COLUMN copper_pieces;
+
+-- Treasure shares keep the coins handed out formatted for display, like a
+-- split's remainder, since a campaign's coins may change after the split
+ALTER TABLE treasure_split_shares ADD COLUMN coins TEXT NOT NULL DEFAULT '';
+
+UPDATE treasure_split_shares
+SET coins = COALESCE(NULLIF(SUBSTR(
+    CASE WHEN platinum_pieces > 0 THEN ', ' || platinum_pieces || ' pp' ELSE '' END ||
+    CASE WHEN gold_pieces > 0 THEN ', ' || gold_pieces || ' gp' ELSE '' END ||
+    CASE WHEN electrum_pieces > 0 THEN ', ' || electrum_pieces || ' ep' ELSE '' END ||
+    CASE WHEN silver_pieces > 0 THEN ', ' || silver_pieces || ' sp' ELSE '' END ||
+    CASE WHEN copper_pieces > 0 THEN ', ' || copper_pieces || ' cp' ELSE '' END,
+    3), ''), '0 cp');
+
+ALTER TABLE treasure_split_shares DROP COLUMN platinum_pieces;
+ALTER TABLE treasure_split_shares DROP COLUMN gold_pieces;
+ALTER TABLE treasure_split_shares DROP COLUMN electrum_pieces;
+ALTER TABLE treasure_split_shares DROP COLUMN silver_pieces;
+ALTER TABLE treasure_split_shares DROP COLUMN copper_pieces;
+
+-- +goose Down
+ALTER TABLE treasure_split_shares ADD COLUMN platinum_pieces INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE treasure_split_shares ADD COLUMN gold_pieces INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE treasure_split_shares ADD COLUMN electrum_pieces INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE treasure_split_shares ADD COLUMN silver_pieces INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE treasure_split_shares ADD COLUMN copper_pieces INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE treasure_split_shares DROP COLUMN coins;
+
+ALTER TABLE characters ADD COLUMN platinum_pieces INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE characters ADD COLUMN gold_pieces INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE characters ADD COLUMN electrum_pieces INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE characters ADD COLUMN silver_pieces INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE characters ADD COLUMN copper_pieces INTEGER NOT NULL DEFAULT 0;
+
+UPDATE characters
+SET
+    platinum_pieces = COALESCE((SELECT quantity FROM character_coins WHERE character_id = characters.id AND denomination = 'pp'), 0),
+    gold_pieces = COALESCE((SELECT quantity FROM character_coins WHERE character_id = characters.id AND denomination = 'gp'), 0),
+    electrum_pieces = COALESCE((SELECT quantity FROM character_coins WHERE character_id = characters.id AND denomination = 'ep'), 0),
+    silver_pieces = COALESCE((SELECT quantity FROM character_coins WHERE character_id = characters.id AND denomination = 'sp'), 0),
+    copper_pieces = COALESCE((SELECT quantity FROM character_coins WHERE character_id = characters.id AND denomination = 'cp'), 0);
+
+DROP TABLE IF EXISTS character_coins;
+
+DROP INDEX IF EXISTS idx_coins_denomination;
+
+CREATE TABLE coinage (
+    denomination TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    weight_per_coin DECIMAL(10, 3) NOT NULL, -- Weight in pounds
+    base_value INTEGER NOT NULL -- Value in copper pieces
+);
+
+INSERT INTO
+    coinage (denomination, name, weight_per_coin, base_value)
+SELECT
+    denomination,
+    name,
+    weight_per_coin,
+    base_value
+FROM
+    coins
+WHERE
+    campaign_id IS NULL;
+
+DROP TABLE coins;
+
+ALTER TABLE coinage RENAME TO coins;
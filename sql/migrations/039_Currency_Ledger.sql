@@ -0,0 +1,107 @@
+-- +goose Up
+-- Append-only ledger of coins entering and leaving purses. The sum of a
+-- character's entries for each denomination is the source of truth for
+-- character_coins.
+CREATE TABLE currency_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    -- Checked by the application against its list of ledger reasons, so a
+    -- new reason doesn't mean rebuilding the ledger
+    reason TEXT NOT NULL,
+    -- Catalog item bought or sold, or XP award made alongside
+    item_id INTEGER,
+    xp_award_id INTEGER,
+    actor_id INTEGER NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE SET NULL,
+    FOREIGN KEY (xp_award_id) REFERENCES xp_awards (id),
+    FOREIGN KEY (actor_id) REFERENCES users (id)
+);
+
+CREATE INDEX idx_currency_transactions_character_id ON currency_transactions (character_id);
+
+-- Coins moved by a transaction, negative for coins paid out
+CREATE TABLE currency_transaction_coins (
+    transaction_id INTEGER NOT NULL,
+    denomination TEXT NOT NULL,
+    amount INTEGER NOT NULL CHECK (amount <> 0),
+    PRIMARY KEY (transaction_id, denomination),
+    FOREIGN KEY (transaction_id) REFERENCES currency_transactions (id) ON DELETE CASCADE
+);
+
+-- +goose StatementBegin
+CREATE TRIGGER currency_transactions_append_only BEFORE UPDATE ON currency_transactions
+BEGIN
+    SELECT RAISE(ABORT, 'currency transactions are append-only');
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER currency_transaction_coins_append_only BEFORE UPDATE ON currency_transaction_coins
+BEGIN
+    SELECT RAISE(ABORT, 'currency transactions are append-only');
+END;
+-- +goose StatementEnd
+
+-- Entries go only with their character, when deleting the character
+-- cascades to its ledger
+-- +goose StatementBegin
+CREATE TRIGGER currency_transactions_no_delete BEFORE DELETE ON currency_transactions
+WHEN EXISTS (SELECT 1 FROM characters WHERE id = OLD.character_id)
+BEGIN
+    SELECT RAISE(ABORT, 'currency transactions are append-only');
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER currency_transaction_coins_no_delete BEFORE DELETE ON currency_transaction_coins
+WHEN EXISTS (SELECT 1 FROM currency_transactions WHERE id = OLD.transaction_id)
+BEGIN
+    SELECT RAISE(ABORT, 'currency transactions are append-only');
+END;
+-- +goose StatementEnd
+
+-- Carry existing purses across as an opening balance
+INSERT INTO
+    currency_transactions (character_id, reason, actor_id, notes)
+SELECT
+    c.id,
+    'opening',
+    c.user_id,
+    'Opening balance'
+FROM
+    characters c
+WHERE
+    EXISTS (
+        SELECT
+            1
+        FROM
+            character_coins cc
+        WHERE
+            cc.character_id = c.id
+            AND cc.quantity > 0
+    );
+
+INSERT INTO
+    currency_transaction_coins (transaction_id, denomination, amount)
+SELECT
+    ct.id,
+    cc.denomination,
+    cc.quantity
+FROM
+    currency_transactions ct
+    JOIN character_coins cc ON cc.character_id = ct.character_id
+WHERE
+    ct.reason = 'opening'
+    AND cc.quantity > 0;
+
+-- +goose Down
+DROP TRIGGER IF EXISTS currency_transaction_coins_no_delete;
+DROP TRIGGER IF EXISTS currency_transactions_no_delete;
+DROP TRIGGER IF EXISTS currency_transaction_coins_append_only;
+DROP TRIGGER IF EXISTS currency_transactions_append_only;
+DROP TABLE IF EXISTS currency_transaction_coins;
+DROP INDEX IF EXISTS idx_currency_transactions_character_id;
+DROP TABLE IF EXISTS currency_transactions;
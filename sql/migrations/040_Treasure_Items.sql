@@ -0,0 +1,69 @@
+-- +goose Up
+-- Gems, jewellery and art objects are catalog items of the treasure type,
+-- with what kind of treasure each is kept here. Their true worth is the
+-- catalog value.
+CREATE TABLE treasure_items (
+    item_id INTEGER PRIMARY KEY,
+    kind TEXT NOT NULL CHECK (kind IN ('gem', 'jewellery', 'art')),
+    FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE
+);
+
+-- What a character has been told an item is worth, in gold pieces each,
+-- which need not be its true worth. Null until appraised.
+ALTER TABLE character_inventory ADD COLUMN appraised_value REAL CHECK (appraised_value IS NULL OR appraised_value >= 0);
+
+-- Share of a treasure item's worth a merchant keeps when buying it for coin
+ALTER TABLE price_lists ADD COLUMN treasure_fee_percent INTEGER NOT NULL DEFAULT 10 CHECK (treasure_fee_percent BETWEEN 0 AND 100);
+
+INSERT INTO items (name, description, weight, value, stackable, item_type)
+VALUES
+    ('Azurite', 'An opaque stone of mottled deep blue', 0.02, 10, 1, 'treasure'),
+    ('Hematite', 'A grey-black stone with a metallic sheen', 0.02, 10, 1, 'treasure'),
+    ('Bloodstone', 'Dark green flecked with red', 0.02, 50, 1, 'treasure'),
+    ('Onyx', 'Banded black and white', 0.02, 50, 1, 'treasure'),
+    ('Amethyst', 'A clear stone of deep violet', 0.02, 100, 1, 'treasure'),
+    ('Pearl', 'A lustrous white pearl', 0.02, 100, 1, 'treasure'),
+    ('Topaz', 'A clear golden-yellow stone', 0.02, 500, 1, 'treasure'),
+    ('Emerald', 'A clear stone of brilliant green', 0.02, 1000, 1, 'treasure'),
+    ('Ruby', 'A clear stone of deep crimson', 0.02, 1000, 1, 'treasure'),
+    ('Silver Ring', 'A plain band of worked silver', 0.1, 50, 0, 'treasure'),
+    ('Electrum Brooch', 'A cloak pin chased with knotwork', 0.1, 200, 0, 'treasure'),
+    ('Gold Bracelet', 'A heavy bracelet of hammered gold', 0.5, 300, 0, 'treasure'),
+    ('Jewelled Necklace', 'A gold chain set with small gems', 0.5, 1500, 0, 'treasure'),
+    ('Painted Icon', 'A small devotional painting on a wooden panel', 3, 150, 0, 'treasure'),
+    ('Ivory Statuette', 'A carved figure of a forgotten god', 2, 250, 0, 'treasure'),
+    ('Gilded Chalice', 'A silver cup washed in gold', 2, 400, 0, 'treasure'),
+    ('Silk Tapestry', 'A rolled hanging of a hunting scene', 10, 600, 0, 'treasure');
+
+INSERT INTO treasure_items (item_id, kind)
+SELECT
+    id,
+    CASE
+        WHEN stackable THEN 'gem'
+        WHEN name IN ('Silver Ring', 'Electrum Brooch', 'Gold Bracelet', 'Jewelled Necklace') THEN 'jewellery'
+        ELSE 'art'
+    END
+FROM
+    items
+WHERE
+    item_type = 'treasure';
+
+-- +goose Down
+ALTER TABLE price_lists DROP COLUMN treasure_fee_percent;
+ALTER TABLE character_inventory DROP COLUMN appraised_value;
+DELETE FROM character_inventory WHERE item_id IN (SELECT id FROM items WHERE item_type = 'treasure');
+DELETE FROM shop_cart_items WHERE item_id IN (SELECT id FROM items WHERE item_type = 'treasure');
+DELETE FROM price_list_items WHERE item_id IN (SELECT id FROM items WHERE item_type = 'treasure');
+DROP TABLE IF EXISTS treasure_items;
+-- The ledger is left as it was, so treasure it names stays in the catalog
+DELETE FROM items
+WHERE
+    item_type = 'treasure'
+    AND id NOT IN (
+        SELECT
+            item_id
+        FROM
+            currency_transactions
+        WHERE
+            item_id IS NOT NULL
+    );
@@ -0,0 +1,161 @@
+-- +goose Up
+-- Light items were carried over from the old integer weight columns as
+-- weighing nothing. Give them their weight in fractions of a pound so a
+-- pack full of them counts towards encumbrance.
+CREATE TABLE light_item_weights (
+    name TEXT PRIMARY KEY,
+    weight REAL NOT NULL
+);
+
+INSERT INTO light_item_weights (name, weight)
+VALUES
+    ('Arrow, Silver-tipped', 0.08),
+    ('Bolt, Heavy, Silver-tipped', 0.2),
+    ('Bolt, Light, Silver-tipped', 0.1),
+    ('Bullet, Sling, Silver', 0.1),
+    ('Bandages, Gauze', 0.1),
+    ('Belladonna', 0.1),
+    ('Candle, Beeswax', 0.1),
+    ('Candle, Tallow', 0.1),
+    ('Chalk', 0.1),
+    ('Cord, Sinew', 0.1),
+    ('Dice, Ivory', 0.05),
+    ('Fishing Hooks', 0.05),
+    ('Fishing String', 0.1),
+    ('Ink and Quill', 0.1),
+    ('Marbles', 0.5),
+    ('Nails', 0.25),
+    ('Needle, Blowgun', 0.02),
+    ('Needle, Sewing', 0.01),
+    ('Parchment', 0.05),
+    ('Pouch, Hard Leather', 0.5),
+    ('Pouch, Soft Leather', 0.25),
+    ('Ring, Signet', 0.05),
+    ('Sack, Large', 0.5),
+    ('Sack, Small', 0.25),
+    ('Scabbard, Leather', 0.5),
+    ('Sheath, Dagger', 0.25),
+    ('Water-/Wineskin', 0.5),
+    ('Wire', 0.25),
+    ('Wolfsbane', 0.1),
+    ('Writing Stick', 0.05),
+    ('Belt', 0.25),
+    ('Gloves, Fur', 0.5),
+    ('Gloves, Leather', 0.25),
+    ('Hat, Wool', 0.25),
+    ('Hat, Fur', 0.5),
+    ('Leggings, Fur', 0.5),
+    ('Sandals', 0.5),
+    ('Tabard', 0.5),
+    ('Spices, Cooking', 0.1),
+    ('Bell, Small', 0.1),
+    ('Flute', 0.25),
+    ('Holy Oil/Water', 0.5),
+    ('Holy Symbol, Wooden', 0.1),
+    ('Holy Symbol, Silver', 0.1),
+    ('Holy Symbol, Ivory', 0.1),
+    ('Holy Symbol, Gold', 0.1),
+    ('Incense Sticks', 0.1),
+    ('Mask, Leather', 0.25),
+    ('Panpipes', 0.25),
+    ('Prayer Beads, Wooden', 0.1),
+    ('Prayer Beads, Ivory', 0.1),
+    ('Rattle', 0.25),
+    ('Ring of Protection +1', 0.05),
+    ('Ring of Protection +2', 0.05),
+    ('Amulet of Warding', 0.1);
+
+UPDATE items
+SET
+    weight = w.weight,
+    updated_at = CURRENT_TIMESTAMP
+FROM
+    light_item_weights w
+WHERE
+    w.name = items.name
+    AND items.weight = 0;
+
+DROP TABLE light_item_weights;
+
+-- Ammunition is weighed by the bundle: items.weight is what a bundle of
+-- ammunition.quantity missiles weighs, so each missile weighs its share
+UPDATE ammunition SET quantity = 1 WHERE quantity IS NULL OR quantity < 1;
+
+-- +goose Down
+-- Only items still at the weight Up gave them go back to weighing nothing;
+-- the old integer columns held no fractions, so any other weight was set
+-- since
+CREATE TABLE light_item_weights (
+    name TEXT PRIMARY KEY,
+    weight REAL NOT NULL
+);
+
+INSERT INTO light_item_weights (name, weight)
+VALUES
+    ('Arrow, Silver-tipped', 0.08),
+    ('Bolt, Heavy, Silver-tipped', 0.2),
+    ('Bolt, Light, Silver-tipped', 0.1),
+    ('Bullet, Sling, Silver', 0.1),
+    ('Bandages, Gauze', 0.1),
+    ('Belladonna', 0.1),
+    ('Candle, Beeswax', 0.1),
+    ('Candle, Tallow', 0.1),
+    ('Chalk', 0.1),
+    ('Cord, Sinew', 0.1),
+    ('Dice, Ivory', 0.05),
+    ('Fishing Hooks', 0.05),
+    ('Fishing String', 0.1),
+    ('Ink and Quill', 0.1),
+    ('Marbles', 0.5),
+    ('Nails', 0.25),
+    ('Needle, Blowgun', 0.02),
+    ('Needle, Sewing', 0.01),
+    ('Parchment', 0.05),
+    ('Pouch, Hard Leather', 0.5),
+    ('Pouch, Soft Leather', 0.25),
+    ('Ring, Signet', 0.05),
+    ('Sack, Large', 0.5),
+    ('Sack, Small', 0.25),
+    ('Scabbard, Leather', 0.5),
+    ('Sheath, Dagger', 0.25),
+    ('Water-/Wineskin', 0.5),
+    ('Wire', 0.25),
+    ('Wolfsbane', 0.1),
+    ('Writing Stick', 0.05),
+    ('Belt', 0.25),
+    ('Gloves, Fur', 0.5),
+    ('Gloves, Leather', 0.25),
+    ('Hat, Wool', 0.25),
+    ('Hat, Fur', 0.5),
+    ('Leggings, Fur', 0.5),
+    ('Sandals', 0.5),
+    ('Tabard', 0.5),
+    ('Spices, Cooking', 0.1),
+    ('Bell, Small', 0.1),
+    ('Flute', 0.25),
+    ('Holy Oil/Water', 0.5),
+    ('Holy Symbol, Wooden', 0.1),
+    ('Holy Symbol, Silver', 0.1),
+    ('Holy Symbol, Ivory', 0.1),
+    ('Holy Symbol, Gold', 0.1),
+    ('Incense Sticks', 0.1),
+    ('Mask, Leather', 0.25),
+    ('Panpipes', 0.25),
+    ('Prayer Beads, Wooden', 0.1),
+    ('Prayer Beads, Ivory', 0.1),
+    ('Rattle', 0.25),
+    ('Ring of Protection +1', 0.05),
+    ('Ring of Protection +2', 0.05),
+    ('Amulet of Warding', 0.1);
+
+UPDATE items
+SET
+    weight = 0,
+    updated_at = CURRENT_TIMESTAMP
+FROM
+    light_item_weights w
+WHERE
+    w.name = items.name
+    AND items.weight = w.weight;
+
+DROP TABLE light_item_weights;
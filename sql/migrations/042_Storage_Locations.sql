@@ -0,0 +1,67 @@
+-- +goose Up
+-- Places a character keeps things off their person: a mount's saddlebags,
+-- a camp cache, a rented room or a bank vault. A location belongs to one
+-- character, or to a campaign when the whole party shares it.
+CREATE TABLE storage_locations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER,
+    campaign_id INTEGER,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('mount', 'camp', 'home', 'bank')),
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((character_id IS NULL) <> (campaign_id IS NULL)),
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE,
+    FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_storage_locations_character_id ON storage_locations (character_id);
+CREATE INDEX idx_storage_locations_campaign_id ON storage_locations (campaign_id);
+
+-- Where an item is kept when it isn't on the character. Only set on items
+-- that aren't in a container; a container's contents go where it goes.
+ALTER TABLE character_inventory ADD COLUMN storage_location_id INTEGER REFERENCES storage_locations (id) ON DELETE SET NULL;
+
+-- Coins a character has left at a location. Stored coins are still the
+-- character's, so a shared location keeps each character's apart.
+CREATE TABLE storage_location_coins (
+    storage_location_id INTEGER NOT NULL,
+    character_id INTEGER NOT NULL,
+    denomination TEXT NOT NULL,
+    amount INTEGER NOT NULL CHECK (amount >= 0),
+    PRIMARY KEY (storage_location_id, character_id, denomination),
+    FOREIGN KEY (storage_location_id) REFERENCES storage_locations (id) ON DELETE CASCADE,
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- Stored coins and items come back to their owners, the coins through the
+-- ledger so it still matches the purse
+INSERT INTO currency_transactions (character_id, reason, actor_id, notes)
+SELECT DISTINCT s.character_id, 'adjustment', c.user_id, 'Returned from storage'
+FROM storage_location_coins s
+JOIN characters c ON c.id = s.character_id
+WHERE s.amount > 0;
+
+INSERT INTO currency_transaction_coins (transaction_id, denomination, amount)
+SELECT
+    (SELECT MAX(t.id) FROM currency_transactions t WHERE t.character_id = s.character_id),
+    s.denomination,
+    SUM(s.amount)
+FROM storage_location_coins s
+WHERE s.amount > 0
+GROUP BY s.character_id, s.denomination;
+
+INSERT INTO character_coins (character_id, denomination, quantity)
+SELECT character_id, denomination, SUM(amount)
+FROM storage_location_coins
+WHERE amount > 0
+GROUP BY character_id, denomination
+ON CONFLICT (character_id, denomination) DO UPDATE SET quantity = quantity + excluded.quantity;
+
+DROP TABLE IF EXISTS storage_location_coins;
+ALTER TABLE character_inventory DROP COLUMN storage_location_id;
+DROP INDEX IF EXISTS idx_storage_locations_campaign_id;
+DROP INDEX IF EXISTS idx_storage_locations_character_id;
+DROP TABLE IF EXISTS storage_locations;
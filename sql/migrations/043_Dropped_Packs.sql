@@ -0,0 +1,10 @@
+-- +goose Up
+-- A pack worn on the back can be dropped at the start of a fight. It stays
+-- equipped, but neither it nor anything in it weighs on the character until
+-- it is picked up again. Where it was left is noted so it isn't forgotten.
+ALTER TABLE character_inventory ADD COLUMN dropped BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE character_inventory ADD COLUMN dropped_location TEXT;
+
+-- +goose Down
+ALTER TABLE character_inventory DROP COLUMN dropped_location;
+ALTER TABLE character_inventory DROP COLUMN dropped;
@@ -0,0 +1,43 @@
+-- +goose Up
+-- Items and coins one character offers another in the same campaign. Nothing
+-- moves until the receiving character's owner accepts; then the item, with
+-- anything packed in it, and the coins change hands in one go.
+CREATE TABLE character_transfers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign_id INTEGER NOT NULL,
+    from_character_id INTEGER NOT NULL,
+    to_character_id INTEGER NOT NULL,
+    -- Item offered and how many of the stack; a smaller quantity splits it
+    inventory_id INTEGER,
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled')),
+    notes TEXT,
+    offered_by INTEGER NOT NULL,
+    resolved_by INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    CHECK (from_character_id <> to_character_id),
+    FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON DELETE CASCADE,
+    FOREIGN KEY (from_character_id) REFERENCES characters (id) ON DELETE CASCADE,
+    FOREIGN KEY (to_character_id) REFERENCES characters (id) ON DELETE CASCADE,
+    FOREIGN KEY (inventory_id) REFERENCES character_inventory (id) ON DELETE SET NULL,
+    FOREIGN KEY (offered_by) REFERENCES users (id),
+    FOREIGN KEY (resolved_by) REFERENCES users (id)
+);
+
+CREATE INDEX idx_character_transfers_from_character_id ON character_transfers (from_character_id);
+CREATE INDEX idx_character_transfers_to_character_id ON character_transfers (to_character_id);
+
+CREATE TABLE character_transfer_coins (
+    transfer_id INTEGER NOT NULL,
+    denomination TEXT NOT NULL,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    PRIMARY KEY (transfer_id, denomination),
+    FOREIGN KEY (transfer_id) REFERENCES character_transfers (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS character_transfer_coins;
+DROP INDEX IF EXISTS idx_character_transfers_to_character_id;
+DROP INDEX IF EXISTS idx_character_transfers_from_character_id;
+DROP TABLE IF EXISTS character_transfers;
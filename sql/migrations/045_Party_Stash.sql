@@ -0,0 +1,60 @@
+-- +goose Up
+-- Loot a campaign hasn't divided yet, held outside any character. The
+-- referee deposits items and coins; party members claim items, and the
+-- treasure split draws on the rest.
+CREATE TABLE party_stash_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign_id INTEGER NOT NULL,
+    item_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    -- Worth in gold pieces when it differs from the catalog, e.g. a gem
+    -- the referee has priced
+    appraised_value REAL CHECK (appraised_value IS NULL OR appraised_value >= 0),
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_party_stash_items_campaign_id ON party_stash_items (campaign_id);
+
+CREATE TABLE party_stash_coins (
+    campaign_id INTEGER NOT NULL,
+    denomination TEXT NOT NULL,
+    amount INTEGER NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    PRIMARY KEY (campaign_id, denomination),
+    FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON DELETE CASCADE
+);
+
+-- Every deposit, claim and split out of the stash. Amounts are positive
+-- going in and negative coming out.
+CREATE TABLE party_stash_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign_id INTEGER NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('deposit', 'claim', 'split')),
+    item_id INTEGER,
+    quantity INTEGER NOT NULL DEFAULT 0,
+    coins TEXT,
+    character_id INTEGER,
+    user_id INTEGER NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE SET NULL,
+    FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE SET NULL,
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX idx_party_stash_log_campaign_id ON party_stash_log (campaign_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_party_stash_log_campaign_id;
+
+DROP TABLE IF EXISTS party_stash_log;
+
+DROP TABLE IF EXISTS party_stash_coins;
+
+DROP INDEX IF EXISTS idx_party_stash_items_campaign_id;
+
+DROP TABLE IF EXISTS party_stash_items;
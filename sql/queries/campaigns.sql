@@ -0,0 +1,230 @@
+-- name: CreateCampaign :one
+INSERT INTO
+    campaigns (name, description, referee_id, invite_code)
+VALUES
+    (?, ?, ?, ?) RETURNING *;
+
+-- name: GetCampaign :one
+SELECT
+    *
+FROM
+    campaigns
+WHERE
+    id = ?
+LIMIT
+    1;
+
+-- name: GetCampaignByInviteCode :one
+SELECT
+    *
+FROM
+    campaigns
+WHERE
+    invite_code = ?
+LIMIT
+    1;
+
+-- name: ListCampaignsForUser :many
+SELECT
+    c.id,
+    c.name,
+    c.description,
+    c.referee_id,
+    u.username AS referee_username
+FROM
+    campaigns c
+    JOIN users u ON c.referee_id = u.id
+WHERE
+    c.referee_id = ?
+    OR c.id IN (
+        SELECT
+            campaign_id
+        FROM
+            campaign_members
+        WHERE
+            user_id = ?
+            AND status = 'active'
+    )
+ORDER BY
+    c.name;
+
+-- name: ListCampaignInvitations :many
+SELECT
+    c.id,
+    c.name,
+    u.username AS referee_username
+FROM
+    campaign_members m
+    JOIN campaigns c ON m.campaign_id = c.id
+    JOIN users u ON c.referee_id = u.id
+WHERE
+    m.user_id = ?
+    AND m.status = 'invited'
+ORDER BY
+    c.name;
+
+-- name: UpdateCampaignSettings :exec
+UPDATE campaigns
+SET
+    referee_can_edit = ?,
+    drain_xp_policy = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND referee_id = ?;
+
+-- name: UpdateCampaignInviteCode :exec
+UPDATE campaigns
+SET
+    invite_code = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND referee_id = ?;
+
+-- name: InviteCampaignMember :exec
+INSERT INTO
+    campaign_members (campaign_id, user_id, status, invited_by)
+VALUES
+    (?, ?, 'invited', ?) ON CONFLICT (campaign_id, user_id) DO NOTHING;
+
+-- name: ActivateCampaignMember :exec
+INSERT INTO
+    campaign_members (campaign_id, user_id, status)
+VALUES
+    (?, ?, 'active') ON CONFLICT (campaign_id, user_id) DO
+UPDATE
+SET
+    status = 'active';
+
+-- name: GetCampaignMember :one
+SELECT
+    *
+FROM
+    campaign_members
+WHERE
+    campaign_id = ?
+    AND user_id = ?
+LIMIT
+    1;
+
+-- name: DeleteCampaignMember :exec
+DELETE FROM campaign_members
+WHERE
+    campaign_id = ?
+    AND user_id = ?;
+
+-- name: ListCampaignMembers :many
+SELECT
+    m.user_id,
+    u.username,
+    m.status,
+    m.created_at
+FROM
+    campaign_members m
+    JOIN users u ON m.user_id = u.id
+WHERE
+    m.campaign_id = ?
+ORDER BY
+    u.username;
+
+-- name: AddCharacterToCampaign :exec
+INSERT INTO
+    campaign_characters (campaign_id, character_id)
+VALUES
+    (?, ?);
+
+-- name: RemoveCharacterFromCampaign :exec
+DELETE FROM campaign_characters
+WHERE
+    campaign_id = ?
+    AND character_id = ?;
+
+-- name: RemoveMemberCharactersFromCampaign :exec
+DELETE FROM campaign_characters
+WHERE
+    campaign_id = ?
+    AND character_id IN (
+        SELECT
+            id
+        FROM
+            characters
+        WHERE
+            user_id = ?
+    );
+
+-- name: ListCampaignCharacters :many
+SELECT
+    ch.id,
+    ch.user_id,
+    ch.name,
+    ch.class,
+    ch.level,
+    ch.max_hp,
+    ch.current_hp,
+    ch.strength,
+    ch.dexterity,
+    ch.constitution,
+    ch.intelligence,
+    ch.wisdom,
+    ch.charisma,
+    ch.experience_points,
+    ch.created_at,
+    ch.updated_at,
+    u.username AS player_username
+FROM
+    campaign_characters cc
+    JOIN characters ch ON cc.character_id = ch.id
+    JOIN users u ON ch.user_id = u.id
+WHERE
+    cc.campaign_id = ?
+ORDER BY
+    ch.name;
+
+-- name: GetCharacterCampaign :one
+SELECT
+    c.id,
+    c.name,
+    c.description,
+    c.referee_id,
+    c.invite_code,
+    c.referee_can_edit,
+    c.drain_xp_policy,
+    c.created_at,
+    c.updated_at
+FROM
+    campaigns c
+    JOIN campaign_characters cc ON cc.campaign_id = c.id
+WHERE
+    cc.character_id = ?
+LIMIT
+    1;
+
+-- name: GetRefereedCharacter :one
+SELECT
+    ch.id,
+    ch.user_id,
+    ch.name,
+    ch.class,
+    ch.level,
+    ch.max_hp,
+    ch.current_hp,
+    ch.strength,
+    ch.dexterity,
+    ch.constitution,
+    ch.intelligence,
+    ch.wisdom,
+    ch.charisma,
+    ch.experience_points,
+    ch.created_at,
+    ch.updated_at,
+    c.referee_can_edit
+FROM
+    characters ch
+    JOIN campaign_characters cc ON cc.character_id = ch.id
+    JOIN campaigns c ON cc.campaign_id = c.id
+WHERE
+    ch.id = ?
+    AND c.referee_id = ?
+LIMIT
+    1;
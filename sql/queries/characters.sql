@@ -13,12 +13,7 @@ INSERT INTO
         intelligence,
         wisdom,
         charisma,
-        experience_points,
-        platinum_pieces,
-        gold_pieces,
-        electrum_pieces,
-        silver_pieces,
-        copper_pieces
+        experience_points
     )
 VALUES
     (
@@ -34,11 +29,6 @@ VALUES
         ?,
         ?,
         ?,
-        ?,
-        ?,
-        ?,
-        ?,
-        ?,
         ?
     ) RETURNING *;
 
@@ -78,11 +68,6 @@ SET
     wisdom = ?,
     charisma = ?,
     experience_points = ?,
-    platinum_pieces = ?,
-    gold_pieces = ?,
-    electrum_pieces = ?,
-    silver_pieces = ?,
-    copper_pieces = ?,
     updated_at = CURRENT_TIMESTAMP
 WHERE
     id = ?
@@ -0,0 +1,50 @@
+-- name: ListCoinage :many
+SELECT
+    *
+FROM
+    coins
+WHERE
+    campaign_id IS NULL
+    OR campaign_id = ?
+ORDER BY
+    base_value DESC,
+    denomination;
+
+-- name: ListCampaignCoins :many
+SELECT
+    *
+FROM
+    coins
+WHERE
+    campaign_id = ?
+ORDER BY
+    base_value DESC,
+    denomination;
+
+-- name: SaveCampaignCoin :one
+INSERT INTO
+    coins (
+        campaign_id,
+        denomination,
+        name,
+        weight_per_coin,
+        base_value
+    )
+VALUES
+    (?, ?, ?, ?, ?) ON CONFLICT (COALESCE(campaign_id, 0), denomination) DO
+UPDATE
+SET
+    name = excluded.name,
+    weight_per_coin = excluded.weight_per_coin,
+    base_value = excluded.base_value RETURNING *;
+
+-- name: ListCharacterCoins :many
+SELECT
+    *
+FROM
+    character_coins
+WHERE
+    character_id = ?
+    AND quantity > 0
+ORDER BY
+    denomination;
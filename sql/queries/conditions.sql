@@ -0,0 +1,22 @@
+-- name: CreateCharacterCondition :one
+INSERT INTO
+    character_conditions (character_id, name, notes)
+VALUES
+    (?, ?, ?) RETURNING *;
+
+-- name: ListCharacterConditions :many
+SELECT
+    *
+FROM
+    character_conditions
+WHERE
+    character_id = ?
+ORDER BY
+    created_at,
+    id;
+
+-- name: DeleteCharacterCondition :exec
+DELETE FROM character_conditions
+WHERE
+    id = ?
+    AND character_id = ?;
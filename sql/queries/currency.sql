@@ -0,0 +1,109 @@
+-- name: CreateCurrencyTransaction :one
+INSERT INTO
+    currency_transactions (
+        character_id,
+        reason,
+        item_id,
+        xp_award_id,
+        actor_id,
+        notes
+    )
+VALUES
+    (?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: CreateCurrencyTransactionCoin :exec
+INSERT INTO
+    currency_transaction_coins (transaction_id, denomination, amount)
+VALUES
+    (?, ?, ?);
+
+-- name: ListCurrencyTransactionsByCharacter :many
+SELECT
+    ct.id,
+    ct.character_id,
+    ct.reason,
+    ct.item_id,
+    ct.xp_award_id,
+    ct.actor_id,
+    ct.notes,
+    ct.created_at,
+    u.username AS actor_username,
+    i.name AS item_name
+FROM
+    currency_transactions ct
+    JOIN users u ON ct.actor_id = u.id
+    LEFT JOIN items i ON ct.item_id = i.id
+WHERE
+    ct.character_id = ?
+ORDER BY
+    ct.id;
+
+-- name: ListCurrencyTransactionCoinsByCharacter :many
+SELECT
+    ctc.transaction_id,
+    ctc.denomination,
+    ctc.amount
+FROM
+    currency_transaction_coins ctc
+    JOIN currency_transactions ct ON ctc.transaction_id = ct.id
+WHERE
+    ct.character_id = ?
+ORDER BY
+    ctc.transaction_id,
+    ctc.denomination;
+
+-- name: ClearCharacterCoins :exec
+DELETE FROM character_coins
+WHERE
+    character_id = ?;
+
+-- name: SyncCharacterCoins :exec
+INSERT INTO
+    character_coins (character_id, denomination, quantity)
+SELECT
+    ct.character_id,
+    ctc.denomination,
+    SUM(ctc.amount)
+FROM
+    currency_transaction_coins ctc
+    JOIN currency_transactions ct ON ctc.transaction_id = ct.id
+WHERE
+    ct.character_id = ?
+GROUP BY
+    ct.character_id,
+    ctc.denomination
+HAVING
+    SUM(ctc.amount) <> 0;
+
+-- name: ReconcileCharacterCoins :many
+SELECT
+    denomination,
+    CAST(SUM(ledger) AS INTEGER) AS ledger,
+    CAST(SUM(stored) AS INTEGER) AS stored
+FROM
+    (
+        SELECT
+            ctc.denomination,
+            ctc.amount AS ledger,
+            0 AS stored
+        FROM
+            currency_transaction_coins ctc
+            JOIN currency_transactions ct ON ctc.transaction_id = ct.id
+        WHERE
+            ct.character_id = ?
+        UNION ALL
+        SELECT
+            cc.denomination,
+            0 AS ledger,
+            cc.quantity AS stored
+        FROM
+            character_coins cc
+        WHERE
+            cc.character_id = ?
+    )
+GROUP BY
+    denomination
+HAVING
+    SUM(ledger) <> SUM(stored)
+ORDER BY
+    denomination;
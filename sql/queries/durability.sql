@@ -0,0 +1,48 @@
+-- name: ListItemConditions :many
+SELECT
+    *
+FROM
+    item_conditions
+ORDER BY
+    rank;
+
+-- name: ListItemWearEvents :many
+SELECT
+    *
+FROM
+    item_wear_events
+ORDER BY
+    label;
+
+-- name: GetItemWearEvent :one
+SELECT
+    *
+FROM
+    item_wear_events
+WHERE
+    name = ?
+LIMIT
+    1;
+
+-- name: GetInventoryItemCondition :one
+SELECT
+    ci.id,
+    ci.condition,
+    ci.equipment_slot_id,
+    i.item_type,
+    i.value
+FROM
+    character_inventory ci
+    JOIN items i ON ci.item_id = i.id
+WHERE
+    ci.id = ?
+    AND ci.character_id = ?;
+
+-- name: SetItemCondition :exec
+UPDATE character_inventory
+SET
+    condition = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?;
@@ -1,146 +1,82 @@
 -- name: GetCharacterInventoryItems :many
 SELECT 
-    ci.id, ci.character_id, ci.item_id, ci.item_type, ci.quantity,
+    ci.id, ci.character_id, ci.item_id, i.item_type, ci.quantity,
     ci.container_id, ci.equipment_slot_id, ci.notes,
     ci.created_at, ci.updated_at,
-    CASE 
-        WHEN ci.item_type = 'equipment' THEN e.name
-        WHEN ci.item_type = 'weapon' THEN w.name
-        WHEN ci.item_type = 'armor' THEN a.name
-        WHEN ci.item_type = 'ammunition' THEN am.name
-        WHEN ci.item_type = 'container' THEN e.name  -- Use equipment name as fallback
-        WHEN ci.item_type = 'shield' THEN s.name
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.name
-    END as item_name,
-    CASE 
-        WHEN ci.item_type = 'equipment' THEN e.weight
-        WHEN ci.item_type = 'weapon' THEN w.weight
-        WHEN ci.item_type = 'armor' THEN a.weight
-        WHEN ci.item_type = 'ammunition' THEN am.weight
-        WHEN ci.item_type = 'container' THEN e.weight  -- Use equipment weight as fallback
-        WHEN ci.item_type = 'shield' THEN s.weight
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.weight
-        ELSE 0
-    END as item_weight,
-    CASE 
-        WHEN ci.item_type = 'shield' THEN s.defense_bonus
-        ELSE NULL
-    END as defense_bonus,
-    CASE 
-        WHEN ci.item_type = 'weapon' THEN w.damage
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.damage
-        ELSE NULL
-    END as damage,
-    CASE 
-        WHEN ci.item_type = 'weapon' THEN w.attacks_per_round
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.rate_of_fire
-        ELSE NULL
-    END as attacks_per_round,
-    CASE 
-        WHEN ci.item_type = 'armor' THEN a.movement_rate
-        ELSE NULL
-    END as movement_rate,
-    CASE 
-        WHEN ci.item_type = 'armor' THEN a.armor_class
-        ELSE NULL
-    END as armor_class,
-    CASE
-        WHEN ci.item_type = 'weapon' THEN w.enhancement_bonus
-        WHEN ci.item_type = 'armor' THEN a.enhancement_bonus
-        WHEN ci.item_type = 'shield' THEN s.enhancement_bonus
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.enhancement_bonus
-        WHEN ci.item_type = 'ammunition' THEN am.enhancement_bonus
-        ELSE NULL
-    END as enhancement_bonus,
+    i.name as item_name,
+    i.weight as item_weight,
+    s.defense_bonus,
+    COALESCE(w.damage, rw.damage) as damage,
+    COALESCE(w.attacks_per_round, rw.rate_of_fire) as attacks_per_round,
+    a.movement_rate,
+    a.armor_class,
+    ci.enhancement_bonus,
     es.name as slot_name,
-    CASE 
-        WHEN ci.item_type = 'container' THEN c.capacity_weight
-        ELSE NULL
-    END as container_capacity,
-    CASE 
-        WHEN ci.item_type = 'container' THEN c.capacity_items
-        ELSE NULL
-    END as container_max_items
+    c.capacity_weight as container_capacity,
+    c.capacity_items as container_max_items,
+    c.weight_multiplier as container_weight_multiplier,
+    ci.charges,
+    ci.is_identified,
+    i.appearance,
+    ci.cursed,
+    ci.curse_target,
+    ci.curse_ability,
+    ci.curse_modifier,
+    ci.curse_notes,
+    ci.condition,
+    CAST(COALESCE(ic.armor_class_penalty, 0) AS INTEGER) as condition_ac_penalty,
+    CAST(COALESCE(ic.usable, 1) AS BOOLEAN) as condition_usable,
+    CAST(COALESCE(ic.value_percent, 100) AS INTEGER) as condition_value_percent,
+    i.value as item_value,
+    ci.appraised_value,
+    ti.kind as treasure_kind,
+    CAST(COALESCE(am.quantity, 1) AS INTEGER) as bundle_size,
+    ci.missiles_used,
+    ci.storage_location_id,
+    ci.dropped,
+    ci.dropped_location
 FROM 
     character_inventory ci
+    JOIN items i ON ci.item_id = i.id
+    LEFT JOIN ammunition am ON am.item_id = i.id
+    LEFT JOIN item_conditions ic ON ic.name = ci.condition
+    LEFT JOIN treasure_items ti ON ti.item_id = i.id
     LEFT JOIN equipment_slots es ON ci.equipment_slot_id = es.id
-    LEFT JOIN equipment e ON ci.item_type = 'equipment' AND ci.item_id = e.id
-    LEFT JOIN weapons w ON ci.item_type = 'weapon' AND ci.item_id = w.id
-    LEFT JOIN armor a ON ci.item_type = 'armor' AND ci.item_id = a.id
-    LEFT JOIN ammunition am ON ci.item_type = 'ammunition' AND ci.item_id = am.id
-    LEFT JOIN containers c ON ci.item_type = 'container' AND ci.item_id = c.id
-    LEFT JOIN shields s ON ci.item_type = 'shield' AND ci.item_id = s.id
-    LEFT JOIN ranged_weapons rw ON ci.item_type = 'ranged_weapon' AND ci.item_id = rw.id
+    LEFT JOIN weapons w ON w.item_id = i.id
+    LEFT JOIN ranged_weapons rw ON rw.item_id = i.id
+    LEFT JOIN armor a ON a.item_id = i.id
+    LEFT JOIN shields s ON s.item_id = i.id
+    LEFT JOIN containers c ON c.base_item_id = i.id
 WHERE 
     ci.character_id = ?
 ORDER BY 
     ci.equipment_slot_id IS NULL, 
     es.name,
     ci.container_id IS NOT NULL,
-    item_name;
+    i.name;
 
 -- name: GetContainerContents :many
 SELECT 
-    ci.id, ci.character_id, ci.item_id, ci.item_type, ci.quantity,
+    ci.id, ci.character_id, ci.item_id, i.item_type, ci.quantity,
     ci.container_id, ci.equipment_slot_id, ci.notes,
     ci.created_at, ci.updated_at,
-    CASE 
-        WHEN ci.item_type = 'equipment' THEN e.name
-        WHEN ci.item_type = 'weapon' THEN w.name
-        WHEN ci.item_type = 'armor' THEN a.name
-        WHEN ci.item_type = 'ammunition' THEN am.name
-        WHEN ci.item_type = 'container' THEN e.name  -- Use equipment name as fallback
-        WHEN ci.item_type = 'shield' THEN s.name
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.name
-    END as item_name,
-    CASE 
-        WHEN ci.item_type = 'equipment' THEN e.weight
-        WHEN ci.item_type = 'weapon' THEN w.weight
-        WHEN ci.item_type = 'armor' THEN a.weight
-        WHEN ci.item_type = 'ammunition' THEN am.weight
-        WHEN ci.item_type = 'container' THEN e.weight  -- Use equipment weight as fallback
-        WHEN ci.item_type = 'shield' THEN s.weight
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.weight
-        ELSE 0
-    END as item_weight
+    i.name as item_name,
+    i.weight as item_weight
 FROM 
     character_inventory ci
-    LEFT JOIN equipment e ON ci.item_type = 'equipment' AND ci.item_id = e.id
-    LEFT JOIN weapons w ON ci.item_type = 'weapon' AND ci.item_id = w.id
-    LEFT JOIN armor a ON ci.item_type = 'armor' AND ci.item_id = a.id
-    LEFT JOIN ammunition am ON ci.item_type = 'ammunition' AND ci.item_id = am.id
-    LEFT JOIN containers c ON ci.item_type = 'container' AND ci.item_id = c.id
-    LEFT JOIN shields s ON ci.item_type = 'shield' AND ci.item_id = s.id
-    LEFT JOIN ranged_weapons rw ON ci.item_type = 'ranged_weapon' AND ci.item_id = rw.id
+    JOIN items i ON ci.item_id = i.id
 WHERE 
     ci.container_id = ?
     AND ci.character_id = ?
 ORDER BY 
-    item_name;
+    i.name;
 
 -- name: GetContainerWeight :one
 SELECT 
-    COALESCE(SUM(
-        CASE 
-            WHEN ci.item_type = 'equipment' THEN e.weight * ci.quantity
-            WHEN ci.item_type = 'weapon' THEN w.weight * ci.quantity
-            WHEN ci.item_type = 'armor' THEN a.weight * ci.quantity
-            WHEN ci.item_type = 'ammunition' THEN COALESCE(am.weight, 0) * ci.quantity
-            WHEN ci.item_type = 'container' THEN COALESCE(e.weight, 0) * ci.quantity  -- Use equipment weight as fallback
-            WHEN ci.item_type = 'shield' THEN s.weight * ci.quantity
-            WHEN ci.item_type = 'ranged_weapon' THEN rw.weight * ci.quantity
-            ELSE 0
-        END
-    ), 0) as total_weight
+    CAST(COALESCE(SUM(i.weight * ci.quantity), 0) AS REAL) as total_weight
 FROM 
     character_inventory ci
-    LEFT JOIN equipment e ON ci.item_type = 'equipment' AND ci.item_id = e.id
-    LEFT JOIN weapons w ON ci.item_type = 'weapon' AND ci.item_id = w.id
-    LEFT JOIN armor a ON ci.item_type = 'armor' AND ci.item_id = a.id
-    LEFT JOIN ammunition am ON ci.item_type = 'ammunition' AND ci.item_id = am.id
-    LEFT JOIN containers c ON ci.item_type = 'container' AND ci.item_id = c.id
-    LEFT JOIN shields s ON ci.item_type = 'shield' AND ci.item_id = s.id
-    LEFT JOIN ranged_weapons rw ON ci.item_type = 'ranged_weapon' AND ci.item_id = rw.id
+    JOIN items i ON ci.item_id = i.id
 WHERE 
     ci.container_id = ?;
 
@@ -153,28 +89,27 @@ FROM
 JOIN 
     containers c ON ci.item_id = c.base_item_id
 WHERE 
-    ci.id = ?
-    AND ci.item_type = 'container';
+    ci.id = ?;
 
 -- name: AddItemToInventory :one
 INSERT INTO
     character_inventory (
         character_id,
         item_id,
-        item_type,
         quantity,
         container_id,
         equipment_slot_id,
+        enhancement_bonus,
         notes
     )
 VALUES
     (?, ?, ?, ?, ?, ?, ?) RETURNING id,
     character_id,
     item_id,
-    item_type,
     quantity,
     container_id,
     equipment_slot_id,
+    enhancement_bonus,
     notes,
     created_at,
     updated_at;
@@ -189,7 +124,7 @@ SET
     updated_at = CURRENT_TIMESTAMP
 WHERE
     id = ?
-    AND character_id = ? RETURNING id, character_id, item_id, item_type, quantity, container_id, equipment_slot_id, notes, created_at, updated_at;
+    AND character_id = ? RETURNING id, character_id, item_id, quantity, container_id, equipment_slot_id, notes, created_at, updated_at;
 
 -- name: RemoveItemFromInventory :exec
 DELETE FROM character_inventory
@@ -210,7 +145,10 @@ WHERE
 UPDATE character_inventory
 SET 
     container_id = ?,
-    equipment_slot_id = NULL
+    equipment_slot_id = NULL,
+    storage_location_id = NULL,
+    dropped = 0,
+    dropped_location = NULL
 WHERE 
     id = ?
     AND character_id = ?;
@@ -219,7 +157,10 @@ WHERE
 UPDATE character_inventory
 SET 
     equipment_slot_id = ?,
-    container_id = NULL
+    container_id = NULL,
+    storage_location_id = NULL,
+    dropped = 0,
+    dropped_location = NULL
 WHERE 
     id = ?
     AND character_id = ?;
@@ -227,7 +168,9 @@ WHERE
 -- name: UnequipItem :exec
 UPDATE character_inventory
 SET 
-    equipment_slot_id = NULL
+    equipment_slot_id = NULL,
+    dropped = 0,
+    dropped_location = NULL
 WHERE 
     id = ?
     AND character_id = ?;
@@ -248,7 +191,6 @@ FROM
 WHERE 
     character_id = ?
     AND item_id = ?
-    AND item_type = ?
     AND container_id IS NULL
     AND equipment_slot_id IS NULL
 LIMIT 1;
@@ -261,37 +203,67 @@ FROM
 WHERE 
     character_id = ?
     AND item_id = ?
-    AND item_type = ?
     AND container_id = ?
 LIMIT 1;
 
--- name: SplitStack :exec
+-- name: SplitStack :one
 INSERT INTO character_inventory (
     character_id, 
     item_id,
-    item_type,
     quantity,
     container_id,
     equipment_slot_id,
+    enhancement_bonus,
     notes,
+    is_identified,
+    identified_by,
+    cursed,
+    curse_target,
+    curse_ability,
+    curse_modifier,
+    curse_notes,
+    condition,
+    appraised_value,
+    charges,
+    custom_name,
+    custom_notes,
+    storage_location_id,
+    dropped,
+    dropped_location,
     created_at,
     updated_at
 )
 SELECT 
     ci.character_id,
     ci.item_id,
-    ci.item_type,
     ?,
-    ci.container_id,
+    ?,
     NULL,
+    ci.enhancement_bonus,
     ci.notes,
+    ci.is_identified,
+    ci.identified_by,
+    ci.cursed,
+    ci.curse_target,
+    ci.curse_ability,
+    ci.curse_modifier,
+    ci.curse_notes,
+    ci.condition,
+    ci.appraised_value,
+    ci.charges,
+    ci.custom_name,
+    ci.custom_notes,
+    ci.storage_location_id,
+    ci.dropped,
+    ci.dropped_location,
     CURRENT_TIMESTAMP,
     CURRENT_TIMESTAMP
 FROM 
     character_inventory ci
 WHERE 
-    ci.id = ?;
-    
+    ci.id = ?
+    AND ci.character_id = ? RETURNING id;
+
 -- name: ReduceStackQuantity :exec
 UPDATE character_inventory
 SET quantity = quantity - ?
@@ -299,142 +271,40 @@ WHERE id = ? AND character_id = ?;
 
 -- name: GetEquippedItems :many
 SELECT
-    ci.id, ci.character_id, ci.item_id, ci.item_type, ci.quantity,
+    ci.id, ci.character_id, ci.item_id, i.item_type, ci.quantity,
     ci.container_id, ci.equipment_slot_id, ci.notes, ci.created_at, ci.updated_at,
     es.name as slot_name,
-    CASE 
-        WHEN ci.item_type = 'equipment' THEN e.name
-        WHEN ci.item_type = 'weapon' THEN w.name
-        WHEN ci.item_type = 'armor' THEN a.name
-        WHEN ci.item_type = 'ammunition' THEN am.name
-        WHEN ci.item_type = 'container' THEN c.name
-        WHEN ci.item_type = 'shield' THEN s.name
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.name
-    END as item_name,
-    CASE 
-        WHEN ci.item_type = 'equipment' THEN e.weight
-        WHEN ci.item_type = 'weapon' THEN w.weight
-        WHEN ci.item_type = 'armor' THEN a.weight
-        WHEN ci.item_type = 'ammunition' THEN am.weight
-        WHEN ci.item_type = 'container' THEN c.weight
-        WHEN ci.item_type = 'shield' THEN s.weight
-        WHEN ci.item_type = 'ranged_weapon' THEN rw.weight
-        ELSE 0
-    END as item_weight
+    i.name as item_name,
+    i.weight as item_weight
 FROM
     character_inventory ci
+    JOIN items i ON ci.item_id = i.id
     JOIN equipment_slots es ON ci.equipment_slot_id = es.id
-    LEFT JOIN equipment e ON ci.item_type = 'equipment' AND ci.item_id = e.id
-    LEFT JOIN weapons w ON ci.item_type = 'weapon' AND ci.item_id = w.id
-    LEFT JOIN armor a ON ci.item_type = 'armor' AND ci.item_id = a.id
-    LEFT JOIN ammunition am ON ci.item_type = 'ammunition' AND ci.item_id = am.id
-    LEFT JOIN containers c ON ci.item_type = 'container' AND ci.item_id = c.id
-    LEFT JOIN shields s ON ci.item_type = 'shield' AND ci.item_id = s.id
-    LEFT JOIN ranged_weapons rw ON ci.item_type = 'ranged_weapon' AND ci.item_id = rw.id
 WHERE
     ci.character_id = ?
     AND ci.equipment_slot_id IS NOT NULL
 ORDER BY
     es.name;
 
-
--- name: GetAllWeapons :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    weapons
-ORDER BY 
-    name;
-
--- name: GetAllArmor :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    armor
-ORDER BY 
-    name;
-
--- name: GetAllShields :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    shields
-ORDER BY 
-    name;
-
--- name: GetAllEquipment :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    equipment
-ORDER BY 
-    name;
-
--- name: GetAllAmmunition :many
+-- name: ListCatalogItems :many
 SELECT 
-    id, name, weight, cost_gp
+    id, name, weight, value as cost_gp
 FROM 
-    ammunition
-ORDER BY 
-    name;
-
--- name: GetAllRangedWeapons :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    ranged_weapons
+    items
+WHERE
+    item_type = ?
 ORDER BY 
     name;
 
 -- name: GetEquipmentSlots :many
 SELECT 
-    id, name, description
+    id, name, description, slot_group
 FROM 
     equipment_slots
 ORDER BY 
     name;
 
--- name: GetEnhancedWeapons :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    weapons
-WHERE
-    enhancement_bonus = ?
-ORDER BY 
-    name;
-
--- name: GetEnhancedArmor :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    armor
-WHERE
-    enhancement_bonus = ?
-ORDER BY 
-    name;
-
--- name: GetEnhancedShields :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    shields
-WHERE
-    enhancement_bonus = ?
-ORDER BY 
-    name;
-
--- name: GetEnhancedRangedWeapons :many
-SELECT 
-    id, name, weight, cost_gp
-FROM 
-    ranged_weapons
-WHERE
-    enhancement_bonus = ?
-ORDER BY 
-    name;
-
-    -- name: UseChargedItem :exec
+-- name: UseChargedItem :exec
 UPDATE character_inventory
 SET 
     charges = charges - 1
@@ -446,48 +316,38 @@ WHERE
 -- name: GetChargedItem :one
 SELECT 
     ci.id, ci.character_id, ci.charges, 
-    mi.name, mi.description, mi.effect_description, mi.category
+    i.name, i.description, mi.effect_description, mi.category
 FROM 
     character_inventory ci
-JOIN 
-    magical_items mi ON ci.item_id = mi.id
+    JOIN items i ON ci.item_id = i.id
+    JOIN magical_items mi ON mi.item_id = i.id
 WHERE 
     ci.id = ?
-    AND ci.character_id = ?
-    AND ci.item_type = 'magical_item';
-
--- name: UseChargedItem :exec
-UPDATE character_inventory
-SET 
-    charges = charges - 1
-WHERE 
-    id = ?
-    AND character_id = ?
-    AND charges > 0;
-
+    AND ci.character_id = ?;
 
 -- name: GetAllMagicalItems :many
 SELECT 
-    id, name, description, weight, cost_gp, max_charges, category, effect_description
+    i.id, i.name, i.description, i.weight, i.value as cost_gp, mi.max_charges, mi.category, mi.effect_description
 FROM 
-    magical_items
+    items i
+    JOIN magical_items mi ON mi.item_id = i.id
 ORDER BY 
-    name;
+    i.name;
 
 -- name: GetMagicalItemByID :one
 SELECT 
-    id, name, description, weight, cost_gp, max_charges, category, effect_description
+    i.id, i.name, i.description, i.weight, i.value as cost_gp, mi.max_charges, mi.category, mi.effect_description
 FROM 
-    magical_items
+    items i
+    JOIN magical_items mi ON mi.item_id = i.id
 WHERE 
-    id = ?;
+    i.id = ?;
 
 -- name: AddMagicalItemToInventory :one
 INSERT INTO
     character_inventory (
         character_id,
         item_id,
-        item_type,
         quantity,
         container_id,
         equipment_slot_id,
@@ -495,15 +355,278 @@ INSERT INTO
         notes
     )
 VALUES
-    (?, ?, 'magical_item', 1, ?, ?, ?, ?) 
+    (?, ?, 1, ?, ?, ?, ?) 
 RETURNING id,
     character_id,
     item_id,
-    item_type,
     quantity,
     container_id,
     equipment_slot_id,
     charges,
     notes,
     created_at,
-    updated_at;
\ No newline at end of file
+    updated_at;
+
+-- name: SetItemEnhancement :exec
+UPDATE character_inventory
+SET
+    enhancement_bonus = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?;
+
+-- name: CreateItemProperty :one
+INSERT INTO
+    character_inventory_properties (inventory_id, name, notes)
+SELECT
+    ci.id, ?, ?
+FROM
+    character_inventory ci
+WHERE
+    ci.id = ?
+    AND ci.character_id = ? RETURNING *;
+
+-- name: ListCharacterItemProperties :many
+SELECT
+    p.id, p.inventory_id, p.name, p.notes, p.created_at
+FROM
+    character_inventory_properties p
+    JOIN character_inventory ci ON p.inventory_id = ci.id
+WHERE
+    ci.character_id = ?
+ORDER BY
+    p.inventory_id,
+    p.id;
+
+-- name: DeleteItemProperty :exec
+DELETE FROM character_inventory_properties
+WHERE
+    id = ?
+    AND inventory_id IN (
+        SELECT
+            id
+        FROM
+            character_inventory
+        WHERE
+            character_id = ?
+    );
+
+-- name: CopyItemProperties :exec
+INSERT INTO
+    character_inventory_properties (inventory_id, name, notes)
+SELECT
+    ?, name, notes
+FROM
+    character_inventory_properties
+WHERE
+    inventory_id = ?;
+
+-- name: ListContainerRuleEntries :many
+SELECT
+    ci.id,
+    ci.container_id,
+    ci.quantity,
+    i.name as item_name,
+    i.weight as item_weight,
+    CAST(COALESCE((SELECT GROUP_CONCAT(t.tag) FROM item_tags t WHERE t.item_id = ci.item_id), '') AS TEXT) as tags,
+    c.capacity_weight,
+    c.capacity_items,
+    c.weight_multiplier,
+    CAST(COALESCE((SELECT GROUP_CONCAT(cat.tag) FROM container_allowed_tags cat WHERE cat.container_id = c.id), '') AS TEXT) as allowed_tags,
+    CAST(COALESCE(am.quantity, 1) AS INTEGER) as bundle_size,
+    ci.missiles_used,
+    ci.is_identified,
+    i.appearance
+FROM
+    character_inventory ci
+    JOIN items i ON ci.item_id = i.id
+    LEFT JOIN ammunition am ON am.item_id = i.id
+    LEFT JOIN containers c ON c.base_item_id = i.id
+WHERE
+    ci.character_id = ?;
+
+-- name: GetCatalogItemRules :one
+SELECT
+    i.id,
+    i.name,
+    i.weight,
+    CAST(COALESCE((SELECT GROUP_CONCAT(t.tag) FROM item_tags t WHERE t.item_id = i.id), '') AS TEXT) as tags,
+    c.capacity_weight,
+    c.capacity_items,
+    c.weight_multiplier,
+    CAST(COALESCE((SELECT GROUP_CONCAT(cat.tag) FROM container_allowed_tags cat WHERE cat.container_id = c.id), '') AS TEXT) as allowed_tags,
+    CAST(COALESCE(am.quantity, 1) AS INTEGER) as bundle_size
+FROM
+    items i
+    LEFT JOIN ammunition am ON am.item_id = i.id
+    LEFT JOIN containers c ON c.base_item_id = i.id
+WHERE
+    i.id = ?;
+
+-- name: ListEquipRuleEntries :many
+SELECT
+    ci.id,
+    ci.equipment_slot_id,
+    i.name as item_name,
+    i.two_handed,
+    CAST(COALESCE(
+        (SELECT GROUP_CONCAT(r.slot_group) FROM equipment_slot_rules r WHERE r.item_id = i.id),
+        (SELECT mc.slot_group FROM magical_items m JOIN magical_item_categories mc ON mc.name = m.category WHERE m.item_id = i.id),
+        (SELECT GROUP_CONCAT(r.slot_group) FROM equipment_slot_rules r WHERE r.item_type = i.item_type),
+        ''
+    ) AS TEXT) as slot_groups,
+    ci.cursed,
+    ci.is_identified,
+    i.appearance
+FROM
+    character_inventory ci
+    JOIN items i ON ci.item_id = i.id
+WHERE
+    ci.character_id = ?;
+
+-- name: GetItemSlotRules :one
+SELECT
+    i.id,
+    i.name,
+    i.two_handed,
+    CAST(COALESCE(
+        (SELECT GROUP_CONCAT(r.slot_group) FROM equipment_slot_rules r WHERE r.item_id = i.id),
+        (SELECT mc.slot_group FROM magical_items m JOIN magical_item_categories mc ON mc.name = m.category WHERE m.item_id = i.id),
+        (SELECT GROUP_CONCAT(r.slot_group) FROM equipment_slot_rules r WHERE r.item_type = i.item_type),
+        ''
+    ) AS TEXT) as slot_groups
+FROM
+    items i
+WHERE
+    i.id = ?;
+
+-- name: ListRangedEntries :many
+SELECT
+    ci.id,
+    ci.item_id,
+    i.name as item_name,
+    i.item_type,
+    ci.quantity,
+    ci.missiles_used,
+    ci.enhancement_bonus,
+    ci.container_id,
+    ci.equipment_slot_id,
+    CAST(COALESCE(a.quantity, 1) AS INTEGER) as per_unit,
+    rw.ammunition_tag,
+    CAST(COALESCE((SELECT GROUP_CONCAT(t.tag) FROM item_tags t WHERE t.item_id = ci.item_id), '') AS TEXT) as tags,
+    CAST(COALESCE((SELECT GROUP_CONCAT(cat.tag) FROM containers c JOIN container_allowed_tags cat ON cat.container_id = c.id WHERE c.base_item_id = ci.item_id), '') AS TEXT) as allowed_tags,
+    CAST(COALESCE(ic.usable, 1) AS BOOLEAN) as usable
+FROM
+    character_inventory ci
+    JOIN items i ON ci.item_id = i.id
+    LEFT JOIN item_conditions ic ON ic.name = ci.condition
+    LEFT JOIN ammunition a ON a.item_id = i.id
+    LEFT JOIN ranged_weapons rw ON rw.item_id = i.id
+WHERE
+    ci.character_id = ?
+    AND i.item_type IN ('ammunition', 'ranged_weapon', 'container')
+ORDER BY
+    ci.id;
+
+-- name: GetAmmunitionPerUnit :one
+SELECT
+    CAST(COALESCE(a.quantity, 1) AS INTEGER) as per_unit
+FROM
+    items i
+    LEFT JOIN ammunition a ON a.item_id = i.id
+WHERE
+    i.id = ?;
+
+-- name: SetAmmunitionCount :exec
+UPDATE character_inventory
+SET
+    quantity = ?,
+    missiles_used = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?;
+
+-- name: RecordMissilesFired :exec
+INSERT INTO
+    missiles_fired (character_id, inventory_id, item_id, enhancement_bonus, count)
+VALUES
+    (?, ?, ?, ?, ?);
+
+-- name: ListMissilesFired :many
+SELECT
+    *
+FROM
+    missiles_fired
+WHERE
+    character_id = ?
+ORDER BY
+    id;
+
+-- name: ClearMissilesFired :exec
+DELETE FROM missiles_fired
+WHERE
+    character_id = ?;
+
+-- name: GetInventoryItemIdentity :one
+SELECT
+    ci.id,
+    ci.is_identified,
+    ci.identified_by,
+    ci.enhancement_bonus,
+    i.name,
+    i.appearance,
+    i.item_type,
+    CAST((SELECT COUNT(*) FROM character_inventory_properties p WHERE p.inventory_id = ci.id) AS INTEGER) as property_count,
+    ci.cursed,
+    ci.equipment_slot_id
+FROM
+    character_inventory ci
+    JOIN items i ON ci.item_id = i.id
+WHERE
+    ci.id = ?
+    AND ci.character_id = ?;
+
+-- name: SetItemIdentified :exec
+UPDATE character_inventory
+SET
+    is_identified = ?,
+    identified_by = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?;
+
+-- name: SetItemCurse :exec
+UPDATE character_inventory
+SET
+    cursed = ?,
+    curse_target = ?,
+    curse_ability = ?,
+    curse_modifier = ?,
+    curse_notes = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?;
+
+-- name: DropPack :exec
+UPDATE character_inventory
+SET
+    dropped = 1,
+    dropped_location = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?;
+
+-- name: PickUpPack :exec
+UPDATE character_inventory
+SET
+    dropped = 0,
+    dropped_location = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?;
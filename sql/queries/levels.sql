@@ -0,0 +1,143 @@
+-- name: CreateLevelHistory :exec
+INSERT INTO
+    character_level_history (character_id, level, hp_gained)
+VALUES
+    (?, ?, ?) ON CONFLICT (character_id, level)
+WHERE
+    drain_id IS NULL DO NOTHING;
+
+-- name: AddLevelHitPoints :exec
+UPDATE character_level_history
+SET
+    hp_gained = hp_gained + ?
+WHERE
+    character_id = ?
+    AND level = ?
+    AND drain_id IS NULL;
+
+-- name: ListLevelHistory :many
+SELECT
+    *
+FROM
+    character_level_history
+WHERE
+    character_id = ?
+    AND drain_id IS NULL
+ORDER BY
+    level;
+
+-- name: GetHitPointsAboveLevel :one
+SELECT
+    CAST(COALESCE(SUM(hp_gained), 0) AS INTEGER) AS hp_gained
+FROM
+    character_level_history
+WHERE
+    character_id = ?
+    AND level > ?
+    AND drain_id IS NULL;
+
+-- name: CountActiveLevelsInRange :one
+SELECT
+    COUNT(*)
+FROM
+    character_level_history
+WHERE
+    character_id = ?
+    AND level > ?
+    AND level <= ?
+    AND drain_id IS NULL;
+
+-- name: DrainLevelHistory :exec
+UPDATE character_level_history
+SET
+    drain_id = ?
+WHERE
+    character_id = ?
+    AND level > ?
+    AND drain_id IS NULL;
+
+-- name: RetireLevelHistory :exec
+DELETE FROM character_level_history
+WHERE
+    character_id = ?
+    AND level > ?
+    AND drain_id IS NULL;
+
+-- name: RestoreLevelHistory :exec
+UPDATE character_level_history
+SET
+    drain_id = NULL
+WHERE
+    drain_id = ?;
+
+-- name: CreateLevelDrain :one
+INSERT INTO
+    level_drains (
+        character_id,
+        from_level,
+        to_level,
+        hp_removed,
+        xp_award_id,
+        drained_by,
+        notes
+    )
+VALUES
+    (?, ?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: GetLevelDrain :one
+SELECT
+    *
+FROM
+    level_drains
+WHERE
+    id = ?
+    AND character_id = ?
+LIMIT
+    1;
+
+-- name: ListLevelDrainsByCharacter :many
+SELECT
+    *
+FROM
+    level_drains
+WHERE
+    character_id = ?
+ORDER BY
+    created_at DESC,
+    id DESC;
+
+-- name: HasLaterUnrestoredDrain :one
+SELECT
+    COUNT(*) > 0 AS has_later
+FROM
+    level_drains
+WHERE
+    character_id = ?
+    AND id > ?
+    AND restored_at IS NULL;
+
+-- name: MarkLevelDrainRestored :exec
+UPDATE level_drains
+SET
+    restored_by = ?,
+    restored_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?;
+
+-- name: UpdateCharacterHitPoints :one
+UPDATE characters
+SET
+    max_hp = ?,
+    current_hp = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND user_id = ? RETURNING *;
+
+-- name: IsLevelDrainAward :one
+SELECT
+    COUNT(*) > 0 AS is_drain
+FROM
+    level_drains
+WHERE
+    xp_award_id = ?;
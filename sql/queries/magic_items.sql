@@ -0,0 +1,139 @@
+-- name: ListInventoryItemEffects :many
+SELECT
+    e.id,
+    e.effect_type,
+    e.dice,
+    e.spell_id,
+    s.name as spell_name,
+    s.range as spell_range,
+    s.duration as spell_duration,
+    e.condition_name,
+    e.ability,
+    e.bonus_target,
+    e.modifier,
+    e.duration_hours
+FROM
+    character_inventory ci
+    JOIN magical_items m ON m.item_id = ci.item_id
+    JOIN magical_item_effects e ON e.magical_item_id = m.id
+    LEFT JOIN spells s ON s.id = e.spell_id
+WHERE
+    ci.id = ?
+    AND ci.character_id = ?
+ORDER BY
+    e.id;
+
+-- name: FillItemCharges :exec
+UPDATE character_inventory
+SET
+    charges = (
+        SELECT
+            m.max_charges
+        FROM
+            magical_items m
+        WHERE
+            m.item_id = character_inventory.item_id
+    )
+WHERE
+    id = ?
+    AND character_id = ?
+    AND charges IS NULL;
+
+-- name: ListWornMagicItems :many
+SELECT
+    ci.id as inventory_id,
+    i.name as item_name,
+    m.category,
+    es.slot_group,
+    mc.slot_group as category_slot_group,
+    mc.max_functioning
+FROM
+    character_inventory ci
+    JOIN items i ON i.id = ci.item_id
+    JOIN magical_items m ON m.item_id = ci.item_id
+    JOIN equipment_slots es ON es.id = ci.equipment_slot_id
+    LEFT JOIN magical_item_categories mc ON mc.name = m.category
+WHERE
+    ci.character_id = ?
+ORDER BY
+    ci.id;
+
+-- name: ListEquippedItemBonuses :many
+SELECT
+    ci.id as inventory_id,
+    i.name as item_name,
+    e.bonus_target,
+    e.ability,
+    e.modifier
+FROM
+    character_inventory ci
+    JOIN items i ON i.id = ci.item_id
+    JOIN magical_items m ON m.item_id = ci.item_id
+    JOIN magical_item_effects e ON e.magical_item_id = m.id
+WHERE
+    ci.character_id = ?
+    AND ci.equipment_slot_id IS NOT NULL
+    AND e.effect_type = 'passive_bonus'
+ORDER BY
+    ci.id,
+    e.id;
+
+-- name: ListEquippedCursePenalties :many
+SELECT
+    ci.id as inventory_id,
+    i.name as item_name,
+    ci.is_identified,
+    ci.curse_target,
+    ci.curse_ability,
+    ci.curse_modifier
+FROM
+    character_inventory ci
+    JOIN items i ON i.id = ci.item_id
+WHERE
+    ci.character_id = ?
+    AND ci.cursed = 1
+    AND ci.equipment_slot_id IS NOT NULL
+    AND ci.curse_target IS NOT NULL
+ORDER BY
+    ci.id;
+
+-- name: CreateCharacterItemEffect :one
+INSERT INTO
+    character_item_effects (character_id, source_name, effect_type, ability, modifier, condition_id, expires_at_hour)
+VALUES
+    (?, ?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: ListCharacterItemEffects :many
+SELECT
+    *
+FROM
+    character_item_effects
+WHERE
+    character_id = ?
+ORDER BY
+    id;
+
+-- name: ListExpiredItemEffects :many
+SELECT
+    *
+FROM
+    character_item_effects
+WHERE
+    character_id = ?
+    AND expires_at_hour IS NOT NULL
+    AND expires_at_hour <= ?;
+
+-- name: GetCharacterItemEffect :one
+SELECT
+    *
+FROM
+    character_item_effects
+WHERE
+    id = ?
+    AND character_id = ?;
+
+-- name: DeleteCharacterItemEffect :exec
+DELETE FROM character_item_effects
+WHERE
+    id = ?
+    AND character_id = ?;
@@ -0,0 +1,133 @@
+-- name: ListShopItems :many
+SELECT
+    i.id,
+    i.name,
+    i.item_type,
+    i.weight,
+    i.value,
+    i.stackable,
+    CAST(COALESCE(pli.available, i.item_type != 'magical_item') AS BOOLEAN) as available,
+    CAST(COALESCE(pli.markup_percent, pl.markup_percent, 100) AS INTEGER) as markup_percent
+FROM
+    items i
+    LEFT JOIN price_lists pl ON pl.id = ?
+    LEFT JOIN price_list_items pli ON pli.price_list_id = pl.id
+    AND pli.item_id = i.id
+ORDER BY
+    i.item_type,
+    i.name;
+
+-- name: ListShopCart :many
+SELECT
+    c.item_id,
+    c.quantity,
+    i.name,
+    i.item_type,
+    i.weight,
+    i.value,
+    i.stackable,
+    CAST(COALESCE(pli.available, i.item_type != 'magical_item') AS BOOLEAN) as available,
+    CAST(COALESCE(pli.markup_percent, pl.markup_percent, 100) AS INTEGER) as markup_percent
+FROM
+    shop_cart_items c
+    JOIN items i ON c.item_id = i.id
+    LEFT JOIN price_lists pl ON pl.id = ?
+    LEFT JOIN price_list_items pli ON pli.price_list_id = pl.id
+    AND pli.item_id = i.id
+WHERE
+    c.character_id = ?
+ORDER BY
+    i.name;
+
+-- name: AddShopCartItem :exec
+INSERT INTO
+    shop_cart_items (character_id, item_id, quantity)
+VALUES
+    (?, ?, ?) ON CONFLICT (character_id, item_id) DO
+UPDATE
+SET
+    quantity = quantity + excluded.quantity;
+
+-- name: RemoveShopCartItem :exec
+DELETE FROM shop_cart_items
+WHERE
+    character_id = ?
+    AND item_id = ?;
+
+-- name: ClearShopCart :exec
+DELETE FROM shop_cart_items
+WHERE
+    character_id = ?;
+
+-- name: ListCampaignPriceLists :many
+SELECT
+    *
+FROM
+    price_lists
+WHERE
+    campaign_id = ?
+ORDER BY
+    settlement;
+
+-- name: GetPriceList :one
+SELECT
+    *
+FROM
+    price_lists
+WHERE
+    id = ?
+LIMIT
+    1;
+
+-- name: SavePriceList :one
+INSERT INTO
+    price_lists (campaign_id, settlement, markup_percent, sell_percent, treasure_fee_percent)
+VALUES
+    (?, ?, ?, ?, ?) ON CONFLICT (campaign_id, settlement) DO
+UPDATE
+SET
+    markup_percent = excluded.markup_percent,
+    sell_percent = excluded.sell_percent,
+    treasure_fee_percent = excluded.treasure_fee_percent,
+    updated_at = CURRENT_TIMESTAMP RETURNING *;
+
+-- name: SetPriceListItem :exec
+INSERT INTO
+    price_list_items (price_list_id, item_id, available, markup_percent)
+VALUES
+    (?, ?, ?, ?) ON CONFLICT (price_list_id, item_id) DO
+UPDATE
+SET
+    available = excluded.available,
+    markup_percent = excluded.markup_percent;
+
+-- name: ListPriceListItems :many
+SELECT
+    pli.price_list_id,
+    pli.item_id,
+    pli.available,
+    pli.markup_percent,
+    i.name
+FROM
+    price_list_items pli
+    JOIN items i ON pli.item_id = i.id
+    JOIN price_lists pl ON pli.price_list_id = pl.id
+WHERE
+    pl.campaign_id = ?
+ORDER BY
+    i.name;
+
+-- name: GetInventoryItemSale :one
+SELECT
+    ci.id,
+    ci.item_id,
+    ci.quantity,
+    ci.condition,
+    i.value,
+    i.item_type
+FROM
+    character_inventory ci
+    JOIN items i ON ci.item_id = i.id
+WHERE
+    ci.id = ?
+    AND ci.character_id = ?;
@@ -0,0 +1,94 @@
+-- name: ListRestModes :many
+SELECT
+    *
+FROM
+    rest_modes
+ORDER BY
+    sort_order,
+    code;
+
+-- name: GetRestMode :one
+SELECT
+    *
+FROM
+    rest_modes
+WHERE
+    code = ?
+LIMIT
+    1;
+
+-- name: ListCharacterResourceUses :many
+SELECT
+    *
+FROM
+    character_resource_uses
+WHERE
+    character_id = ?;
+
+-- name: UseCharacterResource :one
+INSERT INTO
+    character_resource_uses (character_id, resource, used)
+VALUES
+    (?, ?, 1) ON CONFLICT (character_id, resource) DO
+UPDATE
+SET
+    used = used + 1 RETURNING *;
+
+-- name: ResetCharacterResourceUses :exec
+DELETE FROM character_resource_uses
+WHERE
+    character_id = ?;
+
+-- name: EnsureGameClock :exec
+INSERT INTO
+    character_game_clocks (character_id)
+VALUES
+    (?) ON CONFLICT (character_id) DO NOTHING;
+
+-- name: GetGameClock :one
+SELECT
+    *
+FROM
+    character_game_clocks
+WHERE
+    character_id = ?
+LIMIT
+    1;
+
+-- name: AdvanceGameClock :one
+UPDATE character_game_clocks
+SET
+    elapsed_hours = elapsed_hours + ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    character_id = ? RETURNING *;
+
+-- name: SetGameClockTracking :exec
+UPDATE character_game_clocks
+SET
+    track_time = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    character_id = ?;
+
+-- name: ListCharacterRations :many
+WITH RECURSIVE stored (id) AS (
+    SELECT si.id FROM character_inventory si WHERE si.storage_location_id IS NOT NULL
+    UNION ALL
+    SELECT si.id FROM character_inventory si JOIN stored s ON si.container_id = s.id
+)
+SELECT
+    ci.id,
+    ci.quantity,
+    i.name
+FROM
+    character_inventory ci
+    JOIN items i ON ci.item_id = i.id
+WHERE
+    ci.character_id = ?
+    AND i.item_type = 'equipment'
+    AND i.name LIKE 'Rations%'
+    AND ci.id NOT IN (SELECT id FROM stored)
+ORDER BY
+    i.name DESC,
+    ci.id;
@@ -0,0 +1,102 @@
+-- name: AddStashItem :one
+INSERT INTO party_stash_items (campaign_id, item_id, quantity, appraised_value, notes)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetStashItem :one
+SELECT
+    psi.id,
+    psi.campaign_id,
+    psi.item_id,
+    psi.quantity,
+    psi.appraised_value,
+    psi.notes,
+    i.name as item_name,
+    i.value as item_value
+FROM
+    party_stash_items psi
+    JOIN items i ON i.id = psi.item_id
+WHERE
+    psi.id = ?
+    AND psi.campaign_id = ?;
+
+-- name: ListStashItems :many
+SELECT
+    psi.id,
+    psi.item_id,
+    psi.quantity,
+    psi.appraised_value,
+    psi.notes,
+    i.name as item_name,
+    i.item_type,
+    i.weight as item_weight,
+    i.value as item_value
+FROM
+    party_stash_items psi
+    JOIN items i ON i.id = psi.item_id
+WHERE
+    psi.campaign_id = ?
+ORDER BY
+    i.item_type,
+    i.name,
+    psi.id;
+
+-- name: TakeStashItems :exec
+UPDATE party_stash_items
+SET
+    quantity = quantity - ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND campaign_id = ?;
+
+-- name: DeleteStashItem :exec
+DELETE FROM party_stash_items
+WHERE
+    id = ?
+    AND campaign_id = ?;
+
+-- name: ListStashCoins :many
+SELECT * FROM party_stash_coins
+WHERE
+    campaign_id = ?
+    AND amount > 0;
+
+-- name: AddStashCoins :exec
+INSERT INTO party_stash_coins (campaign_id, denomination, amount)
+VALUES (?, ?, ?)
+ON CONFLICT (campaign_id, denomination) DO UPDATE
+SET amount = amount + excluded.amount;
+
+-- name: TakeStashCoins :exec
+UPDATE party_stash_coins
+SET amount = amount - ?
+WHERE
+    campaign_id = ?
+    AND denomination = ?;
+
+-- name: LogStashChange :exec
+INSERT INTO party_stash_log (campaign_id, action, item_id, quantity, coins, character_id, user_id, notes)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListStashLog :many
+SELECT
+    psl.id,
+    psl.action,
+    psl.quantity,
+    psl.coins,
+    psl.notes,
+    psl.created_at,
+    i.name as item_name,
+    ch.name as character_name,
+    u.username
+FROM
+    party_stash_log psl
+    LEFT JOIN items i ON i.id = psl.item_id
+    LEFT JOIN characters ch ON ch.id = psl.character_id
+    JOIN users u ON u.id = psl.user_id
+WHERE
+    psl.campaign_id = ?
+ORDER BY
+    psl.created_at DESC,
+    psl.id DESC;
@@ -0,0 +1,117 @@
+-- name: CreateStorageLocation :one
+INSERT INTO storage_locations (character_id, campaign_id, name, kind, notes)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetStorageLocation :one
+SELECT * FROM storage_locations
+WHERE id = ?;
+
+-- name: ListStorageLocationsForCharacter :many
+SELECT * FROM storage_locations
+WHERE
+    character_id = ?
+    OR campaign_id = ?
+ORDER BY
+    campaign_id IS NOT NULL,
+    name;
+
+-- name: DeleteStorageLocation :exec
+DELETE FROM storage_locations
+WHERE id = ?;
+
+-- name: ClearStorageLocationCoins :exec
+DELETE FROM storage_location_coins
+WHERE storage_location_id = ?;
+
+-- name: CountStorageLocationContents :one
+SELECT
+    CAST(
+        (SELECT COUNT(*) FROM character_inventory ci WHERE ci.storage_location_id = ?)
+        + (SELECT COUNT(*) FROM storage_location_coins slc WHERE slc.storage_location_id = ? AND slc.amount > 0)
+    AS INTEGER) as contents;
+
+-- name: StoreInventoryItem :exec
+UPDATE character_inventory
+SET
+    storage_location_id = ?,
+    container_id = NULL,
+    equipment_slot_id = NULL,
+    dropped = 0,
+    dropped_location = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?;
+
+-- name: ListStorageLocationItems :many
+WITH RECURSIVE stored (id) AS (
+    SELECT ci.id FROM character_inventory ci WHERE ci.storage_location_id = ?
+    UNION ALL
+    SELECT ci.id FROM character_inventory ci JOIN stored s ON ci.container_id = s.id
+)
+SELECT
+    ci.id,
+    ci.character_id,
+    ch.name as character_name,
+    ci.item_id,
+    i.name as item_name,
+    i.item_type,
+    ci.quantity,
+    ci.container_id,
+    i.weight as item_weight,
+    CAST(COALESCE(am.quantity, 1) AS INTEGER) as bundle_size,
+    ci.missiles_used,
+    ci.enhancement_bonus,
+    ci.is_identified,
+    i.appearance,
+    ci.notes,
+    c.capacity_weight,
+    c.capacity_items,
+    c.weight_multiplier
+FROM
+    stored s
+    JOIN character_inventory ci ON ci.id = s.id
+    JOIN characters ch ON ch.id = ci.character_id
+    JOIN items i ON i.id = ci.item_id
+    LEFT JOIN ammunition am ON am.item_id = i.id
+    LEFT JOIN containers c ON c.base_item_id = i.id
+ORDER BY
+    ch.name,
+    i.name;
+
+-- name: ListStorageLocationCoins :many
+SELECT
+    slc.character_id,
+    ch.name as character_name,
+    slc.denomination,
+    slc.amount
+FROM
+    storage_location_coins slc
+    JOIN characters ch ON ch.id = slc.character_id
+WHERE
+    slc.storage_location_id = ?
+    AND slc.amount > 0
+ORDER BY
+    ch.name,
+    slc.denomination;
+
+-- name: ListCharacterStoredCoins :many
+SELECT * FROM storage_location_coins
+WHERE
+    character_id = ?
+    AND amount > 0;
+
+-- name: AddStoredCoins :exec
+INSERT INTO storage_location_coins (storage_location_id, character_id, denomination, amount)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (storage_location_id, character_id, denomination) DO UPDATE
+SET amount = amount + excluded.amount;
+
+-- name: TakeStoredCoins :exec
+UPDATE storage_location_coins
+SET amount = amount - ?
+WHERE
+    storage_location_id = ?
+    AND character_id = ?
+    AND denomination = ?;
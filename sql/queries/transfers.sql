@@ -0,0 +1,79 @@
+-- name: CreateTransfer :one
+INSERT INTO character_transfers (
+    campaign_id,
+    from_character_id,
+    to_character_id,
+    inventory_id,
+    quantity,
+    notes,
+    offered_by
+)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: AddTransferCoins :exec
+INSERT INTO character_transfer_coins (transfer_id, denomination, amount)
+VALUES (?, ?, ?);
+
+-- name: GetTransfer :one
+SELECT * FROM character_transfers
+WHERE id = ?;
+
+-- name: ListTransferCoins :many
+SELECT * FROM character_transfer_coins
+WHERE transfer_id = ?
+ORDER BY denomination;
+
+-- name: ListPendingTransfers :many
+SELECT
+    t.id,
+    t.from_character_id,
+    fc.name as from_name,
+    t.to_character_id,
+    tc.name as to_name,
+    t.inventory_id,
+    t.quantity,
+    t.notes,
+    t.created_at,
+    CASE
+        WHEN ci.is_identified OR i.appearance IS NULL THEN i.name
+        ELSE i.appearance
+    END as item_name
+FROM
+    character_transfers t
+    JOIN characters fc ON fc.id = t.from_character_id
+    JOIN characters tc ON tc.id = t.to_character_id
+    LEFT JOIN character_inventory ci ON ci.id = t.inventory_id
+    LEFT JOIN items i ON i.id = ci.item_id
+WHERE
+    t.status = 'pending'
+    AND (
+        t.from_character_id = ?
+        OR t.to_character_id = ?
+    )
+ORDER BY
+    t.created_at,
+    t.id;
+
+-- name: ResolveTransfer :exec
+UPDATE character_transfers
+SET
+    status = ?,
+    resolved_by = ?,
+    resolved_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND status = 'pending';
+
+-- name: GiveInventoryItem :exec
+WITH RECURSIVE given (id) AS (
+    SELECT ci.id FROM character_inventory ci WHERE ci.id = ? AND ci.character_id = ?
+    UNION ALL
+    SELECT ci.id FROM character_inventory ci JOIN given g ON ci.container_id = g.id
+)
+UPDATE character_inventory
+SET
+    character_id = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id IN (SELECT id FROM given);
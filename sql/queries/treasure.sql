@@ -0,0 +1,76 @@
+-- name: CreateTreasureSplit :one
+INSERT INTO
+    treasure_splits (
+        campaign_id,
+        referee_id,
+        description,
+        total_value_cp,
+        remainder,
+        henchman_coins
+    )
+VALUES
+    (?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: CreateTreasureSplitShare :exec
+INSERT INTO
+    treasure_split_shares (
+        split_id,
+        character_id,
+        weight,
+        value_cp,
+        coins,
+        xp_award_id
+    )
+VALUES
+    (?, ?, ?, ?, ?, ?);
+
+-- name: ListTreasureSplitsByCampaign :many
+SELECT
+    *
+FROM
+    treasure_splits
+WHERE
+    campaign_id = ?
+ORDER BY
+    created_at DESC,
+    id DESC;
+
+-- name: ListTreasureItems :many
+SELECT
+    i.id,
+    i.name,
+    i.description,
+    i.weight,
+    i.value,
+    ti.kind
+FROM
+    items i
+    JOIN treasure_items ti ON ti.item_id = i.id
+ORDER BY
+    ti.kind,
+    i.value,
+    i.name;
+
+-- name: GetInventoryTreasure :one
+SELECT
+    ci.id,
+    ci.quantity,
+    ci.appraised_value,
+    i.name,
+    i.value,
+    i.item_type
+FROM
+    character_inventory ci
+    JOIN items i ON ci.item_id = i.id
+WHERE
+    ci.id = ?
+    AND ci.character_id = ?;
+
+-- name: SetItemAppraisal :exec
+UPDATE character_inventory
+SET
+    appraised_value = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND character_id = ?;
@@ -0,0 +1,73 @@
+-- name: CreateXPAward :one
+INSERT INTO
+    xp_awards (
+        character_id,
+        amount,
+        source,
+        session_date,
+        awarded_by,
+        reverses_award_id,
+        notes
+    )
+VALUES
+    (?, ?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: GetXPAward :one
+SELECT
+    *
+FROM
+    xp_awards
+WHERE
+    id = ?
+    AND character_id = ?
+LIMIT
+    1;
+
+-- name: IsXPAwardReversed :one
+SELECT
+    COUNT(*) > 0 AS is_reversed
+FROM
+    xp_awards
+WHERE
+    reverses_award_id = ?;
+
+-- name: ListXPAwardsByCharacter :many
+SELECT
+    xa.id,
+    xa.character_id,
+    xa.amount,
+    xa.source,
+    xa.session_date,
+    xa.awarded_by,
+    xa.reverses_award_id,
+    xa.notes,
+    xa.created_at,
+    u.username AS awarded_by_username,
+    r.id AS reversed_by_id
+FROM
+    xp_awards xa
+    JOIN users u ON xa.awarded_by = u.id
+    LEFT JOIN xp_awards r ON r.reverses_award_id = xa.id
+WHERE
+    xa.character_id = ?
+ORDER BY
+    xa.session_date,
+    xa.id;
+
+-- name: GetCharacterXPTotal :one
+SELECT
+    CAST(COALESCE(SUM(amount), 0) AS INTEGER) AS total_xp
+FROM
+    xp_awards
+WHERE
+    character_id = ?;
+
+-- name: UpdateCharacterExperience :one
+UPDATE characters
+SET
+    experience_points = ?,
+    level = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?
+    AND user_id = ? RETURNING *;